@@ -1,28 +1,105 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jhjaggars/cc-buddy/internal/ci"
+	"github.com/jhjaggars/cc-buddy/internal/client"
 	"github.com/jhjaggars/cc-buddy/internal/commands"
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/container"
 	"github.com/jhjaggars/cc-buddy/internal/environment"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
 	"github.com/jhjaggars/cc-buddy/internal/ui/models"
 )
 
+// globalOptions are flags accepted before the command
+type globalOptions struct {
+	debug   bool
+	verbose bool
+	profile string
+	ci      bool
+	host    string // daemon to run commands against
+}
+
 func main() {
-	if len(os.Args) > 1 {
+	args, globals, err := parseGlobalFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if globals.profile != "" {
+		// The config is loaded by each command, which reads the profile from here
+		os.Setenv(config.ProfileEnv, globals.profile)
+	}
+	if globals.ci {
+		// Set for child processes too, such as background creates
+		os.Setenv(ci.Env, "1")
+	}
+	finish := func() {}
+	if ci.Enabled() {
+		finish = ci.Setup()
+		defer finish()
+	}
+	if globals.debug || logging.DebugRequested() {
+		path, err := logging.EnableDebug()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: debug logging disabled: %v\n", err)
+		} else {
+			fmt.Fprintf(os.Stderr, "Debug log: %s\n", path)
+			defer logging.Close()
+		}
+	}
+
+	if len(args) > 0 {
+		// Echoed commands would garble the TUI, so only the CLI honors --verbose
+		logging.SetVerbose(globals.verbose)
+
 		// CLI mode for backward compatibility
-		if err := handleCLIMode(os.Args[1:]); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+		run := handleCLIMode
+		if globals.host != "" {
+			run = func(args []string) error {
+				return handleRemoteMode(globals.host, args)
+			}
+		}
+		if err := run(args); err != nil {
+			logging.Logger().Error("command failed", "command", args[0], "error", err.Error())
+			logging.Close()
+			var exitErr *commands.ExitError
+			if !errors.As(err, &exitErr) || exitErr.Timeout > 0 {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+			var unavailableErr *container.UnavailableError
+			if errors.As(err, &unavailableErr) {
+				fmt.Fprintf(os.Stderr, "\n%s\n\nWithout a runtime, cc-buddy still lists and deletes existing environments.\n", container.InstallHint())
+			}
+			finish()
+			os.Exit(exitCode(err))
 		}
 		return
 	}
 
+	if ci.Enabled() {
+		fmt.Fprintln(os.Stderr, "Error: interactive mode is not available in CI mode; run a command (see 'cc-buddy help')")
+		finish()
+		os.Exit(ci.ExitUsage)
+	}
+
+	if globals.host != "" {
+		fmt.Fprintln(os.Stderr, "Error: the TUI is not available with --host; run a command (see 'cc-buddy help'), or the TUI on the daemon's machine")
+		finish()
+		os.Exit(ci.ExitUsage)
+	}
+
 	// TUI mode
+	chooseRuntime()
 	for {
 		mainModel := models.NewMainModel()
 		p := tea.NewProgram(mainModel, tea.WithAltScreen())
@@ -39,9 +116,25 @@ func main() {
 		// Check if we need to launch a terminal
 		finalModel := model.(*models.MainModel)
 		terminalEnv := finalModel.GetTerminalEnvironment()
+		task := finalModel.GetTask()
+		editorEnv := finalModel.GetEditorEnvironment()
 		finalModel.Cleanup()
 		
-		if terminalEnv != "" {
+		if editorEnv != "" {
+			// Open the editor and restart TUI when it exits
+			if err := launchEditor(editorEnv); err != nil {
+				fmt.Fprintf(os.Stderr, "Error opening editor: %v\n", err)
+				fmt.Println("Press Enter to continue...")
+				fmt.Scanln()
+			}
+		} else if terminalEnv != "" && task != "" {
+			// Run the task picked from the menu, then wait so its output can be read
+			if err := launchTask(terminalEnv, task); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+			fmt.Println("Press Enter to return to cc-buddy...")
+			fmt.Scanln()
+		} else if terminalEnv != "" {
 			// Launch terminal and restart TUI when done
 			if err := launchTerminal(terminalEnv); err != nil {
 				fmt.Fprintf(os.Stderr, "Error opening terminal: %v\n", err)
@@ -56,10 +149,97 @@ func main() {
 	}
 }
 
+// exitCode picks the exit status for a failed command: a command run in an
+// environment passes on its own, and CI mode tells apart the failures a
+// pipeline may handle differently
+func exitCode(err error) int {
+	var exitErr *commands.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	if !ci.Enabled() {
+		return 1
+	}
+
+	var configErr *environment.ConfigError
+	var unavailableErr *container.UnavailableError
+	message := err.Error()
+	switch {
+	case strings.HasPrefix(message, "usage:") || strings.Contains(message, "\nusage:") || strings.HasPrefix(message, "unknown command:"):
+		return ci.ExitUsage
+	case errors.As(err, &configErr):
+		return ci.ExitConfig
+	case errors.As(err, &unavailableErr):
+		return ci.ExitUnavailable
+	case errors.Is(err, environment.ErrNotReady):
+		return ci.ExitNotReady
+	}
+	return 1
+}
+
+// parseGlobalFlags strips the global flags preceding the command
+func parseGlobalFlags(args []string) ([]string, globalOptions, error) {
+	var opts globalOptions
+	for len(args) > 0 {
+		switch {
+		case args[0] == "--debug":
+			opts.debug = true
+		case args[0] == "--ci":
+			opts.ci = true
+		case args[0] == "-v" || args[0] == "--verbose":
+			opts.verbose = true
+		case args[0] == "--profile":
+			if len(args) < 2 {
+				return nil, opts, fmt.Errorf("--profile flag requires a profile name")
+			}
+			args = args[1:]
+			opts.profile = args[0]
+		case strings.HasPrefix(args[0], "--profile="):
+			opts.profile = strings.TrimPrefix(args[0], "--profile=")
+		case args[0] == "--host":
+			if len(args) < 2 {
+				return nil, opts, fmt.Errorf("--host flag requires a daemon address")
+			}
+			args = args[1:]
+			opts.host = args[0]
+		case strings.HasPrefix(args[0], "--host="):
+			opts.host = strings.TrimPrefix(args[0], "--host=")
+		default:
+			return finishGlobalFlags(args, opts)
+		}
+		args = args[1:]
+	}
+	return finishGlobalFlags(args, opts)
+}
+
+// finishGlobalFlags fills in global options from the environment
+func finishGlobalFlags(args []string, opts globalOptions) ([]string, globalOptions, error) {
+	if opts.host == "" {
+		opts.host = os.Getenv(client.HostEnv)
+	}
+	return args, opts, nil
+}
+
+// handleRemoteMode runs a command against the daemon at host
+func handleRemoteMode(host string, args []string) error {
+	switch args[0] {
+	case "help", "-h", "--help", "version", "--version":
+		return handleCLIMode(args)
+	}
+	c, err := client.New(host, os.Getenv(client.TokenEnv))
+	if err != nil {
+		return err
+	}
+	if c.Plaintext() {
+		fmt.Fprintf(os.Stderr, "Warning: the API token goes to %s unencrypted; use an https:// URL or an SSH tunnel (unix:// or tcp://localhost) across untrusted networks\n", host)
+	}
+	return commands.NewRemoteCommand(c).Execute(context.Background(), args[0], args[1:])
+}
+
 func handleCLIMode(args []string) error {
 	if len(args) == 0 {
 		fmt.Println("Usage: cc-buddy [command] [args...]")
-		fmt.Println("Commands: init, create, list, delete, terminal, exec")
+		fmt.Println("Commands: init, create, wait, start, list, delete, terminal, exec, task, run, sync, rebuild, publish, export-def, scan, switch, watch, protect, unprotect, label, note, inspect, ports, open, browse, path, shell-init, reap, gc, prefetch, hosts, proxy, history, daemon, doctor, version")
 		fmt.Println("Run without arguments for interactive mode")
 		return nil
 	}
@@ -68,6 +248,13 @@ func handleCLIMode(args []string) error {
 	command := args[0]
 	commandArgs := args[1:]
 
+	switch command {
+	case "help", "-h", "--help", "version", "--version", "shell-init", "path", "history":
+		// These never talk to a runtime
+	default:
+		chooseRuntime()
+	}
+
 	switch command {
 	case "init":
 		envManager, err := environment.NewManager()
@@ -117,6 +304,226 @@ func handleCLIMode(args []string) error {
 		execCmd := commands.NewExecCommand(envManager)
 		return execCmd.Execute(ctx, commandArgs)
 
+	case "task":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		taskCmd := commands.NewTaskCommand(envManager)
+		return taskCmd.Execute(ctx, commandArgs)
+
+	case "run":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		runCmd := commands.NewRunCommand(envManager)
+		return runCmd.Execute(ctx, commandArgs)
+
+	case "hosts":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		hostsCmd := commands.NewHostsCommand(envManager)
+		return hostsCmd.Execute(ctx, commandArgs)
+
+	case "proxy":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		proxyCmd := commands.NewProxyCommand(envManager)
+		return proxyCmd.Execute(ctx, commandArgs)
+
+	case "sync":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		syncCmd := commands.NewSyncCommand(envManager)
+		return syncCmd.Execute(ctx, commandArgs)
+
+	case "wait":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		waitCmd := commands.NewWaitCommand(envManager)
+		return waitCmd.Execute(ctx, commandArgs)
+
+	case "start":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		startCmd := commands.NewStartCommand(envManager)
+		return startCmd.Execute(ctx, commandArgs)
+
+	case "rebuild":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		rebuildCmd := commands.NewRebuildCommand(envManager)
+		return rebuildCmd.Execute(ctx, commandArgs)
+
+	case "publish":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		publishCmd := commands.NewPublishCommand(envManager)
+		return publishCmd.Execute(ctx, commandArgs)
+
+	case "export-def":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		exportDefCmd := commands.NewExportDefCommand(envManager)
+		return exportDefCmd.Execute(ctx, commandArgs)
+
+	case "scan":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		scanCmd := commands.NewScanCommand(envManager)
+		return scanCmd.Execute(ctx, commandArgs)
+
+	case "switch":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		switchCmd := commands.NewSwitchCommand(envManager)
+		return switchCmd.Execute(ctx, commandArgs)
+
+	case "watch":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		watchCmd := commands.NewWatchCommand(envManager)
+		return watchCmd.Execute(ctx, commandArgs)
+
+	case "protect", "unprotect":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		protectCmd := commands.NewProtectCommand(envManager, command == "protect")
+		return protectCmd.Execute(ctx, commandArgs)
+
+	case "label":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		labelCmd := commands.NewLabelCommand(envManager)
+		return labelCmd.Execute(ctx, commandArgs)
+
+	case "note":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		noteCmd := commands.NewNoteCommand(envManager)
+		return noteCmd.Execute(ctx, commandArgs)
+
+	case "inspect":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		inspectCmd := commands.NewInspectCommand(envManager)
+		return inspectCmd.Execute(ctx, commandArgs)
+
+	case "browse":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		browseCmd := commands.NewBrowseCommand(envManager)
+		return browseCmd.Execute(ctx, commandArgs)
+
+	case "ports":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		portsCmd := commands.NewPortsCommand(envManager)
+		return portsCmd.Execute(ctx, commandArgs)
+
+	case "open":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		openCmd := commands.NewOpenCommand(envManager)
+		return openCmd.Execute(ctx, commandArgs)
+
+	case "path":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		pathCmd := commands.NewPathCommand(envManager)
+		return pathCmd.Execute(ctx, commandArgs)
+
+	case "shell-init":
+		shellInitCmd := commands.NewShellInitCommand()
+		return shellInitCmd.Execute(ctx, commandArgs)
+
+	case "reap":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		reapCmd := commands.NewReapCommand(envManager)
+		return reapCmd.Execute(ctx, commandArgs)
+
+	case "gc":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		gcCmd := commands.NewGCCommand(envManager)
+		return gcCmd.Execute(ctx, commandArgs)
+
+	case "prefetch":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		prefetchCmd := commands.NewPrefetchCommand(envManager)
+		return prefetchCmd.Execute(ctx, commandArgs)
+
+	case "daemon":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		daemonCmd := commands.NewDaemonCommand(envManager)
+		return daemonCmd.Execute(ctx, commandArgs)
+
+	case "history":
+		historyCmd := commands.NewHistoryCommand()
+		return historyCmd.Execute(ctx, commandArgs)
+
+	case "doctor":
+		envManager, err := environment.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to initialize: %w", err)
+		}
+		doctorCmd := commands.NewDoctorCommand(envManager)
+		return doctorCmd.Execute(ctx, commandArgs)
+
+	case "version", "--version":
+		versionCmd := commands.NewVersionCommand()
+		return versionCmd.Execute(ctx, commandArgs)
+
 	case "help", "-h", "--help":
 		printHelp()
 		return nil
@@ -126,6 +533,54 @@ func handleCLIMode(args []string) error {
 	}
 }
 
+// chooseRuntime asks once which runtime to use when both podman and docker
+// are installed and the repository leaves the choice to auto-detection, and
+// records the answer in the global config. It only asks at a terminal;
+// otherwise auto-detection prefers podman as before.
+func chooseRuntime() {
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stdout) || ci.Enabled() {
+		return
+	}
+	choices, err := environment.RuntimeChoices()
+	if err != nil || len(choices) == 0 {
+		if err != nil {
+			logging.Logger().Debug("skipping runtime choice", "error", err.Error())
+		}
+		return
+	}
+
+	fmt.Println("Both podman and docker are installed. Which should cc-buddy use?")
+	for i, name := range choices {
+		fmt.Printf("  %d. %s\n", i+1, name)
+	}
+	fmt.Print("Choice [1]: ")
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+
+	choice := choices[0]
+	for i, name := range choices {
+		if response == name || response == fmt.Sprint(i+1) {
+			choice = name
+		}
+	}
+	if err := environment.SaveRuntimeChoice(choice); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save runtime choice: %v\n", err)
+		return
+	}
+	path, _ := config.GlobalConfigPath()
+	fmt.Printf("Using %s; change \"runtime\" in %s to switch.\n\n", choice, path)
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
 // launchTerminal opens a terminal for the specified environment
 func launchTerminal(envName string) error {
 	ctx := context.Background()
@@ -146,40 +601,153 @@ func launchTerminal(envName string) error {
 	fmt.Println()
 
 	// Open terminal
-	if err := envManager.OpenTerminal(ctx, envName); err != nil {
+	if err := envManager.OpenTerminal(ctx, envName, environment.TerminalOptions{}); err != nil {
 		return fmt.Errorf("failed to open terminal: %w", err)
 	}
 
 	return nil
 }
 
+// launchEditor opens an environment's worktree in the editor for the TUI
+func launchEditor(envName string) error {
+	envManager, err := environment.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+	return envManager.OpenEditor(envName)
+}
+
+// launchTask runs a configured task in an environment for the TUI
+func launchTask(envName, task string) error {
+	envManager, err := environment.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	fmt.Printf("Running task '%s' in environment '%s'...\n\n", task, envName)
+	return commands.NewTaskCommand(envManager).Execute(context.Background(), []string{envName, task})
+}
+
 func printHelp() {
 	fmt.Println("cc-buddy - Development Environment Manager")
 	fmt.Println()
 	fmt.Println("USAGE:")
-	fmt.Println("    cc-buddy [command] [args...]")
+	fmt.Println("    cc-buddy [--debug] [-v] [--ci] [--profile <name>] [--host <daemon>] [command] [args...]")
 	fmt.Println("    cc-buddy                    # Interactive TUI mode")
 	fmt.Println()
 	fmt.Println("COMMANDS:")
-	fmt.Println("    init                        Generate Containerfile.dev interactively")
-	fmt.Println("    create <branch-name> [-e \"cmd\"] Create new development environment")
-	fmt.Println("    list [--plain]              Interactive environment list (--plain for text)")
-	fmt.Println("    delete <env-name>           Delete an environment")
-	fmt.Println("    terminal <env-name>         Open terminal in environment")
-	fmt.Println("    exec <env-name> -- <command> Execute command in environment")
+	fmt.Println("    init [--template <name>]    Generate Containerfile.dev with a wizard or from a template")
+	fmt.Println("    create <branch>... [options] Create development environments, several at once if given")
+	fmt.Println("    wait <env-name>...          Block until background creates finish and are ready")
+	fmt.Println("    start <env-name>            Start a stopped environment or one created with --no-start")
+	fmt.Println("    list [--plain|--json] [options] Interactive environment list (--plain for text)")
+	fmt.Println("    delete <env-name> [--yes]   Delete an environment (--yes skips the prompt, --force for protected ones)")
+	fmt.Println("    terminal <env-name>         Open terminal in environment (--session <name>, --fresh)")
+	fmt.Println("    exec <env-name> -- <command> Execute command in environment (--output json, --timeout 300s)")
+	fmt.Println("    open <env-name> [--files|--browser] Open the worktree in the editor or file manager, or the app in a browser")
+	fmt.Println("    browse <env-name> [port]    Open the web service on a container port (default: the main one) in a browser")
+	fmt.Println("    path <env-name>             Print the worktree path, e.g. for cd \"$(cc-buddy path env)\"")
+	fmt.Println("    shell-init <bash|zsh|fish>  Print shell functions (ccd <env-name>) to eval in your shell")
+	fmt.Println("    task <env-name> [task]      Run a task from the config's \"tasks\", or list them")
+	fmt.Println("    run <branch> -- <command>   Run a command in a throwaway environment, then delete it")
+	fmt.Println("    sync <env-name> [--pull]    Copy the worktree to (or back from) a sync-mode environment")
+	fmt.Println("    rebuild <env-name> [--repin] Rebuild the image and replace the container")
+	fmt.Println("    publish <env-name> <image>  Push the environment's image to a registry")
+	fmt.Println("    export-def <env-name> [-o f] Write a lockfile to recreate the environment with --from-def")
+	fmt.Println("    scan <env-name> [--all]     Scan the image with trivy or grype and list critical findings")
+	fmt.Println("    switch <env-name> [--rebuild] Mount the worktree in the shared container (experimental)")
+	fmt.Println("    watch <env-name> [--path p] Rebuild when the Containerfile or watched paths change")
+	fmt.Println("    protect <env-name>...       Make delete refuse an environment without --force")
+	fmt.Println("    unprotect <env-name>...     Allow an environment to be deleted again")
+	fmt.Println("    delete --label k[=v]...     Delete every environment with the labels")
+	fmt.Println("    delete --all                Delete every environment (in GitHub Actions, the run's)")
+	fmt.Println("    label <env-name> [k=v] [k-] Show, set or remove (k-) an environment's labels")
+	fmt.Println("    note <env-name> [\"text\"]    Show or set what an environment is for (--clear removes it)")
+	fmt.Println("    inspect <env-name> [--json] Show everything recorded about an environment")
+	fmt.Println("    ports <env-name> [--json]   Show where a running environment's ports are published")
+	fmt.Println("    gc [--keep-last N] [--older-than 30d] Remove images and volumes no environment needs")
+	fmt.Println("    prefetch [--containerfile p] Pull the Containerfile's base images ahead of a create")
+	fmt.Println("    reap --idle [--after 4h]    Stop (not delete) environments nobody has used")
+	fmt.Println("    hosts [list|sync|clear]     Show or manage <env>.localhost hostnames")
+	fmt.Println("    proxy [status|start|stop]   Manage the https://<env>.dev.local reverse proxy")
+	fmt.Println("    history [env-name]          Show created/deleted events from .cc-buddy/history.jsonl")
+	fmt.Println("    daemon [--listen host:port] Serve metrics, the API and webhooks until stopped")
+	fmt.Println("    doctor                      Check the host setup for common problems")
+	fmt.Println("    version [--short]           Show version, build and runtime details")
 	fmt.Println("    help                        Show this help message")
 	fmt.Println()
+	fmt.Println("GLOBAL OPTIONS:")
+	fmt.Println("    --debug                     Write a debug log to .cc-buddy/logs (also CC_BUDDY_DEBUG=1)")
+	fmt.Println("    -v, --verbose               Print each git and container runtime command on stderr")
+	fmt.Println("    --profile <name>            Apply a configuration profile (also CC_BUDDY_PROFILE)")
+	fmt.Println("    --ci                        CI mode: no prompts or TUI, plain output, exit codes by failure (also CI=true)")
+	fmt.Println("    --host <daemon>             Run list, create, delete, exec, start and rebuild on a daemon:")
+	fmt.Println("                                unix:///path or tcp://host:port (also CC_BUDDY_HOST; token in CC_BUDDY_API_TOKEN)")
+	fmt.Println("                                No TUI or terminal; exec streams output but has no stdin")
+	fmt.Println("                                Warns when the token would cross the network over plain http")
+	fmt.Println()
+	fmt.Println("CREATE OPTIONS:")
+	fmt.Println("    -e \"cmd\"                    Startup command for the container")
+	fmt.Println("    --mount src:dst[:opts]      Extra bind mount or named volume (repeatable)")
+	fmt.Println("    --read-only-workspace       Mount /workspace read-only")
+	fmt.Println("    --network shared            Join the shared cc-buddy network")
+	fmt.Println("    --network host              Use the host's network (reach host localhost, no port mapping)")
+	fmt.Println("    --expose-all                Publish every port the image exposes on random host ports")
+	fmt.Println("    --port [[ip:]host:]ctr[/proto] Publish a container port; no host port picks a free one (repeatable)")
+	fmt.Println("    --no-start                  Build and create the container without starting it")
+	fmt.Println("    --no-container              Only create the branch and worktree, without a container")
+	fmt.Println("    --here                      Mount the current checkout and its branch instead of a new worktree")
+	fmt.Println("    --worktree-path <path>      Adopt an existing worktree of this repository and its branch")
+	fmt.Println("    --image <ref>               Pull a published image instead of building one")
+	fmt.Println("    --runtime-arg <arg>         Pass an argument to the runtime's run command (repeatable)")
+	fmt.Println("    --privileged                Run the container privileged, e.g. to run containers inside it")
+	fmt.Println("    --cap-add <capability>      Add a capability such as SYS_PTRACE (repeatable)")
+	fmt.Println("    --device <path>             Give the container a host device such as /dev/fuse (repeatable)")
+	fmt.Println("    --from-def <file>           Reproduce an environment from an export-def lockfile")
+	fmt.Println("    --detach                    Create in a background process; see 'cc-buddy wait'")
+	fmt.Println("    --wait                      Block until the environment is ready")
+	fmt.Println("    --wait-timeout <duration>   Readiness timeout (default 120s)")
+	fmt.Println("    --wait-port <port>          Port that must accept connections when waiting")
+	fmt.Println("    --parallel <n>              Creates to run at once for several branches (default 3)")
+	fmt.Println("    --from-file <manifest>      Create the environments listed in a YAML manifest")
+	fmt.Println("    --label key=value           Label the environment and its container (repeatable)")
+	fmt.Println()
+	fmt.Println("LIST OPTIONS:")
+	fmt.Println("    --wide                      Add image, published ports, uptime, note and worktree columns")
+	fmt.Println("    --status <s>[,<s>...]       Status or health, e.g. running, stopped, unhealthy, stale, protected")
+	fmt.Println("    --branch <glob>             Branch matching a glob such as 'feature/*'")
+	fmt.Println("    --label key[=value]         Label set, or set to the value (repeatable)")
+	fmt.Println("    --older-than <age>          Created at least this long ago, e.g. 7d, 2w or 36h")
+	fmt.Println("    --sort <key>                Order by created, used, name or status (ties by name)")
+	fmt.Println("    --reverse                   Reverse the order")
+	fmt.Println()
 	fmt.Println("EXAMPLES:")
 	fmt.Println("    cc-buddy init")
+	fmt.Println("    cc-buddy init --template node-postgres")
+	fmt.Println("    cc-buddy init --devcontainer")
 	fmt.Println("    cc-buddy create feature-auth")
 	fmt.Println("    cc-buddy create feature-auth -e \"npm run dev\"")
 	fmt.Println("    cc-buddy create origin/main")
+	fmt.Println("    cc-buddy create origin/pr-123 --read-only-workspace")
+	fmt.Println("    cc-buddy create feature-api --network shared")
+	fmt.Println("    cc-buddy create ci-run --wait --wait-port 8080 --wait-timeout 3m")
+	fmt.Println("    cc-buddy create big-refactor --no-start")
+	fmt.Println("    cc-buddy create feature-a --detach && cc-buddy wait myrepo-feature-a")
+	fmt.Println("    cc-buddy create feature-a feature-b feature-c --parallel 2")
+	fmt.Println("    cc-buddy create --from-file review.yaml --wait")
+	fmt.Println("    cc-buddy create feature-auth --mount ~/.cache/go-build:/home/developer/.cache/go-build")
 	fmt.Println("    cc-buddy list                      # Interactive list with navigation")
 	fmt.Println("    cc-buddy list --plain              # Plain text output for scripts") 
+	fmt.Println("    cc-buddy list --json --status stopped --older-than 7d")
+	fmt.Println("    cc-buddy gc --keep-last 1 --older-than 30d --dry-run")
 	fmt.Println("    cc-buddy terminal myrepo-feature-auth")
 	fmt.Println("    cc-buddy exec myrepo-feature-auth -- npm test")
+	fmt.Println("    cc-buddy open myrepo-feature-auth --browser")
 	fmt.Println("    cc-buddy exec myrepo-feature-auth -- bash -c \"cd /workspace && make build\"")
+	fmt.Println("    cc-buddy run feature-auth -- npm test")
 	fmt.Println("    cc-buddy delete myrepo-feature-auth")
+	fmt.Println("    sudo cc-buddy hosts sync")
+	fmt.Println("    cc-buddy --host tcp://devbox:7777 create feature-auth --wait")
 	fmt.Println()
 	fmt.Println("For more information, visit: https://github.com/jhjaggars/cc-buddy")
 }
\ No newline at end of file
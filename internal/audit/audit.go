@@ -0,0 +1,147 @@
+// Package audit keeps an append-only log of environment lifecycle events
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+)
+
+// HistoryFile is the events log kept in the state directory
+const HistoryFile = "history.jsonl"
+
+// Lifecycle events
+const (
+	EventCreated      = "created"
+	EventCreateFailed = "create_failed"
+	EventRebuilt      = "rebuilt"
+	EventStarted      = "started"
+	EventStopped      = "stopped"
+	EventDeleted      = "deleted"
+	EventMaintenance  = "maintenance" // a scheduled daemon task; Task names it
+)
+
+// Event is one line of the history log
+type Event struct {
+	Time        time.Time        `json:"time"`
+	Event       string           `json:"event"`
+	Environment string           `json:"environment"`
+	Branch      string           `json:"branch,omitempty"`
+	User        string           `json:"user,omitempty"`     // host user who ran the command
+	DurationMS  int64            `json:"duration_ms"`        // how long the operation took
+	BuildMS     int64            `json:"build_ms,omitempty"` // image build time, for creates
+	StepsMS     map[string]int64 `json:"steps_ms,omitempty"` // time spent in each step, for creates
+	Error       string           `json:"error,omitempty"`    // set when the operation failed
+	Task        string           `json:"task,omitempty"`     // maintenance task
+	Result      string           `json:"result,omitempty"`   // what a maintenance task did
+}
+
+// Duration returns how long the operation took, rounded to the second when
+// it took longer than one
+func (e Event) Duration() time.Duration {
+	duration := time.Duration(e.DurationMS) * time.Millisecond
+	if duration < time.Second {
+		return duration
+	}
+	return duration.Round(time.Second)
+}
+
+// Log appends events to, and reads them back from, a history file
+type Log struct {
+	path string
+}
+
+// NewLog returns the history log kept in stateDir
+func NewLog(stateDir string) *Log {
+	return &Log{path: filepath.Join(stateDir, HistoryFile)}
+}
+
+// Record appends an event for an operation that started at began, filling
+// in the time and user
+func (l *Log) Record(event Event, began time.Time) error {
+	event.Time = time.Now()
+	event.DurationMS = event.Time.Sub(began).Milliseconds()
+	if event.User == "" {
+		event.User = currentUser()
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	// A single O_APPEND write keeps lines from concurrent cc-buddy processes intact
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+// Read returns the recorded events, oldest first, limited to envName unless
+// it is empty. Lines that cannot be parsed are skipped.
+func (l *Log) Read(envName string) ([]Event, error) {
+	file, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer file.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		if envName == "" || event.Environment == envName {
+			events = append(events, event)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return events, nil
+}
+
+// Latest returns the most recent event of the given kind, or nil if there
+// is none
+func (l *Log) Latest(kind string) (*Event, error) {
+	events, err := l.Read("")
+	if err != nil {
+		return nil, err
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Event == kind {
+			return &events[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// currentUser names the host user for the log
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	for _, key := range []string{"USER", "USERNAME"} {
+		if name := os.Getenv(key); name != "" {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,348 @@
+// Package ci adapts cc-buddy to running inside CI pipelines: no prompts or
+// TUI, plain output, collapsible log sections and distinct exit codes
+package ci
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Env turns CI mode on for cc-buddy and the processes it starts; the --ci
+// flag sets it
+const Env = "CC_BUDDY_CI"
+
+// Exit codes for the failures pipelines act on, from sysexits.h. Commands
+// run in an environment keep their own exit code, and 124 means --timeout.
+const (
+	ExitUsage       = 64 // bad arguments or unknown command
+	ExitUnavailable = 69 // no usable container runtime
+	ExitNotReady    = 75 // environment not ready in time
+	ExitConfig      = 78 // config file or settings invalid
+)
+
+// Enabled reports whether CI mode is on: set with --ci or CC_BUDDY_CI, or
+// detected from the CI variable most CI services set
+func Enabled() bool {
+	if value, ok := os.LookupEnv(Env); ok {
+		enabled, _ := strconv.ParseBool(value)
+		return enabled
+	}
+	enabled, _ := strconv.ParseBool(os.Getenv("CI"))
+	return enabled
+}
+
+// filter is a stream whose writes are copied to the original through Strip
+type filter struct {
+	original *os.File
+	writer   *os.File
+
+	// marker is written to the stream to find out when everything before
+	// it has reached the original. It holds random bytes, so output that
+	// happens to contain NULs or similar is never taken for it.
+	marker []byte
+
+	mu        sync.Mutex
+	cond      *sync.Cond
+	requested int  // markers written by sync
+	copied    int  // markers the copy has reached
+	closed    bool // the copy has ended
+}
+
+// newFilter returns a filter of original written through writer
+func newFilter(original, writer *os.File) *filter {
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+	f := &filter{
+		original: original,
+		writer:   writer,
+		marker:   []byte("\x00cc-buddy-sync-" + hex.EncodeToString(nonce) + "\x00"),
+	}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+// filters holds stdout's and stderr's filters while Setup is in effect
+var filters struct {
+	mu             sync.Mutex
+	stdout, stderr *filter
+}
+
+// Setup prepares the process for CI mode: colors are turned off for
+// cc-buddy and its children, build output is plain, and emoji are filtered
+// from cc-buddy's own messages on stdout and stderr. Output of commands run
+// in environments and JSON documents bypass the filter (see Stdout). The
+// returned function restores them and waits for the filtered output to be
+// written; call it before exiting.
+func Setup() func() {
+	os.Setenv("NO_COLOR", "1")
+	os.Setenv("BUILDKIT_PROGRESS", "plain")
+
+	stdout, restoreStdout := plainFile(&os.Stdout)
+	stderr, restoreStderr := plainFile(&os.Stderr)
+	filters.mu.Lock()
+	filters.stdout, filters.stderr = stdout, stderr
+	filters.mu.Unlock()
+	return func() {
+		filters.mu.Lock()
+		filters.stdout, filters.stderr = nil, nil
+		filters.mu.Unlock()
+		restoreStdout()
+		restoreStderr()
+	}
+}
+
+// Stdout returns the file for output that must reach the log unchanged:
+// that of commands run in environments, and JSON. In CI mode this is the
+// real stdout, once everything printed before has been written to it, so
+// the two stay in order.
+func Stdout() *os.File {
+	return unfiltered(func() *filter { return filters.stdout }, os.Stdout)
+}
+
+// Stderr is the stderr counterpart of Stdout
+func Stderr() *os.File {
+	return unfiltered(func() *filter { return filters.stderr }, os.Stderr)
+}
+
+// unfiltered returns the original of the filter get returns, after syncing
+// both filters, or current outside CI mode
+func unfiltered(get func() *filter, current *os.File) *os.File {
+	filters.mu.Lock()
+	defer filters.mu.Unlock()
+	f := get()
+	if f == nil {
+		return current
+	}
+	filters.stdout.sync()
+	filters.stderr.sync()
+	return f.original
+}
+
+// sync waits until everything written to f so far reached the original.
+// Callers hold filters.mu, so markers are written in the order counted.
+func (f *filter) sync() {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requested++
+	want := f.requested
+	if _, err := f.writer.Write(f.marker); err != nil {
+		f.requested--
+		return
+	}
+	for f.copied < want && !f.closed {
+		f.cond.Wait()
+	}
+}
+
+// reached records that the copy passed one marker, or ended
+func (f *filter) reached(ended bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if ended {
+		f.closed = true
+	} else {
+		f.copied++
+	}
+	f.cond.Broadcast()
+}
+
+// plainFile replaces *f with a pipe copied to the original through Strip
+func plainFile(f **os.File) (*filter, func()) {
+	original := *f
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, func() {} // keep the unfiltered output
+	}
+	*f = w
+	filtered := newFilter(original, w)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer filtered.reached(true)
+		copyPlain(original, r, filtered.marker, func() { filtered.reached(false) })
+	}()
+	return filtered, func() {
+		*f = original
+		w.Close()
+		<-done
+		r.Close()
+	}
+}
+
+// copyPlain copies src to dst through Strip, holding back runes and
+// markers split between reads. Each marker is dropped and reported to
+// reached once what preceded it is written.
+func copyPlain(dst io.Writer, src io.Reader, marker []byte, reached func()) {
+	buf := make([]byte, 32*1024)
+	var pending []byte
+	skipSpace := false
+	for {
+		n, err := src.Read(buf)
+		data := append(pending, buf[:n]...)
+		for {
+			i := bytes.Index(data, marker)
+			if i < 0 {
+				break
+			}
+			io.WriteString(dst, strip(string(data[:i]), &skipSpace))
+			reached()
+			data = data[i+len(marker):]
+		}
+		cut := len(data)
+		for k := min(len(marker)-1, len(data)); k > 0; k-- {
+			if bytes.HasSuffix(data, marker[:k]) {
+				cut = len(data) - k
+				break
+			}
+		}
+		for i := cut - 1; i >= 0 && i >= cut-utf8.UTFMax; i-- {
+			if utf8.RuneStart(data[i]) {
+				if !utf8.FullRune(data[i:cut]) {
+					cut = i
+				}
+				break
+			}
+		}
+		io.WriteString(dst, strip(string(data[:cut]), &skipSpace))
+		pending = append([]byte(nil), data[cut:]...)
+		if err != nil {
+			dst.Write(pending)
+			return
+		}
+	}
+}
+
+// symbolText spells out the emoji whose meaning matters in a log
+var symbolText = map[rune]string{
+	'❌': "FAILED",
+	'⚠': "WARNING:",
+}
+
+// Strip removes emoji (and the spaces after them) from s, spelling out the
+// ones that mark failures and warnings
+func Strip(s string) string {
+	skipSpace := false
+	return strip(s, &skipSpace)
+}
+
+// strip is Strip for one piece of a stream; skipSpace carries whether the
+// previous piece ended in an emoji whose trailing spaces are dropped
+func strip(s string, skipSpace *bool) string {
+	var b strings.Builder
+	for _, r := range s {
+		if *skipSpace && r == ' ' {
+			continue
+		}
+		*skipSpace = false
+		if text, ok := symbolText[r]; ok {
+			b.WriteString(text + " ")
+			*skipSpace = true
+			continue
+		}
+		if isEmoji(r) {
+			*skipSpace = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// isEmoji reports whether r is an emoji or pictograph, or joins them
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF, // pictographs, emoticons, symbols
+		r >= 0x2600 && r <= 0x27BF, // miscellaneous symbols and dingbats
+		r >= 0x2300 && r <= 0x23FF, // technical symbols such as ⏹
+		r >= 0x2B00 && r <= 0x2BFF, // arrows and shapes such as ⭐
+		r == 0x2139,                // ℹ
+		r == 0xFE0F, r == 0x200D:   // emoji presentation and joiner
+		return true
+	}
+	return false
+}
+
+// sectionsKey is the context key for the log sections of a command
+type sectionsKey struct{}
+
+// sections tracks the open log section
+type sections struct {
+	mu   sync.Mutex
+	open string
+}
+
+// WithSections returns a context in which Section starts collapsible log
+// sections (outside CI mode it returns ctx unchanged). The returned function
+// closes the last section.
+func WithSections(ctx context.Context) (context.Context, func()) {
+	if !Enabled() {
+		return ctx, func() {}
+	}
+	s := &sections{}
+	return context.WithValue(ctx, sectionsKey{}, s), func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.end()
+	}
+}
+
+// Section ends the open log section of ctx, if any, and starts one titled
+// title; it does nothing unless ctx came from WithSections
+func Section(ctx context.Context, title string) {
+	s, ok := ctx.Value(sectionsKey{}).(*sections)
+	if !ok || title == "" {
+		return
+	}
+	title = strings.ToUpper(title[:1]) + title[1:]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.end()
+	s.open = title
+	switch {
+	case GitHubActions():
+		fmt.Printf("::group::%s\n", title)
+	case os.Getenv("GITLAB_CI") == "true":
+		fmt.Printf("\x1b[0Ksection_start:%d:%s[collapsed=true]\r\x1b[0K%s\n", time.Now().Unix(), sectionName(title), title)
+	default:
+		fmt.Printf("--- %s\n", title)
+	}
+}
+
+// end closes the open section; the caller must hold s.mu
+func (s *sections) end() {
+	if s.open == "" {
+		return
+	}
+	switch {
+	case GitHubActions():
+		fmt.Println("::endgroup::")
+	case os.Getenv("GITLAB_CI") == "true":
+		fmt.Printf("\x1b[0Ksection_end:%d:%s\r\x1b[0K\n", time.Now().Unix(), sectionName(s.open))
+	}
+	s.open = ""
+}
+
+// sectionName turns a title into a GitLab section name
+func sectionName(title string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, title)
+}
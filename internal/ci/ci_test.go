@@ -0,0 +1,121 @@
+package ci
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+)
+
+func TestStrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain text", "Creating environment", "Creating environment"},
+		{"success emoji", "✅ Environment created", "Environment created"},
+		{"failure spelled out", "❌ Build failed", "FAILED Build failed"},
+		{"warning spelled out", "⚠️  Port in use", "WARNING: Port in use"},
+		{"emoji mid-line", "Opening 🌐 http://localhost", "Opening http://localhost"},
+		{"joined emoji", "👩‍💻 dev", "dev"},
+		{"NUL kept", "a\x00b", "a\x00b"},
+		{"non-emoji symbols kept", "→ 50% ©", "→ 50% ©"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Strip(tt.in); got != tt.want {
+				t.Errorf("Strip(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyPlain(t *testing.T) {
+	marker := []byte("\x00cc-buddy-sync-0123\x00")
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		reached int
+	}{
+		{"no markers", "✅ done\n", "done\n", 0},
+		{"NUL is data", "a\x00b", "a\x00b", 0},
+		{"marker dropped", "one\n" + string(marker) + "two\n", "one\ntwo\n", 1},
+		{"two markers", string(marker) + "x" + string(marker), "x", 2},
+		{"marker prefix is data", "a\x00cc-buddy-b", "a\x00cc-buddy-b", 0},
+		{"trailing partial marker is data", "a\x00cc-bu", "a\x00cc-bu", 0},
+	}
+	for _, tt := range tests {
+		for _, reader := range []struct {
+			name string
+			wrap func(io.Reader) io.Reader
+		}{
+			{"whole", func(r io.Reader) io.Reader { return r }},
+			{"byte by byte", iotest.OneByteReader},
+		} {
+			t.Run(tt.name+"/"+reader.name, func(t *testing.T) {
+				var out bytes.Buffer
+				reached := 0
+				copyPlain(&out, reader.wrap(bytes.NewReader([]byte(tt.in))), marker, func() { reached++ })
+				if out.String() != tt.want {
+					t.Errorf("output = %q, want %q", out.String(), tt.want)
+				}
+				if reached != tt.reached {
+					t.Errorf("reached %d markers, want %d", reached, tt.reached)
+				}
+			})
+		}
+	}
+}
+
+// captureSetup runs fn under Setup with stdout going to a file, and returns
+// what reached the file
+func captureSetup(t *testing.T, fn func()) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stdout")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	saved := os.Stdout
+	os.Stdout = file
+	defer func() { os.Stdout = saved }()
+
+	restore := Setup()
+	fn()
+	restore()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestSetupPassesNUL(t *testing.T) {
+	got := captureSetup(t, func() {
+		fmt.Fprint(os.Stdout, "a\x00b")
+		// Syncing after a NUL must not block
+		Stdout()
+		fmt.Fprint(os.Stdout, "\x00c")
+	})
+	if want := "a\x00b\x00c"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestStdoutKeepsOrder(t *testing.T) {
+	got := captureSetup(t, func() {
+		fmt.Println("✅ first")
+		fmt.Fprintln(Stdout(), `{"json": "🚀"}`)
+		fmt.Println("❌ last")
+	})
+	if want := "first\n{\"json\": \"🚀\"}\nFAILED last\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
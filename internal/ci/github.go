@@ -0,0 +1,70 @@
+package ci
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// RunLabel is the label create puts on environments made in a GitHub
+// Actions workflow run, holding the run ID, so cleanup jobs find them
+const RunLabel = "github.run_id"
+
+// GitHubActions reports whether cc-buddy runs in a GitHub Actions job
+func GitHubActions() bool {
+	return os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+// RunID returns the ID of the GitHub Actions workflow run, or ""
+func RunID() string {
+	if !GitHubActions() {
+		return ""
+	}
+	return os.Getenv("GITHUB_RUN_ID")
+}
+
+// SetOutputs appends step outputs to the file GITHUB_OUTPUT names, for
+// later steps to read as steps.<id>.outputs.<name>
+func SetOutputs(outputs map[string]string) error {
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		value := outputs[name]
+		if strings.Contains(value, "\n") {
+			fmt.Fprintf(&b, "%s<<CC_BUDDY_EOF\n%s\nCC_BUDDY_EOF\n", name, value)
+		} else {
+			fmt.Fprintf(&b, "%s=%s\n", name, value)
+		}
+	}
+	return appendGitHubFile("GITHUB_OUTPUT", b.String())
+}
+
+// AppendSummary appends markdown to the job summary GITHUB_STEP_SUMMARY
+// names
+func AppendSummary(markdown string) error {
+	return appendGitHubFile("GITHUB_STEP_SUMMARY", markdown)
+}
+
+// appendGitHubFile appends text to the file named by the variable, doing
+// nothing when it is unset
+func appendGitHubFile(variable, text string) error {
+	path := os.Getenv(variable)
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", variable, err)
+	}
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", variable, err)
+	}
+	return f.Close()
+}
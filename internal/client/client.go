@@ -0,0 +1,295 @@
+// Package client talks to the HTTP API of a cc-buddy daemon, so the CLI can
+// manage environments that live on another machine
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+)
+
+// HostEnv names the daemon to use when --host is not given
+const HostEnv = "CC_BUDDY_HOST"
+
+// TokenEnv holds the daemon's API token; the daemon reads its api_token
+// from the same variable
+const TokenEnv = "CC_BUDDY_API_TOKEN"
+
+// Client calls a daemon's /api/v1 endpoints
+type Client struct {
+	host      string // as given, for messages
+	baseURL   string
+	token     string
+	http      *http.Client
+	plaintext bool // token goes over plain HTTP to another machine
+}
+
+// CreateOptions are the settings of a create
+type CreateOptions struct {
+	Branch        string            `json:"branch"`
+	Containerfile string            `json:"containerfile,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	NoStart       bool              `json:"no_start,omitempty"`
+	Wait          bool              `json:"wait,omitempty"`
+	WaitPort      int               `json:"wait_port,omitempty"`
+}
+
+// ExecResult is the outcome of a command run in an environment
+type ExecResult struct {
+	Environment string   `json:"environment"`
+	Command     []string `json:"command"`
+	ExitCode    int      `json:"exit_code"`
+	DurationMS  int64    `json:"duration_ms"`
+	Stdout      string   `json:"stdout"`
+	Stderr      string   `json:"stderr"`
+}
+
+// New returns a client for the daemon at host: unix:///path/to/socket,
+// tcp://host:port, or an http(s) URL
+func New(host, token string) (*Client, error) {
+	if token == "" {
+		return nil, fmt.Errorf("no API token for %s: set %s to the daemon's token (in its .cc-buddy/api-token)", host, TokenEnv)
+	}
+	c := &Client{host: host, token: token, http: &http.Client{}}
+
+	scheme, rest, ok := strings.Cut(host, "://")
+	if !ok {
+		scheme, rest = "tcp", host
+	}
+	switch scheme {
+	case "unix":
+		if rest == "" {
+			return nil, fmt.Errorf("invalid host %q: missing socket path", host)
+		}
+		c.baseURL = "http://cc-buddy"
+		c.http.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", rest)
+			},
+		}
+	case "tcp", "http", "https":
+		if rest == "" {
+			return nil, fmt.Errorf("invalid host %q: missing address", host)
+		}
+		if scheme == "tcp" {
+			scheme = "http"
+		}
+		c.baseURL = scheme + "://" + strings.TrimSuffix(rest, "/")
+		if scheme == "http" {
+			u, err := url.Parse(c.baseURL)
+			if err != nil {
+				return nil, fmt.Errorf("invalid host %q: %w", host, err)
+			}
+			c.plaintext = !isLoopback(u.Hostname())
+		}
+	default:
+		return nil, fmt.Errorf("invalid host %q: use unix:///path, tcp://host:port or an http(s) URL", host)
+	}
+	return c, nil
+}
+
+// Host returns the daemon address as given to New
+func (c *Client) Host() string {
+	return c.host
+}
+
+// Plaintext reports whether the API token is sent unencrypted to another
+// machine: a tcp:// or http:// host that is not loopback
+func (c *Client) Plaintext() bool {
+	return c.plaintext
+}
+
+// isLoopback reports whether hostname names this machine
+func isLoopback(hostname string) bool {
+	if hostname == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(hostname)
+	return ip != nil && ip.IsLoopback()
+}
+
+// List returns every environment, with the published ports of running ones
+func (c *Client) List(ctx context.Context) ([]config.Environment, error) {
+	var environments []config.Environment
+	err := c.do(ctx, http.MethodGet, "/environments", nil, &environments)
+	return environments, err
+}
+
+// Get returns one environment
+func (c *Client) Get(ctx context.Context, name string) (*config.Environment, error) {
+	var env config.Environment
+	if err := c.do(ctx, http.MethodGet, "/environments/"+url.PathEscape(name), nil, &env); err != nil {
+		return nil, err
+	}
+	return &env, nil
+}
+
+// Create creates an environment on the daemon's machine
+func (c *Client) Create(ctx context.Context, opts CreateOptions) (*config.Environment, error) {
+	var env config.Environment
+	if err := c.do(ctx, http.MethodPost, "/environments", opts, &env); err != nil {
+		return nil, err
+	}
+	return &env, nil
+}
+
+// Delete deletes an environment; force deletes protected ones
+func (c *Client) Delete(ctx context.Context, name string, force bool) error {
+	path := "/environments/" + url.PathEscape(name)
+	if force {
+		path += "?force=true"
+	}
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// Start starts a stopped environment
+func (c *Client) Start(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPost, "/environments/"+url.PathEscape(name)+"/start", nil, nil)
+}
+
+// Stop stops a running environment
+func (c *Client) Stop(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPost, "/environments/"+url.PathEscape(name)+"/stop", nil, nil)
+}
+
+// Rebuild rebuilds an environment's image and replaces its container
+func (c *Client) Rebuild(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPost, "/environments/"+url.PathEscape(name)+"/rebuild", nil, nil)
+}
+
+// Exec runs a command in an environment without a terminal; a non-zero
+// exit code is reported in the result, not as an error
+func (c *Client) Exec(ctx context.Context, name string, command []string) (*ExecResult, error) {
+	var result ExecResult
+	body := map[string][]string{"command": command}
+	if err := c.do(ctx, http.MethodPost, "/environments/"+url.PathEscape(name)+"/exec", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ExecStream runs a command in an environment without a terminal or stdin,
+// writing its output to stdout and stderr as it arrives, and returns its
+// exit code; a non-zero exit code is not an error
+func (c *Client) ExecStream(ctx context.Context, name string, command []string, stdout, stderr io.Writer) (int, error) {
+	body := map[string][]string{"command": command}
+	resp, err := c.send(ctx, http.MethodPost, "/environments/"+url.PathEscape(name)+"/exec/stream", body)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var frame struct {
+			Stdout   []byte `json:"stdout"`
+			Stderr   []byte `json:"stderr"`
+			ExitCode *int   `json:"exit_code"`
+			Error    string `json:"error"`
+		}
+		if err := decoder.Decode(&frame); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, fmt.Errorf("lost the command's output from %s: %w", c.host, err)
+		}
+		switch {
+		case frame.Error != "":
+			return 0, errors.New(frame.Error)
+		case frame.ExitCode != nil:
+			return *frame.ExitCode, nil
+		}
+		if _, err := stdout.Write(frame.Stdout); err != nil {
+			return 0, err
+		}
+		if _, err := stderr.Write(frame.Stderr); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Logs returns the container's output; tail > 0 keeps the last tail lines
+func (c *Client) Logs(ctx context.Context, name string, tail int) (string, error) {
+	path := "/environments/" + url.PathEscape(name) + "/logs"
+	if tail > 0 {
+		path += "?tail=" + strconv.Itoa(tail)
+	}
+	var logs bytes.Buffer
+	err := c.do(ctx, http.MethodGet, path, nil, &logs)
+	return logs.String(), err
+}
+
+// do sends a request with body as JSON and decodes the answer into out,
+// or copies it when out is a *bytes.Buffer. API errors carry the daemon's
+// message.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	resp, err := c.send(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch out := out.(type) {
+	case nil:
+		return nil
+	case *bytes.Buffer:
+		_, err := io.Copy(out, resp.Body)
+		return err
+	default:
+		if resp.StatusCode == http.StatusNoContent {
+			return nil
+		}
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("invalid answer from the daemon: %w", err)
+		}
+		return nil
+	}
+}
+
+// send sends a request with body as JSON and returns the successful answer
+// for the caller to read and close; API errors carry the daemon's message
+func (c *Client) send(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+"/api/v1"+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the daemon at %s: %w", c.host, err)
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error != "" {
+			return nil, errors.New(apiErr.Error)
+		}
+		return nil, fmt.Errorf("daemon at %s answered %s", c.host, resp.Status)
+	}
+	return resp, nil
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+	"github.com/jhjaggars/cc-buddy/internal/utils"
+)
+
+// BrowseCommand opens an environment's web service in the browser
+type BrowseCommand struct {
+	envManager *environment.Manager
+}
+
+// NewBrowseCommand creates a new browse command
+func NewBrowseCommand(envManager *environment.Manager) *BrowseCommand {
+	return &BrowseCommand{envManager: envManager}
+}
+
+// Execute runs the browse command: the service on the given container port,
+// or the main one, at the host port it is published on
+func (c *BrowseCommand) Execute(ctx context.Context, args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return fmt.Errorf("usage: cc-buddy browse <environment-name> [container-port]")
+	}
+	envName := args[0]
+
+	containerPort := 0
+	if len(args) == 2 {
+		port, err := strconv.Atoi(args[1])
+		if err != nil || port < 1 || port > 65535 {
+			return fmt.Errorf("invalid container port '%s': must be 1-65535", args[1])
+		}
+		containerPort = port
+	}
+
+	url, err := c.envManager.ServiceURL(ctx, envName, containerPort)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("🌐 Opening %s\n", url)
+	return utils.OpenWithDesktop(url)
+}
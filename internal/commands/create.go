@@ -3,8 +3,13 @@ package commands
 import (
 	"context"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 
+	"github.com/jhjaggars/cc-buddy/internal/ci"
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/container"
 	"github.com/jhjaggars/cc-buddy/internal/environment"
 )
 
@@ -21,16 +26,40 @@ func NewCreateCommand(envManager *environment.Manager) *CreateCommand {
 // Execute runs the create command
 func (c *CreateCommand) Execute(ctx context.Context, args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("usage: cc-buddy create <branch-name> [-e \"command\"]")
+		return fmt.Errorf("usage: cc-buddy create <branch-name>... [-e \"command\"] [options]\n       cc-buddy create --from-file <manifest> [options]\nRun 'cc-buddy help' for the list of options")
 	}
 
 	// Parse arguments
-	var branchName string
+	var branchNames []string
+	var flagArgs []string // everything but the branch names, for background creates
 	var startupCommand []string
+	var mounts []string
+	var readOnlyWorkspace bool
+	var network string
+	exposeAll := c.envManager.GetConfig().GetConfig().ExposeAll
+	var ports []string
+	var wait bool
+	var noStart bool
+	var noContainer bool
+	var here bool
+	var worktreePath string
+	var image string
+	var runtimeArgs []string
+	var privileged bool
+	var capAdd, devices []string
+	var detach bool
+	waitTimeout := environment.DefaultWaitTimeout
+	waitPort := c.envManager.GetConfig().GetConfig().WaitPort
+	parallel := DefaultCreateParallelism
+	parallelSet := false
+	var manifestPath string
+	var defPath string
+	var labels map[string]string
 	
 	i := 0
 	for i < len(args) {
 		arg := args[i]
+		start := i
 		
 		if arg == "-e" {
 			// Next argument should be the command
@@ -40,24 +69,257 @@ func (c *CreateCommand) Execute(ctx context.Context, args []string) error {
 			i++
 			commandStr := args[i]
 			// Parse command string into arguments using shell-like splitting
-			startupCommand = parseCommand(commandStr)
-		} else if branchName == "" {
-			branchName = arg
+			startupCommand = environment.ParseCommand(commandStr)
+		} else if arg == "--mount" {
+			if i+1 >= len(args) {
+				return fmt.Errorf("--mount flag requires a source:target[:options] argument")
+			}
+			i++
+			mounts = append(mounts, args[i])
+		} else if arg == "--read-only-workspace" {
+			readOnlyWorkspace = true
+		} else if arg == "--network" {
+			if i+1 >= len(args) {
+				return fmt.Errorf("--network flag requires a mode argument")
+			}
+			i++
+			network = args[i]
+		} else if arg == "--expose-all" {
+			exposeAll = true
+		} else if arg == "--port" {
+			if i+1 >= len(args) {
+				return fmt.Errorf("--port flag requires a [[ip:]host:]container[/protocol] argument")
+			}
+			i++
+			if _, err := container.ParsePortMapping(args[i]); err != nil {
+				return err
+			}
+			ports = append(ports, args[i])
+		} else if arg == "--detach" {
+			detach = true
+		} else if arg == "--no-start" {
+			noStart = true
+		} else if arg == "--no-container" {
+			noContainer = true
+		} else if arg == "--here" {
+			here = true
+		} else if arg == "--worktree-path" {
+			if i+1 >= len(args) {
+				return fmt.Errorf("--worktree-path flag requires a path to an existing worktree")
+			}
+			i++
+			worktreePath = args[i]
+		} else if arg == "--runtime-arg" {
+			if i+1 >= len(args) {
+				return fmt.Errorf("--runtime-arg flag requires an argument, e.g. --runtime-arg=--shm-size=2g")
+			}
+			i++
+			runtimeArgs = append(runtimeArgs, args[i])
+		} else if strings.HasPrefix(arg, "--runtime-arg=") {
+			runtimeArgs = append(runtimeArgs, strings.TrimPrefix(arg, "--runtime-arg="))
+		} else if arg == "--privileged" {
+			privileged = true
+		} else if arg == "--cap-add" {
+			if i+1 >= len(args) {
+				return fmt.Errorf("--cap-add flag requires a capability, e.g. SYS_PTRACE")
+			}
+			i++
+			capAdd = append(capAdd, args[i])
+		} else if arg == "--device" {
+			if i+1 >= len(args) {
+				return fmt.Errorf("--device flag requires a host device, e.g. /dev/fuse")
+			}
+			i++
+			devices = append(devices, args[i])
+		} else if arg == "--image" {
+			if i+1 >= len(args) {
+				return fmt.Errorf("--image flag requires an image reference")
+			}
+			i++
+			image = args[i]
+		} else if arg == "--wait" {
+			wait = true
+		} else if arg == "--wait-timeout" {
+			if i+1 >= len(args) {
+				return fmt.Errorf("--wait-timeout flag requires a duration argument")
+			}
+			i++
+			timeout, err := environment.ParseWaitTimeout(args[i])
+			if err != nil {
+				return err
+			}
+			waitTimeout = timeout
+		} else if arg == "--wait-port" {
+			if i+1 >= len(args) {
+				return fmt.Errorf("--wait-port flag requires a port argument")
+			}
+			i++
+			port, err := strconv.Atoi(args[i])
+			if err != nil || port <= 0 || port > 65535 {
+				return fmt.Errorf("invalid --wait-port: %s", args[i])
+			}
+			waitPort = port
+		} else if arg == "--parallel" {
+			if i+1 >= len(args) {
+				return fmt.Errorf("--parallel flag requires a number argument")
+			}
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid --parallel: %s", args[i])
+			}
+			parallel = n
+			parallelSet = true
+		} else if arg == "--label" {
+			if i+1 >= len(args) {
+				return fmt.Errorf("--label flag requires a key=value argument")
+			}
+			i++
+			key, value, err := environment.ParseLabel(args[i])
+			if err != nil {
+				return err
+			}
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels[key] = value
+		} else if arg == "--from-def" {
+			if i+1 >= len(args) {
+				return fmt.Errorf("--from-def flag requires a definition path")
+			}
+			i++
+			defPath = args[i]
+		} else if arg == "--from-file" {
+			if i+1 >= len(args) {
+				return fmt.Errorf("--from-file flag requires a manifest path")
+			}
+			i++
+			manifestPath = args[i]
+		} else if strings.HasPrefix(arg, "-") {
+			return fmt.Errorf("unknown option: %s", arg)
 		} else {
-			return fmt.Errorf("unexpected argument: %s", arg)
+			branchNames = append(branchNames, arg)
+			i++
+			continue
 		}
+		flagArgs = append(flagArgs, args[start:i+1]...)
 		i++
 	}
 	
-	if branchName == "" {
+	if here && (len(branchNames) > 1 || manifestPath != "" || detach) {
+		return fmt.Errorf("--here creates one environment for the current checkout; it cannot be combined with several branches, --from-file or --detach")
+	}
+	if worktreePath != "" && (len(branchNames) > 1 || manifestPath != "" || detach) {
+		return fmt.Errorf("--worktree-path adopts one worktree; it cannot be combined with several branches, --from-file or --detach")
+	}
+	var def *environment.Definition
+	if defPath != "" {
+		if len(branchNames) > 1 || manifestPath != "" || image != "" {
+			return fmt.Errorf("--from-def reproduces one environment; it cannot be combined with several branches, --from-file or --image")
+		}
+		loaded, err := environment.LoadDefinition(defPath)
+		if err != nil {
+			return err
+		}
+		def = loaded
+		if len(branchNames) == 0 && !here && worktreePath == "" {
+			branchNames = []string{def.Branch}
+		}
+		for _, warning := range c.envManager.DefinitionWarnings(def) {
+			fmt.Printf("Warning: %s\n", warning)
+		}
+	}
+	if len(branchNames) == 0 && manifestPath == "" && !here && worktreePath == "" {
 		return fmt.Errorf("branch name is required")
 	}
+	if len(branchNames) > 0 && manifestPath != "" {
+		return fmt.Errorf("branch names cannot be combined with --from-file; list them in the manifest")
+	}
+	if detach && manifestPath != "" {
+		return fmt.Errorf("--detach cannot be used with --from-file")
+	}
+	if noContainer && wait {
+		return fmt.Errorf("--wait cannot be used with --no-container")
+	}
+	if noStart && wait {
+		return fmt.Errorf("--wait cannot be used with --no-start")
+	}
+	if detach && wait {
+		return fmt.Errorf("--wait cannot be used with --detach; run 'cc-buddy wait <env-name>' instead")
+	}
 	
-	// Parse branch reference (handle origin/branch-name format)
+	if detach {
+		for _, branchName := range branchNames {
+			if err := c.startDetached(branchName, flagArgs); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	
+	// Tag environments made by a GitHub Actions run for "delete --all"
+	if runID := ci.RunID(); runID != "" {
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		if _, ok := labels[ci.RunLabel]; !ok {
+			labels[ci.RunLabel] = runID
+		}
+	}
+
+	base := environment.CreateEnvironmentOptions{
+		StartupCommand:    startupCommand,
+		Mounts:            mounts,
+		ReadOnlyWorkspace: readOnlyWorkspace,
+		Network:           network,
+		ExposeAllPorts:    exposeAll,
+		Ports:             ports,
+		NoStart:           noStart,
+		NoContainer:       noContainer,
+		Here:              here,
+		WorktreePath:      worktreePath,
+		Image:             image,
+		RuntimeArgs:       runtimeArgs,
+		Privileged:        privileged,
+		CapAdd:            capAdd,
+		Devices:           devices,
+		Labels:            labels,
+	}
+	if def != nil {
+		base = def.Options(base)
+	}
 	gitOps := c.envManager.GetGitOperations()
-	remote, branch, isRemote := gitOps.ParseBranchReference(branchName)
+	if manifestPath != "" {
+		manifest, err := loadCreateManifest(manifestPath)
+		if err != nil {
+			return err
+		}
+		optsList := manifest.options(gitOps, base)
+		if !parallelSet && manifest.Parallel > 0 {
+			parallel = manifest.Parallel
+		}
+		return c.createParallel(ctx, optsList, parallel, wait, waitPort, waitTimeout)
+	}
+	if len(branchNames) > 1 {
+		var optsList []environment.CreateEnvironmentOptions
+		for _, branchName := range branchNames {
+			optsList = append(optsList, branchOptions(gitOps, branchName, base))
+		}
+		return c.createParallel(ctx, optsList, parallel, wait, waitPort, waitTimeout)
+	}
+	
+	// Parse branch reference (handle origin/branch-name format)
+	var remote, branch string
+	var isRemote bool
+	if len(branchNames) > 0 {
+		remote, branch, isRemote = gitOps.ParseBranchReference(branchNames[0])
+	}
 	
-	if isRemote {
+	if here {
+		fmt.Printf("Creating environment for the current checkout...\n")
+	} else if worktreePath != "" {
+		fmt.Printf("Creating environment for worktree %s...\n", worktreePath)
+	} else if isRemote {
 		fmt.Printf("Creating environment for remote branch %s/%s...\n", remote, branch)
 	} else {
 		fmt.Printf("Creating environment for branch %s...\n", branch)
@@ -67,73 +329,131 @@ func (c *CreateCommand) Execute(ctx context.Context, args []string) error {
 		fmt.Printf("Custom startup command: %s\n", strings.Join(startupCommand, " "))
 	}
 
-	opts := environment.CreateEnvironmentOptions{
-		BranchName:     branch,
-		IsRemoteBranch: isRemote,
-		RemoteName:     remote,
-		StartupCommand: startupCommand,
-	}
+	opts := base
+	opts.BranchName = branch
+	opts.IsRemoteBranch = isRemote
+	opts.RemoteName = remote
+
+	// CI logs get a collapsible section per step
+	ctx, endSections := ci.WithSections(ctx)
+	defer endSections()
 
 	// Create the environment
 	env, err := c.envManager.CreateEnvironment(ctx, opts)
+	if envName := os.Getenv(environment.DetachedCreateEnv); envName != "" {
+		// Running in the background for create --detach; report to "wait"
+		environment.FinishDetachedCreate(envName, err)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create environment: %w", err)
 	}
 
+	if wait {
+		if waitPort > 0 {
+			fmt.Printf("Waiting up to %s for port %d to accept connections...\n", waitTimeout, waitPort)
+		} else {
+			fmt.Printf("Waiting up to %s for the container to become ready...\n", waitTimeout)
+		}
+		ci.Section(ctx, "waiting for readiness")
+		if err := c.envManager.WaitForReady(ctx, env.Name, waitPort, waitTimeout); err != nil {
+			return fmt.Errorf("environment '%s' was created but is not ready: %w", env.Name, err)
+		}
+	}
+
+	c.publishGitHubOutputs(ctx, []config.Environment{*env})
+
 	fmt.Printf("✅ Environment '%s' created successfully!\n", env.Name)
 	fmt.Printf("   Branch: %s\n", env.Branch)
 	fmt.Printf("   Worktree: %s\n", env.WorktreePath)
+	if env.InPlace {
+		fmt.Printf("   Workspace: current checkout (kept on delete)\n")
+	}
+	if env.NoContainer {
+		fmt.Printf("   Container: none\n")
+		fmt.Printf("\nTo work in the worktree:\n")
+		fmt.Printf("   cd \"$(cc-buddy path %s)\"\n", env.Name)
+		return nil
+	}
 	fmt.Printf("   Container: %s\n", env.ContainerName)
+	if env.SourceImage != "" {
+		fmt.Printf("   Image: %s\n", env.SourceImage)
+	}
 	fmt.Printf("   Status: %s\n", env.Status)
+	if url := c.envManager.ProxyURL(*env); url != "" {
+		fmt.Printf("   URL: %s\n", url)
+	}
+	for _, port := range env.Ports {
+		fmt.Printf("   Port: %s\n", port)
+	}
+	if env.Network == environment.NetworkShared {
+		fmt.Printf("   Network: %s (hostname: %s)\n", environment.SharedNetworkName, env.Name)
+	} else if env.Network == environment.NetworkHost {
+		fmt.Printf("   Network: host (localhost is the host's; ports need no publishing)\n")
+	}
+	if env.ReadOnlyWorkspace {
+		fmt.Printf("   Workspace: read-only\n")
+	}
+	for _, mount := range env.Mounts {
+		fmt.Printf("   Mount: %s\n", mount)
+	}
+	if env.Privileged {
+		fmt.Printf("   Privileged: yes\n")
+	}
+	if len(env.CapAdd) > 0 {
+		fmt.Printf("   Capabilities: %s\n", strings.Join(env.CapAdd, ", "))
+	}
+	for _, device := range env.Devices {
+		fmt.Printf("   Device: %s\n", device)
+	}
+	if len(env.RuntimeArgs) > 0 {
+		fmt.Printf("   Runtime args: %s\n", strings.Join(env.RuntimeArgs, " "))
+	}
+	if noStart {
+		fmt.Printf("\nTo start the environment:\n")
+		fmt.Printf("   cc-buddy start %s\n", env.Name)
+		return nil
+	}
 	fmt.Printf("\nTo access the environment:\n")
 	fmt.Printf("   cc-buddy terminal %s\n", env.Name)
 
 	return nil
 }
 
-// parseCommand parses a command string into arguments
-// Simple implementation that splits on spaces, respecting quoted strings
-func parseCommand(commandStr string) []string {
-	if commandStr == "" {
-		return nil
+// branchOptions returns base set up to create branchName, which may be a
+// remote reference such as origin/main
+func branchOptions(gitOps *environment.GitOperations, branchName string, base environment.CreateEnvironmentOptions) environment.CreateEnvironmentOptions {
+	remote, branch, isRemote := gitOps.ParseBranchReference(branchName)
+	opts := base
+	opts.BranchName = branch
+	opts.IsRemoteBranch = isRemote
+	opts.RemoteName = remote
+	return opts
+}
+
+// startDetached starts the create of one branch in a background process
+// and returns; flagArgs are the create options to pass on
+func (c *CreateCommand) startDetached(branchName string, flagArgs []string) error {
+	_, branch, _ := c.envManager.GetGitOperations().ParseBranchReference(branchName)
+	envName, err := c.envManager.GetGitOperations().GenerateEnvironmentName(branch)
+	if err != nil {
+		return fmt.Errorf("failed to generate environment name: %w", err)
 	}
-	
-	var args []string
-	var current strings.Builder
-	inQuotes := false
-	quoteChar := byte(0)
-	
-	for i := 0; i < len(commandStr); i++ {
-		char := commandStr[i]
-		
-		switch char {
-		case '"', '\'':
-			if !inQuotes {
-				inQuotes = true
-				quoteChar = char
-			} else if char == quoteChar {
-				inQuotes = false
-				quoteChar = 0
-			} else {
-				current.WriteByte(char)
-			}
-		case ' ', '\t':
-			if inQuotes {
-				current.WriteByte(char)
-			} else {
-				if current.Len() > 0 {
-					args = append(args, current.String())
-					current.Reset()
-				}
-			}
-		default:
-			current.WriteByte(char)
+
+	var childArgs []string
+	for _, arg := range flagArgs {
+		if arg != "--detach" {
+			childArgs = append(childArgs, arg)
 		}
 	}
-	
-	if current.Len() > 0 {
-		args = append(args, current.String())
+	childArgs = append(childArgs, branchName)
+	pending, err := c.envManager.StartDetachedCreate(envName, branch, childArgs)
+	if err != nil {
+		return err
 	}
-	
-	return args
-}
\ No newline at end of file
+
+	fmt.Printf("🚀 Creating environment '%s' in the background (pid %d)\n", envName, pending.PID)
+	fmt.Printf("   Log: %s\n", pending.LogFile)
+	fmt.Printf("\nTo wait until it is ready:\n")
+	fmt.Printf("   cc-buddy wait %s\n", envName)
+	return nil
+}
@@ -0,0 +1,144 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+	"github.com/jhjaggars/cc-buddy/internal/utils"
+)
+
+// DefaultCreateParallelism bounds how many environments create builds at
+// once when given several branches; image builds are CPU and disk heavy
+const DefaultCreateParallelism = 3
+
+// progressInterval is how often concurrent creates' steps are reported
+const progressInterval = 500 * time.Millisecond
+
+// createParallel creates an environment for each of optsList, running at
+// most parallel creates at a time, and prints each one's steps as they
+// change. Ctrl+C cancels every create, rolling each one back.
+func (c *CreateCommand) createParallel(ctx context.Context, optsList []environment.CreateEnvironmentOptions, parallel int, wait bool, waitPort int, waitTimeout time.Duration) error {
+	operations := utils.NewOperationManager()
+
+	// Resolve names up front so duplicates fail before anything is created
+	envNames := make([]string, len(optsList))
+	branchNames := make([]string, len(optsList))
+	seen := map[string]bool{}
+	for i, opts := range optsList {
+		branchNames[i] = opts.BranchName
+		if opts.IsRemoteBranch {
+			branchNames[i] = opts.RemoteName + "/" + opts.BranchName
+		}
+		envName, err := c.envManager.GetGitOperations().GenerateEnvironmentName(opts.BranchName)
+		if err != nil {
+			return fmt.Errorf("failed to generate environment name: %w", err)
+		}
+		if seen[envName] {
+			return fmt.Errorf("branch %s is given more than once", branchNames[i])
+		}
+		seen[envName] = true
+		envNames[i] = envName
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		operations.CancelAll(context.Background())
+	}()
+
+	fmt.Printf("Creating %d environments, %d at a time...\n", len(optsList), parallel)
+	reportDone := make(chan struct{})
+	go reportOperations(operations, reportDone)
+
+	errs := make([]error, len(optsList))
+	slots := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i := range optsList {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			if ctx.Err() != nil {
+				errs[i] = ctx.Err()
+				return
+			}
+
+			started := time.Now()
+			errs[i] = operations.Run(utils.EnvironmentCreate, envNames[i], func(opCtx context.Context) error {
+				env, err := c.envManager.CreateEnvironment(opCtx, optsList[i])
+				if err != nil {
+					return err
+				}
+				if wait && !optsList[i].NoStart {
+					utils.ReportProgress(opCtx, 0.95, "waiting until ready")
+					if err := c.envManager.WaitForReady(opCtx, env.Name, waitPort, waitTimeout); err != nil {
+						return fmt.Errorf("created but not ready: %w", err)
+					}
+				}
+				return nil
+			})
+			if errs[i] != nil {
+				fmt.Printf("[%s] ❌ %v\n", envNames[i], errs[i])
+			} else {
+				fmt.Printf("[%s] ✅ created in %s\n", envNames[i], time.Since(started).Round(time.Second))
+			}
+		}()
+	}
+	wg.Wait()
+	close(reportDone)
+
+	failed := 0
+	var created []config.Environment
+	fmt.Println()
+	for i, envName := range envNames {
+		if errs[i] != nil {
+			failed++
+			fmt.Printf("❌ %s (branch %s)\n", envName, branchNames[i])
+		} else {
+			fmt.Printf("✅ %s (branch %s)\n", envName, branchNames[i])
+			if env, err := c.envManager.GetConfig().GetEnvironment(envName); err == nil {
+				created = append(created, env)
+			}
+		}
+	}
+	c.publishGitHubOutputs(ctx, created)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d environments failed to create", failed, len(envNames))
+	}
+	fmt.Printf("\nOpen one with: cc-buddy terminal <env-name>\n")
+	return nil
+}
+
+// reportOperations prints each running operation's status when it changes,
+// until done is closed
+func reportOperations(operations *utils.OperationManager, done <-chan struct{}) {
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+
+	last := map[string]string{}
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+		active := operations.GetActiveOperations()
+		for i := range active {
+			op := &active[i]
+			if last[op.ID] == op.Status {
+				continue
+			}
+			last[op.ID] = op.Status
+			fmt.Printf("[%s] %s...\n", op.Environment, op.Status)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/daemon"
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// DaemonCommand runs cc-buddy as a long-lived server for the repository
+type DaemonCommand struct {
+	envManager *environment.Manager
+}
+
+// NewDaemonCommand creates a new daemon command
+func NewDaemonCommand(envManager *environment.Manager) *DaemonCommand {
+	return &DaemonCommand{envManager: envManager}
+}
+
+// Execute runs the daemon until interrupted
+func (c *DaemonCommand) Execute(ctx context.Context, args []string) error {
+	listen := c.envManager.GetConfig().GetConfig().DaemonListen
+
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--listen":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--listen requires an address")
+			}
+			listen = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "--listen="):
+			listen = strings.TrimPrefix(args[i], "--listen=")
+		default:
+			return fmt.Errorf("usage: cc-buddy daemon [--listen <host:port>|unix:///path]")
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := daemon.NewServer(c.envManager)
+	return server.ListenAndServe(ctx, listen, func(addr string) {
+		fmt.Printf("cc-buddy daemon listening on %s\n", addr)
+		base := addr // a unix:// socket
+		if !strings.HasPrefix(addr, "unix://") {
+			base = "http://" + addr
+		}
+		fmt.Printf("  metrics: %s/metrics\n", base)
+		if c.envManager.GetConfig().GetConfig().APIToken != "" {
+			fmt.Printf("  api: %s/api/v1 (token from api_token)\n", base)
+		} else {
+			fmt.Printf("  api: %s/api/v1 (token in %s)\n", base, filepath.Join(config.StateDir, daemon.APITokenFile))
+		}
+		if c.envManager.GetConfig().GetConfig().Webhook.Enabled {
+			fmt.Printf("  webhook: %s/webhook\n", base)
+		}
+		if after, err := c.envManager.StopAfterIdle(); err == nil && after > 0 {
+			fmt.Printf("  stopping environments idle for %s\n", after)
+		}
+		for _, job := range c.envManager.GetConfig().GetConfig().Maintenance {
+			fmt.Printf("  maintenance: %s (%s)\n", job.Task, job.Schedule)
+		}
+		fmt.Println("Press Ctrl+C to stop.")
+	})
+}
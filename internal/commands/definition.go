@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jhjaggars/cc-buddy/internal/ci"
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// ExportDefCommand writes an environment's lockfile for create --from-def
+type ExportDefCommand struct {
+	envManager *environment.Manager
+}
+
+// NewExportDefCommand creates a new export-def command
+func NewExportDefCommand(envManager *environment.Manager) *ExportDefCommand {
+	return &ExportDefCommand{envManager: envManager}
+}
+
+// Execute runs the export-def command, printing the definition or writing
+// it to the -o file
+func (c *ExportDefCommand) Execute(ctx context.Context, args []string) error {
+	usage := fmt.Errorf("usage: cc-buddy export-def <environment-name> [-o file]")
+	var envName, output string
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "-o" || arg == "--output":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s flag requires a file path", arg)
+			}
+			i++
+			output = args[i]
+		case envName == "" && len(arg) > 0 && arg[0] != '-':
+			envName = arg
+		default:
+			return usage
+		}
+	}
+	if envName == "" {
+		return usage
+	}
+
+	def, err := c.envManager.ExportDefinition(ctx, envName)
+	if err != nil {
+		return err
+	}
+	data, err := environment.MarshalDefinition(def)
+	if err != nil {
+		return fmt.Errorf("failed to encode definition: %w", err)
+	}
+	if output == "" {
+		_, err := ci.Stdout().Write(data)
+		return err
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write definition: %w", err)
+	}
+	fmt.Printf("✅ Wrote %s\n", output)
+	fmt.Printf("   Recreate it: cc-buddy create --from-def %s\n", output)
+	for _, base := range def.BaseImages {
+		if base.Digest == "" {
+			fmt.Printf("   Warning: %s has no registry digest and cannot be pinned\n", base.Image)
+		}
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/jhjaggars/cc-buddy/internal/ci"
 	"github.com/jhjaggars/cc-buddy/internal/environment"
 )
 
@@ -22,17 +23,61 @@ func NewDeleteCommand(envManager *environment.Manager) *DeleteCommand {
 
 // Execute runs the delete command
 func (c *DeleteCommand) Execute(ctx context.Context, args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("usage: cc-buddy delete <environment-name>")
+	var envName string
+	var selectors []environment.LabelSelector
+	force := false
+	yes := false
+	all := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--force" {
+			force = true
+		} else if arg == "-y" || arg == "--yes" {
+			yes = true
+		} else if arg == "--all" {
+			all = true
+		} else if arg == "--label" {
+			if i+1 >= len(args) {
+				return fmt.Errorf("--label flag requires a key=value argument")
+			}
+			i++
+			selector, err := environment.ParseLabelSelector(args[i])
+			if err != nil {
+				return err
+			}
+			selectors = append(selectors, selector)
+		} else if envName == "" && !strings.HasPrefix(arg, "-") {
+			envName = arg
+		} else {
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	if envName != "" && (len(selectors) > 0 || all) {
+		return fmt.Errorf("give either an environment name or --label/--all, not both")
+	}
+	if all {
+		// In GitHub Actions, only what this workflow run created
+		if runID := ci.RunID(); runID != "" {
+			fmt.Printf("Deleting the environments of workflow run %s (label %s=%s)\n", runID, ci.RunLabel, runID)
+			selectors = append(selectors, environment.LabelSelector{Key: ci.RunLabel, Value: runID, HasValue: true})
+		}
+		return c.deleteByLabel(ctx, selectors, force, yes)
+	}
+	if len(selectors) > 0 {
+		return c.deleteByLabel(ctx, selectors, force, yes)
+	}
+	if envName == "" {
+		return fmt.Errorf("usage: cc-buddy delete <environment-name> [--yes] [--force]\n       cc-buddy delete --label key[=value]... [--yes] [--force]\n       cc-buddy delete --all [--label key[=value]...] [--yes] [--force]")
 	}
-
-	envName := args[0]
 
 	// Check if environment exists
 	env, err := c.envManager.GetConfig().GetEnvironment(envName)
 	if err != nil {
 		return fmt.Errorf("environment '%s' not found", envName)
 	}
+	if env.Protected && !force {
+		return fmt.Errorf("environment '%s' is protected; run 'cc-buddy unprotect %s' first or delete with --force", envName, envName)
+	}
 
 	// Show what will be deleted
 	fmt.Printf("Environment Details:\n")
@@ -42,20 +87,21 @@ func (c *DeleteCommand) Execute(ctx context.Context, args []string) error {
 	fmt.Printf("  Container: %s\n", env.ContainerName)
 	fmt.Printf("  Volume: %s\n", env.VolumeName)
 	fmt.Printf("  Status: %s\n", env.Status)
+	if env.Note != "" {
+		fmt.Printf("  Note: %s\n", env.Note)
+	}
+	if env.Protected {
+		fmt.Printf("  Protected: yes (deleting because of --force)\n")
+	}
 	fmt.Println()
 
 	// Confirmation prompt
 	fmt.Printf("⚠️  This will permanently delete the environment and all associated resources.\n")
-	fmt.Printf("Are you sure you want to delete '%s'? [y/N]: ", envName)
-
-	reader := bufio.NewReader(os.Stdin)
-	response, err := reader.ReadString('\n')
+	confirmed, err := confirmUnless(yes || force, fmt.Sprintf("Are you sure you want to delete '%s'?", envName), "--yes")
 	if err != nil {
-		return fmt.Errorf("failed to read confirmation: %w", err)
+		return err
 	}
-
-	response = strings.TrimSpace(strings.ToLower(response))
-	if response != "y" && response != "yes" {
+	if !confirmed {
 		fmt.Println("Deletion cancelled.")
 		return nil
 	}
@@ -63,10 +109,93 @@ func (c *DeleteCommand) Execute(ctx context.Context, args []string) error {
 	// Perform deletion
 	fmt.Printf("Deleting environment '%s'...\n", envName)
 	
-	if err := c.envManager.DeleteEnvironment(ctx, envName); err != nil {
+	if err := c.envManager.DeleteEnvironment(ctx, envName, force); err != nil {
 		return fmt.Errorf("failed to delete environment: %w", err)
 	}
 
 	fmt.Printf("✅ Environment '%s' deleted successfully!\n", envName)
 	return nil
-}
\ No newline at end of file
+}
+
+// deleteByLabel deletes every environment matching selectors after a single
+// confirmation, which yes or force skips. Protected environments are skipped
+// unless force is set.
+func (c *DeleteCommand) deleteByLabel(ctx context.Context, selectors []environment.LabelSelector, force, yes bool) error {
+	environments, err := c.envManager.ListEnvironments(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list environments: %w", err)
+	}
+
+	var names []string
+	for _, env := range environments {
+		if !environment.MatchLabels(env, selectors) {
+			continue
+		}
+		if env.Protected && !force {
+			fmt.Printf("Skipping protected environment '%s' (use --force to include it)\n", env.Name)
+			continue
+		}
+		names = append(names, env.Name)
+	}
+	if len(names) == 0 {
+		fmt.Println("No environments to delete.")
+		return nil
+	}
+
+	fmt.Println("Environments to delete:")
+	for _, name := range names {
+		fmt.Printf("  %s\n", name)
+	}
+	fmt.Println()
+	fmt.Printf("⚠️  This will permanently delete these environments and all associated resources.\n")
+	confirmed, err := confirmUnless(yes || force, fmt.Sprintf("Delete %d environment(s)?", len(names)), "--yes")
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Println("Deletion cancelled.")
+		return nil
+	}
+
+	failed := 0
+	for _, name := range names {
+		fmt.Printf("Deleting environment '%s'...\n", name)
+		if err := c.envManager.DeleteEnvironment(ctx, name, force); err != nil {
+			fmt.Printf("❌ %s: %v\n", name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("✅ Environment '%s' deleted successfully!\n", name)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d environments failed to delete", failed, len(names))
+	}
+	return nil
+}
+
+// confirmUnless answers yes without asking when skip is set (--yes), and
+// otherwise asks question like confirm
+func confirmUnless(skip bool, question, flag string) (bool, error) {
+	if skip {
+		return true, nil
+	}
+	return confirm(question, flag)
+}
+
+// confirm asks a yes/no question on stdin, defaulting to no. In CI mode
+// nobody can answer, so it fails with a usage error asking for flag.
+func confirm(question, flag string) (bool, error) {
+	if ci.Enabled() {
+		return false, fmt.Errorf("usage: cannot ask %q in CI mode; pass %s to go ahead", question, flag)
+	}
+	fmt.Printf("%s [y/N]: ", question)
+
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes", nil
+}
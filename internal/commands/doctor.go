@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jhjaggars/cc-buddy/internal/container"
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+	"github.com/jhjaggars/cc-buddy/internal/system"
+)
+
+// DoctorCommand checks the host setup for common problems
+type DoctorCommand struct {
+	envManager *environment.Manager
+}
+
+// NewDoctorCommand creates a new doctor command
+func NewDoctorCommand(envManager *environment.Manager) *DoctorCommand {
+	return &DoctorCommand{envManager: envManager}
+}
+
+// Execute runs the doctor command
+func (c *DoctorCommand) Execute(ctx context.Context, args []string) error {
+	problems := 0
+	report := func(ok bool, format string, a ...interface{}) {
+		if ok {
+			fmt.Printf("✅ "+format+"\n", a...)
+		} else {
+			fmt.Printf("❌ "+format+"\n", a...)
+			problems++
+		}
+	}
+
+	runtime := c.envManager.GetContainerManager().GetRuntime()
+	version, err := runtime.Detect(ctx)
+	report(err == nil, "Container runtime: %s", versionOrError(version, err))
+	if c.envManager.WorktreeOnly() {
+		fmt.Println(container.InstallHint())
+	}
+
+	cfg := c.envManager.GetConfig().GetConfig()
+	_, err = os.Stat(cfg.Containerfile)
+	report(err == nil, "Containerfile: %s", existsOrMissing(cfg.Containerfile, err))
+
+	if vm := c.envManager.GetContainerManager().DetectVM(ctx); vm != nil {
+		fmt.Printf("ℹ️  Runtime runs in the %s VM (socket %s)\n", vm, vm.Socket)
+
+		worktreeDir, err := filepath.Abs(cfg.WorktreeDir)
+		if err != nil {
+			return fmt.Errorf("failed to resolve worktree directory: %w", err)
+		}
+		// The directory may not exist until the first environment is
+		// created; the one it will be created in tells as much
+		checkDir := worktreeDir
+		if _, err := os.Stat(worktreeDir); os.IsNotExist(err) {
+			checkDir = environment.NearestExistingDir(worktreeDir)
+			fmt.Printf("⚠️  Worktree dir %s does not exist yet; checking %s instead\n", worktreeDir, checkDir)
+		}
+
+		vmPath := container.TranslatePath(checkDir, cfg.VMPathMap)
+		shared, err := vm.VMHasPath(ctx, vmPath)
+		switch {
+		case err != nil:
+			report(false, "Worktree dir shared into VM: %v", err)
+		case !shared:
+			report(false, "Worktree dir %s is not shared into the %s VM; add it to the VM mounts or set vm_path_map", checkDir, vm.Kind)
+		default:
+			report(true, "Worktree dir %s is shared into the VM", checkDir)
+		}
+	}
+
+	if system.IsWSL() {
+		worktreeDir, err := filepath.Abs(cfg.WorktreeDir)
+		if err != nil {
+			return fmt.Errorf("failed to resolve worktree directory: %w", err)
+		}
+		if container.WindowsDrivePath(worktreeDir) {
+			fmt.Printf("⚠️  Worktree dir %s is on a Windows drive; bind mounts across the WSL boundary are very slow. Clone the repository into the Linux filesystem instead\n", worktreeDir)
+		} else {
+			report(true, "Worktree dir %s is in the WSL Linux filesystem", worktreeDir)
+		}
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("doctor found %d problem(s)", problems)
+	}
+	fmt.Println("\nNo problems found.")
+	return nil
+}
+
+// versionOrError formats a runtime version check result
+func versionOrError(version string, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return version
+}
+
+// existsOrMissing formats a file existence check result
+func existsOrMissing(path string, err error) string {
+	if err != nil {
+		return path + " not found (run 'cc-buddy init')"
+	}
+	return path
+}
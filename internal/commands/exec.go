@@ -2,8 +2,16 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
 
+	"github.com/jhjaggars/cc-buddy/internal/ci"
+	"github.com/jhjaggars/cc-buddy/internal/container"
 	"github.com/jhjaggars/cc-buddy/internal/environment"
 )
 
@@ -17,10 +25,43 @@ func NewExecCommand(envManager *environment.Manager) *ExecCommand {
 	return &ExecCommand{envManager: envManager}
 }
 
+// timeoutExitCode is the exit code when --timeout is exceeded, as with
+// coreutils timeout
+const timeoutExitCode = 124
+
+// timeoutGrace is how long a timed out command has to exit after SIGTERM
+// before it is killed
+const timeoutGrace = 5 * time.Second
+
+// ExitError reports that a command run in an environment exited non-zero,
+// or ran out of time; cc-buddy exits with the same code
+type ExitError struct {
+	Code    int
+	Timeout time.Duration // set when the command exceeded --timeout
+}
+
+func (e *ExitError) Error() string {
+	if e.Timeout > 0 {
+		return fmt.Sprintf("command timed out after %s", e.Timeout)
+	}
+	return fmt.Sprintf("command exited with status %d", e.Code)
+}
+
+// execOutput is what exec --output json prints
+type execOutput struct {
+	Environment string   `json:"environment"`
+	Command     []string `json:"command"`
+	ExitCode    int      `json:"exit_code"`
+	DurationMS  int64    `json:"duration_ms"`
+	Stdout      string   `json:"stdout"`
+	Stderr      string   `json:"stderr"`
+	TimedOut    bool     `json:"timed_out"`
+}
+
 // Execute runs the exec command
 func (c *ExecCommand) Execute(ctx context.Context, args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("usage: cc-buddy exec <environment-name> -- <command> [args...]")
+		return fmt.Errorf("usage: cc-buddy exec <environment-name> [--output json] [--timeout 300s] -- <command> [args...]")
 	}
 
 	// Find the separator "--"
@@ -44,23 +85,198 @@ func (c *ExecCommand) Execute(ctx context.Context, args []string) error {
 		return fmt.Errorf("command is required after '--'")
 	}
 
-	// Parse environment name and command
-	envName := args[0]
-	if separatorIndex > 1 {
-		return fmt.Errorf("only one environment name is allowed before '--'")
+	// Parse environment name and options
+	var envName, output, timeoutValue string
+	for i := 0; i < separatorIndex; i++ {
+		arg := args[i]
+		if arg == "--timeout" {
+			if i+1 >= separatorIndex {
+				return fmt.Errorf("--timeout flag requires a duration")
+			}
+			i++
+			timeoutValue = args[i]
+		} else if strings.HasPrefix(arg, "--timeout=") {
+			timeoutValue = strings.TrimPrefix(arg, "--timeout=")
+		} else if arg == "--output" {
+			if i+1 >= separatorIndex {
+				return fmt.Errorf("--output flag requires a format")
+			}
+			i++
+			output = args[i]
+		} else if strings.HasPrefix(arg, "--output=") {
+			output = strings.TrimPrefix(arg, "--output=")
+		} else if strings.HasPrefix(arg, "-") {
+			return fmt.Errorf("unknown exec option: %s", arg)
+		} else if envName == "" {
+			envName = arg
+		} else {
+			return fmt.Errorf("only one environment name is allowed before '--'")
+		}
+	}
+	if envName == "" {
+		return fmt.Errorf("environment name is required before '--'")
+	}
+	if output != "" && output != "json" {
+		return fmt.Errorf("invalid --output %q: only json is supported", output)
+	}
+
+	var timeout time.Duration
+	if timeoutValue != "" {
+		var err error
+		timeout, err = time.ParseDuration(timeoutValue)
+		if err != nil || timeout < time.Second {
+			return fmt.Errorf("invalid --timeout %q (expected a duration of at least 1s, like 300s or 10m)", timeoutValue)
+		}
 	}
 
 	command := args[separatorIndex+1:]
+	if output == "json" {
+		return c.executeJSON(ctx, envName, command, timeout)
+	}
 
-	// Execute the command
-	if err := c.envManager.ExecuteCommand(ctx, envName, command, true); err != nil {
+	// Execute the command; CI mode has no terminal to attach
+	signalled, err := c.runWithTimeout(ctx, envName, command, timeout, func(ctx context.Context, command []string) error {
+		if ci.Enabled() {
+			return c.envManager.AttachCommand(ctx, envName, command)
+		}
+		return c.envManager.ExecuteCommand(ctx, envName, command, true)
+	})
+	return execError(signalled, timeout, err)
+}
+
+// execError is what Execute returns for a command that ended with err: an
+// ExitError with the exit code cc-buddy exits with, and the timeout once
+// the command was signalled at the deadline, however it then exited
+func execError(signalled bool, timeout time.Duration, err error) error {
+	if signalled {
+		return &ExitError{Code: timeoutExitCode, Timeout: timeout}
+	}
+	if err != nil {
+		if exitErr := commandExitError(err); exitErr != nil {
+			return exitErr
+		}
 		return fmt.Errorf("failed to execute command: %w", err)
 	}
-
 	return nil
 }
 
+// reportedExitCode returns the exit code cc-buddy reports for a command
+// that exited with code, and whether it timed out: a command signalled at
+// the --timeout deadline timed out whatever it then exited with, 0 after
+// trapping SIGTERM included
+func reportedExitCode(signalled bool, code int) (int, bool) {
+	if signalled {
+		return timeoutExitCode, true
+	}
+	return code, false
+}
+
+// commandExitError converts the error of a command that ran in a container
+// and exited non-zero into an ExitError; other errors give nil. The
+// command's own output already explains the failure.
+func commandExitError(err error) *ExitError {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return nil
+	}
+	code := exitErr.ExitCode()
+	if code < 0 {
+		code = 1 // killed by a signal
+	}
+	return &ExitError{Code: code}
+}
+
+// useTTY reports whether commands run in an environment get a terminal:
+// only when cc-buddy is attached to one, and never in CI mode
+func useTTY() bool {
+	return !ci.Enabled() && isTerminal(os.Stdin) && isTerminal(os.Stdout)
+}
+
 // ExecuteNonInteractive executes a command without TTY/interactive mode
 func (c *ExecCommand) ExecuteNonInteractive(ctx context.Context, envName string, command []string) error {
 	return c.envManager.ExecuteCommand(ctx, envName, command, false)
-}
\ No newline at end of file
+}
+
+// pidWrapper runs a command through sh after recording its PID in the file
+// given as $0, so it can be signalled from another exec
+const pidWrapper = `echo $$ > "$0"; exec "$@"`
+
+// runWithTimeout executes command in the environment through run. With a
+// timeout, the command's PID is recorded and once the deadline passes it
+// gets SIGTERM, then SIGKILL after timeoutGrace; signalled reports whether
+// that happened before the command exited.
+func (c *ExecCommand) runWithTimeout(ctx context.Context, envName string, command []string, timeout time.Duration, run func(context.Context, []string) error) (signalled bool, err error) {
+	if timeout == 0 {
+		return false, run(ctx, command)
+	}
+
+	probe, err := c.envManager.CaptureCommand(ctx, envName, []string{"sh", "-c", "true"})
+	if err != nil {
+		return false, err
+	}
+	if probe.ExitCode != 0 {
+		return false, fmt.Errorf("--timeout needs sh in the container (sh -c true exited with status %d)", probe.ExitCode)
+	}
+
+	pidFile := fmt.Sprintf("/tmp/cc-buddy-exec-%d-%d.pid", os.Getpid(), time.Now().UnixNano())
+	defer c.envManager.CaptureCommand(context.Background(), envName, []string{"rm", "-f", pidFile})
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- run(runCtx, append([]string{"sh", "-c", pidWrapper, pidFile}, command...))
+	}()
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	select {
+	case err := <-done:
+		return false, err
+	case <-deadline.C:
+	}
+
+	for _, signal := range []string{"TERM", "KILL"} {
+		c.envManager.CaptureCommand(ctx, envName, []string{"sh", "-c", `kill -s "$1" "$(cat "$0")" 2>/dev/null`, pidFile, signal})
+		select {
+		case err := <-done:
+			return true, err
+		case <-time.After(timeoutGrace):
+		}
+	}
+
+	// The command is gone or unkillable; stop waiting on the runtime
+	cancel()
+	return true, <-done
+}
+
+// executeJSON runs a command non-interactively and prints its exit code,
+// duration and output as JSON; the command failing is not an error here
+func (c *ExecCommand) executeJSON(ctx context.Context, envName string, command []string, timeout time.Duration) error {
+	began := time.Now()
+	var result container.ExecResult
+	signalled, err := c.runWithTimeout(ctx, envName, command, timeout, func(ctx context.Context, command []string) error {
+		var err error
+		result, err = c.envManager.CaptureCommand(ctx, envName, command)
+		return err
+	})
+	// Once signalled, a runtime call cut short still reports the timeout
+	if err != nil && !signalled {
+		return fmt.Errorf("failed to execute command: %w", err)
+	}
+	elapsed := time.Since(began)
+	var expired bool
+	result.ExitCode, expired = reportedExitCode(signalled, result.ExitCode)
+
+	encoder := json.NewEncoder(ci.Stdout())
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(execOutput{
+		Environment: envName,
+		Command:     command,
+		ExitCode:    result.ExitCode,
+		DurationMS:  elapsed.Milliseconds(),
+		Stdout:      string(result.Stdout),
+		Stderr:      string(result.Stderr),
+		TimedOut:    expired,
+	})
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// exitWith returns the error of a process that exited with code
+func exitWith(t *testing.T, code string) error {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	err := exec.Command("sh", "-c", "exit "+code).Run()
+	if err == nil && code != "0" {
+		t.Fatalf("sh exited 0, want %s", code)
+	}
+	return err
+}
+
+func TestExecError(t *testing.T) {
+	const timeout = 30 * time.Second
+	tests := []struct {
+		name        string
+		signalled   bool
+		err         error
+		wantCode    int // -1 for no ExitError
+		wantTimeout time.Duration
+		wantErr     bool
+	}{
+		{name: "success", wantCode: -1},
+		{name: "command failed", err: exitWith(t, "3"), wantCode: 3, wantErr: true},
+		{name: "killed before the deadline", err: exitWith(t, "137"), wantCode: 137, wantErr: true},
+		{name: "runtime failed", err: errors.New("no such container"), wantCode: -1, wantErr: true},
+		{name: "signalled and killed", signalled: true, err: exitWith(t, "143"), wantCode: timeoutExitCode, wantTimeout: timeout, wantErr: true},
+		{name: "signalled but exited 0", signalled: true, wantCode: timeoutExitCode, wantTimeout: timeout, wantErr: true},
+		{name: "signalled and runtime cut short", signalled: true, err: errors.New("context canceled"), wantCode: timeoutExitCode, wantTimeout: timeout, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := execError(tt.signalled, timeout, tt.err)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error: %v", err, tt.wantErr)
+			}
+			var exitErr *ExitError
+			if !errors.As(err, &exitErr) {
+				if tt.wantCode != -1 {
+					t.Fatalf("error = %v, want exit code %d", err, tt.wantCode)
+				}
+				return
+			}
+			if exitErr.Code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", exitErr.Code, tt.wantCode)
+			}
+			if exitErr.Timeout != tt.wantTimeout {
+				t.Errorf("timeout = %s, want %s", exitErr.Timeout, tt.wantTimeout)
+			}
+		})
+	}
+}
+
+func TestReportedExitCode(t *testing.T) {
+	tests := []struct {
+		name      string
+		signalled bool
+		code      int
+		wantCode  int
+		timedOut  bool
+	}{
+		{"success", false, 0, 0, false},
+		{"failure", false, 2, 2, false},
+		{"own 124 is not a timeout", false, timeoutExitCode, timeoutExitCode, false},
+		{"signalled and terminated", true, 143, timeoutExitCode, true},
+		{"signalled but exited 0", true, 0, timeoutExitCode, true},
+		{"signalled and cut short", true, -1, timeoutExitCode, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, timedOut := reportedExitCode(tt.signalled, tt.code)
+			if code != tt.wantCode || timedOut != tt.timedOut {
+				t.Errorf("reportedExitCode(%v, %d) = %d, %v; want %d, %v", tt.signalled, tt.code, code, timedOut, tt.wantCode, tt.timedOut)
+			}
+		})
+	}
+}
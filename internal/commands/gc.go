@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// GCCommand removes images and volumes no environment needs any more
+type GCCommand struct {
+	envManager *environment.Manager
+}
+
+// NewGCCommand creates a new gc command
+func NewGCCommand(envManager *environment.Manager) *GCCommand {
+	return &GCCommand{envManager: envManager}
+}
+
+// Execute runs the gc command: it lists what would be removed and asks
+// before removing anything
+func (c *GCCommand) Execute(ctx context.Context, args []string) error {
+	var opts environment.GCOptions
+	dryRun := false
+	yes := false
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--dry-run":
+			dryRun = true
+		case "-y", "--yes":
+			yes = true
+		case "--keep-last", "--older-than":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s flag requires a value", arg)
+			}
+			i++
+			if arg == "--keep-last" {
+				n, err := strconv.Atoi(args[i])
+				if err != nil || n < 0 {
+					return fmt.Errorf("invalid --keep-last: %s", args[i])
+				}
+				opts.KeepLast = n
+			} else {
+				age, err := parseAge(args[i])
+				if err != nil {
+					return err
+				}
+				opts.OlderThan = age
+			}
+		default:
+			return fmt.Errorf("usage: cc-buddy gc [--keep-last N] [--older-than 30d] [--dry-run] [--yes]")
+		}
+	}
+
+	items, err := c.envManager.GCCandidates(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		fmt.Println("Nothing to clean up.")
+		return nil
+	}
+
+	images, volumes := 0, 0
+	fmt.Printf("%-7s %-40s %-25s %-10s %s\n", "KIND", "NAME", "ENVIRONMENT", "AGE", "REASON")
+	fmt.Printf("%s\n", strings.Repeat("-", 100))
+	for _, item := range items {
+		if item.Kind == "volume" {
+			volumes++
+		} else {
+			images++
+		}
+		fmt.Printf("%-7s %-40s %-25s %-10s %s\n", item.Kind, item.Name, item.Environment, formatUptime(time.Since(item.Created)), item.Reason)
+	}
+	fmt.Printf("\n%d image(s) and %d volume(s) can be removed.\n", images, volumes)
+
+	if dryRun {
+		return nil
+	}
+	if !yes {
+		confirmed, err := confirm("Remove them?", "--yes")
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Nothing removed.")
+			return nil
+		}
+	}
+
+	removed, err := c.envManager.RemoveGCItems(ctx, items)
+	fmt.Printf("✅ Removed %d of %d\n", removed, len(items))
+	return err
+}
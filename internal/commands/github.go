@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/ci"
+	"github.com/jhjaggars/cc-buddy/internal/config"
+)
+
+// publishGitHubOutputs writes created environments to the step outputs and
+// job summary when running in GitHub Actions. Failing to write them only
+// warns: the environments exist either way.
+func (c *CreateCommand) publishGitHubOutputs(ctx context.Context, envs []config.Environment) {
+	if !ci.GitHubActions() || len(envs) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(envs))
+	var summary strings.Builder
+	summary.WriteString("### cc-buddy environments\n\n")
+	summary.WriteString("| Environment | Branch | Container | Ports |\n")
+	summary.WriteString("|---|---|---|---|\n")
+	outputs := map[string]string{}
+	for _, env := range envs {
+		names = append(names, env.Name)
+		ports, err := c.envManager.PublishedPorts(ctx, env)
+		if err != nil {
+			fmt.Printf("Warning: failed to read the ports of '%s': %v\n", env.Name, err)
+		}
+		fmt.Fprintf(&summary, "| `%s` | `%s` | `%s` | %s |\n", env.Name, env.Branch, env.ContainerName, strings.Join(ports, ", "))
+
+		if len(envs) == 1 {
+			outputs["name"] = env.Name
+			outputs["branch"] = env.Branch
+			outputs["container"] = env.ContainerName
+			outputs["worktree"] = env.WorktreePath
+			outputs["ports"] = strings.Join(ports, ",")
+			if url := c.envManager.ProxyURL(env); url != "" {
+				outputs["url"] = url
+			}
+		}
+	}
+	outputs["environments"] = strings.Join(names, ",")
+	summary.WriteString("\n")
+
+	if err := ci.SetOutputs(outputs); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	if err := ci.AppendSummary(summary.String()); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+}
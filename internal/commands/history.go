@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/audit"
+	"github.com/jhjaggars/cc-buddy/internal/config"
+)
+
+// HistoryCommand shows the environment lifecycle events log. It reads the
+// log directly so it works without a container runtime.
+type HistoryCommand struct {
+	log *audit.Log
+}
+
+// NewHistoryCommand creates a new history command
+func NewHistoryCommand() *HistoryCommand {
+	return &HistoryCommand{log: audit.NewLog(config.StateDir)}
+}
+
+// Execute runs the history command
+func (c *HistoryCommand) Execute(ctx context.Context, args []string) error {
+	var envName string
+	for _, arg := range args {
+		if envName != "" || strings.HasPrefix(arg, "-") {
+			return fmt.Errorf("usage: cc-buddy history [environment-name]")
+		}
+		envName = arg
+	}
+
+	events, err := c.log.Read(envName)
+	if err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+
+	if len(events) == 0 {
+		if envName != "" {
+			fmt.Printf("No history for '%s'.\n", envName)
+		} else {
+			fmt.Println("No history recorded yet.")
+		}
+		return nil
+	}
+
+	fmt.Printf("%-19s %-14s %-25s %-12s %-9s\n", "TIME", "EVENT", "ENVIRONMENT", "USER", "DURATION")
+	fmt.Printf("%s\n", strings.Repeat("-", 83))
+	for _, event := range events {
+		target := event.Environment
+		if event.Event == audit.EventMaintenance {
+			target = event.Task
+		}
+		fmt.Printf("%-19s %-14s %-25s %-12s %-9v\n",
+			event.Time.Local().Format("2006-01-02 15:04:05"),
+			event.Event,
+			target,
+			event.User,
+			event.Duration())
+		if event.Result != "" {
+			fmt.Printf("    result: %s\n", event.Result)
+		}
+		if event.Error != "" {
+			fmt.Printf("    error: %s\n", event.Error)
+		}
+	}
+
+	return nil
+}
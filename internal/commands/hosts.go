@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// HostsCommand handles <env>.localhost hostname management
+type HostsCommand struct {
+	envManager *environment.Manager
+}
+
+// NewHostsCommand creates a new hosts command
+func NewHostsCommand(envManager *environment.Manager) *HostsCommand {
+	return &HostsCommand{envManager: envManager}
+}
+
+// Execute runs the hosts command
+func (c *HostsCommand) Execute(ctx context.Context, args []string) error {
+	action := "list"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "list":
+		return c.list(ctx)
+	case "sync":
+		if err := c.envManager.SyncHosts(); err != nil {
+			return fmt.Errorf("failed to sync hosts file: %w", err)
+		}
+		fmt.Println("✅ Hosts file updated")
+		return nil
+	case "clear":
+		if err := c.envManager.ClearHosts(); err != nil {
+			return fmt.Errorf("failed to clear hosts file: %w", err)
+		}
+		fmt.Println("✅ cc-buddy entries removed from hosts file")
+		return nil
+	default:
+		return fmt.Errorf("usage: cc-buddy hosts [list|sync|clear]")
+	}
+}
+
+// list prints each environment's hostname and URLs for its published ports
+func (c *HostsCommand) list(ctx context.Context) error {
+	environments := c.envManager.GetConfig().Environments()
+	if len(environments) == 0 {
+		fmt.Println("No environments found.")
+		return nil
+	}
+
+	for _, env := range environments {
+		fmt.Printf("%s\n", environment.Hostname(env.Name))
+
+		urls, err := c.envManager.EnvironmentURLs(ctx, env.Name)
+		if err != nil {
+			fmt.Printf("  (ports unavailable: %v)\n", err)
+			continue
+		}
+		if len(urls) == 0 {
+			fmt.Println("  (no published ports)")
+			continue
+		}
+		for _, url := range urls {
+			fmt.Printf("  %s\n", url)
+		}
+	}
+
+	return nil
+}
@@ -5,14 +5,27 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jhjaggars/cc-buddy/internal/ci"
 	"github.com/jhjaggars/cc-buddy/internal/environment"
+	"github.com/jhjaggars/cc-buddy/internal/scaffold"
+	"github.com/jhjaggars/cc-buddy/internal/ui/models"
+)
+
+// Devcontainer output modes for init
+const (
+	devcontainerNone = ""
+	devcontainerAlso = "also" // devcontainer.json next to Containerfile.dev
+	devcontainerOnly = "only" // Containerfile and devcontainer.json both in .devcontainer
 )
 
 // InitCommand handles Containerfile.dev generation
 type InitCommand struct {
-	envManager *environment.Manager
+	envManager   *environment.Manager
+	devcontainer string
 }
 
 // NewInitCommand creates a new init command
@@ -22,30 +35,98 @@ func NewInitCommand(envManager *environment.Manager) *InitCommand {
 
 // Execute runs the init command
 func (c *InitCommand) Execute(ctx context.Context, args []string) error {
+	var templateRef string
+	force := false
+	plain := false
+	
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--template":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--template flag requires a template name or git URL")
+			}
+			i++
+			templateRef = args[i]
+		case "--list-templates":
+			return c.listTemplates()
+		case "--force":
+			force = true
+		case "--plain":
+			plain = true
+		case "--devcontainer":
+			c.devcontainer = devcontainerAlso
+		case "--devcontainer-only":
+			c.devcontainer = devcontainerOnly
+		default:
+			return fmt.Errorf("unexpected argument: %s\nusage: cc-buddy init [--template <name|git-url>] [--list-templates] [--force] [--plain] [--devcontainer|--devcontainer-only]", args[i])
+		}
+	}
+	
+	containerfilePath := "Containerfile.dev"
+	if c.devcontainer == devcontainerOnly {
+		// Keep the whole environment definition inside .devcontainer
+		containerfilePath = filepath.Join(scaffold.DevcontainerDir, "Containerfile")
+		if err := os.MkdirAll(scaffold.DevcontainerDir, 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", scaffold.DevcontainerDir, err)
+		}
+	}
+	
+	// Use the wizard on a terminal; plain prompts work when piped or scripted
+	if templateRef == "" && !plain && isTerminal(os.Stdin) && !ci.Enabled() {
+		return c.runWizard(containerfilePath)
+	}
+	
 	fmt.Println("🐋 cc-buddy Containerfile.dev Generator")
 	fmt.Println("=====================================")
 	fmt.Println()
 
 	// Check if Containerfile.dev already exists
-	containerfilePath := "Containerfile.dev"
-	if _, err := os.Stat(containerfilePath); err == nil {
+	if _, err := os.Stat(containerfilePath); err == nil && !force {
 		fmt.Printf("⚠️  %s already exists.\n", containerfilePath)
 		if !c.confirmOverwrite() {
 			fmt.Println("Initialization cancelled.")
 			return nil
 		}
 	}
+	
+	if templateRef != "" {
+		return c.applyTemplate(ctx, templateRef, containerfilePath)
+	}
 
+	// Pre-fill the prompts from the project files in the repository
+	suggestedImage := ""
+	suggestedPackages := []string{"git", "curl", "wget"}
+	var suggestedPorts []string
+	if projects := scaffold.Detect("."); len(projects) > 0 {
+		fmt.Printf("🔍 Detected project: %s\n\n", scaffold.Describe(projects))
+		primary := projects[0]
+		suggestedImage = primary.BaseImage
+		suggestedPackages = scaffold.SuggestedPackages(projects)
+		suggestedPorts = primary.Ports
+	}
+	
 	// Interactive prompts
-	baseImage := c.promptForBaseImage()
-	packages := c.promptForPackages()
-	ports := c.promptForPorts()
+	baseImage := c.promptForBaseImage(suggestedImage)
+	packages := c.promptForPackages(suggestedPackages)
+	ports := c.promptForPorts(suggestedPorts)
 	volumes := c.promptForVolumes()
 	envVars := c.promptForEnvVars()
 	commands := c.promptForCommands()
+	gpgSigning := c.promptForGPGSigning()
+	services := c.promptForServices()
+	username := c.promptForUsername()
 
 	// Generate Containerfile content
-	content := c.generateContainerfile(baseImage, packages, ports, volumes, envVars, commands)
+	content := scaffold.GenerateContainerfile(scaffold.ContainerfileOptions{
+		BaseImage:  baseImage,
+		Username:   username,
+		Packages:   packages,
+		Ports:      ports,
+		Volumes:    volumes,
+		EnvVars:    append(envVars, scaffold.ServiceEnvVars(services)...),
+		Commands:   commands,
+		GPGSigning: gpgSigning,
+	})
 
 	// Write to file
 	if err := os.WriteFile(containerfilePath, []byte(content), 0644); err != nil {
@@ -53,12 +134,175 @@ func (c *InitCommand) Execute(ctx context.Context, args []string) error {
 	}
 
 	fmt.Printf("✅ %s created successfully!\n", containerfilePath)
+	if err := c.saveContainerUser(username); err != nil {
+		return err
+	}
+	if len(services) > 0 {
+		if _, err := os.Stat(scaffold.ComposeFile); err == nil && !force {
+			fmt.Printf("⚠️  %s already exists.\n", scaffold.ComposeFile)
+			if !c.confirmOverwrite() {
+				services = nil
+			}
+		}
+	}
+	if len(services) > 0 {
+		if err := scaffold.WriteCompose(c.envManager.GetConfig(), services); err != nil {
+			return err
+		}
+		fmt.Printf("✅ %s created and set as \"compose_file\" in .cc-buddy/config.json\n", scaffold.ComposeFile)
+	}
+	if err := c.writeDevcontainer(containerfilePath); err != nil {
+		return err
+	}
+	printInitNextSteps(containerfilePath, gpgSigning)
+
+	return nil
+}
+
+// runWizard generates the Containerfile with the Bubble Tea wizard
+func (c *InitCommand) runWizard(containerfilePath string) error {
+	wizard := models.NewInitWizardModel(containerfilePath, true)
+	if _, err := tea.NewProgram(wizard, tea.WithAltScreen()).Run(); err != nil {
+		return fmt.Errorf("init wizard failed: %w", err)
+	}
+	
+	result := wizard.Result()
+	if result == nil || result.Cancelled {
+		fmt.Println("Initialization cancelled.")
+		return nil
+	}
+	
+	fmt.Printf("✅ %s created successfully!\n", result.Path)
+	if len(result.Services) > 0 {
+		fmt.Printf("✅ %s created and set as \"compose_file\" in .cc-buddy/config.json\n", scaffold.ComposeFile)
+	}
+	// Pick up the wizard's configuration changes before updating it again
+	if err := c.envManager.GetConfig().LoadConfig(); err != nil {
+		return fmt.Errorf("failed to reload configuration: %w", err)
+	}
+	if err := c.writeDevcontainer(result.Path); err != nil {
+		return err
+	}
+	printInitNextSteps(result.Path, result.GPGSigning)
+	return nil
+}
+
+// writeDevcontainer generates .devcontainer/devcontainer.json for the
+// Containerfile when requested. In devcontainer-only mode the project
+// configuration is pointed at .devcontainer/Containerfile.
+func (c *InitCommand) writeDevcontainer(containerfilePath string) error {
+	if c.devcontainer == devcontainerNone {
+		return nil
+	}
+	
+	containerfile, err := os.ReadFile(containerfilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", containerfilePath, err)
+	}
+	
+	name := "cc-buddy"
+	if cwd, err := os.Getwd(); err == nil {
+		name = filepath.Base(cwd)
+	}
+	
+	// Paths in devcontainer.json are relative to the .devcontainer directory
+	dockerfile, err := filepath.Rel(scaffold.DevcontainerDir, containerfilePath)
+	if err != nil {
+		return fmt.Errorf("failed to locate %s: %w", containerfilePath, err)
+	}
+	content, err := scaffold.GenerateDevcontainer(name, filepath.ToSlash(dockerfile), "..", containerfile)
+	if err != nil {
+		return fmt.Errorf("failed to generate %s: %w", scaffold.DevcontainerFile, err)
+	}
+	
+	if err := os.MkdirAll(scaffold.DevcontainerDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", scaffold.DevcontainerDir, err)
+	}
+	devcontainerPath := filepath.Join(scaffold.DevcontainerDir, scaffold.DevcontainerFile)
+	if err := os.WriteFile(devcontainerPath, content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", devcontainerPath, err)
+	}
+	fmt.Printf("✅ %s created for VS Code and Codespaces\n", devcontainerPath)
+	
+	if c.devcontainer == devcontainerOnly {
+		setting := fmt.Sprintf(`{"containerfile": %q}`, filepath.ToSlash(containerfilePath))
+		if err := c.envManager.GetConfig().MergeConfig([]byte(setting)); err != nil {
+			return fmt.Errorf("failed to update configuration: %w", err)
+		}
+		fmt.Printf("✅ Set \"containerfile\" to %s in .cc-buddy/config.json\n", filepath.ToSlash(containerfilePath))
+	}
+	return nil
+}
+
+// printInitNextSteps tells the user what to do with a generated Containerfile
+func printInitNextSteps(containerfilePath string, gpgSigning bool) {
+	fmt.Println()
+	fmt.Println("Next steps:")
+	fmt.Printf("  1. Review and customize %s\n", containerfilePath)
+	fmt.Println("  2. Create your first environment:")
+	fmt.Println("     cc-buddy create <branch-name>")
+	if gpgSigning {
+		fmt.Println()
+		fmt.Println("GPG signing: set \"forward_gpg\": true in .cc-buddy/config.json so")
+		fmt.Println("cc-buddy forwards your gpg-agent socket and public keyring into the container.")
+	}
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// applyTemplate writes a template's Containerfile and merges its recommended configuration
+func (c *InitCommand) applyTemplate(ctx context.Context, ref, containerfilePath string) error {
+	tmpl, err := scaffold.LoadTemplate(ctx, ref)
+	if err != nil {
+		return err
+	}
+	
+	if err := os.WriteFile(containerfilePath, tmpl.Containerfile, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", containerfilePath, err)
+	}
+	fmt.Printf("✅ %s created from template '%s' (%s)\n", containerfilePath, tmpl.Name, tmpl.Source)
+	
+	if len(tmpl.Config) > 0 {
+		if err := c.envManager.GetConfig().MergeConfig(tmpl.Config); err != nil {
+			return fmt.Errorf("failed to apply template configuration: %w", err)
+		}
+		fmt.Printf("✅ Applied recommended settings to .cc-buddy/config.json:\n%s\n", strings.TrimSpace(string(tmpl.Config)))
+	}
+	
+	if err := c.writeDevcontainer(containerfilePath); err != nil {
+		return err
+	}
+	
 	fmt.Println()
 	fmt.Println("Next steps:")
 	fmt.Printf("  1. Review and customize %s\n", containerfilePath)
 	fmt.Println("  2. Create your first environment:")
 	fmt.Println("     cc-buddy create <branch-name>")
+	return nil
+}
 
+// listTemplates prints the available built-in and user templates
+func (c *InitCommand) listTemplates() error {
+	templates, err := scaffold.ListTemplates()
+	if err != nil {
+		return err
+	}
+	
+	fmt.Println("Available templates:")
+	for _, tmpl := range templates {
+		fmt.Printf("  %-16s %s\n", tmpl.Name, tmpl.Description)
+	}
+	if userDir, err := scaffold.UserTemplateDir(); err == nil {
+		fmt.Printf("\nUser templates are read from %s/<name>/%s\n", userDir, scaffold.TemplateContainerfile)
+	}
+	fmt.Println("Templates can also be cloned from git: cc-buddy init --template <git-url>[#subdir]")
 	return nil
 }
 
@@ -70,23 +314,99 @@ func (c *InitCommand) confirmOverwrite() bool {
 	return response == "y" || response == "yes"
 }
 
-func (c *InitCommand) promptForBaseImage() string {
+func (c *InitCommand) promptForUsername() string {
+	current := c.envManager.GetConfig().GetConfig().ContainerUser
+	if current == "" {
+		current = scaffold.DefaultUsername
+	}
+	
+	fmt.Println()
+	fmt.Println("9. Container User")
+	fmt.Printf("   Name of the non-root user in the container [%s]: ", current)
+	
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(response)
+	
+	if response == "" {
+		return current
+	}
+	if !scaffold.ValidUsername(response) {
+		fmt.Printf("   ⚠️  invalid user name %q, using %s\n", response, current)
+		return current
+	}
+	return response
+}
+
+// saveContainerUser records a non-default container user in the project
+// configuration so create passes it as the USERNAME build arg
+func (c *InitCommand) saveContainerUser(username string) error {
+	configMgr := c.envManager.GetConfig()
+	if configMgr.GetConfig().ContainerUser == username {
+		return nil
+	}
+	if err := configMgr.MergeConfig([]byte(fmt.Sprintf(`{"container_user": %q}`, username))); err != nil {
+		return fmt.Errorf("failed to update configuration: %w", err)
+	}
+	fmt.Printf("✅ Set \"container_user\" to %s in .cc-buddy/config.json\n", username)
+	return nil
+}
+
+func (c *InitCommand) promptForServices() []scaffold.Service {
+	fmt.Println()
+	fmt.Println("8. Backing Services")
+	for _, service := range scaffold.Services {
+		fmt.Printf("   %-6s %s (%s)\n", service.Name, service.Description, service.Image)
+	}
+	fmt.Print("   Services to run with each environment (space-separated) []: ")
+	
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	
+	var services []scaffold.Service
+	for _, name := range strings.Fields(strings.ReplaceAll(response, ",", " ")) {
+		service, err := scaffold.LookupServices([]string{name})
+		if err != nil {
+			fmt.Printf("   ⚠️  %v, skipping\n", err)
+			continue
+		}
+		services = append(services, service...)
+	}
+	return services
+}
+
+func (c *InitCommand) promptForBaseImage(suggested string) string {
 	fmt.Println("1. Base Image Selection")
 	fmt.Println("   Choose a base image for your development environment:")
+	if suggested != "" {
+		fmt.Printf("   0) %s (detected)\n", suggested)
+	}
 	fmt.Println("   1) ubuntu:22.04 (recommended)")
 	fmt.Println("   2) node:18")
 	fmt.Println("   3) python:3.11")
 	fmt.Println("   4) golang:1.21")
 	fmt.Println("   5) rust:1.70")
 	fmt.Println("   6) Custom")
-	fmt.Print("   Enter choice [1]: ")
+	defaultChoice := "1"
+	if suggested != "" {
+		defaultChoice = "0"
+	}
+	fmt.Printf("   Enter choice [%s]: ", defaultChoice)
 
 	reader := bufio.NewReader(os.Stdin)
 	response, _ := reader.ReadString('\n')
 	response = strings.TrimSpace(response)
+	if response == "" {
+		response = defaultChoice
+	}
 
 	switch response {
-	case "", "1":
+	case "0":
+		if suggested != "" {
+			return suggested
+		}
+		return "ubuntu:22.04"
+	case "1":
 		return "ubuntu:22.04"
 	case "2":
 		return "node:18"
@@ -105,32 +425,35 @@ func (c *InitCommand) promptForBaseImage() string {
 	}
 }
 
-func (c *InitCommand) promptForPackages() []string {
+func (c *InitCommand) promptForPackages(defaults []string) []string {
 	fmt.Println()
 	fmt.Println("2. System Packages")
-	fmt.Print("   Enter additional packages to install (space-separated) [git curl wget]: ")
+	fmt.Printf("   Enter additional packages to install (space-separated) [%s]: ", strings.Join(defaults, " "))
 	
 	reader := bufio.NewReader(os.Stdin)
 	response, _ := reader.ReadString('\n')
 	response = strings.TrimSpace(response)
 	
 	if response == "" {
-		response = "git curl wget"
+		return defaults
 	}
 	
 	return strings.Fields(response)
 }
 
-func (c *InitCommand) promptForPorts() []string {
+func (c *InitCommand) promptForPorts(defaults []string) []string {
 	fmt.Println()
 	fmt.Println("3. Port Exposure")
-	fmt.Print("   Enter ports to expose (space-separated) []: ")
+	fmt.Printf("   Enter ports to expose (space-separated, \"none\" for none) [%s]: ", strings.Join(defaults, " "))
 	
 	reader := bufio.NewReader(os.Stdin)
 	response, _ := reader.ReadString('\n')
 	response = strings.TrimSpace(response)
 	
 	if response == "" {
+		return defaults
+	}
+	if response == "none" {
 		return []string{}
 	}
 	
@@ -192,100 +515,14 @@ func (c *InitCommand) promptForCommands() []string {
 	return commands
 }
 
-func (c *InitCommand) generateContainerfile(baseImage string, packages, ports, volumes, envVars, commands []string) string {
-	var content strings.Builder
-	
-	content.WriteString("# Development Container for cc-buddy\n")
-	content.WriteString("# Generated automatically - feel free to customize!\n\n")
-	
-	// Base image
-	content.WriteString(fmt.Sprintf("FROM %s\n\n", baseImage))
-	
-	// System packages - always include sudo for user sync functionality
-	allPackages := append([]string{"sudo"}, packages...)
-	if len(allPackages) > 0 {
-		content.WriteString("# Install system packages\n")
-		content.WriteString("RUN apt-get update && apt-get install -y \\\n")
-		for i, pkg := range allPackages {
-			if i == len(allPackages)-1 {
-				content.WriteString(fmt.Sprintf("    %s \\\n", pkg))
-			} else {
-				content.WriteString(fmt.Sprintf("    %s \\\n", pkg))
-			}
-		}
-		content.WriteString("    && rm -rf /var/lib/apt/lists/*\n\n")
-	}
-	
-	// User synchronization setup
-	content.WriteString("# Create a non-root user with dynamic UID/GID matching host user\n")
-	content.WriteString("ARG USERNAME=developer\n")
-	content.WriteString("ARG USER_UID=1000\n")
-	content.WriteString("ARG USER_GID=1000\n\n")
-	
-	content.WriteString("# Create group and user with dynamic IDs\n")
-	content.WriteString("RUN groupadd --gid $USER_GID $USERNAME \\\n")
-	content.WriteString("    && useradd --uid $USER_UID --gid $USER_GID -m $USERNAME \\\n")
-	content.WriteString("    && echo $USERNAME ALL=\\(root\\) NOPASSWD:ALL > /etc/sudoers.d/$USERNAME \\\n")
-	content.WriteString("    && chmod 0440 /etc/sudoers.d/$USERNAME\n\n")
-	
-	// Environment variables
-	if len(envVars) > 0 {
-		content.WriteString("# Environment variables\n")
-		for _, env := range envVars {
-			content.WriteString(fmt.Sprintf("ENV %s\n", env))
-		}
-		content.WriteString("\n")
-	}
-	
-	// Expose ports
-	if len(ports) > 0 {
-		content.WriteString("# Expose ports\n")
-		for _, port := range ports {
-			content.WriteString(fmt.Sprintf("EXPOSE %s\n", port))
-		}
-		content.WriteString("\n")
-	}
+func (c *InitCommand) promptForGPGSigning() bool {
+	fmt.Println()
+	fmt.Println("7. Commit Signing")
+	fmt.Print("   Prepare the image for GPG-signed commits via agent forwarding? [y/N]: ")
 	
-	// Volume mount points
-	if len(volumes) > 0 {
-		content.WriteString("# Volume mount points\n")
-		for _, volume := range volumes {
-			content.WriteString(fmt.Sprintf("VOLUME %s\n", volume))
-		}
-		content.WriteString("\n")
-	}
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
 	
-	// Startup commands (run as root before user switch)
-	if len(commands) > 0 {
-		content.WriteString("# Startup commands\n")
-		for _, cmd := range commands {
-			content.WriteString(fmt.Sprintf("RUN %s\n", cmd))
-		}
-		content.WriteString("\n")
-	}
-	
-	// Create workspace ownership fix script
-	content.WriteString("# Create workspace ownership fix script\n")
-	content.WriteString("RUN echo '#!/bin/bash\\n\\\n")
-	content.WriteString("# Fix workspace ownership to match container user\\n\\\n")
-	content.WriteString("if [ -d \"/workspace\" ]; then\\n\\\n")
-	content.WriteString("    sudo chown -R developer:developer /workspace || true\\n\\\n")
-	content.WriteString("fi\\n\\\n")
-	content.WriteString("# Execute the original command\\n\\\n")
-	content.WriteString("exec \"$@\"' > /usr/local/bin/fix-workspace-ownership.sh \\\n")
-	content.WriteString("    && chmod +x /usr/local/bin/fix-workspace-ownership.sh\n\n")
-	
-	// Switch to non-root user
-	content.WriteString("USER $USERNAME\n\n")
-	
-	// Working directory
-	content.WriteString("# Set working directory\n")
-	content.WriteString("WORKDIR /workspace\n\n")
-	
-	// Use the ownership fix script as entrypoint
-	content.WriteString("# Use the ownership fix script as entrypoint\n")
-	content.WriteString("ENTRYPOINT [\"/usr/local/bin/fix-workspace-ownership.sh\"]\n")
-	content.WriteString("CMD [\"tail\", \"-f\", \"/dev/null\"]\n")
-	
-	return content.String()
-}
\ No newline at end of file
+	return response == "y" || response == "yes"
+}
@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/ci"
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// InspectCommand shows everything recorded about one environment
+type InspectCommand struct {
+	envManager *environment.Manager
+}
+
+// NewInspectCommand creates a new inspect command
+func NewInspectCommand(envManager *environment.Manager) *InspectCommand {
+	return &InspectCommand{envManager: envManager}
+}
+
+// Execute runs the inspect command: the environment's state with its live
+// status, image and published ports, as text or JSON
+func (c *InspectCommand) Execute(ctx context.Context, args []string) error {
+	usage := fmt.Errorf("usage: cc-buddy inspect <environment-name> [--json]")
+	var envName string
+	useJSON := false
+	for _, arg := range args {
+		switch {
+		case arg == "--json":
+			useJSON = true
+		case envName == "" && len(arg) > 0 && arg[0] != '-':
+			envName = arg
+		default:
+			return usage
+		}
+	}
+	if envName == "" {
+		return usage
+	}
+
+	environments, err := c.envManager.ListEnvironments(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list environments: %w", err)
+	}
+	var env *config.Environment
+	for i := range environments {
+		if environments[i].Name == envName {
+			env = &environments[i]
+			break
+		}
+	}
+	if env == nil {
+		return fmt.Errorf("environment '%s' not found", envName)
+	}
+	if env.Status == "running" {
+		if ports, err := c.envManager.PublishedPorts(ctx, *env); err == nil {
+			env.Ports = ports
+		}
+	}
+
+	if useJSON {
+		encoder := json.NewEncoder(ci.Stdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(env)
+	}
+
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Printf("%-18s %s\n", name+":", value)
+		}
+	}
+	field("Name", env.Name)
+	field("Branch", env.Branch)
+	field("Status", env.Status)
+	field("Health", env.Health)
+	field("Created", env.Created.Format("2006-01-02 15:04:05"))
+	field("Worktree", env.WorktreePath)
+	if env.NoContainer {
+		field("Container", "none (--no-container)")
+	} else {
+		field("Container", env.ContainerName)
+		field("Image", env.Image)
+		field("Source image", env.SourceImage)
+		field("Volume", env.VolumeName)
+		field("Network", inspectNetwork(env))
+		field("Port mappings", strings.Join(env.PortMappings, ", "))
+		if env.ExposeAllPorts {
+			field("Expose all", "yes")
+		}
+		field("Published ports", strings.Join(env.Ports, ", "))
+		field("Proxy host", env.ProxyHost)
+		field("Mounts", strings.Join(env.Mounts, ", "))
+		if env.ReadOnlyWorkspace {
+			field("Workspace", "read-only")
+		}
+		field("Runtime args", strings.Join(env.RuntimeArgs, " "))
+		if env.Privileged {
+			field("Privileged", "yes")
+		}
+		field("Capabilities", strings.Join(env.CapAdd, ", "))
+		field("Devices", strings.Join(env.Devices, ", "))
+		field("Nested", env.NestedContainers)
+		field("Compose project", env.ComposeProject)
+	}
+	if len(env.Labels) > 0 {
+		var labels []string
+		for key, value := range env.Labels {
+			labels = append(labels, key+"="+value)
+		}
+		sort.Strings(labels)
+		field("Labels", strings.Join(labels, ", "))
+	}
+	if env.Protected {
+		field("Protected", "yes")
+	}
+	field("Note", env.Note)
+	return nil
+}
+
+// inspectNetwork describes an environment's network mode
+func inspectNetwork(env *config.Environment) string {
+	switch env.Network {
+	case environment.NetworkShared:
+		return fmt.Sprintf("%s (hostname: %s)", environment.SharedNetworkName, env.Name)
+	case environment.NetworkHost:
+		return "host"
+	case "":
+		return "default"
+	}
+	return env.Network
+}
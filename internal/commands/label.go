@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// LabelCommand shows or changes an environment's labels
+type LabelCommand struct {
+	envManager *environment.Manager
+}
+
+// NewLabelCommand creates a new label command
+func NewLabelCommand(envManager *environment.Manager) *LabelCommand {
+	return &LabelCommand{envManager: envManager}
+}
+
+// Execute runs the label command: key=value sets a label, key- removes it,
+// and with neither the labels are printed
+func (c *LabelCommand) Execute(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: cc-buddy label <environment-name> [key=value]... [key-]...")
+	}
+	envName := args[0]
+
+	set := map[string]string{}
+	var remove []string
+	for _, arg := range args[1:] {
+		if key, ok := strings.CutSuffix(arg, "-"); ok && !strings.Contains(arg, "=") {
+			remove = append(remove, key)
+			continue
+		}
+		key, value, err := environment.ParseLabel(arg)
+		if err != nil {
+			return err
+		}
+		set[key] = value
+	}
+
+	if len(set) == 0 && len(remove) == 0 {
+		env, err := c.envManager.GetConfig().GetEnvironment(envName)
+		if err != nil {
+			return fmt.Errorf("environment '%s' not found", envName)
+		}
+		keys := make([]string, 0, len(env.Labels))
+		for key := range env.Labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Printf("%s=%s\n", key, env.Labels[key])
+		}
+		return nil
+	}
+
+	if err := c.envManager.SetLabels(envName, set, remove); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Updated labels of '%s'\n", envName)
+	fmt.Println("Note: the container gets the new labels when it is next recreated (e.g. by rebuild)")
+	return nil
+}
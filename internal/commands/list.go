@@ -2,11 +2,19 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jhjaggars/cc-buddy/internal/ci"
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/container"
 	"github.com/jhjaggars/cc-buddy/internal/environment"
 	"github.com/jhjaggars/cc-buddy/internal/ui/models"
 )
@@ -21,19 +29,91 @@ func NewListCommand(envManager *environment.Manager) *ListCommand {
 	return &ListCommand{envManager: envManager}
 }
 
+// listFilter selects environments for plain and JSON output
+type listFilter struct {
+	statuses  []string                    // status, health, "stale" or "protected"; any may match
+	branch    string                      // glob the branch must match
+	olderThan time.Duration               // minimum age
+	labels    []environment.LabelSelector // labels that must all match
+	sortBy    string                      // "created", "used", "name" or "status"; empty keeps state order
+	reverse   bool
+}
+
 // Execute runs the list command
 func (c *ListCommand) Execute(ctx context.Context, args []string) error {
-	// Check for --plain flag for backward compatibility
 	usePlainOutput := false
-	for _, arg := range args {
-		if arg == "--plain" {
+	useJSON := false
+	wide := false
+	var filter listFilter
+	
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--plain":
 			usePlainOutput = true
-			break
+		case "--json":
+			useJSON = true
+		case "--wide":
+			wide = true
+		case "--reverse":
+			filter.reverse = true
+		case "--status", "--branch", "--older-than", "--sort", "--label":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s flag requires a value", arg)
+			}
+			i++
+			value := args[i]
+			switch arg {
+			case "--status":
+				for _, status := range strings.Split(value, ",") {
+					if status = strings.TrimSpace(status); status != "" {
+						filter.statuses = append(filter.statuses, status)
+					}
+				}
+			case "--branch":
+				if _, err := path.Match(value, ""); err != nil {
+					return fmt.Errorf("invalid --branch pattern %q: %w", value, err)
+				}
+				filter.branch = value
+			case "--label":
+				selector, err := environment.ParseLabelSelector(value)
+				if err != nil {
+					return err
+				}
+				filter.labels = append(filter.labels, selector)
+			case "--older-than":
+				age, err := parseAge(value)
+				if err != nil {
+					return err
+				}
+				filter.olderThan = age
+			case "--sort":
+				switch value {
+				case "created", "used", "name", "status":
+					filter.sortBy = value
+				default:
+					return fmt.Errorf("invalid --sort %q: use created, used, name or status", value)
+				}
+			}
+		default:
+			return fmt.Errorf("unknown list option: %s", arg)
 		}
 	}
 
-	if usePlainOutput {
-		return c.executePlainList(ctx)
+	if c.envManager.WorktreeOnly() {
+		// On stderr, so JSON and plain output stay parseable
+		fmt.Fprintln(os.Stderr, "⚠️  No container runtime found: showing worktrees only, with their last known status.")
+		fmt.Fprintln(os.Stderr, container.InstallHint())
+		fmt.Fprintln(os.Stderr)
+	}
+
+	if useJSON {
+		return c.executeJSONList(ctx, filter)
+	}
+	// The interactive list shows everything, so filters imply --plain; CI
+	// mode never starts the TUI
+	if usePlainOutput || wide || filter.active() || filter.sortBy != "" || filter.reverse || ci.Enabled() {
+		return c.executePlainList(ctx, filter, wide)
 	}
 
 	// Launch interactive TUI list
@@ -56,13 +136,67 @@ func (c *ListCommand) executeInteractiveList() error {
 	return nil
 }
 
-// executePlainList provides the original plain text output for scripts
-func (c *ListCommand) executePlainList(ctx context.Context) error {
+// listEnvironments returns the environments filter selects, in its order
+func (c *ListCommand) listEnvironments(ctx context.Context, filter listFilter) ([]config.Environment, error) {
 	environments, err := c.envManager.ListEnvironments(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to list environments: %w", err)
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+	
+	var selected []config.Environment
+	for _, env := range environments {
+		if filter.matches(env) {
+			selected = append(selected, env)
+		}
+	}
+	filter.sort(selected)
+	return selected, nil
+}
+
+// executeJSONList prints the selected environments as a JSON array
+func (c *ListCommand) executeJSONList(ctx context.Context, filter listFilter) error {
+	environments, err := c.listEnvironments(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if environments == nil {
+		environments = []config.Environment{} // [] rather than null
+	}
+	c.fillPorts(ctx, environments)
+	
+	encoder := json.NewEncoder(ci.Stdout())
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(environments)
+}
+
+// fillPorts looks up the published ports of running environments; lookup
+// failures keep the ports recorded when the container started, and
+// environments that are not running publish none
+func (c *ListCommand) fillPorts(ctx context.Context, environments []config.Environment) {
+	for i := range environments {
+		if environments[i].Status != "running" {
+			environments[i].Ports = nil
+			continue
+		}
+		ports, err := c.envManager.PublishedPorts(ctx, environments[i])
+		if err == nil {
+			environments[i].Ports = ports
+		}
+	}
+}
+
+// executePlainList provides the original plain text output for scripts;
+// wide adds the image, published ports, uptime and worktree path
+func (c *ListCommand) executePlainList(ctx context.Context, filter listFilter, wide bool) error {
+	environments, err := c.listEnvironments(ctx, filter)
+	if err != nil {
+		return err
 	}
 
+	if len(environments) == 0 && filter.active() {
+		fmt.Println("No environments match the filters.")
+		return nil
+	}
 	if len(environments) == 0 {
 		fmt.Println("No environments found.")
 		fmt.Println("\nCreate your first environment with:")
@@ -73,19 +207,53 @@ func (c *ListCommand) executePlainList(ctx context.Context) error {
 	fmt.Printf("Environments (%d):\n\n", len(environments))
 
 	// Print header
-	fmt.Printf("%-25s %-20s %-10s %-15s\n", "NAME", "BRANCH", "STATUS", "CREATED")
-	fmt.Printf("%s\n", strings.Repeat("-", 70))
+	if wide {
+		c.fillPorts(ctx, environments)
+		fmt.Printf("%-25s %-20s %-10s %-15s %-15s %-32s %-20s %-8s %-30s %s\n", "NAME", "BRANCH", "STATUS", "CREATED", "LAST USED", "IMAGE", "PORTS", "UPTIME", "NOTE", "WORKTREE")
+		fmt.Printf("%s\n", strings.Repeat("-", 197))
+	} else {
+		fmt.Printf("%-25s %-20s %-10s %-15s %-15s\n", "NAME", "BRANCH", "STATUS", "CREATED", "LAST USED")
+		fmt.Printf("%s\n", strings.Repeat("-", 86))
+	}
 
 	// Print environments
 	for _, env := range environments {
-		status := getStatusDisplay(env.Status)
+		status := getStatusDisplay(env.Status, env.Health)
+		if env.Stale && env.Status == "running" {
+			// The Containerfile changed since the image was built
+			status = "⚠️ stale"
+		}
+		if env.Protected {
+			status += " 🔒"
+		}
 		created := formatTimeAgo(env.Created)
+		lastUsed := "never"
+		if env.LastUsed != nil {
+			lastUsed = formatTimeAgo(*env.LastUsed)
+		}
+		
+		if wide {
+			ports, uptime, note := "-", "-", "-"
+			if len(env.Ports) > 0 {
+				ports = strings.Join(env.Ports, ",")
+			}
+			if env.StartedAt != nil {
+				uptime = formatUptime(time.Since(*env.StartedAt))
+			}
+			if env.Note != "" {
+				note = truncate(env.Note, 30)
+			}
+			fmt.Printf("%-25s %-20s %-10s %-15s %-15s %-32s %-20s %-8s %-30s %s\n",
+				env.Name, env.Branch, status, created, lastUsed, env.Image, ports, uptime, note, env.WorktreePath)
+			continue
+		}
 		
-		fmt.Printf("%-25s %-20s %-10s %-15s\n", 
+		fmt.Printf("%-25s %-20s %-10s %-15s %-15s\n", 
 			env.Name, 
 			env.Branch, 
 			status, 
-			created)
+			created,
+			lastUsed)
 	}
 
 	fmt.Printf("\nCommands:\n")
@@ -95,13 +263,119 @@ func (c *ListCommand) executePlainList(ctx context.Context) error {
 	return nil
 }
 
+// active reports whether the filter selects anything less than everything
+func (f listFilter) active() bool {
+	return len(f.statuses) > 0 || f.branch != "" || f.olderThan > 0 || len(f.labels) > 0
+}
+
+// matches reports whether env passes every filter
+func (f listFilter) matches(env config.Environment) bool {
+	if len(f.statuses) > 0 {
+		found := false
+		for _, status := range f.statuses {
+			if status == env.Status || (env.Status == "running" && status == env.Health) || (status == "stale" && env.Stale) || (status == "protected" && env.Protected) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.branch != "" {
+		if ok, _ := path.Match(f.branch, env.Branch); !ok {
+			return false
+		}
+	}
+	if f.olderThan > 0 && time.Since(env.Created) < f.olderThan {
+		return false
+	}
+	return environment.MatchLabels(env, f.labels)
+}
+
+// sort orders environments by the sort key, ties broken by name so output
+// is deterministic; --reverse without a key reverses state order
+func (f listFilter) sort(environments []config.Environment) {
+	less := func(a, b config.Environment) bool { return false }
+	switch f.sortBy {
+	case "created":
+		less = func(a, b config.Environment) bool {
+			if !a.Created.Equal(b.Created) {
+				return a.Created.Before(b.Created)
+			}
+			return a.Name < b.Name
+		}
+	case "used":
+		// Never-used environments come first, as the likeliest to be abandoned
+		less = func(a, b config.Environment) bool {
+			if a.LastUsed == nil || b.LastUsed == nil {
+				if (a.LastUsed == nil) != (b.LastUsed == nil) {
+					return a.LastUsed == nil
+				}
+				return a.Name < b.Name
+			}
+			if !a.LastUsed.Equal(*b.LastUsed) {
+				return a.LastUsed.Before(*b.LastUsed)
+			}
+			return a.Name < b.Name
+		}
+	case "name":
+		less = func(a, b config.Environment) bool { return a.Name < b.Name }
+	case "status":
+		less = func(a, b config.Environment) bool {
+			if a.Status != b.Status {
+				return a.Status < b.Status
+			}
+			return a.Name < b.Name
+		}
+	}
+	sort.SliceStable(environments, func(i, j int) bool {
+		return less(environments[i], environments[j])
+	})
+	if f.reverse {
+		slices.Reverse(environments)
+	}
+}
+
+// truncate shortens s to at most width runes, marking the cut with …
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// parseAge parses an --older-than value
+func parseAge(value string) (time.Duration, error) {
+	age, err := environment.ParseAge(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid --older-than %q: use a duration like 7d, 2w or 36h", value)
+	}
+	return age, nil
+}
+
 // getStatusDisplay returns a user-friendly status display
-func getStatusDisplay(status string) string {
+func getStatusDisplay(status, health string) string {
+	// A running container with a HEALTHCHECK is shown by its health instead
+	if status == "running" {
+		switch health {
+		case "healthy":
+			return "🟢 healthy"
+		case "unhealthy":
+			return "🔴 unhealthy"
+		case "starting":
+			return "🔄 starting"
+		}
+	}
+	
 	switch status {
 	case "running":
 		return "🟢 running"
 	case "stopped":
 		return "🟡 stopped"
+	case "created":
+		return "⚪ created"
 	case "creating":
 		return "🔄 creating"
 	case "error":
@@ -111,6 +385,21 @@ func getStatusDisplay(status string) string {
 	}
 }
 
+// formatUptime formats how long a container has run as "45s", "12m",
+// "3h5m" or "2d4h"
+func formatUptime(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
 // formatTimeAgo formats a time as "2h ago", "1d ago", etc.
 func formatTimeAgo(t time.Time) string {
 	now := time.Now()
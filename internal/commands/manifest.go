@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// createManifest describes a set of environments for create --from-file
+type createManifest struct {
+	Parallel     int             `yaml:"parallel"` // creates at once; --parallel overrides it
+	Defaults     manifestEntry   `yaml:"defaults"` // settings every environment starts from
+	Environments []manifestEntry `yaml:"environments"`
+}
+
+// manifestEntry is one environment of a manifest, or the manifest's defaults
+type manifestEntry struct {
+	Branch            string            `yaml:"branch"`
+	Template          string            `yaml:"template"`      // init template to build from
+	Containerfile     string            `yaml:"containerfile"` // Containerfile in the worktree
+	Command           string            `yaml:"command"`       // startup command, as for -e
+	Mounts            []string          `yaml:"mounts"`        // added to the defaults' mounts
+	ReadOnlyWorkspace *bool             `yaml:"read_only_workspace"`
+	Network           string            `yaml:"network"`
+	ExposeAll         *bool             `yaml:"expose_all"`
+	NoStart           *bool             `yaml:"no_start"`
+	Labels            map[string]string `yaml:"labels"` // added to the defaults' labels
+}
+
+// loadCreateManifest reads and checks a manifest file. Unknown keys are
+// errors so typos do not silently drop settings.
+func loadCreateManifest(path string) (*createManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var manifest createManifest
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+
+	if len(manifest.Environments) == 0 {
+		return nil, fmt.Errorf("manifest %s lists no environments", path)
+	}
+	if manifest.Defaults.Branch != "" {
+		return nil, fmt.Errorf("manifest %s: defaults cannot set a branch", path)
+	}
+	if manifest.Parallel < 0 {
+		return nil, fmt.Errorf("manifest %s: invalid parallel %d", path, manifest.Parallel)
+	}
+	if manifest.Defaults.Template != "" && manifest.Defaults.Containerfile != "" {
+		return nil, fmt.Errorf("manifest %s: defaults set both template and containerfile", path)
+	}
+	for i, entry := range manifest.Environments {
+		if entry.Branch == "" {
+			return nil, fmt.Errorf("manifest %s: environment %d has no branch", path, i+1)
+		}
+		if entry.Template != "" && entry.Containerfile != "" {
+			return nil, fmt.Errorf("manifest %s: %s sets both template and containerfile", path, entry.Branch)
+		}
+	}
+	for _, entry := range append([]manifestEntry{manifest.Defaults}, manifest.Environments...) {
+		for key, value := range entry.Labels {
+			if _, _, err := environment.ParseLabel(key + "=" + value); err != nil {
+				return nil, fmt.Errorf("manifest %s: %w", path, err)
+			}
+		}
+	}
+	return &manifest, nil
+}
+
+// options returns the create options of each environment: base (the
+// command-line options), then the manifest defaults, then the entry's own
+// settings, later ones taking precedence
+func (manifest *createManifest) options(gitOps *environment.GitOperations, base environment.CreateEnvironmentOptions) []environment.CreateEnvironmentOptions {
+	base = manifest.Defaults.apply(base)
+
+	var optsList []environment.CreateEnvironmentOptions
+	for _, entry := range manifest.Environments {
+		opts := branchOptions(gitOps, entry.Branch, base)
+		// An entry's template or containerfile replaces either default
+		if entry.Template != "" {
+			opts.Containerfile = ""
+		} else if entry.Containerfile != "" {
+			opts.Template = ""
+		}
+		optsList = append(optsList, entry.apply(opts))
+	}
+	return optsList
+}
+
+// apply returns opts with the settings entry makes
+func (entry manifestEntry) apply(opts environment.CreateEnvironmentOptions) environment.CreateEnvironmentOptions {
+	if entry.Template != "" {
+		opts.Template = entry.Template
+	}
+	if entry.Containerfile != "" {
+		opts.Containerfile = entry.Containerfile
+	}
+	if entry.Command != "" {
+		opts.StartupCommand = environment.ParseCommand(entry.Command)
+	}
+	opts.Mounts = append(append([]string{}, opts.Mounts...), entry.Mounts...)
+	if entry.ReadOnlyWorkspace != nil {
+		opts.ReadOnlyWorkspace = *entry.ReadOnlyWorkspace
+	}
+	if entry.Network != "" {
+		opts.Network = entry.Network
+	}
+	if entry.ExposeAll != nil {
+		opts.ExposeAllPorts = *entry.ExposeAll
+	}
+	if entry.NoStart != nil {
+		opts.NoStart = *entry.NoStart
+	}
+	if len(entry.Labels) > 0 {
+		labels := map[string]string{}
+		for key, value := range opts.Labels {
+			labels[key] = value
+		}
+		for key, value := range entry.Labels {
+			labels[key] = value
+		}
+		opts.Labels = labels
+	}
+	return opts
+}
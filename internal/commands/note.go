@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// NoteCommand shows or sets an environment's note
+type NoteCommand struct {
+	envManager *environment.Manager
+}
+
+// NewNoteCommand creates a new note command
+func NewNoteCommand(envManager *environment.Manager) *NoteCommand {
+	return &NoteCommand{envManager: envManager}
+}
+
+// Execute runs the note command: with text it replaces the note, with
+// --clear it removes it, and otherwise it prints it
+func (c *NoteCommand) Execute(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: cc-buddy note <environment-name> [\"text\" | --clear]")
+	}
+	envName := args[0]
+
+	if len(args) == 1 {
+		env, err := c.envManager.GetConfig().GetEnvironment(envName)
+		if err != nil {
+			return fmt.Errorf("environment '%s' not found", envName)
+		}
+		if env.Note != "" {
+			fmt.Println(env.Note)
+		}
+		return nil
+	}
+
+	note := strings.Join(args[1:], " ")
+	if len(args) == 2 && args[1] == "--clear" {
+		note = ""
+	}
+	if err := c.envManager.SetNote(envName, note); err != nil {
+		return err
+	}
+	if note == "" {
+		fmt.Printf("✅ Cleared the note of '%s'\n", envName)
+	} else {
+		fmt.Printf("✅ Noted '%s': %s\n", envName, strings.TrimSpace(note))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+	"github.com/jhjaggars/cc-buddy/internal/utils"
+)
+
+// OpenCommand opens an environment in the editor, file manager or browser
+type OpenCommand struct {
+	envManager *environment.Manager
+}
+
+// NewOpenCommand creates a new open command
+func NewOpenCommand(envManager *environment.Manager) *OpenCommand {
+	return &OpenCommand{envManager: envManager}
+}
+
+// Execute runs the open command: the worktree in the editor (the default)
+// or file manager, or the environment's web service in the browser
+func (c *OpenCommand) Execute(ctx context.Context, args []string) error {
+	usage := fmt.Errorf("usage: cc-buddy open <environment-name> [--editor | --files | --browser]")
+
+	var envName string
+	target := "--editor"
+	for _, arg := range args {
+		switch arg {
+		case "--editor", "--files", "--browser":
+			target = arg
+		default:
+			if envName != "" || len(arg) > 0 && arg[0] == '-' {
+				return usage
+			}
+			envName = arg
+		}
+	}
+	if envName == "" {
+		return usage
+	}
+
+	env, err := c.envManager.GetConfig().GetEnvironment(envName)
+	if err != nil {
+		return fmt.Errorf("environment '%s' not found", envName)
+	}
+
+	switch target {
+	case "--files":
+		fmt.Printf("📂 Opening %s\n", env.WorktreePath)
+		return utils.OpenWithDesktop(env.WorktreePath)
+	case "--browser":
+		url, err := c.envManager.PrimaryURL(ctx, envName)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("🌐 Opening %s\n", url)
+		return utils.OpenWithDesktop(url)
+	default:
+		return c.envManager.OpenEditor(envName)
+	}
+}
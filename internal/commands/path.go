@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// PathCommand prints an environment's worktree path for shell integration
+type PathCommand struct {
+	envManager *environment.Manager
+}
+
+// NewPathCommand creates a new path command
+func NewPathCommand(envManager *environment.Manager) *PathCommand {
+	return &PathCommand{envManager: envManager}
+}
+
+// Execute prints the worktree path and nothing else, so that
+// cd "$(cc-buddy path <env>)" works
+func (c *PathCommand) Execute(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: cc-buddy path <environment-name>")
+	}
+	env, err := c.envManager.GetConfig().GetEnvironment(args[0])
+	if err != nil {
+		return fmt.Errorf("environment '%s' not found", args[0])
+	}
+	fmt.Println(env.WorktreePath)
+	return nil
+}
+
+// shellInitScripts define ccd, which changes to an environment's worktree
+var shellInitScripts = map[string]string{
+	"bash": `# cc-buddy: ccd <env-name> changes to the environment's worktree
+ccd() {
+  local dir
+  dir="$(cc-buddy path "$@")" && cd "$dir"
+}
+`,
+	"zsh": `# cc-buddy: ccd <env-name> changes to the environment's worktree
+ccd() {
+  local dir
+  dir="$(cc-buddy path "$@")" && cd "$dir"
+}
+`,
+	"fish": `# cc-buddy: ccd <env-name> changes to the environment's worktree
+function ccd
+    set -l dir (cc-buddy path $argv); and cd $dir
+end
+`,
+}
+
+// ShellInitCommand prints shell functions for integrating cc-buddy
+type ShellInitCommand struct{}
+
+// NewShellInitCommand creates a new shell-init command
+func NewShellInitCommand() *ShellInitCommand {
+	return &ShellInitCommand{}
+}
+
+// Execute prints the script for the shell, meant to be evaluated from the
+// shell's startup file
+func (c *ShellInitCommand) Execute(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: cc-buddy shell-init <bash|zsh|fish>")
+	}
+	script, ok := shellInitScripts[args[0]]
+	if !ok {
+		return fmt.Errorf("unsupported shell '%s'; use bash, zsh or fish", args[0])
+	}
+	fmt.Print(script)
+	return nil
+}
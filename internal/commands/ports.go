@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jhjaggars/cc-buddy/internal/ci"
+	"github.com/jhjaggars/cc-buddy/internal/container"
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// PortsCommand lists the host ports an environment's container publishes
+type PortsCommand struct {
+	envManager *environment.Manager
+}
+
+// NewPortsCommand creates a new ports command
+func NewPortsCommand(envManager *environment.Manager) *PortsCommand {
+	return &PortsCommand{envManager: envManager}
+}
+
+// Execute runs the ports command: the running container's current
+// mappings, one per line as container/protocol -> host address, or as JSON
+func (c *PortsCommand) Execute(ctx context.Context, args []string) error {
+	usage := fmt.Errorf("usage: cc-buddy ports <environment-name> [--json]")
+	var envName string
+	useJSON := false
+	for _, arg := range args {
+		switch {
+		case arg == "--json":
+			useJSON = true
+		case envName == "" && len(arg) > 0 && arg[0] != '-':
+			envName = arg
+		default:
+			return usage
+		}
+	}
+	if envName == "" {
+		return usage
+	}
+
+	ports, err := c.envManager.RunningPorts(ctx, envName)
+	if err != nil {
+		return err
+	}
+	if useJSON {
+		if ports == nil {
+			ports = []container.PortMapping{}
+		}
+		encoder := json.NewEncoder(ci.Stdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(ports)
+	}
+
+	if len(ports) == 0 {
+		fmt.Printf("%s publishes no ports; create it with --port or --expose-all\n", envName)
+		return nil
+	}
+	for _, port := range ports {
+		fmt.Printf("%d/%s -> %s\n", port.Container, port.Protocol, port.HostAddress())
+	}
+	return nil
+}
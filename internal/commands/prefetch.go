@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// PrefetchCommand pulls the base images of the Containerfile ahead of time
+type PrefetchCommand struct {
+	envManager *environment.Manager
+}
+
+// NewPrefetchCommand creates a new prefetch command
+func NewPrefetchCommand(envManager *environment.Manager) *PrefetchCommand {
+	return &PrefetchCommand{envManager: envManager}
+}
+
+// Execute runs the prefetch command
+func (c *PrefetchCommand) Execute(ctx context.Context, args []string) error {
+	containerfile := ""
+	quiet := false
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--containerfile":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--containerfile flag requires a value")
+			}
+			i++
+			containerfile = args[i]
+		case "-q", "--quiet":
+			quiet = true
+		default:
+			return fmt.Errorf("usage: cc-buddy prefetch [--containerfile path] [--quiet]")
+		}
+	}
+
+	images, err := c.envManager.PrefetchImages(containerfile)
+	if err != nil {
+		return err
+	}
+	if len(images) == 0 {
+		if !quiet {
+			fmt.Println("The Containerfile has no base images to pull.")
+		}
+		return nil
+	}
+
+	failed := 0
+	for _, image := range images {
+		began := time.Now()
+		if err := c.envManager.PullImage(ctx, image); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			failed++
+			continue
+		}
+		if !quiet {
+			fmt.Printf("✅ %s (%s)\n", image, time.Since(began).Round(time.Second))
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to pull %d of %d base images", failed, len(images))
+	}
+	return nil
+}
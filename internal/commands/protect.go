@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// ProtectCommand marks environments as protected against deletion, or
+// removes the mark
+type ProtectCommand struct {
+	envManager *environment.Manager
+	protect    bool // false for unprotect
+}
+
+// NewProtectCommand creates a protect command, or an unprotect command when
+// protect is false
+func NewProtectCommand(envManager *environment.Manager, protect bool) *ProtectCommand {
+	return &ProtectCommand{envManager: envManager, protect: protect}
+}
+
+// Execute runs the protect or unprotect command
+func (c *ProtectCommand) Execute(ctx context.Context, args []string) error {
+	name := "protect"
+	if !c.protect {
+		name = "unprotect"
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("usage: cc-buddy %s <environment-name>...", name)
+	}
+
+	for _, envName := range args {
+		if err := c.envManager.SetProtected(envName, c.protect); err != nil {
+			return err
+		}
+		if c.protect {
+			fmt.Printf("🔒 Protected '%s'; delete now requires --force\n", envName)
+		} else {
+			fmt.Printf("🔓 Unprotected '%s'\n", envName)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// ProxyCommand manages the companion reverse proxy container
+type ProxyCommand struct {
+	envManager *environment.Manager
+}
+
+// NewProxyCommand creates a new proxy command
+func NewProxyCommand(envManager *environment.Manager) *ProxyCommand {
+	return &ProxyCommand{envManager: envManager}
+}
+
+// Execute runs the proxy command
+func (c *ProxyCommand) Execute(ctx context.Context, args []string) error {
+	action := "status"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "status":
+		return c.status(ctx)
+	case "start":
+		if err := c.envManager.EnsureProxy(ctx); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Proxy %s is running\n", environment.ProxyContainerName)
+		return nil
+	case "stop":
+		if err := c.envManager.StopProxy(ctx); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Proxy %s stopped\n", environment.ProxyContainerName)
+		return nil
+	default:
+		return fmt.Errorf("usage: cc-buddy proxy [status|start|stop]")
+	}
+}
+
+// status prints whether the proxy is running and the routes it serves
+func (c *ProxyCommand) status(ctx context.Context) error {
+	if c.envManager.ProxyRunning(ctx) {
+		fmt.Printf("Proxy: running (%s)\n", environment.ProxyContainerName)
+	} else {
+		fmt.Println("Proxy: not running")
+	}
+	if !c.envManager.GetConfig().GetConfig().Proxy {
+		fmt.Println("Routing is disabled; set \"proxy\": true in .cc-buddy/config.json to enable it.")
+	}
+
+	var routed int
+	for _, env := range c.envManager.GetConfig().Environments() {
+		if url := c.envManager.ProxyURL(env); url != "" {
+			if routed == 0 {
+				fmt.Println("\nRoutes:")
+			}
+			fmt.Printf("  %-30s %s\n", env.Name, url)
+			routed++
+		}
+	}
+	if routed == 0 {
+		fmt.Println("No environments are routed through the proxy.")
+	}
+
+	return nil
+}
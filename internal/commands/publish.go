@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// PublishCommand pushes an environment's image to a registry
+type PublishCommand struct {
+	envManager *environment.Manager
+}
+
+// NewPublishCommand creates a new publish command
+func NewPublishCommand(envManager *environment.Manager) *PublishCommand {
+	return &PublishCommand{envManager: envManager}
+}
+
+// Execute runs the publish command
+func (c *PublishCommand) Execute(ctx context.Context, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: cc-buddy publish <environment-name> <image-ref>")
+	}
+	envName, ref := args[0], args[1]
+
+	fmt.Printf("Publishing environment '%s' as %s...\n", envName, ref)
+	if err := c.envManager.PublishEnvironment(ctx, envName, ref); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Published %s\n", ref)
+	fmt.Printf("   Create from it: cc-buddy create <branch-name> --image %s\n", ref)
+	return nil
+}
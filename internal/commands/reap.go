@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// ReapCommand stops environments nobody is using
+type ReapCommand struct {
+	envManager *environment.Manager
+}
+
+// NewReapCommand creates a new reap command
+func NewReapCommand(envManager *environment.Manager) *ReapCommand {
+	return &ReapCommand{envManager: envManager}
+}
+
+// Execute runs the reap command: it stops (never deletes) the running
+// environments idle for stop_after_idle or --after
+func (c *ReapCommand) Execute(ctx context.Context, args []string) error {
+	usage := "usage: cc-buddy reap --idle [--after 4h] [--dry-run]"
+	idle := false
+	dryRun := false
+	after, err := c.envManager.StopAfterIdle()
+	if err != nil {
+		return fmt.Errorf("stop_after_idle: %w", err)
+	}
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--idle":
+			idle = true
+		case "--dry-run":
+			dryRun = true
+		case "--after":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--after flag requires a duration")
+			}
+			i++
+			if after, err = environment.ParseIdleDuration(args[i]); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%s", usage)
+		}
+	}
+	if !idle {
+		return fmt.Errorf("%s", usage)
+	}
+	if after == 0 {
+		return fmt.Errorf("no idle timeout: set stop_after_idle in .cc-buddy/config.json or pass --after")
+	}
+
+	environments, err := c.envManager.IdleEnvironments(ctx, after)
+	if err != nil {
+		return err
+	}
+	if len(environments) == 0 {
+		fmt.Printf("No environments have been idle for %s.\n", after)
+		return nil
+	}
+
+	fmt.Printf("%-25s %-10s %s\n", "NAME", "IDLE", "CPU")
+	fmt.Printf("%s\n", strings.Repeat("-", 45))
+	for _, env := range environments {
+		fmt.Printf("%-25s %-10s %.1f%%\n", env.Name, formatUptime(env.IdleFor), env.CPUPercent)
+	}
+	fmt.Println()
+	if dryRun {
+		fmt.Printf("Dry run: %d environment(s) would be stopped.\n", len(environments))
+		return nil
+	}
+
+	failed := 0
+	for _, env := range environments {
+		if err := c.envManager.StopEnvironment(ctx, env.Name); err != nil {
+			fmt.Printf("❌ %s: %v\n", env.Name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("⏹  Stopped %s (start it again with 'cc-buddy start %s')\n", env.Name, env.Name)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d environments failed to stop", failed, len(environments))
+	}
+	return nil
+}
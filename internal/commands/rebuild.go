@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// RebuildCommand rebuilds an environment's image and replaces its container
+type RebuildCommand struct {
+	envManager *environment.Manager
+}
+
+// NewRebuildCommand creates a new rebuild command
+func NewRebuildCommand(envManager *environment.Manager) *RebuildCommand {
+	return &RebuildCommand{envManager: envManager}
+}
+
+// Execute runs the rebuild command
+func (c *RebuildCommand) Execute(ctx context.Context, args []string) error {
+	var envName string
+	repin := false
+	for _, arg := range args {
+		switch {
+		case arg == "--repin":
+			repin = true
+		case envName == "" && len(arg) > 0 && arg[0] != '-':
+			envName = arg
+		default:
+			return fmt.Errorf("usage: cc-buddy rebuild <environment-name> [--repin]")
+		}
+	}
+	if envName == "" {
+		return fmt.Errorf("usage: cc-buddy rebuild <environment-name> [--repin]")
+	}
+
+	if repin {
+		// The rebuild resolves the FROM tags again and pins the result
+		if err := c.envManager.UnpinBaseImages(envName); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Rebuilding environment '%s'...\n", envName)
+	if err := c.envManager.RebuildEnvironment(ctx, envName); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Rebuilt environment '%s'\n", envName)
+	return nil
+}
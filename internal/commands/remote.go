@@ -0,0 +1,248 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/ci"
+	"github.com/jhjaggars/cc-buddy/internal/client"
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// RemoteCommands are the commands that work against a daemon with --host
+var RemoteCommands = []string{"list", "create", "delete", "exec", "start", "rebuild"}
+
+// RemoteCommand runs commands against the environments of a cc-buddy
+// daemon on another machine, through its API
+type RemoteCommand struct {
+	client *client.Client
+}
+
+// NewRemoteCommand creates a command that talks to the daemon c reaches
+func NewRemoteCommand(c *client.Client) *RemoteCommand {
+	return &RemoteCommand{client: c}
+}
+
+// Execute runs command with args on the daemon
+func (c *RemoteCommand) Execute(ctx context.Context, command string, args []string) error {
+	switch command {
+	case "list":
+		return c.list(ctx, args)
+	case "create":
+		return c.create(ctx, args)
+	case "delete":
+		return c.delete(ctx, args)
+	case "exec":
+		return c.exec(ctx, args)
+	case "start":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: cc-buddy start <environment-name>")
+		}
+		if err := c.client.Start(ctx, args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Started environment '%s' on %s\n", args[0], c.client.Host())
+		return nil
+	case "rebuild":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: cc-buddy rebuild <environment-name>")
+		}
+		fmt.Printf("Rebuilding environment '%s' on %s...\n", args[0], c.client.Host())
+		if err := c.client.Rebuild(ctx, args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Rebuilt environment '%s'\n", args[0])
+		return nil
+	case "terminal":
+		return fmt.Errorf("terminal is not available with --host: remote commands have no terminal or stdin; run cc-buddy terminal on %s", c.client.Host())
+	}
+	return fmt.Errorf("'%s' is not available with --host; remote commands are %s", command, strings.Join(RemoteCommands, ", "))
+}
+
+// list prints the daemon's environments as list --plain or --json does
+func (c *RemoteCommand) list(ctx context.Context, args []string) error {
+	useJSON := false
+	for _, arg := range args {
+		switch arg {
+		case "--json":
+			useJSON = true
+		case "--plain":
+		default:
+			return fmt.Errorf("usage: cc-buddy --host <host> list [--plain|--json]")
+		}
+	}
+
+	environments, err := c.client.List(ctx)
+	if err != nil {
+		return err
+	}
+	if useJSON {
+		if environments == nil {
+			environments = []config.Environment{} // [] rather than null
+		}
+		encoder := json.NewEncoder(ci.Stdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(environments)
+	}
+
+	if len(environments) == 0 {
+		fmt.Printf("No environments found on %s.\n", c.client.Host())
+		return nil
+	}
+	fmt.Printf("Environments on %s (%d):\n\n", c.client.Host(), len(environments))
+	fmt.Printf("%-25s %-20s %-10s %-15s %s\n", "NAME", "BRANCH", "STATUS", "CREATED", "PORTS")
+	fmt.Printf("%s\n", strings.Repeat("-", 86))
+	for _, env := range environments {
+		status := getStatusDisplay(env.Status, env.Health)
+		if env.Protected {
+			status += " 🔒"
+		}
+		ports := "-"
+		if len(env.Ports) > 0 {
+			ports = strings.Join(env.Ports, ",")
+		}
+		fmt.Printf("%-25s %-20s %-10s %-15s %s\n", env.Name, env.Branch, status, formatTimeAgo(env.Created), ports)
+	}
+	return nil
+}
+
+// create creates one environment on the daemon's machine
+func (c *RemoteCommand) create(ctx context.Context, args []string) error {
+	var opts client.CreateOptions
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--no-start":
+			opts.NoStart = true
+		case arg == "--wait":
+			opts.Wait = true
+		case arg == "--wait-port":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--wait-port flag requires a port argument")
+			}
+			i++
+			port, err := strconv.Atoi(args[i])
+			if err != nil || port <= 0 || port > 65535 {
+				return fmt.Errorf("invalid --wait-port: %s", args[i])
+			}
+			opts.WaitPort = port
+		case arg == "--label":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--label flag requires a key=value argument")
+			}
+			i++
+			key, value, err := environment.ParseLabel(args[i])
+			if err != nil {
+				return err
+			}
+			if opts.Labels == nil {
+				opts.Labels = map[string]string{}
+			}
+			opts.Labels[key] = value
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("%s is not available with --host; remote creates take --label, --no-start, --wait and --wait-port", arg)
+		case opts.Branch != "":
+			return fmt.Errorf("remote creates take one branch")
+		default:
+			opts.Branch = arg
+		}
+	}
+	if opts.Branch == "" {
+		return fmt.Errorf("usage: cc-buddy --host <host> create <branch> [--label k=v] [--no-start] [--wait] [--wait-port <port>]")
+	}
+
+	fmt.Printf("Creating environment for '%s' on %s...\n", opts.Branch, c.client.Host())
+	env, err := c.client.Create(ctx, opts)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ Environment '%s' created\n", env.Name)
+	fmt.Printf("   cc-buddy --host %s exec %s -- <command>\n", c.client.Host(), env.Name)
+	return nil
+}
+
+// delete deletes environments on the daemon's machine without asking
+func (c *RemoteCommand) delete(ctx context.Context, args []string) error {
+	force := false
+	var names []string
+	for _, arg := range args {
+		switch {
+		case arg == "--force":
+			force = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("%s is not available with --host", arg)
+		default:
+			names = append(names, arg)
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("usage: cc-buddy --host <host> delete <environment-name>... [--force]")
+	}
+
+	for _, name := range names {
+		if err := c.client.Delete(ctx, name, force); err != nil {
+			return fmt.Errorf("failed to delete '%s': %w", name, err)
+		}
+		fmt.Printf("✅ Deleted environment '%s'\n", name)
+	}
+	return nil
+}
+
+// exec runs a command without a terminal or stdin, streaming its output,
+// and exits with its status; --output json prints it all once it finishes
+func (c *RemoteCommand) exec(ctx context.Context, args []string) error {
+	usage := fmt.Errorf("usage: cc-buddy --host <host> exec <environment-name> [--output json] -- <command> [args...]")
+	var envName, output string
+	var command []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			command = args[i+1:]
+			i = len(args)
+		case arg == "--output":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--output flag requires a format")
+			}
+			i++
+			output = args[i]
+		case strings.HasPrefix(arg, "--output="):
+			output = strings.TrimPrefix(arg, "--output=")
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("%s is not available with --host", arg)
+		case envName == "":
+			envName = arg
+		default:
+			return usage
+		}
+	}
+	if envName == "" || len(command) == 0 {
+		return usage
+	}
+	if output != "" && output != "json" {
+		return fmt.Errorf("invalid --output %q: only json is supported", output)
+	}
+
+	if output == "json" {
+		result, err := c.client.Exec(ctx, envName, command)
+		if err != nil {
+			return fmt.Errorf("failed to execute command: %w", err)
+		}
+		encoder := json.NewEncoder(ci.Stdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	code, err := c.client.ExecStream(ctx, envName, command, ci.Stdout(), ci.Stderr())
+	if err != nil {
+		return fmt.Errorf("failed to execute command: %w", err)
+	}
+	if code != 0 {
+		return &ExitError{Code: code}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/jhjaggars/cc-buddy/internal/ci"
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// RunCommand runs one command in a throwaway environment for a branch
+type RunCommand struct {
+	envManager *environment.Manager
+}
+
+// NewRunCommand creates a new run command
+func NewRunCommand(envManager *environment.Manager) *RunCommand {
+	return &RunCommand{envManager: envManager}
+}
+
+// Execute runs the run command: create an environment with the branch's
+// commit checked out detached, run the command in it with output streamed,
+// then delete the environment whatever happened
+func (c *RunCommand) Execute(ctx context.Context, args []string) error {
+	usage := fmt.Errorf("usage: cc-buddy run <branch> [--containerfile path] -- <command> [args...]")
+
+	separatorIndex := -1
+	for i, arg := range args {
+		if arg == "--" {
+			separatorIndex = i
+			break
+		}
+	}
+	if separatorIndex == -1 || separatorIndex == len(args)-1 {
+		return usage
+	}
+
+	var branchName, containerfile string
+	for i := 0; i < separatorIndex; i++ {
+		arg := args[i]
+		if arg == "--containerfile" {
+			if i+1 >= separatorIndex {
+				return fmt.Errorf("--containerfile flag requires a path argument")
+			}
+			i++
+			containerfile = args[i]
+		} else if strings.HasPrefix(arg, "-") {
+			return fmt.Errorf("unknown run option: %s", arg)
+		} else if branchName == "" {
+			branchName = arg
+		} else {
+			return fmt.Errorf("only one branch is allowed before '--'")
+		}
+	}
+	if branchName == "" {
+		return usage
+	}
+	command := args[separatorIndex+1:]
+
+	// A throwaway run must not leave a new branch behind
+	gitOps := c.envManager.GetGitOperations()
+	opts := branchOptions(gitOps, branchName, environment.CreateEnvironmentOptions{Containerfile: containerfile, Throwaway: true})
+	if !opts.IsRemoteBranch {
+		exists, err := gitOps.BranchExists(ctx, opts.BranchName)
+		if err != nil {
+			return fmt.Errorf("failed to check branch: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("branch %s does not exist; run only checks out existing branches", opts.BranchName)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, endSections := ci.WithSections(ctx)
+	defer endSections()
+
+	// Everything cc-buddy prints goes to stderr so stdout carries only the
+	// command's output
+	var env *config.Environment
+	err := toStderr(func() error {
+		fmt.Printf("Creating temporary environment for %s...\n", branchName)
+		var err error
+		env, err = c.envManager.CreateEnvironment(ctx, opts)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create environment: %w", err)
+	}
+	defer toStderr(func() error {
+		// Ctrl+C cancels ctx, and the environment must still go
+		ci.Section(ctx, "removing temporary environment")
+		fmt.Printf("Removing temporary environment '%s'...\n", env.Name)
+		if err := c.envManager.DiscardEnvironment(context.WithoutCancel(ctx), env.Name); err != nil {
+			fmt.Printf("Warning: failed to remove temporary environment '%s': %v\n", env.Name, err)
+		}
+		return nil
+	})
+
+	err = toStderr(func() error {
+		return c.envManager.WaitForReady(ctx, env.Name, 0, environment.DefaultWaitTimeout)
+	})
+	if err != nil {
+		return fmt.Errorf("environment '%s' did not become ready: %w", env.Name, err)
+	}
+
+	toStderr(func() error {
+		ci.Section(ctx, "running "+strings.Join(command, " "))
+		return nil
+	})
+	if useTTY() {
+		err = c.envManager.ExecuteCommand(ctx, env.Name, command, true)
+	} else {
+		err = c.envManager.AttachCommand(ctx, env.Name, command)
+	}
+	if err != nil {
+		if exitErr := commandExitError(err); exitErr != nil && ctx.Err() == nil {
+			return exitErr
+		}
+		return fmt.Errorf("failed to run command: %w", err)
+	}
+	return nil
+}
+
+// toStderr runs fn with os.Stdout pointed at stderr, so the progress and
+// notices it prints stay out of the command's output
+func toStderr(fn func() error) error {
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = stdout }()
+	return fn()
+}
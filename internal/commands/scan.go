@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/ci"
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// scanListLimit caps the findings scan prints without --all
+const scanListLimit = 20
+
+// ScanCommand scans an environment's image for vulnerabilities
+type ScanCommand struct {
+	envManager *environment.Manager
+}
+
+// NewScanCommand creates a new scan command
+func NewScanCommand(envManager *environment.Manager) *ScanCommand {
+	return &ScanCommand{envManager: envManager}
+}
+
+// Execute runs the scan command: severity counts, then the critical
+// findings (all of them with --all)
+func (c *ScanCommand) Execute(ctx context.Context, args []string) error {
+	usage := fmt.Errorf("usage: cc-buddy scan <environment-name> [--all] [--json]")
+	var envName string
+	all, useJSON := false, false
+	for _, arg := range args {
+		switch {
+		case arg == "--all":
+			all = true
+		case arg == "--json":
+			useJSON = true
+		case envName == "" && len(arg) > 0 && arg[0] != '-':
+			envName = arg
+		default:
+			return usage
+		}
+	}
+	if envName == "" {
+		return usage
+	}
+
+	if !useJSON {
+		fmt.Printf("Scanning the image of '%s'...\n", envName)
+	}
+	result, err := c.envManager.ScanEnvironment(ctx, envName)
+	if err != nil {
+		return err
+	}
+	if useJSON {
+		encoder := json.NewEncoder(ci.Stdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	fmt.Printf("%s (%s): ", result.Image, result.Scanner)
+	var counts []string
+	for _, severity := range []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"} {
+		counts = append(counts, fmt.Sprintf("%d %s", result.Counts[severity], strings.ToLower(severity)))
+	}
+	fmt.Println(strings.Join(counts, ", "))
+
+	shown := 0
+	for _, finding := range result.Findings {
+		if !all && finding.Severity != "CRITICAL" {
+			break
+		}
+		if !all && shown == scanListLimit {
+			fmt.Printf("  ... and %d more; use --all to list them\n", result.Counts["CRITICAL"]-shown)
+			break
+		}
+		fixed := "no fix yet"
+		if finding.FixedVersion != "" {
+			fixed = "fixed in " + finding.FixedVersion
+		}
+		fmt.Printf("  %-9s %-20s %s %s (%s)\n", finding.Severity, finding.ID, finding.Package, finding.Version, fixed)
+		shown++
+	}
+	if result.Counts["CRITICAL"] == 0 && !all {
+		fmt.Println("✅ No critical findings")
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// StartCommand starts a stopped environment or one created with --no-start
+type StartCommand struct {
+	envManager *environment.Manager
+}
+
+// NewStartCommand creates a new start command
+func NewStartCommand(envManager *environment.Manager) *StartCommand {
+	return &StartCommand{envManager: envManager}
+}
+
+// Execute runs the start command
+func (c *StartCommand) Execute(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: cc-buddy start <environment-name>")
+	}
+	envName := args[0]
+
+	if err := c.envManager.StartEnvironment(ctx, envName); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Started environment '%s'\n", envName)
+	fmt.Printf("   cc-buddy terminal %s\n", envName)
+	return nil
+}
@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// SwitchCommand mounts an environment's worktree in the repository's shared
+// container
+type SwitchCommand struct {
+	envManager *environment.Manager
+}
+
+// NewSwitchCommand creates a new switch command
+func NewSwitchCommand(envManager *environment.Manager) *SwitchCommand {
+	return &SwitchCommand{envManager: envManager}
+}
+
+// Execute runs the switch command
+func (c *SwitchCommand) Execute(ctx context.Context, args []string) error {
+	var envName string
+	rebuild := false
+	for _, arg := range args {
+		switch {
+		case arg == "--rebuild":
+			rebuild = true
+		case envName == "" && len(arg) > 0 && arg[0] != '-':
+			envName = arg
+		default:
+			return fmt.Errorf("usage: cc-buddy switch <environment-name> [--rebuild]")
+		}
+	}
+	if envName == "" {
+		return fmt.Errorf("usage: cc-buddy switch <environment-name> [--rebuild]")
+	}
+
+	fmt.Printf("Switching the shared container to '%s'...\n", envName)
+	if err := c.envManager.SwitchSharedContainer(ctx, envName, rebuild); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ %s is mounted at /workspace\n", envName)
+	fmt.Printf("   Open a terminal: cc-buddy terminal %s\n", envName)
+	return nil
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// SyncCommand copies worktrees to and from sync-mode environments
+type SyncCommand struct {
+	envManager *environment.Manager
+}
+
+// NewSyncCommand creates a new sync command
+func NewSyncCommand(envManager *environment.Manager) *SyncCommand {
+	return &SyncCommand{envManager: envManager}
+}
+
+// Execute runs the sync command
+func (c *SyncCommand) Execute(ctx context.Context, args []string) error {
+	var envName string
+	pull := false
+
+	for _, arg := range args {
+		if arg == "--pull" {
+			pull = true
+		} else if envName == "" {
+			envName = arg
+		} else {
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+
+	if envName == "" {
+		return fmt.Errorf("usage: cc-buddy sync <environment-name> [--pull]")
+	}
+
+	if err := c.envManager.SyncWorkspace(ctx, envName, pull); err != nil {
+		return err
+	}
+
+	if pull {
+		fmt.Printf("✅ Copied /workspace from '%s' into the worktree\n", envName)
+	} else {
+		fmt.Printf("✅ Copied the worktree into '%s' /workspace\n", envName)
+	}
+	return nil
+}
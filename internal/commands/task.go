@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// TaskCommand runs a task defined in the configuration inside an environment
+type TaskCommand struct {
+	envManager *environment.Manager
+}
+
+// NewTaskCommand creates a new task command
+func NewTaskCommand(envManager *environment.Manager) *TaskCommand {
+	return &TaskCommand{envManager: envManager}
+}
+
+// Execute runs the task command: with a task name it runs that task, passing
+// on any further arguments, and otherwise it lists the tasks
+func (c *TaskCommand) Execute(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: cc-buddy task <environment-name> [task [args...]]")
+	}
+	envName := args[0]
+
+	if len(args) == 1 {
+		names := c.envManager.TaskNames()
+		if len(names) == 0 {
+			fmt.Println("No tasks configured; add them under \"tasks\" in .cc-buddy/config.json")
+			return nil
+		}
+		width := 0
+		for _, name := range names {
+			width = max(width, len(name))
+		}
+		tasks := c.envManager.GetConfig().GetConfig().Tasks
+		for _, name := range names {
+			fmt.Printf("%-*s  %s\n", width, name, tasks[name])
+		}
+		return nil
+	}
+
+	taskArgs := args[2:]
+	if len(taskArgs) > 0 && taskArgs[0] == "--" {
+		taskArgs = taskArgs[1:]
+	}
+	command, err := c.envManager.TaskCommand(args[1], taskArgs)
+	if err != nil {
+		return err
+	}
+
+	if useTTY() {
+		err = c.envManager.ExecuteCommand(ctx, envName, command, true)
+	} else {
+		err = c.envManager.AttachCommand(ctx, envName, command)
+	}
+	if err != nil {
+		if exitErr := commandExitError(err); exitErr != nil {
+			return exitErr
+		}
+		return fmt.Errorf("failed to run task %s: %w", args[1], err)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package commands
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/jhjaggars/cc-buddy/internal/environment"
 )
@@ -19,11 +20,30 @@ func NewTerminalCommand(envManager *environment.Manager) *TerminalCommand {
 
 // Execute runs the terminal command
 func (c *TerminalCommand) Execute(ctx context.Context, args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("usage: cc-buddy terminal <environment-name>")
+	var envName string
+	var opts environment.TerminalOptions
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--fresh" {
+			opts.Fresh = true
+		} else if arg == "--session" {
+			if i+1 >= len(args) {
+				return fmt.Errorf("--session flag requires a name")
+			}
+			i++
+			opts.Session = args[i]
+		} else if envName == "" && !strings.HasPrefix(arg, "-") {
+			envName = arg
+		} else {
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	if envName == "" {
+		return fmt.Errorf("usage: cc-buddy terminal <environment-name> [--session <name>] [--fresh]")
+	}
+	if opts.Fresh && opts.Session != "" {
+		return fmt.Errorf("--fresh cannot be combined with --session")
 	}
-
-	envName := args[0]
 
 	// Check if environment exists
 	env, err := c.envManager.GetConfig().GetEnvironment(envName)
@@ -37,9 +57,9 @@ func (c *TerminalCommand) Execute(ctx context.Context, args []string) error {
 	fmt.Println()
 
 	// Open terminal
-	if err := c.envManager.OpenTerminal(ctx, envName); err != nil {
+	if err := c.envManager.OpenTerminal(ctx, envName, opts); err != nil {
 		return fmt.Errorf("failed to open terminal: %w", err)
 	}
 
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhjaggars/cc-buddy/internal/container"
+	"github.com/jhjaggars/cc-buddy/internal/version"
+)
+
+// VersionCommand prints build metadata and runtime versions for bug reports
+type VersionCommand struct{}
+
+// NewVersionCommand creates a new version command. It needs no environment
+// manager so it works outside a repository and without a container runtime.
+func NewVersionCommand() *VersionCommand {
+	return &VersionCommand{}
+}
+
+// Execute runs the version command
+func (c *VersionCommand) Execute(ctx context.Context, args []string) error {
+	short := false
+	for _, arg := range args {
+		switch arg {
+		case "--short":
+			short = true
+		default:
+			return fmt.Errorf("unexpected argument: %s\nusage: cc-buddy version [--short]", arg)
+		}
+	}
+
+	info := version.Get()
+	if short {
+		fmt.Println(info.Version)
+		return nil
+	}
+
+	commit := info.Commit
+	if info.Modified {
+		commit += " (modified)"
+	}
+	fmt.Printf("cc-buddy %s\n", info.Version)
+	fmt.Printf("  Commit:     %s\n", commit)
+	fmt.Printf("  Built:      %s\n", info.Date)
+	fmt.Printf("  Go version: %s\n", info.GoVersion)
+	fmt.Printf("  Platform:   %s\n", info.Platform)
+	fmt.Println()
+	fmt.Println("Container runtimes:")
+	for _, runtime := range container.DetectRuntimes(ctx) {
+		if runtime.Err != nil {
+			fmt.Printf("  %-10s not available\n", runtime.Name)
+			continue
+		}
+		fmt.Printf("  %-10s %s\n", runtime.Name, runtime.Version)
+	}
+	return nil
+}
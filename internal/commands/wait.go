@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// WaitCommand blocks until environments created with create --detach are ready
+type WaitCommand struct {
+	envManager *environment.Manager
+}
+
+// NewWaitCommand creates a new wait command
+func NewWaitCommand(envManager *environment.Manager) *WaitCommand {
+	return &WaitCommand{envManager: envManager}
+}
+
+// Execute waits for each named environment in turn
+func (c *WaitCommand) Execute(ctx context.Context, args []string) error {
+	var envNames []string
+	var timeout time.Duration
+	waitPort := c.envManager.GetConfig().GetConfig().WaitPort
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--timeout":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--timeout flag requires a duration argument")
+			}
+			i++
+			value, err := environment.ParseWaitTimeout(args[i])
+			if err != nil {
+				return err
+			}
+			timeout = value
+		case "--wait-port":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--wait-port flag requires a port argument")
+			}
+			i++
+			port, err := strconv.Atoi(args[i])
+			if err != nil || port <= 0 || port > 65535 {
+				return fmt.Errorf("invalid --wait-port: %s", args[i])
+			}
+			waitPort = port
+		default:
+			envNames = append(envNames, args[i])
+		}
+	}
+	if len(envNames) == 0 {
+		return fmt.Errorf("usage: cc-buddy wait <environment-name>... [--timeout <duration>] [--wait-port <port>]")
+	}
+
+	// One deadline covers every environment; without --timeout creates may
+	// take as long as they need and readiness gets the usual default
+	readyTimeout := environment.DefaultWaitTimeout
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+		readyTimeout = timeout
+	}
+
+	for _, envName := range envNames {
+		fmt.Printf("Waiting for '%s'...\n", envName)
+		if err := c.envManager.WaitForCreate(ctx, envName); err != nil {
+			return fmt.Errorf("environment '%s': %w", envName, err)
+		}
+
+		env, err := c.envManager.GetConfig().GetEnvironment(envName)
+		if err != nil {
+			return fmt.Errorf("environment '%s': %w", envName, err)
+		}
+		if env.Status == "created" {
+			// Created with --no-start; there is nothing running to check
+			fmt.Printf("✅ Environment '%s' is created (start it with 'cc-buddy start %s')\n", envName, envName)
+			continue
+		}
+		if err := c.envManager.WaitForReady(ctx, envName, waitPort, readyTimeout); err != nil {
+			return fmt.Errorf("environment '%s' was created but is not ready: %w", envName, err)
+		}
+		fmt.Printf("✅ Environment '%s' is ready\n", envName)
+	}
+	return nil
+}
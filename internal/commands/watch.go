@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// WatchCommand rebuilds an environment whenever its Containerfile changes
+type WatchCommand struct {
+	envManager *environment.Manager
+}
+
+// NewWatchCommand creates a new watch command
+func NewWatchCommand(envManager *environment.Manager) *WatchCommand {
+	return &WatchCommand{envManager: envManager}
+}
+
+// Execute watches until interrupted
+func (c *WatchCommand) Execute(ctx context.Context, args []string) error {
+	var envName string
+	var opts environment.WatchOptions
+	usage := fmt.Errorf("usage: cc-buddy watch <environment-name> [--path <path>]... [--debounce <duration>]")
+
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		if name != "--path" && name != "--debounce" {
+			if strings.HasPrefix(args[i], "-") || envName != "" {
+				return usage
+			}
+			envName = args[i]
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s requires a value", name)
+			}
+			value = args[i+1]
+			i++
+		}
+
+		if name == "--path" {
+			opts.Paths = append(opts.Paths, value)
+			continue
+		}
+		debounce, err := time.ParseDuration(value)
+		if err != nil || debounce <= 0 {
+			return fmt.Errorf("invalid --debounce %q (expected a duration like 2s)", value)
+		}
+		opts.Debounce = debounce
+	}
+	if envName == "" {
+		return usage
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := c.envManager.Watch(ctx, envName, opts, func(message string) {
+		fmt.Printf("[%s] %s\n", time.Now().Format("15:04:05"), message)
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println("Stopped watching.")
+	return nil
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 )
 
 const (
@@ -16,8 +19,12 @@ const (
 // Manager handles configuration and state persistence
 type Manager struct {
 	stateDir string
-	config   *Config
+	config   *Config // local config with cc-buddy.yaml applied
+	local    *Config // as stored in config.json
 	state    *State
+
+	// mu guards state; concurrent creates share a manager
+	mu sync.Mutex
 }
 
 // NewManager creates a new configuration manager
@@ -32,35 +39,114 @@ func NewManager() (*Manager, error) {
 	return &Manager{
 		stateDir: stateDir,
 		config:   DefaultConfig(),
+		local:    DefaultConfig(),
 		state:    &State{Environments: []Environment{}},
 	}, nil
 }
 
-// LoadConfig loads configuration from disk or creates default if not found
+// LoadConfig loads configuration from disk or creates default if not found,
+// then applies the repository's cc-buddy.yaml. config.yaml (or config.yml)
+// is read instead of config.json when present.
 func (m *Manager) LoadConfig() error {
-	configPath := filepath.Join(m.stateDir, ConfigFile)
+	configPath := findConfigFile(m.stateDir)
 	
 	data, err := os.ReadFile(configPath)
 	if os.IsNotExist(err) {
 		// Config doesn't exist, use defaults and save
-		return m.SaveConfig()
-	}
-	if err != nil {
+		if err := m.SaveConfig(); err != nil {
+			return err
+		}
+	} else if err != nil {
 		return fmt.Errorf("failed to read config file: %w", err)
+	} else if err := decodeConfig(configPath, data, m.local); err != nil {
+		return fmt.Errorf("invalid %s:\n  %w", configPath, err)
 	}
-	
-	if err := json.Unmarshal(data, m.config); err != nil {
-		return fmt.Errorf("failed to parse config file: %w", err)
+	if err := m.local.Validate(); err != nil {
+		return fmt.Errorf("invalid %s:\n  %w", configPath, err)
 	}
 	
+	return m.applyProject()
+}
+
+// applyProject recomputes the effective configuration from the local one,
+// cc-buddy.yaml and CC_BUDDY_* variables (which win), updating it in place
+// for holders of GetConfig
+func (m *Manager) applyProject() error {
+	project, err := LoadProjectConfig(filepath.Join(filepath.Dir(m.stateDir), ProjectFile))
+	if err != nil {
+		return err
+	}
+	effective := *m.local
+	if project != nil {
+		project.apply(&effective)
+	}
+	if err := applyProfile(&effective); err != nil {
+		return err
+	}
+	used, err := applyEnv(&effective)
+	if err != nil {
+		return fmt.Errorf("invalid %s* variables:\n  %w", EnvPrefix, err)
+	}
+	if len(used) > 0 {
+		if err := effective.Validate(); err != nil {
+			return fmt.Errorf("invalid settings with %s:\n  %w", strings.Join(used, ", "), err)
+		}
+	}
+	*m.config = effective
+	return nil
+}
+
+// applyProfile applies the settings of the selected profile, if any, to cfg.
+// CC_BUDDY_PROFILE (set by --profile) takes precedence over the profile
+// setting.
+func applyProfile(cfg *Config) error {
+	name := cfg.Profile
+	if value, ok := os.LookupEnv(ProfileEnv); ok {
+		name = value
+	}
+	if name == "" {
+		return nil
+	}
+	raw, ok := cfg.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(cfg.Profiles))
+		for profile := range cfg.Profiles {
+			names = append(names, profile)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return fmt.Errorf("unknown profile '%s': no profiles are configured", name)
+		}
+		return fmt.Errorf("unknown profile '%s' (available: %s)", name, strings.Join(names, ", "))
+	}
+
+	// Decode into a deep copy: cfg shares its maps with the local config,
+	// which must not pick up the profile's settings
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to copy config: %w", err)
+	}
+	profiled := &Config{}
+	if err := json.Unmarshal(data, profiled); err != nil {
+		return fmt.Errorf("failed to copy config: %w", err)
+	}
+	if err := json.Unmarshal(raw, profiled); err != nil {
+		return fmt.Errorf("invalid profile '%s': %w", name, typeProblem(err))
+	}
+	profiled.Profile, profiled.Profiles = name, cfg.Profiles
+	*cfg = *profiled
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid settings with profile '%s':\n  %w", name, err)
+	}
 	return nil
 }
 
-// SaveConfig saves current configuration to disk
+// SaveConfig saves the local configuration to disk; settings that come
+// from cc-buddy.yaml stay out of it
 func (m *Manager) SaveConfig() error {
-	configPath := filepath.Join(m.stateDir, ConfigFile)
+	configPath := findConfigFile(m.stateDir)
 	
-	data, err := json.MarshalIndent(m.config, "", "  ")
+	data, err := encodeConfig(configPath, m.local)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -72,8 +158,27 @@ func (m *Manager) SaveConfig() error {
 	return nil
 }
 
+// MergeConfig applies the settings in a JSON config fragment on top of the
+// current configuration and saves the result
+func (m *Manager) MergeConfig(data []byte) error {
+	if err := json.Unmarshal(data, m.local); err != nil {
+		return fmt.Errorf("failed to parse config: %w", err)
+	}
+	if err := m.SaveConfig(); err != nil {
+		return err
+	}
+	return m.applyProject()
+}
+
 // LoadState loads environment state from disk
 func (m *Manager) LoadState() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.loadState()
+}
+
+// loadState reads the state file; the caller must hold m.mu
+func (m *Manager) loadState() error {
 	statePath := filepath.Join(m.stateDir, EnvironmentsFile)
 	
 	data, err := os.ReadFile(statePath)
@@ -86,15 +191,26 @@ func (m *Manager) LoadState() error {
 		return fmt.Errorf("failed to read state file: %w", err)
 	}
 	
-	if err := json.Unmarshal(data, m.state); err != nil {
+	// Decode into a fresh state so a reload drops entries other processes removed
+	state := &State{}
+	if err := json.Unmarshal(data, state); err != nil {
 		return fmt.Errorf("failed to parse state file: %w", err)
 	}
+	m.state = state
 	
 	return nil
 }
 
 // SaveState saves current environment state to disk
 func (m *Manager) SaveState() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.saveState()
+}
+
+// saveState writes the state file; the caller must hold m.mu. It writes a
+// temporary file and renames it so other processes never read a partial file.
+func (m *Manager) saveState() error {
 	statePath := filepath.Join(m.stateDir, EnvironmentsFile)
 	
 	data, err := json.MarshalIndent(m.state, "", "  ")
@@ -102,7 +218,11 @@ func (m *Manager) SaveState() error {
 		return fmt.Errorf("failed to marshal state: %w", err)
 	}
 	
-	if err := os.WriteFile(statePath, data, 0644); err != nil {
+	tmpPath := statePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmpPath, statePath); err != nil {
 		return fmt.Errorf("failed to write state file: %w", err)
 	}
 	
@@ -119,8 +239,23 @@ func (m *Manager) GetState() *State {
 	return m.state
 }
 
+// Environments returns a copy of the environments in the state
+func (m *Manager) Environments() []Environment {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Environment(nil), m.state.Environments...)
+}
+
 // AddEnvironment adds a new environment to the state
 func (m *Manager) AddEnvironment(env Environment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	// Other processes may have changed the state since it was loaded
+	if err := m.loadState(); err != nil {
+		return err
+	}
+	
 	// Check for duplicate names
 	for _, existing := range m.state.Environments {
 		if existing.Name == env.Name {
@@ -129,15 +264,21 @@ func (m *Manager) AddEnvironment(env Environment) error {
 	}
 	
 	m.state.Environments = append(m.state.Environments, env)
-	return m.SaveState()
+	return m.saveState()
 }
 
 // RemoveEnvironment removes an environment from the state
 func (m *Manager) RemoveEnvironment(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	if err := m.loadState(); err != nil {
+		return err
+	}
 	for i, env := range m.state.Environments {
 		if env.Name == name {
 			m.state.Environments = append(m.state.Environments[:i], m.state.Environments[i+1:]...)
-			return m.SaveState()
+			return m.saveState()
 		}
 	}
 	return fmt.Errorf("environment %s not found", name)
@@ -145,10 +286,16 @@ func (m *Manager) RemoveEnvironment(name string) error {
 
 // UpdateEnvironment updates an existing environment in the state
 func (m *Manager) UpdateEnvironment(name string, updater func(*Environment)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	if err := m.loadState(); err != nil {
+		return err
+	}
 	for i, env := range m.state.Environments {
 		if env.Name == name {
 			updater(&m.state.Environments[i])
-			return m.SaveState()
+			return m.saveState()
 		}
 	}
 	return fmt.Errorf("environment %s not found", name)
@@ -156,6 +303,9 @@ func (m *Manager) UpdateEnvironment(name string, updater func(*Environment)) err
 
 // GetEnvironment returns an environment by name
 func (m *Manager) GetEnvironment(name string) (Environment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
 	for _, env := range m.state.Environments {
 		if env.Name == name {
 			return env, nil
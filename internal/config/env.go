@@ -0,0 +1,89 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix starts the environment variables that override settings: the
+// setting's key in upper case, e.g. CC_BUDDY_RUNTIME for "runtime" and
+// CC_BUDDY_NOTIFY_WEBHOOK_URL for "notify.webhook_url"
+const EnvPrefix = "CC_BUDDY_"
+
+// ProfileEnv selects a configuration profile, like the --profile flag
+const ProfileEnv = EnvPrefix + "PROFILE"
+
+// applyEnv overrides the settings of cfg whose variable is set and returns
+// the names of the variables used
+func applyEnv(cfg *Config) ([]string, error) {
+	var used, problems []string
+	applyEnvFields(reflect.ValueOf(cfg).Elem(), EnvPrefix, &used, &problems)
+	return used, problemsError(problems)
+}
+
+// applyEnvFields sets the fields of the struct v from the variables named
+// prefix plus the field's key, descending into nested settings
+func applyEnvFields(v reflect.Value, prefix string, used, problems *[]string) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+		variable := prefix + strings.ToUpper(key)
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			applyEnvFields(field, variable+"_", used, problems)
+			continue
+		}
+		value, ok := os.LookupEnv(variable)
+		if !ok {
+			continue
+		}
+		if err := setFromEnv(field, value); err != nil {
+			*problems = append(*problems, fmt.Sprintf("%s %v", variable, err))
+			continue
+		}
+		*used = append(*used, variable)
+	}
+}
+
+// setFromEnv parses value into field; lists are comma-separated
+func setFromEnv(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("must be true or false, got '%s'", value)
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("must be a whole number, got '%s'", value)
+		}
+		field.SetInt(int64(n))
+	case reflect.Slice:
+		items := reflect.MakeSlice(field.Type(), 0, 0)
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			elem := reflect.New(field.Type().Elem()).Elem()
+			if err := setFromEnv(elem, item); err != nil {
+				return fmt.Errorf("items %w", err)
+			}
+			items = reflect.Append(items, elem)
+		}
+		field.Set(items)
+	default:
+		return errors.New("cannot be set from the environment; use the config file")
+	}
+	return nil
+}
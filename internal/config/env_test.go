@@ -0,0 +1,198 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// loadTestConfig loads configJSON as the local config.json, with projectYAML
+// as cc-buddy.yaml when set, after setting the given variables
+func loadTestConfig(t *testing.T, configJSON, projectYAML string, env map[string]string) (*Config, error) {
+	t.Helper()
+	dir := t.TempDir()
+	stateDir := filepath.Join(dir, StateDir)
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(stateDir, ConfigFile), []byte(configJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if projectYAML != "" {
+		if err := os.WriteFile(filepath.Join(dir, ProjectFile), []byte(projectYAML), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, value := range env {
+		t.Setenv(name, value)
+	}
+
+	m := &Manager{stateDir: stateDir, config: DefaultConfig(), local: DefaultConfig(), state: &State{}}
+	if err := m.LoadConfig(); err != nil {
+		return nil, err
+	}
+	return m.GetConfig(), nil
+}
+
+func TestEnvOverridePrecedence(t *testing.T) {
+	profiles := `"profiles": {"ci": {"runtime": "podman", "max_environments": 3}, "fast": {"runtime": "docker"}}`
+	tests := []struct {
+		name    string
+		config  string
+		project string
+		env     map[string]string
+		check   func(*Config) any
+		want    any
+	}{
+		{
+			name:   "config file",
+			config: `{"runtime": "docker"}`,
+			check:  func(c *Config) any { return c.Runtime },
+			want:   "docker",
+		},
+		{
+			name:   "variable beats config file",
+			config: `{"runtime": "docker"}`,
+			env:    map[string]string{"CC_BUDDY_RUNTIME": "podman"},
+			check:  func(c *Config) any { return c.Runtime },
+			want:   "podman",
+		},
+		{
+			name:   "profile beats config file",
+			config: `{"runtime": "docker", "profile": "ci", ` + profiles + `}`,
+			check:  func(c *Config) any { return c.Runtime },
+			want:   "podman",
+		},
+		{
+			name:   "variable beats profile",
+			config: `{"profile": "ci", ` + profiles + `}`,
+			env:    map[string]string{"CC_BUDDY_MAX_ENVIRONMENTS": "5"},
+			check:  func(c *Config) any { return c.MaxEnvironments },
+			want:   5,
+		},
+		{
+			name:   "CC_BUDDY_PROFILE beats profile setting",
+			config: `{"profile": "ci", ` + profiles + `}`,
+			env:    map[string]string{ProfileEnv: "fast"},
+			check:  func(c *Config) any { return c.Runtime },
+			want:   "docker",
+		},
+		{
+			name:    "project file fills the default",
+			config:  `{}`,
+			project: "containerfile: build/Containerfile\n",
+			check:   func(c *Config) any { return c.Containerfile },
+			want:    "build/Containerfile",
+		},
+		{
+			name:    "variable beats project file",
+			config:  `{}`,
+			project: "containerfile: build/Containerfile\n",
+			env:     map[string]string{"CC_BUDDY_CONTAINERFILE": "ci/Containerfile"},
+			check:   func(c *Config) any { return c.Containerfile },
+			want:    "ci/Containerfile",
+		},
+		{
+			name:   "nested setting",
+			config: `{}`,
+			env:    map[string]string{"CC_BUDDY_NOTIFY_WEBHOOK_URL": "https://hooks.example.com/x"},
+			check:  func(c *Config) any { return c.Notify.WebhookURL },
+			want:   "https://hooks.example.com/x",
+		},
+		{
+			name:   "list is comma-separated",
+			config: `{"ports": [9000]}`,
+			env:    map[string]string{"CC_BUDDY_PORTS": "3000, 8080,"},
+			check:  func(c *Config) any { return c.Ports },
+			want:   []int{3000, 8080},
+		},
+		{
+			name:   "boolean",
+			config: `{"expose_all": false}`,
+			env:    map[string]string{"CC_BUDDY_EXPOSE_ALL": "true"},
+			check:  func(c *Config) any { return c.ExposeAll },
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := loadTestConfig(t, tt.config, tt.project, tt.env)
+			if err != nil {
+				t.Fatalf("LoadConfig: %v", err)
+			}
+			if got := tt.check(cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvOverrideErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		env    map[string]string
+		want   []string // substrings of the error
+	}{
+		{
+			name: "boolean not parsed",
+			env:  map[string]string{"CC_BUDDY_EXPOSE_ALL": "maybe"},
+			want: []string{"CC_BUDDY_EXPOSE_ALL must be true or false, got 'maybe'"},
+		},
+		{
+			name: "number not parsed",
+			env:  map[string]string{"CC_BUDDY_MAX_ENVIRONMENTS": "lots"},
+			want: []string{"CC_BUDDY_MAX_ENVIRONMENTS must be a whole number, got 'lots'"},
+		},
+		{
+			name: "list item not parsed",
+			env:  map[string]string{"CC_BUDDY_PORTS": "3000,web"},
+			want: []string{"CC_BUDDY_PORTS items must be a whole number, got 'web'"},
+		},
+		{
+			name: "map not settable",
+			env:  map[string]string{"CC_BUDDY_TASKS": "test"},
+			want: []string{"CC_BUDDY_TASKS cannot be set from the environment"},
+		},
+		{
+			name: "every problem reported",
+			env:  map[string]string{"CC_BUDDY_EXPOSE_ALL": "maybe", "CC_BUDDY_WAIT_PORT": "http"},
+			want: []string{"CC_BUDDY_EXPOSE_ALL", "CC_BUDDY_WAIT_PORT"},
+		},
+		{
+			name: "parsed value still validated",
+			env:  map[string]string{"CC_BUDDY_RUNTIME": "rkt"},
+			want: []string{"CC_BUDDY_RUNTIME", "runtime must be one of auto|docker|podman|container, got 'rkt'"},
+		},
+		{
+			name: "port out of range",
+			env:  map[string]string{"CC_BUDDY_PORTS": "70000"},
+			want: []string{"ports[0] must be a port between 1 and 65535, got 70000"},
+		},
+		{
+			name:   "unknown profile",
+			config: `{"profiles": {"ci": {}}}`,
+			env:    map[string]string{ProfileEnv: "nightly"},
+			want:   []string{"unknown profile 'nightly' (available: ci)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			if config == "" {
+				config = "{}"
+			}
+			_, err := loadTestConfig(t, config, "", tt.env)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
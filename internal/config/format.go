@@ -0,0 +1,74 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlConfigFiles are the YAML alternatives to ConfigFile, in order of
+// preference
+var yamlConfigFiles = []string{"config.yaml", "config.yml"}
+
+// findConfigFile returns the config file in dir: config.yaml or config.yml
+// when one exists, otherwise config.json
+func findConfigFile(dir string) string {
+	for _, name := range yamlConfigFiles {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(dir, ConfigFile)
+}
+
+// isYAML reports whether path names a YAML file
+func isYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// decodeConfig unmarshals a JSON or YAML config file, picked by path's
+// extension, into v. YAML goes through JSON so both use the json tags.
+// Unknown keys and values of the wrong type are reported by field.
+func decodeConfig(path string, data []byte, v any) error {
+	var doc any
+	if isYAML(path) {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+		if doc == nil {
+			return nil // empty file
+		}
+		converted, err := json.Marshal(doc)
+		if err != nil {
+			return fmt.Errorf("unsupported YAML: %w", err)
+		}
+		data = converted
+	} else if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	if err := checkKeys(doc, reflect.TypeOf(v), "json"); err != nil {
+		return err
+	}
+	return typeProblem(json.Unmarshal(data, v))
+}
+
+// encodeConfig marshals v as JSON or YAML, picked by path's extension
+func encodeConfig(path string, v any) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil || !isYAML(path) {
+		return data, err
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(doc)
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// GlobalConfig holds machine-wide settings that apply to every repository
+type GlobalConfig struct {
+	// MaxEnvironments caps the environments of all repositories together;
+	// 0 means no limit
+	MaxEnvironments int `json:"max_environments,omitempty"`
+
+	// Runtime is the runtime ("podman" or "docker") used by repositories
+	// whose runtime is "auto" when both are installed; cc-buddy asks once
+	// and records the answer here
+	Runtime string `json:"runtime,omitempty"`
+}
+
+// GlobalConfigPath returns the machine-wide config file,
+// ~/.config/cc-buddy/config.json on Linux, or config.yaml beside it
+func GlobalConfigPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine config directory: %w", err)
+	}
+	return findConfigFile(filepath.Join(configDir, "cc-buddy")), nil
+}
+
+// LoadGlobalConfig reads the machine-wide config; a missing file gives the
+// zero config
+func LoadGlobalConfig() (*GlobalConfig, error) {
+	cfg := &GlobalConfig{}
+	path, err := GlobalConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read global config: %w", err)
+	}
+	if err := decodeConfig(path, data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid global config %s:\n  %w", path, err)
+	}
+	return cfg, nil
+}
+
+// SaveGlobalConfig writes the machine-wide config, creating its directory
+func SaveGlobalConfig(cfg *GlobalConfig) error {
+	path, err := GlobalConfigPath()
+	if err != nil {
+		return err
+	}
+	data, err := encodeConfig(path, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal global config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write global config: %w", err)
+	}
+	return nil
+}
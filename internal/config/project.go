@@ -0,0 +1,145 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectFile is the committed project configuration at the repository root
+const ProjectFile = "cc-buddy.yaml"
+
+// ProjectConfig is the configuration a repository shares through
+// cc-buddy.yaml, so every teammate gets the same environments. The
+// git-ignored .cc-buddy/config.json can override it locally.
+type ProjectConfig struct {
+	Containerfile string            `yaml:"containerfile"`
+	Ports         []int             `yaml:"ports"`     // container ports to publish on random host ports
+	Mounts        []string          `yaml:"mounts"`    // source:target[:options], as in config.json
+	Services      string            `yaml:"services"`  // compose file with backing services
+	EnvFiles      []string          `yaml:"env_files"` // KEY=value files in the worktree passed to the container
+	Hooks         Hooks             `yaml:"hooks"`
+	Tasks         map[string]string `yaml:"tasks"`
+}
+
+// Hooks are shell commands run inside an environment's container at points
+// of its lifecycle
+type Hooks struct {
+	PostCreate []string `json:"post_create,omitempty" yaml:"post_create"` // once the container first runs; failing fails the create
+	PreDelete  []string `json:"pre_delete,omitempty" yaml:"pre_delete"`   // before a running environment is deleted; failures only warn
+}
+
+// LoadProjectConfig reads path; a missing file gives nil. Unknown keys are
+// errors so typos do not silently drop settings.
+func LoadProjectConfig(path string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid %s:\n  %w", path, err)
+	}
+	if err := checkKeys(doc, reflect.TypeOf(ProjectConfig{}), "yaml"); err != nil {
+		return nil, fmt.Errorf("invalid %s:\n  %w", path, err)
+	}
+
+	project := &ProjectConfig{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(project); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid %s:\n  %w", path, err)
+	}
+	if err := project.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s:\n  %w", path, err)
+	}
+	return project, nil
+}
+
+// validate checks the values of the project settings
+func (p *ProjectConfig) validate() error {
+	var problems []string
+	for i, port := range p.Ports {
+		if port < 1 || port > 65535 {
+			problems = append(problems, fmt.Sprintf("ports[%d] must be a port between 1 and 65535, got %d", i, port))
+		}
+	}
+	for i, command := range p.Hooks.PostCreate {
+		if strings.TrimSpace(command) == "" {
+			problems = append(problems, fmt.Sprintf("hooks.post_create[%d] is empty", i))
+		}
+	}
+	for i, command := range p.Hooks.PreDelete {
+		if strings.TrimSpace(command) == "" {
+			problems = append(problems, fmt.Sprintf("hooks.pre_delete[%d] is empty", i))
+		}
+	}
+	for name, command := range p.Tasks {
+		if strings.TrimSpace(command) == "" {
+			problems = append(problems, fmt.Sprintf("tasks.%s has no command", name))
+		}
+	}
+	sort.Strings(problems)
+	return problemsError(problems)
+}
+
+// apply layers the project settings under cfg, the local configuration:
+// local settings changed from their defaults win, and lists are combined
+func (p *ProjectConfig) apply(cfg *Config) {
+	defaults := DefaultConfig()
+	if p.Containerfile != "" && cfg.Containerfile == defaults.Containerfile {
+		cfg.Containerfile = p.Containerfile
+	}
+	if p.Services != "" && cfg.ComposeFile == "" {
+		cfg.ComposeFile = p.Services
+	}
+
+	cfg.Ports = appendNew(p.Ports, cfg.Ports)
+	cfg.Mounts = appendNew(p.Mounts, cfg.Mounts)
+	cfg.EnvFiles = appendNew(p.EnvFiles, cfg.EnvFiles)
+	cfg.Hooks = Hooks{
+		PostCreate: append(append([]string(nil), p.Hooks.PostCreate...), cfg.Hooks.PostCreate...),
+		PreDelete:  append(append([]string(nil), p.Hooks.PreDelete...), cfg.Hooks.PreDelete...),
+	}
+
+	if len(p.Tasks) > 0 {
+		tasks := make(map[string]string, len(p.Tasks)+len(cfg.Tasks))
+		for name, command := range p.Tasks {
+			tasks[name] = command
+		}
+		for name, command := range cfg.Tasks {
+			tasks[name] = command
+		}
+		cfg.Tasks = tasks
+	}
+}
+
+// appendNew returns a new slice of base followed by the items of extra not
+// already in it
+func appendNew[T comparable](base, extra []T) []T {
+	result := append([]T(nil), base...)
+	for _, item := range extra {
+		found := false
+		for _, existing := range result {
+			if existing == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, item)
+		}
+	}
+	return result
+}
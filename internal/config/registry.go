@@ -0,0 +1,155 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// RegistryFile lists the repositories with environments on this machine,
+// in the global config directory
+const RegistryFile = "repositories.json"
+
+// Repository is a repository in the registry
+type Repository struct {
+	Path  string    `json:"path"` // absolute path of the repository root
+	Added time.Time `json:"added"`
+	Used  time.Time `json:"used,omitempty"` // last switched to in the TUI
+}
+
+// Name returns the repository's directory name
+func (r Repository) Name() string {
+	return filepath.Base(r.Path)
+}
+
+// registry is the contents of RegistryFile
+type registry struct {
+	Repositories []Repository `json:"repositories"`
+}
+
+// RegistryPath returns the path of the repository registry
+func RegistryPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine config directory: %w", err)
+	}
+	return filepath.Join(configDir, "cc-buddy", RegistryFile), nil
+}
+
+// Repositories returns the registered repositories sorted by path; a
+// missing registry gives none
+func Repositories() ([]Repository, error) {
+	reg, _, err := loadRegistry()
+	if err != nil {
+		return nil, err
+	}
+	return reg.Repositories, nil
+}
+
+// RegisterRepository adds the repository at root to the registry unless it
+// is there already
+func RegisterRepository(root string) error {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	reg, path, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+	for _, repo := range reg.Repositories {
+		if repo.Path == root {
+			return nil
+		}
+	}
+	reg.Repositories = append(reg.Repositories, Repository{Path: root, Added: time.Now().UTC()})
+	return saveRegistry(path, reg)
+}
+
+// RecordRepositoryUse marks the repository at root as just switched to,
+// registering it if needed, so the TUI can offer recent repositories first
+func RecordRepositoryUse(root string) error {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	reg, path, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for i := range reg.Repositories {
+		if reg.Repositories[i].Path == root {
+			reg.Repositories[i].Used = now
+			return saveRegistry(path, reg)
+		}
+	}
+	reg.Repositories = append(reg.Repositories, Repository{Path: root, Added: now, Used: now})
+	return saveRegistry(path, reg)
+}
+
+// UnregisterRepository removes the repository at root from the registry
+func UnregisterRepository(root string) error {
+	reg, path, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+	kept := reg.Repositories[:0]
+	for _, repo := range reg.Repositories {
+		if repo.Path != root {
+			kept = append(kept, repo)
+		}
+	}
+	if len(kept) == len(reg.Repositories) {
+		return nil
+	}
+	reg.Repositories = kept
+	return saveRegistry(path, reg)
+}
+
+// loadRegistry reads the registry and returns it with its path
+func loadRegistry() (*registry, string, error) {
+	path, err := RegistryPath()
+	if err != nil {
+		return nil, "", err
+	}
+	reg := &registry{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return reg, path, nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read repository registry: %w", err)
+	}
+	if err := json.Unmarshal(data, reg); err != nil {
+		return nil, "", fmt.Errorf("invalid repository registry %s: %w", path, err)
+	}
+	sort.Slice(reg.Repositories, func(i, j int) bool {
+		return reg.Repositories[i].Path < reg.Repositories[j].Path
+	})
+	return reg, path, nil
+}
+
+// saveRegistry writes the registry atomically, since every repository's
+// cc-buddy processes share it
+func saveRegistry(path string, reg *registry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	data, err := json.MarshalIndent(reg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode repository registry: %w", err)
+	}
+	tmpPath := fmt.Sprintf("%s.%d.tmp", path, os.Getpid())
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write repository registry: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write repository registry: %w", err)
+	}
+	return nil
+}
@@ -1,25 +1,279 @@
 package config
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Environment represents a development environment with its associated resources
 type Environment struct {
-	Name          string    `json:"name"`
-	Branch        string    `json:"branch"`
-	WorktreePath  string    `json:"worktree_path"`
-	ContainerID   string    `json:"container_id"`
-	ContainerName string    `json:"container_name"`
-	VolumeName    string    `json:"volume_name"`
-	Created       time.Time `json:"created"`
-	Status        string    `json:"status"`
+	Name              string            `json:"name"`
+	Branch            string            `json:"branch"`
+	WorktreePath      string            `json:"worktree_path"`
+	ContainerID       string            `json:"container_id"`
+	ContainerName     string            `json:"container_name"`
+	VolumeName        string            `json:"volume_name"`
+	Created           time.Time         `json:"created"`
+	Status            string            `json:"status"`
+	Mounts            []string          `json:"mounts,omitempty"` // extra mounts as source:target[:options]
+	ReadOnlyWorkspace bool              `json:"read_only_workspace,omitempty"`
+	Network           string            `json:"network,omitempty"`
+	ProxyHost         string            `json:"proxy_host,omitempty"`         // hostname routed by the reverse proxy
+	Health            string            `json:"health,omitempty"`             // HEALTHCHECK status, refreshed on list
+	WorkspaceVolume   string            `json:"workspace_volume,omitempty"`   // set when the worktree is synced into a volume
+	WorkspaceSync     string            `json:"workspace_sync,omitempty"`     // tool that syncs WorkspaceVolume
+	ComposeProject    string            `json:"compose_project,omitempty"`    // compose project running the backing services
+	ComposeFile       string            `json:"compose_file,omitempty"`       // compose file path inside the worktree
+	ContainerUser     string            `json:"container_user,omitempty"`     // non-root user the image was built for
+	Containerfile     string            `json:"containerfile,omitempty"`      // containerfile path inside the worktree
+	ExposeAllPorts    bool              `json:"expose_all_ports,omitempty"`   // publish all container ports
+	PortMappings      []string          `json:"port_mappings,omitempty"`      // ports to publish as [host:]container[/protocol]
+	RuntimeArgs       []string          `json:"runtime_args,omitempty"`       // extra arguments the container was run with (runtime_args, --runtime-arg)
+	Privileged        bool              `json:"privileged,omitempty"`         // container runs privileged
+	CapAdd            []string          `json:"cap_add,omitempty"`            // capabilities added to the container
+	Devices           []string          `json:"devices,omitempty"`            // host devices given to the container
+	NestedContainers  string            `json:"nested_containers,omitempty"`  // "socket" or "dind" when the container can run containers
+	StartupCommand    []string          `json:"startup_command,omitempty"`    // command the container was started with
+	ContainerfileHash string            `json:"containerfile_hash,omitempty"` // sha256 of the Containerfile the image was built from
+	SourceImage       string            `json:"source_image,omitempty"`       // published image used instead of a build (--image)
+	BaseImageDigests  map[string]string `json:"base_image_digests,omitempty"` // FROM images pinned to the digests of the first build
+	Stale             bool              `json:"stale,omitempty"`              // image predates Containerfile or watched file changes
+	WorkspacePending  bool              `json:"workspace_pending,omitempty"`  // WorkspaceVolume is filled when the container first starts
+	HooksPending      bool              `json:"hooks_pending,omitempty"`      // post_create hooks run when the container first starts
+	Protected         bool              `json:"protected,omitempty"`          // delete refuses without force
+	Labels            map[string]string `json:"labels,omitempty"`             // user labels, also set on the container
+	Note              string            `json:"note,omitempty"`               // what the environment is for
+	LastUsed          *time.Time        `json:"last_used,omitempty"`          // last terminal, exec or start
+	NoContainer       bool              `json:"no_container,omitempty"`       // only a branch and worktree, created with --no-container
+	InPlace           bool              `json:"in_place,omitempty"`           // mounts the checkout it was created from (--here), which delete keeps
+	SharedContainer   bool              `json:"shared_container,omitempty"`   // worktree mounted in the repository's shared container
+	Ports             []string          `json:"ports,omitempty"`              // published ports as [ip:]host->container/protocol, read back whenever the container starts
+
+	// Filled in when listing, not stored
+	Image     string     `json:"image,omitempty"`      // image tag the container runs
+	StartedAt *time.Time `json:"started_at,omitempty"` // when the running container started
 }
 
 // Config holds user configuration settings
 type Config struct {
 	WorktreeDir   string `json:"worktree_dir"`
-	Runtime       string `json:"runtime"`       // "docker" or "podman"
+	Runtime       string `json:"runtime"`       // "auto", "docker", "podman" or "container"
 	Containerfile string `json:"containerfile"` // path to containerfile
 	ExposeAll     bool   `json:"expose_all"`    // expose all container ports
+	ForwardGPG    bool   `json:"forward_gpg"`   // forward gpg-agent for commit signing
+
+	// ContainerUser is the non-root user created in the image (passed as the
+	// USERNAME build arg); its home is where host files and caches are mounted
+	ContainerUser string `json:"container_user"`
+
+	ShareGitConfig      bool `json:"share_gitconfig"`       // mount ~/.gitconfig and pass git identity
+	ShareGitCredentials bool `json:"share_git_credentials"` // also mount ~/.git-credentials
+
+	// Network is the default network mode; "shared" attaches environments to
+	// a common cc-buddy network where they resolve each other by name, and
+	// "host" runs them on the host's network
+	Network string `json:"network,omitempty"`
+
+	// ManageHosts keeps <env>.localhost entries in HostsFile (default /etc/hosts)
+	ManageHosts bool   `json:"manage_hosts"`
+	HostsFile   string `json:"hosts_file,omitempty"`
+
+	// Proxy runs a traefik companion container routing https://<env>.<proxy_domain>
+	// to each environment's first exposed port over the shared network
+	Proxy          bool   `json:"proxy"`
+	ProxyDomain    string `json:"proxy_domain"`
+	ProxyImage     string `json:"proxy_image"`
+	ProxyHTTPSPort int    `json:"proxy_https_port"`
+
+	// ComposeFile is a compose file (relative to the worktree) whose services
+	// run alongside each environment as a separate compose project. The
+	// container joins the project network and reaches services by name.
+	ComposeFile string `json:"compose_file,omitempty"`
+
+	// HealthCmd overrides the Containerfile HEALTHCHECK command (run with sh -c)
+	HealthCmd string `json:"health_cmd,omitempty"`
+
+	// WaitPort is the container port create --wait probes for readiness;
+	// when unset, --wait relies on the HEALTHCHECK status
+	WaitPort int `json:"wait_port,omitempty"`
+
+	// RuntimeHost points docker/podman at a daemon on another machine
+	// (e.g. ssh://me@builder). DOCKER_HOST and CONTAINER_HOST are honored too.
+	RuntimeHost string `json:"runtime_host,omitempty"`
+
+	// RuntimeConnection selects a saved podman connection (or docker context)
+	// for this repository, e.g. a podman machine or remote podman socket
+	RuntimeConnection string `json:"runtime_connection,omitempty"`
+
+	// WorkspaceMode is "bind" to mount the worktree, "sync" to copy it into a
+	// volume, or "auto" (default) to sync only when the runtime host is remote
+	WorkspaceMode string `json:"workspace_mode"`
+
+	// WorkspaceSync picks how sync mode moves files: "copy" (runtime cp),
+	// "rsync" (incremental, needs rsync in the image) or "mutagen"
+	// (continuous two-way sync, docker only)
+	WorkspaceSync string `json:"workspace_sync,omitempty"`
+
+	// MountConsistency sets the workspace bind mount consistency ("cached" or
+	// "delegated") to speed up Docker Desktop file sharing on macOS
+	MountConsistency string `json:"mount_consistency,omitempty"`
+
+	// SELinuxLabel controls relabeling of the workspace bind mount: "auto"
+	// (relabel with Z when SELinux is enabled and the worktree is not on a
+	// network filesystem), "Z", "z" or "none"
+	SELinuxLabel string `json:"selinux_label"`
+
+	// VMPathMap maps host path prefixes to their location inside a Lima or
+	// Colima VM for bind mounts, when the VM mounts them somewhere else
+	VMPathMap map[string]string `json:"vm_path_map,omitempty"`
+
+	// Mounts are extra mounts (source:target[:options]) added to every environment.
+	// Path-like sources are bind mounts; other sources are named volumes.
+	Mounts []string `json:"mounts,omitempty"`
+
+	// SharedCaches maps cache names to container paths (e.g. "gomod":
+	// "/home/developer/go/pkg/mod"). Each is a named volume shared by every
+	// environment of the repository and kept when environments are deleted.
+	SharedCaches map[string]string `json:"shared_caches,omitempty"`
+
+	// CachePreset enables the shared caches and cache environment variables
+	// for one or more languages: "go", "node", "python" or "rust"
+	// (comma-separated, e.g. "go,node")
+	CachePreset string `json:"cache_preset,omitempty"`
+
+	// Secrets maps container environment variable names to secret references
+	// (e.g. "secret://op/vault/item/field"). Values are resolved when the
+	// container starts and are never written to the state file.
+	Secrets map[string]string `json:"secrets,omitempty"`
+
+	// DaemonListen is the address "cc-buddy daemon" serves /metrics on
+	DaemonListen string `json:"daemon_listen"`
+
+	// APIToken is the bearer token the daemon's /api requires; when unset
+	// the daemon generates one in .cc-buddy/api-token
+	APIToken string `json:"api_token,omitempty"`
+
+	// Webhook lets the daemon create and delete environments for pull
+	// requests as GitHub or GitLab reports them
+	Webhook WebhookConfig `json:"webhook,omitempty"`
+
+	// Notify sends notifications when long creates and deletes finish or fail
+	Notify NotifyConfig `json:"notify,omitempty"`
+
+	// WatchPaths are files or directories (relative to the worktree) that
+	// "cc-buddy watch" monitors in addition to the Containerfile
+	WatchPaths []string `json:"watch_paths,omitempty"`
+
+	// MaxEnvironments caps the environments of this repository; create
+	// refuses to go past it. 0 means no limit.
+	MaxEnvironments int `json:"max_environments,omitempty"`
+
+	// ScanOnCreate ("critical", "high", ...) scans each new environment's
+	// image with trivy or grype and warns about findings of that severity or
+	// worse
+	ScanOnCreate string `json:"scan_on_create,omitempty"`
+
+	// PinBaseImages records the digests the Containerfile's FROM images
+	// resolve to at an environment's first build; rebuilds use those
+	// digests and warn when the tags have moved upstream
+	PinBaseImages bool `json:"pin_base_images,omitempty"`
+
+	// WarmPool is how many images "cc-buddy daemon" keeps built ahead from
+	// the repository's Containerfile; create claims one instead of building
+	// when its Containerfile is the same
+	WarmPool int `json:"warm_pool,omitempty"`
+
+	// StopAfterIdle (e.g. "4h") stops running environments nobody has used
+	// for that long; enforced by "cc-buddy daemon" and "cc-buddy reap --idle"
+	StopAfterIdle string `json:"stop_after_idle,omitempty"`
+
+	// Tmux makes terminal attach to a tmux session in the container (installed
+	// on first use if missing), so builds and REPLs survive closing it
+	Tmux bool `json:"tmux,omitempty"`
+
+	// Editor is the command that opens a worktree on the host, e.g.
+	// "code --wait"; the worktree path is appended. When unset, $VISUAL or
+	// $EDITOR is used.
+	Editor string `json:"editor,omitempty"`
+
+	// SharedContainer (experimental) lets "cc-buddy switch" mount the
+	// worktree of a --no-container environment into one long-lived container
+	// per repository, replacing only the container on each switch instead of
+	// building an image per environment
+	SharedContainer bool `json:"shared_container,omitempty"`
+
+	// Envrc writes a direnv .envrc into each new worktree exporting the
+	// environment and container names and the published ports, so host tools
+	// run there know about the paired container
+	Envrc bool `json:"envrc,omitempty"`
+
+	// Tasks names shell commands run in /workspace by "cc-buddy task", e.g.
+	// "test": "make test"
+	Tasks map[string]string `json:"tasks,omitempty"`
+
+	// Ports are container ports published on random host ports
+	Ports []int `json:"ports,omitempty"`
+
+	// EnvFiles are KEY=value files (relative to the worktree) whose variables
+	// are set in the container
+	EnvFiles []string `json:"env_files,omitempty"`
+
+	// Hooks run shell commands in the container after create and before delete
+	Hooks Hooks `json:"hooks,omitempty"`
+
+	// Maintenance lists jobs "cc-buddy daemon" runs on a cron schedule
+	Maintenance []MaintenanceJob `json:"maintenance,omitempty"`
+
+	// CPUs and Memory limit each environment container, e.g. "2" and "4g"
+	CPUs   string `json:"cpus,omitempty"`
+	Memory string `json:"memory,omitempty"`
+
+	// RuntimeArgs are passed to the runtime's run command for needs cc-buddy
+	// has no option for, e.g. "--shm-size=2g"; each entry is split like a
+	// shell command line
+	RuntimeArgs []string `json:"runtime_args,omitempty"`
+
+	// Privileged, CapAdd (e.g. "SYS_PTRACE") and Devices (e.g. "/dev/fuse")
+	// grant environment containers what running containers, strace or FUSE
+	// mounts need
+	Privileged bool     `json:"privileged,omitempty"`
+	CapAdd     []string `json:"cap_add,omitempty"`
+	Devices    []string `json:"devices,omitempty"`
+
+	// NestedContainers lets environments build and run their own images:
+	// "socket" mounts the host runtime's API socket, "dind" runs a
+	// Docker-in-Docker sidecar per environment
+	NestedContainers string `json:"nested_containers,omitempty"`
+
+	// Profiles are named sets of settings applied on top of the others when
+	// selected with --profile, CC_BUDDY_PROFILE or Profile (the default)
+	Profile  string                     `json:"profile,omitempty"`
+	Profiles map[string]json.RawMessage `json:"profiles,omitempty"`
+}
+
+// MaintenanceJob is a task the daemon runs on a schedule
+type MaintenanceJob struct {
+	Task      string `json:"task"`                 // "gc", "refresh" or "ttl"
+	Schedule  string `json:"schedule"`             // cron expression, or @daily, @weekly, @every 6h...
+	KeepLast  int    `json:"keep_last,omitempty"`  // gc: superseded builds to keep per environment
+	OlderThan string `json:"older_than,omitempty"` // gc: only remove resources this old, e.g. 7d
+	MaxAge    string `json:"max_age,omitempty"`    // ttl: delete environments created this long ago, e.g. 14d
+}
+
+// WebhookConfig configures the daemon's /webhook endpoint
+type WebhookConfig struct {
+	Enabled        bool     `json:"enabled,omitempty"`
+	Secret         string   `json:"secret,omitempty"`          // GitHub webhook secret or GitLab secret token
+	AllowedSenders []string `json:"allowed_senders,omitempty"` // GitHub logins or GitLab usernames; empty allows anyone
+	Remote         string   `json:"remote,omitempty"`          // remote the branches are fetched from (default origin)
+}
+
+// NotifyConfig configures completion notifications
+type NotifyConfig struct {
+	Desktop       bool   `json:"desktop,omitempty"`        // notify-send on Linux, osascript on macOS
+	WebhookURL    string `json:"webhook_url,omitempty"`    // Slack or Discord incoming webhook, or any URL accepting JSON
+	WebhookFormat string `json:"webhook_format,omitempty"` // "slack", "discord" or "json"; guessed from the URL when empty
+	MinDuration   string `json:"min_duration,omitempty"`   // skip successes quicker than this (default 30s); failures always notify
 }
 
 // State represents the persistent application state
@@ -30,9 +284,18 @@ type State struct {
 // DefaultConfig returns configuration with sensible defaults
 func DefaultConfig() *Config {
 	return &Config{
-		WorktreeDir:   ".worktrees",
-		Runtime:       "auto", // auto-detect
-		Containerfile: "Containerfile.dev",
-		ExposeAll:     false,
+		WorktreeDir:    ".worktrees",
+		Runtime:        "auto", // auto-detect
+		Containerfile:  "Containerfile.dev",
+		ExposeAll:      false,
+		ForwardGPG:     false,
+		ContainerUser:  "developer",
+		ShareGitConfig: true,
+		WorkspaceMode:  "auto",
+		SELinuxLabel:   "auto",
+		ProxyDomain:    "dev.local",
+		ProxyImage:     "docker.io/library/traefik:v3.1",
+		ProxyHTTPSPort: 443,
+		DaemonListen:   "127.0.0.1:7777",
 	}
 }
\ No newline at end of file
@@ -0,0 +1,224 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validate checks the settings limited to a set of choices or a range and
+// returns every problem, one per field
+func (c *Config) Validate() error {
+	var problems []string
+	oneOf := func(field, value string, allowed ...string) {
+		if value == "" {
+			return
+		}
+		for _, choice := range allowed {
+			if value == choice {
+				return
+			}
+		}
+		problems = append(problems, fmt.Sprintf("%s must be one of %s, got '%s'", field, strings.Join(allowed, "|"), value))
+	}
+	port := func(field string, value int, optional bool) {
+		if (value != 0 || !optional) && (value < 1 || value > 65535) {
+			problems = append(problems, fmt.Sprintf("%s must be a port between 1 and 65535, got %d", field, value))
+		}
+	}
+	duration := func(field, value string) {
+		if value == "" {
+			return
+		}
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			problems = append(problems, fmt.Sprintf("%s must be a duration like 30s, 10m or 4h, got '%s'", field, value))
+		}
+	}
+
+	oneOf("runtime", c.Runtime, "auto", "docker", "podman", "container")
+	oneOf("network", c.Network, "shared", "host")
+	oneOf("workspace_mode", c.WorkspaceMode, "auto", "bind", "sync")
+	oneOf("workspace_sync", c.WorkspaceSync, "copy", "rsync", "mutagen")
+	oneOf("mount_consistency", c.MountConsistency, "consistent", "cached", "delegated")
+	oneOf("selinux_label", c.SELinuxLabel, "auto", "Z", "z", "none")
+	oneOf("notify.webhook_format", c.Notify.WebhookFormat, "slack", "discord", "json")
+	duration("notify.min_duration", c.Notify.MinDuration)
+	duration("stop_after_idle", c.StopAfterIdle)
+	port("proxy_https_port", c.ProxyHTTPSPort, true)
+	port("wait_port", c.WaitPort, true)
+	for i, value := range c.Ports {
+		port(fmt.Sprintf("ports[%d]", i), value, false)
+	}
+	if c.Webhook.Enabled && c.Webhook.Secret == "" {
+		problems = append(problems, "webhook.secret is required when webhook.enabled is true")
+	}
+	if c.MaxEnvironments < 0 {
+		problems = append(problems, fmt.Sprintf("max_environments must be 0 (no limit) or more, got %d", c.MaxEnvironments))
+	}
+	for i, job := range c.Maintenance {
+		oneOf(fmt.Sprintf("maintenance[%d].task", i), job.Task, "gc", "refresh", "ttl")
+		if job.Task == "" {
+			problems = append(problems, fmt.Sprintf("maintenance[%d].task is required", i))
+		}
+	}
+	for name, command := range c.Tasks {
+		if strings.TrimSpace(command) == "" {
+			problems = append(problems, fmt.Sprintf("tasks.%s has no command", name))
+		}
+	}
+	if c.CPUs != "" {
+		if cpus, err := strconv.ParseFloat(c.CPUs, 64); err != nil || cpus <= 0 {
+			problems = append(problems, fmt.Sprintf("cpus must be a positive number like 2 or 1.5, got '%s'", c.CPUs))
+		}
+	}
+	if c.Memory != "" && !memoryPattern.MatchString(c.Memory) {
+		problems = append(problems, fmt.Sprintf("memory must be a size like 512m or 4g, got '%s'", c.Memory))
+	}
+	for name, raw := range c.Profiles {
+		var doc any
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			problems = append(problems, fmt.Sprintf("profiles.%s is not valid: %v", name, err))
+			continue
+		}
+		if _, ok := doc.(map[string]any); !ok {
+			problems = append(problems, fmt.Sprintf("profiles.%s must be an object of settings", name))
+			continue
+		}
+		walkKeys(doc, reflect.TypeOf(Config{}), "json", "profiles."+name+".", &problems)
+	}
+
+	sort.Strings(problems)
+	return problemsError(problems)
+}
+
+// memoryPattern matches the memory sizes docker and podman accept
+var memoryPattern = regexp.MustCompile(`^[0-9]+[bkmgBKMG]?$`)
+
+// problemsError joins field problems into one error, or nil without any
+func problemsError(problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(problems, "\n  "))
+}
+
+// checkKeys reports the keys of doc, a decoded JSON or YAML document, that
+// name no field of t (by the given struct tag), suggesting near misses
+func checkKeys(doc any, t reflect.Type, tag string) error {
+	var problems []string
+	walkKeys(doc, t, tag, "", &problems)
+	return problemsError(problems)
+}
+
+// walkKeys collects the unknown keys under prefix for checkKeys
+func walkKeys(doc any, t reflect.Type, tag, prefix string, problems *[]string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := doc.(map[string]any)
+		if !ok {
+			return // type mismatches are reported by decoding
+		}
+		fields := map[string]reflect.Type{}
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get(tag), ",")
+			if name != "" && name != "-" {
+				fields[name] = t.Field(i).Type
+			}
+		}
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fieldType, ok := fields[key]
+			if !ok {
+				*problems = append(*problems, unknownKeyProblem(prefix+key, key, fields))
+				continue
+			}
+			walkKeys(object[key], fieldType, tag, prefix+key+".", problems)
+		}
+	case reflect.Slice:
+		items, ok := doc.([]any)
+		if !ok {
+			return
+		}
+		parent := strings.TrimSuffix(prefix, ".")
+		for i, item := range items {
+			walkKeys(item, t.Elem(), tag, fmt.Sprintf("%s[%d].", parent, i), problems)
+		}
+	}
+}
+
+// unknownKeyProblem describes an unknown key, with the closest known one
+// when it looks like a typo
+func unknownKeyProblem(path, key string, fields map[string]reflect.Type) string {
+	best, bestDistance := "", 3
+	for name := range fields {
+		if distance := editDistance(strings.ToLower(key), name); distance < bestDistance || (distance == bestDistance && name < best) {
+			best, bestDistance = name, distance
+		}
+	}
+	if best != "" {
+		return fmt.Sprintf("unknown setting '%s' (did you mean '%s'?)", path, best)
+	}
+	return fmt.Sprintf("unknown setting '%s'", path)
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// typeProblem rewords a JSON type mismatch as a field-level problem
+func typeProblem(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || typeErr.Field == "" {
+		return err
+	}
+	return fmt.Errorf("%s must be %s, got %s", typeErr.Field, describeType(typeErr.Type), typeErr.Value)
+}
+
+// describeType names a Go type the way a config file author thinks of it
+func describeType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a whole number"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "a list"
+	default:
+		return "an object"
+	}
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		want   []string // substrings of the error; none for a valid config
+	}{
+		{name: "defaults", modify: func(*Config) {}},
+		{
+			name:   "unknown runtime",
+			modify: func(c *Config) { c.Runtime = "rkt" },
+			want:   []string{"runtime must be one of auto|docker|podman|container, got 'rkt'"},
+		},
+		{
+			name:   "unknown network",
+			modify: func(c *Config) { c.Network = "bridge" },
+			want:   []string{"network must be one of shared|host, got 'bridge'"},
+		},
+		{
+			name:   "bad duration",
+			modify: func(c *Config) { c.StopAfterIdle = "soon" },
+			want:   []string{"stop_after_idle must be a duration"},
+		},
+		{
+			name:   "zero duration",
+			modify: func(c *Config) { c.Notify.MinDuration = "0s" },
+			want:   []string{"notify.min_duration must be a duration"},
+		},
+		{
+			name:   "optional port unset",
+			modify: func(c *Config) { c.WaitPort = 0 },
+		},
+		{
+			name:   "port out of range",
+			modify: func(c *Config) { c.Ports = []int{8080, 0} },
+			want:   []string{"ports[1] must be a port between 1 and 65535, got 0"},
+		},
+		{
+			name:   "webhook without secret",
+			modify: func(c *Config) { c.Webhook.Enabled = true },
+			want:   []string{"webhook.secret is required"},
+		},
+		{
+			name:   "negative limit",
+			modify: func(c *Config) { c.MaxEnvironments = -1 },
+			want:   []string{"max_environments must be 0 (no limit) or more, got -1"},
+		},
+		{
+			name:   "bad cpus",
+			modify: func(c *Config) { c.CPUs = "-2" },
+			want:   []string{"cpus must be a positive number"},
+		},
+		{
+			name:   "bad memory",
+			modify: func(c *Config) { c.Memory = "4 GB" },
+			want:   []string{"memory must be a size like 512m or 4g, got '4 GB'"},
+		},
+		{
+			name:   "empty task",
+			modify: func(c *Config) { c.Tasks = map[string]string{"test": " "} },
+			want:   []string{"tasks.test has no command"},
+		},
+		{
+			name:   "profile with unknown key",
+			modify: func(c *Config) { c.Profiles = map[string]json.RawMessage{"ci": json.RawMessage(`{"runtim": "docker"}`)} },
+			want:   []string{"unknown setting 'profiles.ci.runtim' (did you mean 'runtime'?)"},
+		},
+		{
+			name:   "profile not an object",
+			modify: func(c *Config) { c.Profiles = map[string]json.RawMessage{"ci": json.RawMessage(`"docker"`)} },
+			want:   []string{"profiles.ci must be an object of settings"},
+		},
+		{
+			name: "every problem reported",
+			modify: func(c *Config) {
+				c.Runtime = "rkt"
+				c.MaxEnvironments = -1
+			},
+			want: []string{"runtime must be one of", "max_environments must be"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
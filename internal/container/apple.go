@@ -0,0 +1,340 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// AppleRuntime implements Runtime for Apple's native "container" CLI on
+// macOS 15+. Each container runs in its own lightweight VM, so there is no
+// SELinux labeling, no HEALTHCHECK support and no Docker-compatible API socket.
+type AppleRuntime struct {
+	baseRuntime
+}
+
+func (r *AppleRuntime) Name() string {
+	return "container"
+}
+
+func (r *AppleRuntime) Detect(ctx context.Context) (string, error) {
+	r.command = "container"
+	out, err := r.execCommand(ctx, "--version")
+	if err != nil {
+		return "", fmt.Errorf("container not available: %w", err)
+	}
+	// The CLI works only while the container system service is running
+	if _, err := r.execCommand(ctx, "system", "status"); err != nil {
+		return "", fmt.Errorf("container system service not running (start it with 'container system start'): %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (r *AppleRuntime) Build(ctx context.Context, opts BuildOptions) error {
+	args := []string{"build"}
+
+	if opts.NoCache {
+		args = append(args, "--no-cache")
+	}
+
+	if opts.Target != "" {
+		args = append(args, "--target", opts.Target)
+	}
+
+	if opts.Dockerfile != "" {
+		args = append(args, "-f", opts.Dockerfile)
+	}
+
+	for key, value := range opts.BuildArgs {
+		args = append(args, "--build-arg", fmt.Sprintf("%s=%s", key, value))
+	}
+
+	for key, value := range opts.Labels {
+		args = append(args, "--label", fmt.Sprintf("%s=%s", key, value))
+	}
+
+	for _, tag := range opts.Tags {
+		args = append(args, "-t", tag)
+	}
+
+	args = append(args, opts.Context)
+
+	return r.execCommandStreaming(ctx, args...)
+}
+
+func (r *AppleRuntime) Run(ctx context.Context, opts RunOptions) (string, error) {
+	if opts.Privileged || len(opts.CapAdd) > 0 || len(opts.Devices) > 0 {
+		return "", fmt.Errorf("privileged mode, added capabilities and devices are not supported by the container runtime")
+	}
+
+	args := []string{"run"}
+	if opts.NoStart {
+		args = []string{"create"}
+	}
+
+	if opts.Detach && !opts.NoStart {
+		args = append(args, "-d")
+	}
+
+	if opts.Remove {
+		args = append(args, "--rm")
+	}
+
+	if opts.Interactive {
+		args = append(args, "-i")
+	}
+
+	if opts.TTY {
+		args = append(args, "-t")
+	}
+
+	if opts.Name != "" {
+		args = append(args, "--name", opts.Name)
+	}
+
+	if opts.WorkingDir != "" {
+		args = append(args, "-w", opts.WorkingDir)
+	}
+
+	if opts.CPUs != "" {
+		args = append(args, "--cpus", opts.CPUs)
+	}
+
+	if opts.Memory != "" {
+		args = append(args, "--memory", opts.Memory)
+	}
+
+	// Containers are reachable by name on the network; aliases are unsupported
+	if opts.Network != "" {
+		args = append(args, "--network", opts.Network)
+	}
+
+	// Only the -v form is supported; SELinux options such as Z do not apply
+	for _, mount := range opts.Mounts {
+		mountStr := mount.Source + ":" + mount.Target
+		for _, option := range mount.Options {
+			if option == "ro" {
+				mountStr += ":ro"
+			}
+		}
+		args = append(args, "-v", mountStr)
+	}
+
+	for _, port := range opts.Ports {
+		args = append(args, "-p", port.PublishSpec())
+	}
+
+	for key, value := range opts.EnvVars {
+		args = append(args, "-e", fmt.Sprintf("%s=%s", key, value))
+	}
+
+	if len(opts.SecretEnv) > 0 {
+		// Secrets go through a private env file, removed once the run is done
+		envFile, err := writeSecretEnvFile(opts.SecretEnv)
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(envFile)
+		args = append(args, "--env-file", envFile)
+	}
+
+	for key, value := range opts.Labels {
+		args = append(args, "--label", fmt.Sprintf("%s=%s", key, value))
+	}
+
+	args = append(args, opts.ExtraArgs...)
+	args = append(args, opts.Image)
+
+	if len(opts.Command) > 0 {
+		args = append(args, opts.Command...)
+	}
+
+	out, err := r.execCommand(ctx, args...)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (r *AppleRuntime) Start(ctx context.Context, containerID string) error {
+	return r.execCommandStreaming(ctx, "start", containerID)
+}
+
+func (r *AppleRuntime) Stop(ctx context.Context, containerID string) error {
+	return r.execCommandStreaming(ctx, "stop", containerID)
+}
+
+func (r *AppleRuntime) Remove(ctx context.Context, containerID string) error {
+	return r.execCommandStreaming(ctx, "delete", "--force", containerID)
+}
+
+func (r *AppleRuntime) Exec(ctx context.Context, containerID string, command []string) error {
+	args := append([]string{"exec", "-it", containerID}, command...)
+	return r.execCommandInteractive(ctx, args...)
+}
+
+func (r *AppleRuntime) ExecNonInteractive(ctx context.Context, containerID string, command []string) error {
+	args := append([]string{"exec", containerID}, command...)
+	return r.execCommandStreaming(ctx, args...)
+}
+
+func (r *AppleRuntime) ExecCapture(ctx context.Context, containerID string, command []string) (ExecResult, error) {
+	return r.execCapture(ctx, containerID, command)
+}
+
+func (r *AppleRuntime) ExecAttached(ctx context.Context, containerID string, command []string) error {
+	return r.execAttached(ctx, containerID, command)
+}
+
+func (r *AppleRuntime) ExecStream(ctx context.Context, containerID string, command []string, stdout, stderr io.Writer) (int, error) {
+	return r.execStream(ctx, containerID, command, stdout, stderr)
+}
+
+// appleContainer is the subset of "container inspect" output cc-buddy uses
+type appleContainer struct {
+	Status        string `json:"status"`
+	Configuration struct {
+		PublishedPorts []struct {
+			HostAddress   string `json:"hostAddress"`
+			HostPort      int    `json:"hostPort"`
+			ContainerPort int    `json:"containerPort"`
+			Proto         string `json:"proto"`
+		} `json:"publishedPorts"`
+	} `json:"configuration"`
+}
+
+// inspect returns the parsed inspect output for a single container
+func (r *AppleRuntime) inspect(ctx context.Context, containerID string) (appleContainer, error) {
+	out, err := r.execCommand(ctx, "inspect", containerID)
+	if err != nil {
+		return appleContainer{}, err
+	}
+
+	var containers []appleContainer
+	if err := json.Unmarshal(out, &containers); err != nil {
+		return appleContainer{}, fmt.Errorf("failed to parse inspect output: %w", err)
+	}
+	if len(containers) == 0 {
+		return appleContainer{}, fmt.Errorf("container %s not found", containerID)
+	}
+	return containers[0], nil
+}
+
+func (r *AppleRuntime) Status(ctx context.Context, containerID string) (Status, error) {
+	info, err := r.inspect(ctx, containerID)
+	if err != nil {
+		return Status{Running: false}, fmt.Errorf("failed to get container status: %w", err)
+	}
+
+	return Status{
+		Running: info.Status == "running",
+		State:   info.Status,
+	}, nil
+}
+
+func (r *AppleRuntime) Logs(ctx context.Context, containerID string, follow bool) ([]string, error) {
+	args := []string{"logs"}
+	if follow {
+		args = append(args, "-f")
+	}
+	args = append(args, containerID)
+
+	out, err := r.execCommand(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Split(string(out), "\n"), nil
+}
+
+func (r *AppleRuntime) CreateVolume(ctx context.Context, name string, labels map[string]string) error {
+	return r.execCommandStreaming(ctx, append(append([]string{"volume", "create"}, labelArgs(labels)...), name)...)
+}
+
+func (r *AppleRuntime) RemoveVolume(ctx context.Context, name string) error {
+	return r.execCommandStreaming(ctx, "volume", "delete", name)
+}
+
+func (r *AppleRuntime) RemoveImage(ctx context.Context, imageID string) error {
+	return r.execCommandStreaming(ctx, "image", "delete", imageID)
+}
+
+func (r *AppleRuntime) Tag(ctx context.Context, source, target string) error {
+	return r.execCommandStreaming(ctx, "image", "tag", source, target)
+}
+
+func (r *AppleRuntime) Pull(ctx context.Context, image string) error {
+	return r.pull(ctx, "image", "pull", image)
+}
+
+func (r *AppleRuntime) Push(ctx context.Context, image string) error {
+	return r.execCommandStreaming(ctx, "image", "push", image)
+}
+
+func (r *AppleRuntime) Images(ctx context.Context, label string) ([]Image, error) {
+	return nil, fmt.Errorf("listing images by label is not supported by the container runtime")
+}
+
+func (r *AppleRuntime) Volumes(ctx context.Context, label string) ([]Volume, error) {
+	return nil, fmt.Errorf("listing volumes by label is not supported by the container runtime")
+}
+
+func (r *AppleRuntime) Containers(ctx context.Context, label string) ([]string, error) {
+	return nil, fmt.Errorf("listing containers by label is not supported by the container runtime")
+}
+
+func (r *AppleRuntime) EnsureNetwork(ctx context.Context, name string) error {
+	if _, err := r.execCommand(ctx, "network", "inspect", name); err == nil {
+		return nil
+	}
+	return r.execCommandStreaming(ctx, "network", "create", name)
+}
+
+func (r *AppleRuntime) Ports(ctx context.Context, containerID string) ([]PortMapping, error) {
+	info, err := r.inspect(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ports: %w", err)
+	}
+
+	var mappings []PortMapping
+	for _, port := range info.Configuration.PublishedPorts {
+		protocol := port.Proto
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		mappings = append(mappings, PortMapping{HostIP: specificHostIP(port.HostAddress), Host: port.HostPort, Container: port.ContainerPort, Protocol: protocol})
+	}
+	return mappings, nil
+}
+
+func (r *AppleRuntime) ImageDigest(ctx context.Context, image string) (string, error) {
+	return "", fmt.Errorf("reading image digests is not supported by the container runtime")
+}
+
+func (r *AppleRuntime) ImageExposedPorts(ctx context.Context, image string) ([]PortMapping, error) {
+	return nil, fmt.Errorf("reading exposed ports is not supported by the container runtime")
+}
+
+func (r *AppleRuntime) SocketPath(ctx context.Context) (string, error) {
+	return "", fmt.Errorf("the container runtime has no Docker-compatible API socket")
+}
+
+func (r *AppleRuntime) CopyTo(ctx context.Context, containerID, src, dst string) error {
+	return fmt.Errorf("copying files is not supported by the container runtime")
+}
+
+func (r *AppleRuntime) CopyFrom(ctx context.Context, containerID, src, dst string) error {
+	return fmt.Errorf("copying files is not supported by the container runtime")
+}
+
+func (r *AppleRuntime) Stats(ctx context.Context, containerID string) (ResourceUsage, error) {
+	return ResourceUsage{}, fmt.Errorf("resource usage is not supported by the container runtime")
+}
+
+func (r *AppleRuntime) ExecSessions(ctx context.Context, containerID string) (int, error) {
+	return 0, fmt.Errorf("listing exec sessions is not supported by the container runtime")
+}
@@ -0,0 +1,61 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/jhjaggars/cc-buddy/internal/ci"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// ExecResult is the outcome of a command run with ExecCapture
+type ExecResult struct {
+	ExitCode int
+	Stdout   []byte
+	Stderr   []byte
+}
+
+// execCapture runs a command in a container collecting its output and exit
+// code. A non-zero exit is reported in the result rather than as an error.
+func (r *baseRuntime) execCapture(ctx context.Context, containerID string, command []string) (ExecResult, error) {
+	var stdout, stderr bytes.Buffer
+	code, err := r.execStream(ctx, containerID, command, &stdout, &stderr)
+	return ExecResult{ExitCode: code, Stdout: stdout.Bytes(), Stderr: stderr.Bytes()}, err
+}
+
+// execStream runs a command in a container without stdin, writing its
+// output to stdout and stderr as it arrives, and returns its exit code.
+// A non-zero exit is not an error.
+func (r *baseRuntime) execStream(ctx context.Context, containerID string, command []string, stdout, stderr io.Writer) (int, error) {
+	args := append([]string{"exec", containerID}, command...)
+	cmd := exec.CommandContext(ctx, r.command, r.withGlobalArgs(args)...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := logging.Run(cmd)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to run command: %w", err)
+	}
+	return 0, nil
+}
+
+// execAttached runs a command in a container with the caller's stdin,
+// stdout and stderr but no TTY, so output streams through pipes and logs
+func (r *baseRuntime) execAttached(ctx context.Context, containerID string, command []string) error {
+	args := append([]string{"exec", "-i", containerID}, command...)
+	cmd := exec.CommandContext(ctx, r.command, r.withGlobalArgs(args)...)
+	cmd.Stdin = os.Stdin
+	// The command's output bypasses CI mode's emoji filter
+	cmd.Stdout = ci.Stdout()
+	cmd.Stderr = ci.Stderr()
+	return logging.Run(cmd)
+}
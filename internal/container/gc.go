@@ -0,0 +1,144 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Image is a local image as listed for garbage collection
+type Image struct {
+	ID      string
+	Tags    []string // empty for dangling images
+	Created time.Time
+	Labels  map[string]string
+}
+
+// Volume is a named volume as listed for garbage collection
+type Volume struct {
+	Name    string
+	Created time.Time // zero if the runtime does not report it
+	Labels  map[string]string
+}
+
+// labelArgs returns --label flags for labels, in a stable order
+func labelArgs(labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var args []string
+	for _, key := range keys {
+		args = append(args, "--label", key+"="+labels[key])
+	}
+	return args
+}
+
+// images lists images carrying label and inspects them for their tags,
+// creation time and labels, which "images --format" cannot show on docker
+func (r *baseRuntime) images(ctx context.Context, label string) ([]Image, error) {
+	out, err := r.execCommand(ctx, "images", "-q", "--no-trunc", "--filter", "label="+label)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %w", err)
+	}
+	ids := uniqueFields(string(out))
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	out, err = r.execCommand(ctx, append([]string{"image", "inspect"}, ids...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect images: %w", err)
+	}
+	var inspected []struct {
+		ID       string    `json:"Id"`
+		RepoTags []string  `json:"RepoTags"`
+		Created  time.Time `json:"Created"`
+		Config   struct {
+			Labels map[string]string `json:"Labels"`
+		} `json:"Config"`
+	}
+	if err := json.Unmarshal(out, &inspected); err != nil {
+		return nil, fmt.Errorf("failed to parse image details: %w", err)
+	}
+
+	var images []Image
+	for _, image := range inspected {
+		var tags []string
+		for _, tag := range image.RepoTags {
+			// podman reports dangling images with a <none> tag
+			if !strings.Contains(tag, "<none>") {
+				tags = append(tags, tag)
+			}
+		}
+		images = append(images, Image{
+			ID:      image.ID,
+			Tags:    tags,
+			Created: image.Created,
+			Labels:  image.Config.Labels,
+		})
+	}
+	return images, nil
+}
+
+// volumes lists volumes carrying label with their creation time and labels
+func (r *baseRuntime) volumes(ctx context.Context, label string) ([]Volume, error) {
+	out, err := r.execCommand(ctx, "volume", "ls", "-q", "--filter", "label="+label)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volumes: %w", err)
+	}
+	names := uniqueFields(string(out))
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	out, err = r.execCommand(ctx, append([]string{"volume", "inspect"}, names...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect volumes: %w", err)
+	}
+	var inspected []struct {
+		Name      string            `json:"Name"`
+		CreatedAt string            `json:"CreatedAt"`
+		Labels    map[string]string `json:"Labels"`
+	}
+	if err := json.Unmarshal(out, &inspected); err != nil {
+		return nil, fmt.Errorf("failed to parse volume details: %w", err)
+	}
+
+	var volumes []Volume
+	for _, volume := range inspected {
+		volumes = append(volumes, Volume{
+			Name:    volume.Name,
+			Created: parseStartedAt(volume.CreatedAt),
+			Labels:  volume.Labels,
+		})
+	}
+	return volumes, nil
+}
+
+// containers lists the names of containers carrying label
+func (r *baseRuntime) containers(ctx context.Context, label string) ([]string, error) {
+	out, err := r.execCommand(ctx, "ps", "-a", "--filter", "label="+label, "--format", "{{.Names}}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	return uniqueFields(string(out)), nil
+}
+
+// uniqueFields splits command output into its distinct whitespace-separated fields
+func uniqueFields(out string) []string {
+	seen := map[string]bool{}
+	var fields []string
+	for _, field := range strings.Fields(out) {
+		if !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,82 @@
+package container
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/system"
+)
+
+// InstallHint explains how to install a container runtime on this platform
+func InstallHint() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return `Install one of:
+  Podman:         brew install podman && podman machine init && podman machine start
+  Docker Desktop: https://docs.docker.com/desktop/setup/install/mac-install/
+  Apple container (macOS 15+): https://github.com/apple/container/releases`
+	case "windows":
+		return `Install one of:
+  Podman Desktop: https://podman-desktop.io/downloads
+  Docker Desktop: https://docs.docker.com/desktop/setup/install/windows-install/`
+	}
+
+	if system.IsWSL() {
+		return `Enable Docker Desktop's WSL integration for this distribution
+(Settings > Resources > WSL integration), or install Podman in it:
+  ` + linuxInstallCommand()
+	}
+	return `Install Podman:
+  ` + linuxInstallCommand() + `
+or Docker Engine: https://docs.docker.com/engine/install/`
+}
+
+// linuxInstallCommand returns the package manager command installing
+// podman on this distribution, read from /etc/os-release
+func linuxInstallCommand() string {
+	ids := osReleaseIDs()
+	for _, id := range ids {
+		switch id {
+		case "fedora", "rhel", "centos":
+			return "sudo dnf install podman"
+		case "debian", "ubuntu":
+			return "sudo apt install podman"
+		case "arch":
+			return "sudo pacman -S podman"
+		case "suse", "opensuse":
+			return "sudo zypper install podman"
+		case "alpine":
+			return "sudo apk add podman"
+		}
+	}
+	return "see https://podman.io/docs/installation"
+}
+
+// osReleaseIDs returns the distribution's ID followed by its ID_LIKE
+// entries
+func osReleaseIDs() []string {
+	f, err := os.Open("/etc/os-release")
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var id, like []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"'`)
+		switch key {
+		case "ID":
+			id = []string{value}
+		case "ID_LIKE":
+			like = strings.Fields(value)
+		}
+	}
+	return append(id, like...)
+}
@@ -0,0 +1,152 @@
+package container
+
+import (
+	"fmt"
+	goruntime "runtime"
+	"strings"
+)
+
+// ParseMount parses a mount specification of the form source:target[:options],
+// where options is a comma-separated list of ro, nocopy, z, Z and a
+// consistency mode (consistent, cached or delegated).
+// Sources that look like paths (starting with "/", "~", "." or a Windows
+// drive letter) become bind mounts; anything else is treated as a named volume.
+func ParseMount(spec string) (Mount, error) {
+	// Keep a Windows drive letter ("C:\\src") attached to the source; elsewhere
+	// "c:/data" is a volume named c
+	var drive string
+	rest := spec
+	if goruntime.GOOS == "windows" && hasDriveLetter(spec) {
+		drive, rest = spec[:2], spec[2:]
+	}
+	
+	parts := strings.Split(rest, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Mount{}, fmt.Errorf("invalid mount %q: expected source:target[:options]", spec)
+	}
+	parts[0] = drive + parts[0]
+
+	source, target := parts[0], parts[1]
+	if source == "" || target == "" {
+		return Mount{}, fmt.Errorf("invalid mount %q: source and target are required", spec)
+	}
+	if !strings.HasPrefix(target, "/") {
+		return Mount{}, fmt.Errorf("invalid mount %q: target %s must be an absolute container path", spec, target)
+	}
+
+	mount := Mount{
+		Source: source,
+		Target: target,
+		Type:   "volume",
+	}
+	if isPathSource(source) {
+		mount.Type = "bind"
+	}
+
+	if len(parts) == 3 {
+		for _, option := range strings.Split(parts[2], ",") {
+			switch option {
+			case "rw":
+				// the default
+			case "ro", "nocopy", "z", "Z":
+				mount.Options = append(mount.Options, option)
+			case "consistent", "cached", "delegated":
+				mount.Options = append(mount.Options, "consistency="+option)
+			case "consistency=consistent", "consistency=cached", "consistency=delegated":
+				mount.Options = append(mount.Options, option)
+			default:
+				return Mount{}, fmt.Errorf("invalid mount %q: unsupported option %q (supported: ro, nocopy, z, Z, consistent, cached, delegated)", spec, option)
+			}
+		}
+		if hasOption(mount.Options, "nocopy") && mount.Type != "volume" {
+			return Mount{}, fmt.Errorf("invalid mount %q: nocopy only applies to named volumes", spec)
+		}
+		if mount.Type != "bind" && (hasRelabelOption(mount.Options) || consistencyOption(mount.Options) != "") {
+			return Mount{}, fmt.Errorf("invalid mount %q: SELinux and consistency options only apply to bind mounts", spec)
+		}
+	}
+
+	return mount, nil
+}
+
+// ValidateMountOptions checks that the runtime named runtimeName supports
+// every option on mount
+func ValidateMountOptions(runtimeName string, mount Mount) error {
+	if runtimeName != "container" {
+		return nil
+	}
+	// Apple's container CLI only understands read-only mounts
+	for _, option := range mount.Options {
+		if option != "ro" {
+			return fmt.Errorf("mount %s: option %q is not supported by the container runtime", mount, option)
+		}
+	}
+	return nil
+}
+
+// String formats the mount back into its source:target[:options] form
+func (m Mount) String() string {
+	spec := m.Source + ":" + m.Target
+	if len(m.Options) > 0 {
+		spec += ":" + strings.Join(m.Options, ",")
+	}
+	return spec
+}
+
+// isPathSource reports whether a mount source refers to a host path
+func isPathSource(source string) bool {
+	return strings.HasPrefix(source, "/") ||
+		strings.HasPrefix(source, "~") ||
+		strings.HasPrefix(source, ".") ||
+		strings.HasPrefix(source, `\\`) ||
+		hasDriveLetter(source)
+}
+
+// hasDriveLetter reports whether path starts with a Windows drive such as C:\ or C:/
+func hasDriveLetter(path string) bool {
+	if len(path) < 3 || path[1] != ':' || (path[2] != '\\' && path[2] != '/') {
+		return false
+	}
+	letter := path[0]
+	return (letter >= 'a' && letter <= 'z') || (letter >= 'A' && letter <= 'Z')
+}
+
+// volumeOptions formats --mount style options for -v, where consistency is
+// given bare ("cached") rather than as "consistency=cached"
+func volumeOptions(options []string) string {
+	formatted := make([]string, len(options))
+	for i, option := range options {
+		formatted[i] = strings.TrimPrefix(option, "consistency=")
+	}
+	return strings.Join(formatted, ",")
+}
+
+// hasOption reports whether options contains name
+func hasOption(options []string, name string) bool {
+	for _, option := range options {
+		if option == name {
+			return true
+		}
+	}
+	return false
+}
+
+// consistencyOption returns the consistency mode in options, if any
+func consistencyOption(options []string) string {
+	for _, option := range options {
+		if mode, ok := strings.CutPrefix(option, "consistency="); ok {
+			return mode
+		}
+	}
+	return ""
+}
+
+// hasRelabelOption reports whether options request SELinux relabeling
+func hasRelabelOption(options []string) bool {
+	for _, option := range options {
+		if option == "Z" || option == "z" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package container
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PublishSpec formats the mapping for the runtimes' -p flag as
+// [ip:]host:container/protocol, bracketing IPv6 addresses, e.g.
+// "[::1]:8080:3000/tcp"
+func (p PortMapping) PublishSpec() string {
+	return fmt.Sprintf("%s%d:%d/%s", hostIPPrefix(p.HostIP), p.Host, p.Container, p.Protocol)
+}
+
+// HostAddress returns where the port is reachable on the host as host:port,
+// using localhost when the mapping binds every address
+func (p PortMapping) HostAddress() string {
+	host := p.HostIP
+	if host == "" {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, strconv.Itoa(p.Host))
+}
+
+// String formats the mapping as [ip:]host->container/protocol
+func (p PortMapping) String() string {
+	return fmt.Sprintf("%s%d->%d/%s", hostIPPrefix(p.HostIP), p.Host, p.Container, p.Protocol)
+}
+
+// hostIPPrefix returns ip followed by a colon, bracketed when it is IPv6, or
+// nothing for an empty ip
+func hostIPPrefix(ip string) string {
+	switch {
+	case ip == "":
+		return ""
+	case strings.Contains(ip, ":"):
+		return "[" + ip + "]:"
+	}
+	return ip + ":"
+}
+
+// specificHostIP returns addr unless it is empty or a wildcard address
+// (0.0.0.0, ::), which the runtimes report for ports bound everywhere
+func specificHostIP(addr string) string {
+	addr = strings.Trim(addr, "[]")
+	if ip := net.ParseIP(addr); ip == nil || ip.IsUnspecified() {
+		return ""
+	}
+	return addr
+}
+
+// parsePortOutput parses "port" subcommand output such as
+// "8080/tcp -> 0.0.0.0:32768" or "8080/tcp -> [::1]:32768". Ports bound to
+// a specific address keep it; the IPv6 duplicate of a binding to every
+// address is skipped.
+func parsePortOutput(output string) []PortMapping {
+	var mappings []PortMapping
+	seen := make(map[PortMapping]bool)
+
+	for _, line := range strings.Split(output, "\n") {
+		containerSide, hostSide, ok := strings.Cut(strings.TrimSpace(line), " -> ")
+		if !ok {
+			continue
+		}
+
+		portStr, protocol, _ := strings.Cut(containerSide, "/")
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		containerPort, err := strconv.Atoi(portStr)
+		if err != nil {
+			continue
+		}
+
+		idx := strings.LastIndex(hostSide, ":")
+		if idx < 0 {
+			continue
+		}
+		hostPort, err := strconv.Atoi(hostSide[idx+1:])
+		if err != nil {
+			continue
+		}
+
+		mapping := PortMapping{HostIP: specificHostIP(hostSide[:idx]), Host: hostPort, Container: containerPort, Protocol: protocol}
+		if !seen[mapping] {
+			seen[mapping] = true
+			mappings = append(mappings, mapping)
+		}
+	}
+
+	return mappings
+}
+
+// parseExposedPorts parses the JSON ExposedPorts map from image metadata,
+// e.g. {"8080/tcp":{}}, returning mappings sorted by container port
+func parseExposedPorts(data []byte) ([]PortMapping, error) {
+	var exposed map[string]struct{}
+	if err := json.Unmarshal(data, &exposed); err != nil {
+		return nil, fmt.Errorf("failed to parse exposed ports: %w", err)
+	}
+
+	var mappings []PortMapping
+	for spec := range exposed {
+		portStr, protocol, _ := strings.Cut(spec, "/")
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			continue
+		}
+		mappings = append(mappings, PortMapping{Container: port, Protocol: protocol})
+	}
+
+	sort.Slice(mappings, func(i, j int) bool {
+		return mappings[i].Container < mappings[j].Container
+	})
+	return mappings, nil
+}
+
+// ParsePortMapping parses a port mapping given as
+// [[ip:]host:]container[/protocol], e.g. "8080:3000", "5353/udp",
+// "127.0.0.1:8080:3000" or "[::1]:8080:3000". IPv6 addresses must be
+// bracketed. Without a host port, or with host port 0, the runtime picks a
+// free one.
+func ParsePortMapping(spec string) (PortMapping, error) {
+	ports, protocol, _ := strings.Cut(strings.TrimSpace(spec), "/")
+	switch protocol {
+	case "":
+		protocol = "tcp"
+	case "tcp", "udp":
+	default:
+		return PortMapping{}, fmt.Errorf("invalid port mapping %q: protocol must be tcp or udp", spec)
+	}
+
+	var hostIP string
+	if strings.HasPrefix(ports, "[") {
+		addr, rest, ok := strings.Cut(ports[1:], "]")
+		if !ok || !strings.HasPrefix(rest, ":") || net.ParseIP(addr) == nil || !strings.Contains(addr, ":") {
+			return PortMapping{}, fmt.Errorf("invalid port mapping %q: host address must be an IPv6 address like [::1]", spec)
+		}
+		hostIP, ports = addr, rest[1:]
+		if !strings.Contains(ports, ":") {
+			return PortMapping{}, fmt.Errorf("invalid port mapping %q: a host address needs a host port (use 0 for a free one)", spec)
+		}
+	} else if parts := strings.Split(ports, ":"); len(parts) == 3 {
+		if ip := net.ParseIP(parts[0]); ip == nil || ip.To4() == nil {
+			return PortMapping{}, fmt.Errorf("invalid port mapping %q: host address must be an IPv4 address, or IPv6 in brackets like [::1]", spec)
+		}
+		hostIP, ports = parts[0], parts[1]+":"+parts[2]
+	} else if len(parts) > 3 {
+		return PortMapping{}, fmt.Errorf("invalid port mapping %q: IPv6 host addresses must be bracketed, like [::1]:8080:3000", spec)
+	}
+
+	hostStr, containerStr, hasHost := strings.Cut(ports, ":")
+	if !hasHost {
+		hostStr, containerStr = "0", ports
+	}
+	hostPort, err := strconv.Atoi(hostStr)
+	if err != nil || hostPort < 0 || hostPort > 65535 {
+		return PortMapping{}, fmt.Errorf("invalid port mapping %q: host port must be 0-65535", spec)
+	}
+	containerPort, err := strconv.Atoi(containerStr)
+	if err != nil || containerPort < 1 || containerPort > 65535 {
+		return PortMapping{}, fmt.Errorf("invalid port mapping %q: container port must be 1-65535", spec)
+	}
+	return PortMapping{HostIP: hostIP, Host: hostPort, Container: containerPort, Protocol: protocol}, nil
+}
@@ -3,31 +3,63 @@ package container
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	goruntime "runtime"
 	"strings"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/ci"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
 )
 
 // Status represents container status
 type Status struct {
-	Running bool
-	Health  string
-	Uptime  string
+	Running   bool
+	State     string // runtime state, e.g. "running", "exited"
+	Health    string // HEALTHCHECK status: "healthy", "unhealthy", "starting" or "" if none
+	Uptime    string
+	StartedAt time.Time // when the running container started; zero if unknown
+}
+
+// parseStartedAt parses a .State.StartedAt value: RFC 3339 from docker, Go's
+// time.String format from podman. Unparseable values give the zero time.
+func parseStartedAt(value string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999 -0700 MST"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
 }
 
 // RunOptions holds container run configuration
 type RunOptions struct {
-	Name        string
-	Image       string
-	WorkingDir  string
-	Mounts      []Mount
-	Ports       []PortMapping
-	EnvVars     map[string]string
-	Detach      bool
-	Remove      bool
-	Interactive bool
-	TTY         bool
-	Command     []string
+	Name           string
+	Image          string
+	WorkingDir     string
+	Mounts         []Mount
+	Ports          []PortMapping
+	EnvVars        map[string]string
+	SecretEnv      map[string]string // like EnvVars, but kept off the runtime's command line
+	Detach         bool
+	NoStart        bool // create the container without starting it
+	Remove         bool
+	Interactive    bool
+	TTY            bool
+	Command        []string
+	Network        string   // network to attach to; empty uses the runtime default
+	NetworkAliases []string // DNS names for the container on Network
+	HealthCmd      string   // overrides the image HEALTHCHECK command
+	CPUs           string   // CPU limit, e.g. "1.5"
+	Memory         string   // memory limit, e.g. "4g"
+	Labels         map[string]string
+	SecurityOpts   []string
+	Privileged     bool     // all capabilities and host devices, e.g. for nested containers
+	CapAdd         []string // capabilities to add, e.g. SYS_PTRACE
+	Devices        []string // host devices as host[:container[:permissions]]
+	ExtraArgs      []string // passed to the runtime's run command as is, before the image
 }
 
 // Mount represents a volume mount
@@ -40,9 +72,10 @@ type Mount struct {
 
 // PortMapping represents port forwarding
 type PortMapping struct {
-	Host      int
-	Container int
-	Protocol  string // "tcp", "udp"
+	HostIP    string `json:"host_ip,omitempty"` // host address to bind, e.g. "127.0.0.1" or "::1"; empty binds all
+	Host      int    `json:"host_port"`
+	Container int    `json:"container_port"`
+	Protocol  string `json:"protocol"` // "tcp", "udp"
 }
 
 // BuildOptions holds container build configuration
@@ -51,6 +84,7 @@ type BuildOptions struct {
 	Dockerfile    string
 	Tags          []string
 	BuildArgs     map[string]string
+	Labels        map[string]string
 	Target        string
 	NoCache       bool
 	Progress      string // "auto", "plain", "tty"
@@ -58,6 +92,9 @@ type BuildOptions struct {
 
 // Runtime defines the interface for container operations
 type Runtime interface {
+	// Name returns the runtime CLI name ("podman" or "docker")
+	Name() string
+	
 	// Detect returns the runtime name if available
 	Detect(ctx context.Context) (string, error)
 	
@@ -67,6 +104,9 @@ type Runtime interface {
 	// Run starts a new container
 	Run(ctx context.Context, opts RunOptions) (string, error)
 	
+	// Start starts a created or stopped container
+	Start(ctx context.Context, containerID string) error
+	
 	// Stop stops a running container
 	Stop(ctx context.Context, containerID string) error
 	
@@ -79,65 +119,218 @@ type Runtime interface {
 	// ExecNonInteractive executes a command in a running container (non-interactive mode)
 	ExecNonInteractive(ctx context.Context, containerID string, command []string) error
 	
+	// ExecCapture runs a command in a running container and collects its output and exit code
+	ExecCapture(ctx context.Context, containerID string, command []string) (ExecResult, error)
+	
+	// ExecAttached runs a command in a running container without a TTY, attached to the caller's stdio
+	ExecAttached(ctx context.Context, containerID string, command []string) error
+	
+	// ExecStream runs a command in a running container, writing its output as it arrives, and returns its exit code
+	ExecStream(ctx context.Context, containerID string, command []string, stdout, stderr io.Writer) (int, error)
+	
 	// Status returns the status of a container
 	Status(ctx context.Context, containerID string) (Status, error)
 	
 	// Logs returns container logs
 	Logs(ctx context.Context, containerID string, follow bool) ([]string, error)
 	
-	// CreateVolume creates a named volume
-	CreateVolume(ctx context.Context, name string) error
+	// CreateVolume creates a named volume with labels
+	CreateVolume(ctx context.Context, name string, labels map[string]string) error
 	
 	// RemoveVolume removes a named volume
 	RemoveVolume(ctx context.Context, name string) error
 	
 	// RemoveImage removes a container image
 	RemoveImage(ctx context.Context, imageID string) error
+	
+	// Tag gives an existing image another name
+	Tag(ctx context.Context, source, target string) error
+	
+	// Pull fetches the latest version of an image from its registry
+	Pull(ctx context.Context, image string) error
+	
+	// Push uploads an image to its registry with the runtime's login
+	Push(ctx context.Context, image string) error
+	
+	// Images lists local images, dangling ones included, carrying a key=value label
+	Images(ctx context.Context, label string) ([]Image, error)
+	
+	// Volumes lists named volumes carrying a key=value label
+	Volumes(ctx context.Context, label string) ([]Volume, error)
+	
+	// Containers lists the names of containers, stopped ones included, carrying a key=value label
+	Containers(ctx context.Context, label string) ([]string, error)
+	
+	// EnsureNetwork creates a named network if it does not already exist
+	EnsureNetwork(ctx context.Context, name string) error
+	
+	// Ports returns the host ports published by a container
+	Ports(ctx context.Context, containerID string) ([]PortMapping, error)
+	
+	// ImageDigest returns the registry digest reference (name@sha256:...) of
+	// a pulled image, or "" for one that never came from a registry
+	ImageDigest(ctx context.Context, image string) (string, error)
+	
+	// ImageExposedPorts returns the ports declared with EXPOSE in an image
+	ImageExposedPorts(ctx context.Context, image string) ([]PortMapping, error)
+	
+	// SocketPath returns the host path of the runtime's API socket
+	SocketPath(ctx context.Context) (string, error)
+	
+	// CopyTo copies a host path into a container
+	CopyTo(ctx context.Context, containerID, src, dst string) error
+	
+	// CopyFrom copies a container path to the host
+	CopyFrom(ctx context.Context, containerID, src, dst string) error
+	
+	// Stats samples a running container's CPU and memory usage
+	Stats(ctx context.Context, containerID string) (ResourceUsage, error)
+	
+	// ExecSessions counts the exec sessions (terminals, exec commands) open in a container
+	ExecSessions(ctx context.Context, containerID string) (int, error)
 }
 
 // Manager manages container runtime detection and operations
 type Manager struct {
-	runtime Runtime
+	runtime    Runtime
+	host       string
+	connection string
+}
+
+// RuntimeOptions configures how the runtime CLI reaches its daemon
+type RuntimeOptions struct {
+	// Host is a daemon URL such as ssh://user@builder or tcp://builder:2376,
+	// passed to docker as --host and to podman as --url
+	Host string
+	
+	// Connection names a saved podman system connection (podman --connection)
+	// or docker context (docker --context). It cannot be combined with Host.
+	Connection string
 }
 
 // NewManager creates a new container manager with auto-detected runtime
 func NewManager() (*Manager, error) {
+	return NewManagerWithOptions("auto", RuntimeOptions{})
+}
+
+// NewManagerWithRuntime creates a manager with a specific runtime
+func NewManagerWithRuntime(runtimeName string) (*Manager, error) {
+	return NewManagerWithOptions(runtimeName, RuntimeOptions{})
+}
+
+// UnavailableError reports that no usable container runtime was found
+type UnavailableError struct {
+	Runtime string // the configured runtime; empty when auto-detecting
+}
+
+func (e *UnavailableError) Error() string {
+	if e.Runtime == "" {
+		return "no container runtime found (tried podman, docker, container)"
+	}
+	return fmt.Sprintf("runtime %s is not available", e.Runtime)
+}
+
+// NewManagerWithOptions creates a manager for runtimeName ("auto" to detect)
+// connected according to opts
+func NewManagerWithOptions(runtimeName string, opts RuntimeOptions) (*Manager, error) {
 	ctx := context.Background()
 	
-	// Try Podman first (preferred)
-	podman := &PodmanRuntime{}
-	if isRuntimeAvailable(ctx, podman) {
-		return &Manager{runtime: podman}, nil
+	if opts.Host != "" && opts.Connection != "" {
+		return nil, fmt.Errorf("runtime_host and runtime_connection cannot both be set")
+	}
+	
+	if runtimeName == "" || strings.ToLower(runtimeName) == "auto" {
+		// Podman is preferred, then Docker, then Apple's container CLI on macOS 15+
+		for _, name := range []string{"podman", "docker", "container"} {
+			runtime, err := newRuntime(name, opts)
+			if err != nil {
+				continue
+			}
+			if isRuntimeAvailable(ctx, runtime) {
+				return &Manager{runtime: runtime, host: opts.Host, connection: opts.Connection}, nil
+			}
+		}
+		return nil, &UnavailableError{}
+	}
+	
+	runtime, err := newRuntime(runtimeName, opts)
+	if err != nil {
+		return nil, err
 	}
 	
-	// Fall back to Docker
-	docker := &DockerRuntime{}
-	if isRuntimeAvailable(ctx, docker) {
-		return &Manager{runtime: docker}, nil
+	if !isRuntimeAvailable(ctx, runtime) {
+		return nil, &UnavailableError{Runtime: runtimeName}
 	}
 	
-	return nil, fmt.Errorf("no container runtime found (tried podman, docker)")
+	return &Manager{runtime: runtime, host: opts.Host, connection: opts.Connection}, nil
 }
 
-// NewManagerWithRuntime creates a manager with a specific runtime
-func NewManagerWithRuntime(runtimeName string) (*Manager, error) {
-	ctx := context.Background()
-	
-	var runtime Runtime
+// RuntimeAvailable reports whether the named runtime is installed and
+// responding, connected according to opts
+func RuntimeAvailable(runtimeName string, opts RuntimeOptions) bool {
+	runtime, err := newRuntime(runtimeName, opts)
+	if err != nil {
+		return false
+	}
+	return isRuntimeAvailable(context.Background(), runtime)
+}
+
+// newRuntime constructs a runtime by name with its connection flags
+func newRuntime(runtimeName string, opts RuntimeOptions) (Runtime, error) {
 	switch strings.ToLower(runtimeName) {
 	case "podman":
-		runtime = &PodmanRuntime{}
+		runtime := &PodmanRuntime{}
+		if opts.Host != "" {
+			runtime.host = opts.Host
+			runtime.globalArgs = []string{"--url", opts.Host}
+		}
+		if opts.Connection != "" {
+			runtime.globalArgs = []string{"--connection", opts.Connection}
+		}
+		return runtime, nil
 	case "docker":
-		runtime = &DockerRuntime{}
+		runtime := &DockerRuntime{}
+		if opts.Host != "" {
+			runtime.host = opts.Host
+			runtime.globalArgs = []string{"--host", opts.Host}
+		}
+		if opts.Connection != "" {
+			runtime.globalArgs = []string{"--context", opts.Connection}
+		}
+		return runtime, nil
+	case "container", "apple":
+		if opts.Host != "" || opts.Connection != "" {
+			return nil, fmt.Errorf("runtime %s does not support remote hosts", runtimeName)
+		}
+		return &AppleRuntime{}, nil
 	default:
 		return nil, fmt.Errorf("unsupported runtime: %s", runtimeName)
 	}
-	
-	if !isRuntimeAvailable(ctx, runtime) {
-		return nil, fmt.Errorf("runtime %s is not available", runtimeName)
+}
+
+// Endpoint returns the daemon endpoint the runtime uses: the configured
+// host, or whatever the CLI resolves from its environment and context
+func (m *Manager) Endpoint(ctx context.Context) string {
+	if !m.Available() {
+		return ""
 	}
-	
-	return &Manager{runtime: runtime}, nil
+	if m.host != "" {
+		return m.host
+	}
+	if m.connection != "" {
+		return connectionEndpoint(ctx, m.runtime.Name(), m.connection)
+	}
+	return runtimeEndpoint(ctx, m.runtime.Name())
+}
+
+// RemoteHost returns the endpoint when the daemon runs on another machine,
+// in which case local paths cannot be bind mounted into containers
+func (m *Manager) RemoteHost(ctx context.Context) (string, bool) {
+	endpoint := m.Endpoint(ctx)
+	if isRemoteEndpoint(endpoint) {
+		return endpoint, true
+	}
+	return "", false
 }
 
 // GetRuntime returns the underlying runtime interface
@@ -145,6 +338,41 @@ func (m *Manager) GetRuntime() Runtime {
 	return m.runtime
 }
 
+// CommandLine returns the runtime CLI and its connection flags, for tools
+// such as rsync that shell out to the runtime themselves
+func (m *Manager) CommandLine() []string {
+	switch r := m.runtime.(type) {
+	case *PodmanRuntime:
+		return append([]string{r.Name()}, r.globalArgs...)
+	case *DockerRuntime:
+		return append([]string{r.Name()}, r.globalArgs...)
+	default:
+		return []string{m.runtime.Name()}
+	}
+}
+
+// RuntimeVersion is the result of probing one runtime CLI
+type RuntimeVersion struct {
+	Name    string
+	Version string
+	Err     error
+}
+
+// DetectRuntimes probes every supported runtime CLI on the local host,
+// in auto-detection order
+func DetectRuntimes(ctx context.Context) []RuntimeVersion {
+	var versions []RuntimeVersion
+	for _, name := range []string{"podman", "docker", "container"} {
+		runtime, err := newRuntime(name, RuntimeOptions{})
+		if err != nil {
+			continue
+		}
+		version, err := runtime.Detect(ctx)
+		versions = append(versions, RuntimeVersion{Name: name, Version: version, Err: err})
+	}
+	return versions
+}
+
 // isRuntimeAvailable checks if a runtime is available on the system
 func isRuntimeAvailable(ctx context.Context, runtime Runtime) bool {
 	_, err := runtime.Detect(ctx)
@@ -153,27 +381,122 @@ func isRuntimeAvailable(ctx context.Context, runtime Runtime) bool {
 
 // Base implementation for common runtime operations
 type baseRuntime struct {
-	command string
+	command    string
+	host       string   // configured daemon URL, if any
+	globalArgs []string // connection flags placed before every subcommand
 }
 
 func (r *baseRuntime) execCommand(ctx context.Context, args ...string) ([]byte, error) {
-	cmd := exec.CommandContext(ctx, r.command, args...)
-	return cmd.Output()
+	cmd := exec.CommandContext(ctx, r.command, r.withGlobalArgs(args)...)
+	return logging.Output(cmd)
+}
+
+// writeSecretEnvFile writes secrets as KEY=value lines to a file only the
+// user can read, returning its path
+func writeSecretEnvFile(secrets map[string]string) (string, error) {
+	file, err := os.CreateTemp("", "cc-buddy-secrets-*.env")
+	if err != nil {
+		return "", fmt.Errorf("failed to create secrets env file: %w", err)
+	}
+	defer file.Close()
+	if err := file.Chmod(0600); err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to protect secrets env file: %w", err)
+	}
+	for key, value := range secrets {
+		if strings.ContainsAny(value, "\r\n") {
+			os.Remove(file.Name())
+			return "", fmt.Errorf("secret %s spans several lines, which env files cannot hold", key)
+		}
+		if _, err := fmt.Fprintf(file, "%s=%s\n", key, value); err != nil {
+			os.Remove(file.Name())
+			return "", fmt.Errorf("failed to write secrets env file: %w", err)
+		}
+	}
+	return file.Name(), nil
 }
 
 func (r *baseRuntime) execCommandStreaming(ctx context.Context, args ...string) error {
-	cmd := exec.CommandContext(ctx, r.command, args...)
+	cmd := exec.CommandContext(ctx, r.command, r.withGlobalArgs(args)...)
 	cmd.Stdout = nil // TODO: wire up to progress reporting
 	cmd.Stderr = nil // TODO: wire up to error reporting
-	return cmd.Run()
+	return logging.Run(cmd)
+}
+
+// withGlobalArgs prefixes args with the runtime's connection flags
+func (r *baseRuntime) withGlobalArgs(args []string) []string {
+	if len(r.globalArgs) == 0 {
+		return args
+	}
+	return append(append([]string{}, r.globalArgs...), args...)
+}
+
+// pull runs a pull command quietly, keeping its output for the error
+func (r *baseRuntime) pull(ctx context.Context, args ...string) error {
+	cmd := exec.CommandContext(ctx, r.command, r.withGlobalArgs(args)...)
+	if out, err := logging.CombinedOutput(cmd); err != nil {
+		return fmt.Errorf("failed to pull %s: %w: %s", args[len(args)-1], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// copyFiles copies between the host and a container ("container:path")
+func (r *baseRuntime) copyFiles(ctx context.Context, src, dst string) error {
+	return r.execCommandStreaming(ctx, "cp", src, dst)
+}
+
+// ports lists published ports via the "port" subcommand shared by both runtimes
+func (r *baseRuntime) ports(ctx context.Context, containerID string) ([]PortMapping, error) {
+	out, err := r.execCommand(ctx, "port", containerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ports: %w", err)
+	}
+	return parsePortOutput(string(out)), nil
+}
+
+// imageExposedPorts reads EXPOSE declarations from image metadata
+func (r *baseRuntime) imageExposedPorts(ctx context.Context, image string) ([]PortMapping, error) {
+	out, err := r.execCommand(ctx, "image", "inspect", "--format", "{{json .Config.ExposedPorts}}", image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect image %s: %w", image, err)
+	}
+	return parseExposedPorts(out)
+}
+
+func (r *baseRuntime) imageDigest(ctx context.Context, image string) (string, error) {
+	out, err := r.execCommand(ctx, "image", "inspect", "--format", "{{range .RepoDigests}}{{println .}}{{end}}", image)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect image %s: %w", image, err)
+	}
+	digests := strings.Fields(string(out))
+	if len(digests) == 0 {
+		return "", nil
+	}
+	return digests[0], nil
 }
 
 func (r *baseRuntime) execCommandInteractive(ctx context.Context, args ...string) error {
-	cmd := exec.CommandContext(ctx, r.command, args...)
+	argv := append([]string{r.command}, r.withGlobalArgs(args)...)
+	if underMintty() {
+		// Git Bash's mintty is not a Windows console; winpty bridges the TTY
+		if _, err := exec.LookPath("winpty"); err == nil {
+			argv = append([]string{"winpty"}, argv...)
+		} else {
+			fmt.Println("Warning: mintty is not a Windows console and winpty is not installed; if the session fails with \"not a TTY\", install winpty or use Windows Terminal or PowerShell")
+		}
+	}
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
 	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	cmd.Stdout = ci.Stdout()
+	cmd.Stderr = ci.Stderr()
+	return logging.Run(cmd)
+}
+
+// underMintty reports whether cc-buddy runs in Git Bash's default mintty
+// window on Windows. Windows Terminal, PowerShell and cmd are consoles the
+// runtime attaches to directly, Git Bash inside Windows Terminal included.
+func underMintty() bool {
+	return goruntime.GOOS == "windows" && os.Getenv("MSYSTEM") != "" && os.Getenv("TERM_PROGRAM") == "mintty"
 }
 
 // PodmanRuntime implements Runtime for Podman
@@ -181,6 +504,10 @@ type PodmanRuntime struct {
 	baseRuntime
 }
 
+func (r *PodmanRuntime) Name() string {
+	return "podman"
+}
+
 func (r *PodmanRuntime) Detect(ctx context.Context) (string, error) {
 	r.command = "podman"
 	out, err := r.execCommand(ctx, "--version")
@@ -210,6 +537,10 @@ func (r *PodmanRuntime) Build(ctx context.Context, opts BuildOptions) error {
 		args = append(args, "--build-arg", fmt.Sprintf("%s=%s", key, value))
 	}
 	
+	for key, value := range opts.Labels {
+		args = append(args, "--label", fmt.Sprintf("%s=%s", key, value))
+	}
+	
 	for _, tag := range opts.Tags {
 		args = append(args, "-t", tag)
 	}
@@ -221,8 +552,11 @@ func (r *PodmanRuntime) Build(ctx context.Context, opts BuildOptions) error {
 
 func (r *PodmanRuntime) Run(ctx context.Context, opts RunOptions) (string, error) {
 	args := []string{"run"}
+	if opts.NoStart {
+		args = []string{"create"}
+	}
 	
-	if opts.Detach {
+	if opts.Detach && !opts.NoStart {
 		args = append(args, "-d")
 	}
 	
@@ -246,7 +580,27 @@ func (r *PodmanRuntime) Run(ctx context.Context, opts RunOptions) (string, error
 		args = append(args, "-w", opts.WorkingDir)
 	}
 	
+	if opts.CPUs != "" {
+		args = append(args, "--cpus", opts.CPUs)
+	}
+	
+	if opts.Memory != "" {
+		args = append(args, "--memory", opts.Memory)
+	}
+	
+	if opts.Network != "" {
+		args = append(args, "--network", opts.Network)
+		for _, alias := range opts.NetworkAliases {
+			args = append(args, "--network-alias", alias)
+		}
+	}
+	
 	for _, mount := range opts.Mounts {
+		// nocopy is only accepted in the -v form
+		if hasOption(mount.Options, "nocopy") {
+			args = append(args, "-v", fmt.Sprintf("%s:%s:%s", mount.Source, mount.Target, volumeOptions(mount.Options)))
+			continue
+		}
 		mountStr := fmt.Sprintf("type=%s,source=%s,target=%s", mount.Type, mount.Source, mount.Target)
 		if len(mount.Options) > 0 {
 			for _, option := range mount.Options {
@@ -257,14 +611,47 @@ func (r *PodmanRuntime) Run(ctx context.Context, opts RunOptions) (string, error
 	}
 	
 	for _, port := range opts.Ports {
-		portStr := fmt.Sprintf("%d:%d/%s", port.Host, port.Container, port.Protocol)
-		args = append(args, "-p", portStr)
+		args = append(args, "-p", port.PublishSpec())
 	}
 	
 	for key, value := range opts.EnvVars {
 		args = append(args, "-e", fmt.Sprintf("%s=%s", key, value))
 	}
+	if len(opts.SecretEnv) > 0 {
+		// Secrets go through a private env file, removed once the run is
+		// done, so they are neither in the arguments nor in the
+		// environment of the runtime's own CLI
+		envFile, err := writeSecretEnvFile(opts.SecretEnv)
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(envFile)
+		args = append(args, "--env-file", envFile)
+	}
+	
+	for key, value := range opts.Labels {
+		args = append(args, "--label", fmt.Sprintf("%s=%s", key, value))
+	}
+	
+	for _, securityOpt := range opts.SecurityOpts {
+		args = append(args, "--security-opt", securityOpt)
+	}
+	
+	if opts.Privileged {
+		args = append(args, "--privileged")
+	}
+	for _, capability := range opts.CapAdd {
+		args = append(args, "--cap-add", capability)
+	}
+	for _, device := range opts.Devices {
+		args = append(args, "--device", device)
+	}
+	
+	if opts.HealthCmd != "" {
+		args = append(args, "--health-cmd", opts.HealthCmd)
+	}
 	
+	args = append(args, opts.ExtraArgs...)
 	args = append(args, opts.Image)
 	
 	// Add custom command if specified
@@ -280,6 +667,10 @@ func (r *PodmanRuntime) Run(ctx context.Context, opts RunOptions) (string, error
 	return strings.TrimSpace(string(out)), nil
 }
 
+func (r *PodmanRuntime) Start(ctx context.Context, containerID string) error {
+	return r.execCommandStreaming(ctx, "start", containerID)
+}
+
 func (r *PodmanRuntime) Stop(ctx context.Context, containerID string) error {
 	return r.execCommandStreaming(ctx, "stop", containerID)
 }
@@ -298,6 +689,18 @@ func (r *PodmanRuntime) ExecNonInteractive(ctx context.Context, containerID stri
 	return r.execCommandStreaming(ctx, args...)
 }
 
+func (r *PodmanRuntime) ExecCapture(ctx context.Context, containerID string, command []string) (ExecResult, error) {
+	return r.execCapture(ctx, containerID, command)
+}
+
+func (r *PodmanRuntime) ExecAttached(ctx context.Context, containerID string, command []string) error {
+	return r.execAttached(ctx, containerID, command)
+}
+
+func (r *PodmanRuntime) ExecStream(ctx context.Context, containerID string, command []string, stdout, stderr io.Writer) (int, error) {
+	return r.execStream(ctx, containerID, command, stdout, stderr)
+}
+
 func (r *PodmanRuntime) Status(ctx context.Context, containerID string) (Status, error) {
 	out, err := r.execCommand(ctx, "inspect", "--format", "{{.State.Status}}", containerID)
 	if err != nil {
@@ -316,10 +719,19 @@ func (r *PodmanRuntime) Status(ctx context.Context, containerID string) (Status,
 		}
 	}
 	
+	// Health is only reported for images or runs with a HEALTHCHECK
+	var health string
+	healthOut, err := r.execCommand(ctx, "inspect", "--format", "{{if .State.Health}}{{.State.Health.Status}}{{end}}", containerID)
+	if err == nil {
+		health = strings.TrimSpace(string(healthOut))
+	}
+	
 	return Status{
-		Running: running,
-		Health:  statusStr,
-		Uptime:  uptime,
+		Running:   running,
+		State:     statusStr,
+		Health:    health,
+		Uptime:    uptime,
+		StartedAt: parseStartedAt(uptime),
 	}, nil
 }
 
@@ -338,8 +750,8 @@ func (r *PodmanRuntime) Logs(ctx context.Context, containerID string, follow boo
 	return strings.Split(string(out), "\n"), nil
 }
 
-func (r *PodmanRuntime) CreateVolume(ctx context.Context, name string) error {
-	return r.execCommandStreaming(ctx, "volume", "create", name)
+func (r *PodmanRuntime) CreateVolume(ctx context.Context, name string, labels map[string]string) error {
+	return r.execCommandStreaming(ctx, append(append([]string{"volume", "create"}, labelArgs(labels)...), name)...)
 }
 
 func (r *PodmanRuntime) RemoveVolume(ctx context.Context, name string) error {
@@ -350,11 +762,89 @@ func (r *PodmanRuntime) RemoveImage(ctx context.Context, imageID string) error {
 	return r.execCommandStreaming(ctx, "rmi", imageID)
 }
 
+func (r *PodmanRuntime) Tag(ctx context.Context, source, target string) error {
+	return r.execCommandStreaming(ctx, "tag", source, target)
+}
+
+func (r *PodmanRuntime) Pull(ctx context.Context, image string) error {
+	return r.pull(ctx, "pull", image)
+}
+
+func (r *PodmanRuntime) Push(ctx context.Context, image string) error {
+	return r.execCommandStreaming(ctx, "push", image)
+}
+
+func (r *PodmanRuntime) Images(ctx context.Context, label string) ([]Image, error) {
+	return r.images(ctx, label)
+}
+
+func (r *PodmanRuntime) Volumes(ctx context.Context, label string) ([]Volume, error) {
+	return r.volumes(ctx, label)
+}
+
+func (r *PodmanRuntime) Containers(ctx context.Context, label string) ([]string, error) {
+	return r.containers(ctx, label)
+}
+
+func (r *PodmanRuntime) Ports(ctx context.Context, containerID string) ([]PortMapping, error) {
+	return r.ports(ctx, containerID)
+}
+
+func (r *PodmanRuntime) ImageDigest(ctx context.Context, image string) (string, error) {
+	return r.imageDigest(ctx, image)
+}
+
+func (r *PodmanRuntime) ImageExposedPorts(ctx context.Context, image string) ([]PortMapping, error) {
+	return r.imageExposedPorts(ctx, image)
+}
+
+func (r *PodmanRuntime) SocketPath(ctx context.Context) (string, error) {
+	out, err := r.execCommand(ctx, "info", "--format", "{{.Host.RemoteSocket.Path}}")
+	if err != nil {
+		return "", fmt.Errorf("failed to query podman socket: %w", err)
+	}
+	path := strings.TrimPrefix(strings.TrimSpace(string(out)), "unix://")
+	if path == "" {
+		return "", fmt.Errorf("podman API socket not configured (try 'systemctl --user enable --now podman.socket')")
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("podman API socket %s not found (try 'systemctl --user enable --now podman.socket')", path)
+	}
+	return path, nil
+}
+
+func (r *PodmanRuntime) CopyTo(ctx context.Context, containerID, src, dst string) error {
+	return r.copyFiles(ctx, src, containerID+":"+dst)
+}
+
+func (r *PodmanRuntime) CopyFrom(ctx context.Context, containerID, src, dst string) error {
+	return r.copyFiles(ctx, containerID+":"+src, dst)
+}
+
+func (r *PodmanRuntime) Stats(ctx context.Context, containerID string) (ResourceUsage, error) {
+	return r.stats(ctx, containerID)
+}
+
+func (r *PodmanRuntime) ExecSessions(ctx context.Context, containerID string) (int, error) {
+	return r.execSessions(ctx, containerID)
+}
+
+func (r *PodmanRuntime) EnsureNetwork(ctx context.Context, name string) error {
+	if _, err := r.execCommand(ctx, "network", "inspect", name); err == nil {
+		return nil
+	}
+	return r.execCommandStreaming(ctx, "network", "create", name)
+}
+
 // DockerRuntime implements Runtime for Docker
 type DockerRuntime struct {
 	baseRuntime
 }
 
+func (r *DockerRuntime) Name() string {
+	return "docker"
+}
+
 func (r *DockerRuntime) Detect(ctx context.Context) (string, error) {
 	r.command = "docker"
 	out, err := r.execCommand(ctx, "--version")
@@ -385,6 +875,10 @@ func (r *DockerRuntime) Build(ctx context.Context, opts BuildOptions) error {
 		args = append(args, "--build-arg", fmt.Sprintf("%s=%s", key, value))
 	}
 	
+	for key, value := range opts.Labels {
+		args = append(args, "--label", fmt.Sprintf("%s=%s", key, value))
+	}
+	
 	for _, tag := range opts.Tags {
 		args = append(args, "-t", tag)
 	}
@@ -396,8 +890,11 @@ func (r *DockerRuntime) Build(ctx context.Context, opts BuildOptions) error {
 
 func (r *DockerRuntime) Run(ctx context.Context, opts RunOptions) (string, error) {
 	args := []string{"run"}
+	if opts.NoStart {
+		args = []string{"create"}
+	}
 	
-	if opts.Detach {
+	if opts.Detach && !opts.NoStart {
 		args = append(args, "-d")
 	}
 	
@@ -421,7 +918,28 @@ func (r *DockerRuntime) Run(ctx context.Context, opts RunOptions) (string, error
 		args = append(args, "-w", opts.WorkingDir)
 	}
 	
+	if opts.CPUs != "" {
+		args = append(args, "--cpus", opts.CPUs)
+	}
+	
+	if opts.Memory != "" {
+		args = append(args, "--memory", opts.Memory)
+	}
+	
+	if opts.Network != "" {
+		args = append(args, "--network", opts.Network)
+		for _, alias := range opts.NetworkAliases {
+			args = append(args, "--network-alias", alias)
+		}
+	}
+	
 	for _, mount := range opts.Mounts {
+		// Docker's --mount has no SELinux relabel field and spells nocopy
+		// differently; -v takes both as plain options
+		if (mount.Type == "bind" && hasRelabelOption(mount.Options)) || hasOption(mount.Options, "nocopy") {
+			args = append(args, "-v", fmt.Sprintf("%s:%s:%s", mount.Source, mount.Target, volumeOptions(mount.Options)))
+			continue
+		}
 		mountStr := fmt.Sprintf("type=%s,source=%s,target=%s", mount.Type, mount.Source, mount.Target)
 		if len(mount.Options) > 0 {
 			for _, option := range mount.Options {
@@ -432,14 +950,47 @@ func (r *DockerRuntime) Run(ctx context.Context, opts RunOptions) (string, error
 	}
 	
 	for _, port := range opts.Ports {
-		portStr := fmt.Sprintf("%d:%d/%s", port.Host, port.Container, port.Protocol)
-		args = append(args, "-p", portStr)
+		args = append(args, "-p", port.PublishSpec())
 	}
 	
 	for key, value := range opts.EnvVars {
 		args = append(args, "-e", fmt.Sprintf("%s=%s", key, value))
 	}
+	if len(opts.SecretEnv) > 0 {
+		// Secrets go through a private env file, removed once the run is
+		// done, so they are neither in the arguments nor in the
+		// environment of the runtime's own CLI
+		envFile, err := writeSecretEnvFile(opts.SecretEnv)
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(envFile)
+		args = append(args, "--env-file", envFile)
+	}
+	
+	for key, value := range opts.Labels {
+		args = append(args, "--label", fmt.Sprintf("%s=%s", key, value))
+	}
+	
+	for _, securityOpt := range opts.SecurityOpts {
+		args = append(args, "--security-opt", securityOpt)
+	}
+	
+	if opts.Privileged {
+		args = append(args, "--privileged")
+	}
+	for _, capability := range opts.CapAdd {
+		args = append(args, "--cap-add", capability)
+	}
+	for _, device := range opts.Devices {
+		args = append(args, "--device", device)
+	}
 	
+	if opts.HealthCmd != "" {
+		args = append(args, "--health-cmd", opts.HealthCmd)
+	}
+	
+	args = append(args, opts.ExtraArgs...)
 	args = append(args, opts.Image)
 	
 	// Add custom command if specified
@@ -455,6 +1006,10 @@ func (r *DockerRuntime) Run(ctx context.Context, opts RunOptions) (string, error
 	return strings.TrimSpace(string(out)), nil
 }
 
+func (r *DockerRuntime) Start(ctx context.Context, containerID string) error {
+	return r.execCommandStreaming(ctx, "start", containerID)
+}
+
 func (r *DockerRuntime) Stop(ctx context.Context, containerID string) error {
 	return r.execCommandStreaming(ctx, "stop", containerID)
 }
@@ -473,6 +1028,18 @@ func (r *DockerRuntime) ExecNonInteractive(ctx context.Context, containerID stri
 	return r.execCommandStreaming(ctx, args...)
 }
 
+func (r *DockerRuntime) ExecCapture(ctx context.Context, containerID string, command []string) (ExecResult, error) {
+	return r.execCapture(ctx, containerID, command)
+}
+
+func (r *DockerRuntime) ExecAttached(ctx context.Context, containerID string, command []string) error {
+	return r.execAttached(ctx, containerID, command)
+}
+
+func (r *DockerRuntime) ExecStream(ctx context.Context, containerID string, command []string, stdout, stderr io.Writer) (int, error) {
+	return r.execStream(ctx, containerID, command, stdout, stderr)
+}
+
 func (r *DockerRuntime) Status(ctx context.Context, containerID string) (Status, error) {
 	out, err := r.execCommand(ctx, "inspect", "--format", "{{.State.Status}}", containerID)
 	if err != nil {
@@ -491,10 +1058,19 @@ func (r *DockerRuntime) Status(ctx context.Context, containerID string) (Status,
 		}
 	}
 	
+	// Health is only reported for images or runs with a HEALTHCHECK
+	var health string
+	healthOut, err := r.execCommand(ctx, "inspect", "--format", "{{if .State.Health}}{{.State.Health.Status}}{{end}}", containerID)
+	if err == nil {
+		health = strings.TrimSpace(string(healthOut))
+	}
+	
 	return Status{
-		Running: running,
-		Health:  statusStr,
-		Uptime:  uptime,
+		Running:   running,
+		State:     statusStr,
+		Health:    health,
+		Uptime:    uptime,
+		StartedAt: parseStartedAt(uptime),
 	}, nil
 }
 
@@ -513,8 +1089,8 @@ func (r *DockerRuntime) Logs(ctx context.Context, containerID string, follow boo
 	return strings.Split(string(out), "\n"), nil
 }
 
-func (r *DockerRuntime) CreateVolume(ctx context.Context, name string) error {
-	return r.execCommandStreaming(ctx, "volume", "create", name)
+func (r *DockerRuntime) CreateVolume(ctx context.Context, name string, labels map[string]string) error {
+	return r.execCommandStreaming(ctx, append(append([]string{"volume", "create"}, labelArgs(labels)...), name)...)
 }
 
 func (r *DockerRuntime) RemoveVolume(ctx context.Context, name string) error {
@@ -523,4 +1099,82 @@ func (r *DockerRuntime) RemoveVolume(ctx context.Context, name string) error {
 
 func (r *DockerRuntime) RemoveImage(ctx context.Context, imageID string) error {
 	return r.execCommandStreaming(ctx, "rmi", imageID)
+}
+
+func (r *DockerRuntime) Tag(ctx context.Context, source, target string) error {
+	return r.execCommandStreaming(ctx, "tag", source, target)
+}
+
+func (r *DockerRuntime) Pull(ctx context.Context, image string) error {
+	return r.pull(ctx, "pull", image)
+}
+
+func (r *DockerRuntime) Push(ctx context.Context, image string) error {
+	return r.execCommandStreaming(ctx, "push", image)
+}
+
+func (r *DockerRuntime) Images(ctx context.Context, label string) ([]Image, error) {
+	return r.images(ctx, label)
+}
+
+func (r *DockerRuntime) Volumes(ctx context.Context, label string) ([]Volume, error) {
+	return r.volumes(ctx, label)
+}
+
+func (r *DockerRuntime) Containers(ctx context.Context, label string) ([]string, error) {
+	return r.containers(ctx, label)
+}
+
+func (r *DockerRuntime) Stats(ctx context.Context, containerID string) (ResourceUsage, error) {
+	return r.stats(ctx, containerID)
+}
+
+func (r *DockerRuntime) ExecSessions(ctx context.Context, containerID string) (int, error) {
+	return r.execSessions(ctx, containerID)
+}
+
+func (r *DockerRuntime) EnsureNetwork(ctx context.Context, name string) error {
+	if _, err := r.execCommand(ctx, "network", "inspect", name); err == nil {
+		return nil
+	}
+	return r.execCommandStreaming(ctx, "network", "create", name)
+}
+
+func (r *DockerRuntime) Ports(ctx context.Context, containerID string) ([]PortMapping, error) {
+	return r.ports(ctx, containerID)
+}
+
+func (r *DockerRuntime) ImageDigest(ctx context.Context, image string) (string, error) {
+	return r.imageDigest(ctx, image)
+}
+
+func (r *DockerRuntime) ImageExposedPorts(ctx context.Context, image string) ([]PortMapping, error) {
+	return r.imageExposedPorts(ctx, image)
+}
+
+func (r *DockerRuntime) SocketPath(ctx context.Context) (string, error) {
+	path := "/var/run/docker.sock"
+	// Configured host, DOCKER_HOST or the active context (e.g. ~/.colima/default/docker.sock)
+	host := r.host
+	if host == "" {
+		host = runtimeEndpoint(ctx, "docker")
+	}
+	if host != "" {
+		if !strings.HasPrefix(host, "unix://") {
+			return "", fmt.Errorf("docker endpoint %s is not a local unix socket", host)
+		}
+		path = strings.TrimPrefix(host, "unix://")
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("docker socket %s not found", path)
+	}
+	return path, nil
+}
+
+func (r *DockerRuntime) CopyTo(ctx context.Context, containerID, src, dst string) error {
+	return r.copyFiles(ctx, src, containerID+":"+dst)
+}
+
+func (r *DockerRuntime) CopyFrom(ctx context.Context, containerID, src, dst string) error {
+	return r.copyFiles(ctx, containerID+":"+src, dst)
 }
\ No newline at end of file
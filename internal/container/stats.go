@@ -0,0 +1,90 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ResourceUsage is a point-in-time sample of a container's resource usage
+type ResourceUsage struct {
+	CPUPercent  float64 // share of one CPU, e.g. 150 for one and a half cores
+	MemoryBytes uint64
+	MemoryLimit uint64 // 0 when unlimited or unknown
+}
+
+// stats samples resource usage via "stats --no-stream", whose CPUPerc and
+// MemUsage template fields both runtimes support
+func (r *baseRuntime) stats(ctx context.Context, containerID string) (ResourceUsage, error) {
+	out, err := r.execCommand(ctx, "stats", "--no-stream", "--format", "{{.CPUPerc}}|{{.MemUsage}}", containerID)
+	if err != nil {
+		return ResourceUsage{}, fmt.Errorf("failed to get container stats: %w", err)
+	}
+	return parseStats(strings.TrimSpace(string(out)))
+}
+
+// execSessions counts the container's exec sessions from its ExecIDs, which
+// both runtimes keep while a session runs
+func (r *baseRuntime) execSessions(ctx context.Context, containerID string) (int, error) {
+	out, err := r.execCommand(ctx, "inspect", "--format", "{{len .ExecIDs}}", containerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to inspect exec sessions: %w", err)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		return 0, fmt.Errorf("unexpected exec session count %q", strings.TrimSpace(string(out)))
+	}
+	return count, nil
+}
+
+// parseStats parses a "12.5%|100MiB / 2GiB" stats line
+func parseStats(line string) (ResourceUsage, error) {
+	cpu, memory, ok := strings.Cut(line, "|")
+	if !ok {
+		return ResourceUsage{}, fmt.Errorf("unexpected stats output %q", line)
+	}
+
+	var usage ResourceUsage
+	cpuPercent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(cpu), "%"), 64)
+	if err != nil {
+		return ResourceUsage{}, fmt.Errorf("unexpected CPU usage %q", cpu)
+	}
+	usage.CPUPercent = cpuPercent
+
+	used, limit, _ := strings.Cut(memory, "/")
+	if usage.MemoryBytes, err = parseSize(used); err != nil {
+		return ResourceUsage{}, err
+	}
+	if limit != "" {
+		// A limit that cannot be parsed is reported as unknown
+		usage.MemoryLimit, _ = parseSize(limit)
+	}
+	return usage, nil
+}
+
+// sizeUnits are the suffixes used by the runtimes' human-readable sizes
+var sizeUnits = []struct {
+	suffix     string
+	multiplier float64
+}{
+	// Longest suffixes first so "MiB" is not matched as "B"
+	{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30}, {"TiB", 1 << 40},
+	{"kB", 1e3}, {"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9}, {"TB", 1e12},
+	{"B", 1},
+}
+
+// parseSize converts sizes such as "512MiB" or "1.5GB" to bytes
+func parseSize(size string) (uint64, error) {
+	size = strings.TrimSpace(size)
+	for _, unit := range sizeUnits {
+		if number, ok := strings.CutSuffix(size, unit.suffix); ok {
+			value, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+			if err != nil {
+				break
+			}
+			return uint64(value * unit.multiplier), nil
+		}
+	}
+	return 0, fmt.Errorf("unexpected size %q", size)
+}
@@ -0,0 +1,127 @@
+package container
+
+import (
+	"context"
+	"io"
+)
+
+// unavailableRuntime stands in for a missing runtime so that commands which
+// only need worktrees and state, such as list and delete, still work. Every
+// operation fails with the UnavailableError.
+type unavailableRuntime struct {
+	err *UnavailableError
+}
+
+// NewUnavailableManager returns a manager without a runtime whose
+// operations all fail with err
+func NewUnavailableManager(err *UnavailableError) *Manager {
+	return &Manager{runtime: &unavailableRuntime{err: err}}
+}
+
+// Available reports whether the manager has a container runtime
+func (m *Manager) Available() bool {
+	_, missing := m.runtime.(*unavailableRuntime)
+	return !missing
+}
+
+func (r *unavailableRuntime) Name() string { return "none" }
+
+func (r *unavailableRuntime) Detect(ctx context.Context) (string, error) { return "", r.err }
+
+func (r *unavailableRuntime) Build(ctx context.Context, opts BuildOptions) error { return r.err }
+
+func (r *unavailableRuntime) Run(ctx context.Context, opts RunOptions) (string, error) {
+	return "", r.err
+}
+
+func (r *unavailableRuntime) Start(ctx context.Context, containerID string) error { return r.err }
+
+func (r *unavailableRuntime) Stop(ctx context.Context, containerID string) error { return r.err }
+
+func (r *unavailableRuntime) Remove(ctx context.Context, containerID string) error { return r.err }
+
+func (r *unavailableRuntime) Exec(ctx context.Context, containerID string, command []string) error {
+	return r.err
+}
+
+func (r *unavailableRuntime) ExecNonInteractive(ctx context.Context, containerID string, command []string) error {
+	return r.err
+}
+
+func (r *unavailableRuntime) ExecCapture(ctx context.Context, containerID string, command []string) (ExecResult, error) {
+	return ExecResult{}, r.err
+}
+
+func (r *unavailableRuntime) ExecAttached(ctx context.Context, containerID string, command []string) error {
+	return r.err
+}
+
+func (r *unavailableRuntime) ExecStream(ctx context.Context, containerID string, command []string, stdout, stderr io.Writer) (int, error) {
+	return 0, r.err
+}
+
+func (r *unavailableRuntime) Status(ctx context.Context, containerID string) (Status, error) {
+	return Status{}, r.err
+}
+
+func (r *unavailableRuntime) Logs(ctx context.Context, containerID string, follow bool) ([]string, error) {
+	return nil, r.err
+}
+
+func (r *unavailableRuntime) CreateVolume(ctx context.Context, name string, labels map[string]string) error {
+	return r.err
+}
+
+func (r *unavailableRuntime) RemoveVolume(ctx context.Context, name string) error { return r.err }
+
+func (r *unavailableRuntime) RemoveImage(ctx context.Context, imageID string) error { return r.err }
+
+func (r *unavailableRuntime) Tag(ctx context.Context, source, target string) error { return r.err }
+
+func (r *unavailableRuntime) Pull(ctx context.Context, image string) error { return r.err }
+
+func (r *unavailableRuntime) Push(ctx context.Context, image string) error { return r.err }
+
+func (r *unavailableRuntime) Images(ctx context.Context, label string) ([]Image, error) {
+	return nil, r.err
+}
+
+func (r *unavailableRuntime) Volumes(ctx context.Context, label string) ([]Volume, error) {
+	return nil, r.err
+}
+
+func (r *unavailableRuntime) Containers(ctx context.Context, label string) ([]string, error) {
+	return nil, r.err
+}
+
+func (r *unavailableRuntime) EnsureNetwork(ctx context.Context, name string) error { return r.err }
+
+func (r *unavailableRuntime) Ports(ctx context.Context, containerID string) ([]PortMapping, error) {
+	return nil, r.err
+}
+
+func (r *unavailableRuntime) ImageDigest(ctx context.Context, image string) (string, error) {
+	return "", r.err
+}
+
+func (r *unavailableRuntime) ImageExposedPorts(ctx context.Context, image string) ([]PortMapping, error) {
+	return nil, r.err
+}
+
+func (r *unavailableRuntime) SocketPath(ctx context.Context) (string, error) { return "", r.err }
+
+func (r *unavailableRuntime) CopyTo(ctx context.Context, containerID, src, dst string) error {
+	return r.err
+}
+
+func (r *unavailableRuntime) CopyFrom(ctx context.Context, containerID, src, dst string) error {
+	return r.err
+}
+
+func (r *unavailableRuntime) Stats(ctx context.Context, containerID string) (ResourceUsage, error) {
+	return ResourceUsage{}, r.err
+}
+
+func (r *unavailableRuntime) ExecSessions(ctx context.Context, containerID string) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,162 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// VMInfo describes a Lima-based VM that hosts the container runtime on macOS
+type VMInfo struct {
+	Kind     string // "colima" or "lima"
+	Instance string // Colima profile or Lima instance name
+	Socket   string // host path of the runtime socket forwarded from the VM
+}
+
+// String formats the VM for display, e.g. "colima (default)"
+func (v *VMInfo) String() string {
+	return fmt.Sprintf("%s (%s)", v.Kind, v.Instance)
+}
+
+// DetectVM reports whether the runtime talks to a daemon inside a Lima or
+// Colima VM, based on the socket its CLI is configured to use. It returns nil
+// when the runtime runs natively or remotely.
+func (m *Manager) DetectVM(ctx context.Context) *VMInfo {
+	if goruntime.GOOS != "darwin" {
+		return nil
+	}
+
+	endpoint := m.Endpoint(ctx)
+	socket := strings.TrimPrefix(endpoint, "unix://")
+
+	for _, kind := range []string{"colima", "lima"} {
+		marker := string(filepath.Separator) + "." + kind + string(filepath.Separator)
+		idx := strings.Index(socket, marker)
+		if idx < 0 {
+			continue
+		}
+		// The directory after ~/.colima/ or ~/.lima/ names the instance
+		rest := socket[idx+len(marker):]
+		instance, _, _ := strings.Cut(rest, string(filepath.Separator))
+		if instance == "" {
+			instance = "default"
+		}
+		return &VMInfo{Kind: kind, Instance: instance, Socket: socket}
+	}
+
+	return nil
+}
+
+// runtimeEndpoint returns the daemon endpoint the runtime CLI will use
+func runtimeEndpoint(ctx context.Context, runtimeName string) string {
+	switch runtimeName {
+	case "docker":
+		if host := os.Getenv("DOCKER_HOST"); host != "" {
+			return host
+		}
+		out, err := logging.Output(exec.CommandContext(ctx, "docker", "context", "inspect", "--format", "{{.Endpoints.docker.Host}}"))
+		if err == nil {
+			return strings.TrimSpace(string(out))
+		}
+	case "podman":
+		if host := os.Getenv("CONTAINER_HOST"); host != "" {
+			return host
+		}
+		if connection := os.Getenv("CONTAINER_CONNECTION"); connection != "" {
+			return connectionEndpoint(ctx, "podman", connection)
+		}
+		out, err := logging.Output(exec.CommandContext(ctx, "podman", "system", "connection", "list", "--format", "{{if .Default}}{{.URI}}{{end}}"))
+		if err == nil {
+			return strings.TrimSpace(string(out))
+		}
+	}
+	return ""
+}
+
+// connectionEndpoint returns the URI of a named podman connection or docker context
+func connectionEndpoint(ctx context.Context, runtimeName, connection string) string {
+	switch runtimeName {
+	case "docker":
+		out, err := logging.Output(exec.CommandContext(ctx, "docker", "context", "inspect", connection, "--format", "{{.Endpoints.docker.Host}}"))
+		if err == nil {
+			return strings.TrimSpace(string(out))
+		}
+	case "podman":
+		out, err := logging.Output(exec.CommandContext(ctx, "podman", "system", "connection", "list", "--format", "{{.Name}} {{.URI}}"))
+		if err != nil {
+			return ""
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			name, uri, ok := strings.Cut(strings.TrimSpace(line), " ")
+			if ok && name == connection {
+				return uri
+			}
+		}
+	}
+	return ""
+}
+
+// isRemoteEndpoint reports whether an endpoint URI points at another machine.
+// SSH connections to the loopback address (podman machine) are local: the VM
+// shares the home directory, so bind mounts keep working.
+func isRemoteEndpoint(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "ssh":
+		host := u.Hostname()
+		return host != "localhost" && host != "127.0.0.1" && host != "::1"
+	case "tcp", "http", "https":
+		return true
+	default:
+		return false
+	}
+}
+
+// VMHasPath reports whether path is visible inside the VM, i.e. whether the
+// directory is shared into it and can be bind mounted into containers
+func (v *VMInfo) VMHasPath(ctx context.Context, path string) (bool, error) {
+	var cmd *exec.Cmd
+	switch v.Kind {
+	case "colima":
+		cmd = exec.CommandContext(ctx, "colima", "ssh", "--profile", v.Instance, "--", "test", "-d", path)
+	case "lima":
+		cmd = exec.CommandContext(ctx, "limactl", "shell", v.Instance, "test", "-d", path)
+	default:
+		return false, fmt.Errorf("unsupported VM kind %s", v.Kind)
+	}
+
+	if err := logging.Run(cmd); err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to query %s VM: %w", v.Kind, err)
+	}
+	return true, nil
+}
+
+// TranslatePath maps a host path to its location inside the VM using
+// pathMap (host prefix to VM prefix). Lima and Colima mount host
+// directories at the same path by default, so unmapped paths are unchanged.
+func TranslatePath(path string, pathMap map[string]string) string {
+	// Prefer the longest matching prefix
+	var bestHost string
+	for hostPrefix := range pathMap {
+		if (path == hostPrefix || strings.HasPrefix(path, strings.TrimSuffix(hostPrefix, "/")+"/")) && len(hostPrefix) > len(bestHost) {
+			bestHost = hostPrefix
+		}
+	}
+	if bestHost == "" {
+		return path
+	}
+	return filepath.Join(pathMap[bestHost], strings.TrimPrefix(path, strings.TrimSuffix(bestHost, "/")))
+}
@@ -0,0 +1,57 @@
+package container
+
+import (
+	"context"
+	"strings"
+)
+
+// dockerDesktopHostMount is where Docker Desktop exposes Windows drives to
+// containers, e.g. C:\ is /run/desktop/mnt/host/c
+const dockerDesktopHostMount = "/run/desktop/mnt/host"
+
+// IsDockerDesktop reports whether the runtime is docker backed by Docker
+// Desktop, as it is inside WSL with Docker Desktop integration enabled
+func (m *Manager) IsDockerDesktop(ctx context.Context) bool {
+	docker, ok := m.runtime.(*DockerRuntime)
+	if !ok {
+		return false
+	}
+	out, err := docker.execCommand(ctx, "info", "--format", "{{.OperatingSystem}}")
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(out), "Docker Desktop")
+}
+
+// WindowsDrivePath reports whether path is on a Windows drive mounted into
+// WSL (/mnt/c/...). Files there cross the 9P boundary on every access.
+func WindowsDrivePath(path string) bool {
+	_, _, ok := splitWindowsDrivePath(path)
+	return ok
+}
+
+// TranslateWSLPath maps a WSL path on a Windows drive (/mnt/c/src) to where
+// Docker Desktop exposes that drive to containers. Other paths are unchanged.
+func TranslateWSLPath(path string) string {
+	drive, rest, ok := splitWindowsDrivePath(path)
+	if !ok {
+		return path
+	}
+	return dockerDesktopHostMount + "/" + drive + rest
+}
+
+// splitWindowsDrivePath splits /mnt/c/src into the drive letter "c" and "/src"
+func splitWindowsDrivePath(path string) (string, string, bool) {
+	if !strings.HasPrefix(path, "/mnt/") || len(path) < len("/mnt/c") {
+		return "", "", false
+	}
+	drive := path[len("/mnt/") : len("/mnt/")+1]
+	rest := path[len("/mnt/c"):]
+	if rest != "" && rest[0] != '/' {
+		return "", "", false
+	}
+	if drive[0] < 'a' || drive[0] > 'z' {
+		return "", "", false
+	}
+	return drive, rest, true
+}
@@ -0,0 +1,456 @@
+package daemon
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+)
+
+// APITokenFile is where the daemon keeps its generated API token, in the
+// state directory; clients on the machine read it from there
+const APITokenFile = "api-token"
+
+// maxAPIBody bounds the size of an API request body
+const maxAPIBody = 1 << 20
+
+// createRequest is the body of POST /api/v1/environments
+type createRequest struct {
+	Branch        string            `json:"branch"` // may be a remote reference such as origin/main
+	Containerfile string            `json:"containerfile,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	NoStart       bool              `json:"no_start,omitempty"`
+	Wait          bool              `json:"wait,omitempty"`      // wait until ready before answering
+	WaitPort      int               `json:"wait_port,omitempty"` // port --wait probes
+}
+
+// execRequest is the body of POST /api/v1/environments/{name}/exec
+type execRequest struct {
+	Command []string `json:"command"`
+}
+
+// execResponse is the result of an exec, as exec --output json prints it
+type execResponse struct {
+	Environment string   `json:"environment"`
+	Command     []string `json:"command"`
+	ExitCode    int      `json:"exit_code"`
+	DurationMS  int64    `json:"duration_ms"`
+	Stdout      string   `json:"stdout"`
+	Stderr      string   `json:"stderr"`
+}
+
+// execFrame is one line of an exec/stream answer: a chunk of the command's
+// output, then last its exit code or the error that stopped it. Output is
+// base64 in JSON, so it arrives byte for byte.
+type execFrame struct {
+	Stdout     []byte `json:"stdout,omitempty"`
+	Stderr     []byte `json:"stderr,omitempty"`
+	ExitCode   *int   `json:"exit_code,omitempty"`
+	DurationMS int64  `json:"duration_ms,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// loadAPIToken returns the configured api_token, or else the token in
+// .cc-buddy/api-token, generating it on first use
+func loadAPIToken(configured string) (string, error) {
+	if configured != "" {
+		return configured, nil
+	}
+	path := filepath.Join(config.StateDir, APITokenFile)
+	data, err := os.ReadFile(path)
+	if err == nil && len(strings.TrimSpace(string(data))) > 0 {
+		return strings.TrimSpace(string(data)), nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read API token: %w", err)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate API token: %w", err)
+	}
+	token := hex.EncodeToString(secret)
+	if err := os.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("failed to write API token: %w", err)
+	}
+	return token, nil
+}
+
+// apiRoutes adds the /api/v1 endpoints to mux, each requiring the token
+func (s *Server) apiRoutes(mux *http.ServeMux) {
+	routes := map[string]http.HandlerFunc{
+		"GET /api/v1/environments":                     s.apiList,
+		"POST /api/v1/environments":                    s.apiCreate,
+		"GET /api/v1/environments/{name}":              s.apiGet,
+		"DELETE /api/v1/environments/{name}":           s.apiDelete,
+		"POST /api/v1/environments/{name}/exec":        s.apiExec,
+		"POST /api/v1/environments/{name}/exec/stream": s.apiExecStream,
+		"GET /api/v1/environments/{name}/logs":         s.apiLogs,
+		"POST /api/v1/environments/{name}/start":       s.apiStart,
+		"POST /api/v1/environments/{name}/stop":        s.apiStop,
+		"POST /api/v1/environments/{name}/rebuild":     s.apiRebuild,
+	}
+	for pattern, handler := range routes {
+		mux.Handle(pattern, s.requireToken(handler))
+	}
+}
+
+// requireToken rejects requests without the API token as a bearer token
+func (s *Server) requireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.apiToken == "" {
+			writeAPIError(w, http.StatusServiceUnavailable, errors.New("the API is not available"))
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.apiToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeAPIError(w, http.StatusUnauthorized, errors.New("missing or invalid API token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// apiList answers with every environment, with the ports of running ones
+func (s *Server) apiList(w http.ResponseWriter, r *http.Request) {
+	environments, err := s.listWithPorts(r.Context())
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, environments)
+}
+
+// apiGet answers with one environment
+func (s *Server) apiGet(w http.ResponseWriter, r *http.Request) {
+	environments, err := s.listWithPorts(r.Context())
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err)
+		return
+	}
+	for _, env := range environments {
+		if env.Name == r.PathValue("name") {
+			writeJSON(w, http.StatusOK, env)
+			return
+		}
+	}
+	writeAPIError(w, http.StatusNotFound, fmt.Errorf("environment '%s' not found", r.PathValue("name")))
+}
+
+// apiCreate creates an environment and answers with it once created (and
+// ready, when asked to wait)
+func (s *Server) apiCreate(w http.ResponseWriter, r *http.Request) {
+	var req createRequest
+	if !decodeAPIRequest(w, r, &req) {
+		return
+	}
+	if req.Branch == "" {
+		writeAPIError(w, http.StatusBadRequest, errors.New("branch is required"))
+		return
+	}
+	for key := range req.Labels {
+		if _, _, err := environment.ParseLabel(key + "="); err != nil {
+			writeAPIError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	remote, branch, isRemote := s.envManager.GetGitOperations().ParseBranchReference(req.Branch)
+	envName, err := s.envManager.GetGitOperations().GenerateEnvironmentName(branch)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Hold the environment only while creating it, not while waiting
+	s.maintenance.RLock()
+	unlock := s.locks.lock(envName)
+	env, err := s.envManager.CreateEnvironment(r.Context(), environment.CreateEnvironmentOptions{
+		BranchName:     branch,
+		IsRemoteBranch: isRemote,
+		RemoteName:     remote,
+		Containerfile:  req.Containerfile,
+		Labels:         req.Labels,
+		NoStart:        req.NoStart,
+	})
+	unlock()
+	s.maintenance.RUnlock()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, fmt.Errorf("failed to create environment: %w", err))
+		return
+	}
+	if req.Wait && !req.NoStart {
+		if err := s.envManager.WaitForReady(r.Context(), env.Name, req.WaitPort, environment.DefaultWaitTimeout); err != nil {
+			writeAPIError(w, http.StatusGatewayTimeout, fmt.Errorf("environment '%s' was created but is not ready: %w", env.Name, err))
+			return
+		}
+	}
+	writeJSON(w, http.StatusCreated, env)
+}
+
+// apiDelete deletes an environment; ?force=true deletes protected ones
+func (s *Server) apiDelete(w http.ResponseWriter, r *http.Request) {
+	force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+	s.withEnvironment(w, r, func(ctx context.Context, name string) error {
+		return s.envManager.DeleteEnvironment(ctx, name, force)
+	})
+}
+
+// apiStart starts a stopped environment
+func (s *Server) apiStart(w http.ResponseWriter, r *http.Request) {
+	s.withEnvironment(w, r, s.envManager.StartEnvironment)
+}
+
+// apiStop stops a running environment
+func (s *Server) apiStop(w http.ResponseWriter, r *http.Request) {
+	s.withEnvironment(w, r, s.envManager.StopEnvironment)
+}
+
+// apiRebuild rebuilds an environment's image and replaces its container
+func (s *Server) apiRebuild(w http.ResponseWriter, r *http.Request) {
+	s.withEnvironment(w, r, func(ctx context.Context, name string) error {
+		s.maintenance.RLock()
+		defer s.maintenance.RUnlock()
+		return s.envManager.RebuildEnvironment(ctx, name)
+	})
+}
+
+// apiExec runs a command in an environment without a TTY and answers with
+// its exit code and output; a non-zero exit code is not an API error
+func (s *Server) apiExec(w http.ResponseWriter, r *http.Request) {
+	var req execRequest
+	if !decodeAPIRequest(w, r, &req) {
+		return
+	}
+	if len(req.Command) == 0 {
+		writeAPIError(w, http.StatusBadRequest, errors.New("command is required"))
+		return
+	}
+
+	// No lock: the command may run for minutes and only reads the state
+	name := r.PathValue("name")
+	if !s.environmentExists(w, name) {
+		return
+	}
+	began := time.Now()
+	result, err := s.envManager.CaptureCommand(r.Context(), name, req.Command)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, fmt.Errorf("failed to execute command: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, execResponse{
+		Environment: name,
+		Command:     req.Command,
+		ExitCode:    result.ExitCode,
+		DurationMS:  time.Since(began).Milliseconds(),
+		Stdout:      string(result.Stdout),
+		Stderr:      string(result.Stderr),
+	})
+}
+
+// apiExecStream runs a command in an environment without a TTY or stdin
+// and answers with newline-delimited execFrames as its output arrives
+func (s *Server) apiExecStream(w http.ResponseWriter, r *http.Request) {
+	var req execRequest
+	if !decodeAPIRequest(w, r, &req) {
+		return
+	}
+	if len(req.Command) == 0 {
+		writeAPIError(w, http.StatusBadRequest, errors.New("command is required"))
+		return
+	}
+
+	// No lock, as for apiExec
+	name := r.PathValue("name")
+	if !s.environmentExists(w, name) {
+		return
+	}
+	stream := &execStream{w: w, controller: http.NewResponseController(w)}
+	began := time.Now()
+	code, err := s.envManager.StreamCommand(r.Context(), name, req.Command, stream.writer(false), stream.writer(true))
+	if err != nil {
+		err = fmt.Errorf("failed to execute command: %w", err)
+		if !stream.started {
+			writeAPIError(w, http.StatusInternalServerError, err)
+			return
+		}
+		stream.send(execFrame{Error: err.Error()})
+		return
+	}
+	stream.send(execFrame{ExitCode: &code, DurationMS: time.Since(began).Milliseconds()})
+}
+
+// execStream writes execFrames to an exec/stream answer, flushing each so
+// the client sees output as it arrives; the command's stdout and stderr
+// write to it concurrently
+type execStream struct {
+	mu         sync.Mutex
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	started    bool
+}
+
+// send writes one frame, starting the answer on the first
+func (s *execStream) send(frame execFrame) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.started {
+		s.w.Header().Set("Content-Type", "application/x-ndjson")
+		s.w.WriteHeader(http.StatusOK)
+		s.started = true
+	}
+	if err := json.NewEncoder(s.w).Encode(frame); err != nil {
+		return err
+	}
+	return s.controller.Flush()
+}
+
+// writer returns an io.Writer sending what is written as stdout frames, or
+// stderr frames
+func (s *execStream) writer(stderr bool) io.Writer {
+	return streamWriter{stream: s, stderr: stderr}
+}
+
+// streamWriter is one output stream of an execStream
+type streamWriter struct {
+	stream *execStream
+	stderr bool
+}
+
+func (w streamWriter) Write(p []byte) (int, error) {
+	frame := execFrame{Stdout: p}
+	if w.stderr {
+		frame = execFrame{Stderr: p}
+	}
+	if err := w.stream.send(frame); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// apiLogs answers with the container's output as plain text; ?tail=N
+// limits it to the last N lines
+func (s *Server) apiLogs(w http.ResponseWriter, r *http.Request) {
+	tail := 0
+	if value := r.URL.Query().Get("tail"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			writeAPIError(w, http.StatusBadRequest, fmt.Errorf("invalid tail %q", value))
+			return
+		}
+		tail = n
+	}
+
+	name := r.PathValue("name")
+	if !s.environmentExists(w, name) {
+		return
+	}
+	lines, err := s.envManager.Logs(r.Context(), name)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, fmt.Errorf("failed to read logs: %w", err))
+		return
+	}
+	if tail > 0 && len(lines) > tail {
+		lines = lines[len(lines)-tail:]
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+}
+
+// withEnvironment runs action on the named environment and answers with
+// the environment afterwards, or 204 when it is gone
+func (s *Server) withEnvironment(w http.ResponseWriter, r *http.Request, action func(ctx context.Context, name string) error) {
+	name := r.PathValue("name")
+	defer s.locks.lock(name)()
+	if !s.environmentExists(w, name) {
+		return
+	}
+	if err := action(r.Context(), name); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err)
+		return
+	}
+	env, err := s.envManager.GetConfig().GetEnvironment(name)
+	if err != nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, env)
+}
+
+// environmentExists reloads the state and reports whether the environment
+// exists, answering 404 when it does not
+func (s *Server) environmentExists(w http.ResponseWriter, name string) bool {
+	if err := s.envManager.GetConfig().LoadState(); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err)
+		return false
+	}
+	if _, err := s.envManager.GetConfig().GetEnvironment(name); err != nil {
+		writeAPIError(w, http.StatusNotFound, fmt.Errorf("environment '%s' not found", name))
+		return false
+	}
+	return true
+}
+
+// listWithPorts lists the environments, filling in the published ports of
+// running ones
+func (s *Server) listWithPorts(ctx context.Context) ([]config.Environment, error) {
+	environments, err := s.envManager.ListEnvironments(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+	if environments == nil {
+		environments = []config.Environment{} // [] rather than null
+	}
+	for i := range environments {
+		if environments[i].Status != "running" {
+			continue
+		}
+		if ports, err := s.envManager.PublishedPorts(ctx, environments[i]); err == nil {
+			environments[i].Ports = ports
+		}
+	}
+	return environments, nil
+}
+
+// decodeAPIRequest decodes a JSON request body into v, answering 400 and
+// returning false when it is invalid
+func decodeAPIRequest(w http.ResponseWriter, r *http.Request, v any) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAPIBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return false
+	}
+	return true
+}
+
+// writeJSON answers with v as JSON
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+// writeAPIError answers with {"error": "..."}
+func writeAPIError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,177 @@
+// Package daemon runs cc-buddy as a long-lived server for a repository,
+// exposing Prometheus metrics about its environments
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/audit"
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// scrapeTimeout bounds how long collecting metrics may query the runtime
+const scrapeTimeout = 20 * time.Second
+
+// Server serves the daemon's HTTP endpoints
+type Server struct {
+	envManager *environment.Manager
+	history    *audit.Log
+
+	// ctx lives as long as the server; background work such as webhook
+	// creates runs under it
+	ctx context.Context
+
+	// apiToken authenticates /api requests; the API is off while it is empty
+	apiToken string
+
+	// locks serializes changes to each environment. envManager is otherwise
+	// safe for concurrent use, so reads such as listing, metrics, logs and
+	// exec take no lock, and nothing holds one across a wait or an exec.
+	locks environmentLocks
+
+	// maintenance is held exclusively by maintenance jobs, which may remove
+	// images and volumes, and shared by creates and rebuilds, whose images
+	// are not in the state yet
+	maintenance sync.RWMutex
+}
+
+// NewServer creates a daemon server for the repository envManager manages
+func NewServer(envManager *environment.Manager) *Server {
+	return &Server{
+		envManager: envManager,
+		history:    audit.NewLog(config.StateDir),
+		ctx:        context.Background(),
+	}
+}
+
+// Handler returns the daemon's HTTP routes
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
+	mux.HandleFunc("POST /webhook", s.handleWebhook)
+	s.apiRoutes(mux)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	return mux
+}
+
+// ListenAndServe serves on addr (see listen) until ctx is cancelled, then shuts down
+// gracefully. ready is called with the bound address once listening. With
+// stop_after_idle set it also stops idle environments, with warm_pool set it
+// keeps that many images built ahead, and it runs the configured
+// maintenance jobs.
+func (s *Server) ListenAndServe(ctx context.Context, addr string, ready func(addr string)) error {
+	s.ctx = ctx
+	stopAfterIdle, err := s.envManager.StopAfterIdle()
+	if err != nil {
+		return fmt.Errorf("stop_after_idle: %w", err)
+	}
+	jobs := s.envManager.GetConfig().GetConfig().Maintenance
+	if err := environment.ValidateMaintenance(jobs); err != nil {
+		return err
+	}
+	if len(jobs) > 0 {
+		scheduler, err := s.scheduleMaintenance(ctx, jobs)
+		if err != nil {
+			return fmt.Errorf("failed to schedule maintenance: %w", err)
+		}
+		defer scheduler.Stop()
+	}
+
+	token, err := loadAPIToken(s.envManager.GetConfig().GetConfig().APIToken)
+	if err != nil {
+		return err
+	}
+	s.apiToken = token
+
+	listener, err := listen(addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	bound := listener.Addr().String()
+	if listener.Addr().Network() == "unix" {
+		bound = "unix://" + bound
+	}
+
+	server := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Serve(listener)
+	}()
+	logging.Logger().Info("daemon listening", "address", bound)
+	if stopAfterIdle > 0 {
+		go s.stopIdle(ctx, stopAfterIdle)
+	}
+	if s.envManager.GetConfig().GetConfig().WarmPool > 0 {
+		go s.fillPool(ctx)
+	}
+	if ready != nil {
+		ready(bound)
+	}
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("daemon server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to shut down daemon server: %w", err)
+	}
+	return nil
+}
+
+// listen opens addr: host:port or tcp://host:port, or unix:///path for a
+// socket only the user can connect to
+func listen(addr string) (net.Listener, error) {
+	path, ok := strings.CutPrefix(addr, "unix://")
+	if !ok {
+		return net.Listen("tcp", strings.TrimPrefix(addr, "tcp://"))
+	}
+	// A socket left behind by a daemon that did not shut down cleanly
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		os.Remove(path)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// handleMetrics serves the Prometheus text exposition format
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), scrapeTimeout)
+	defer cancel()
+
+	metrics, err := s.collect(ctx)
+	if err != nil {
+		logging.Logger().Error("metrics collection failed", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	metrics.write(w)
+}
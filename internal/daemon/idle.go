@@ -0,0 +1,45 @@
+package daemon
+
+import (
+	"context"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// idleCheckInterval is how often the daemon looks for idle environments
+const idleCheckInterval = 5 * time.Minute
+
+// stopIdle stops environments idle for after, checking every
+// idleCheckInterval until ctx is cancelled
+func (s *Server) stopIdle(ctx context.Context, after time.Duration) {
+	ticker := time.NewTicker(idleCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.stopIdleOnce(ctx, after)
+		}
+	}
+}
+
+// stopIdleOnce stops the environments that are idle now
+func (s *Server) stopIdleOnce(ctx context.Context, after time.Duration) {
+	environments, err := s.envManager.IdleEnvironments(ctx, after)
+	if err != nil {
+		logging.Logger().Warn("idle check failed", "error", err.Error())
+		return
+	}
+	for _, env := range environments {
+		unlock := s.locks.lock(env.Name)
+		err := s.envManager.StopEnvironment(ctx, env.Name)
+		unlock()
+		if err != nil {
+			logging.Logger().Warn("failed to stop idle environment", "environment", env.Name, "error", err.Error())
+			continue
+		}
+		logging.Logger().Info("stopped idle environment", "environment", env.Name, "idle", env.IdleFor.Round(time.Minute).String())
+	}
+}
@@ -0,0 +1,29 @@
+package daemon
+
+import "sync"
+
+// environmentLocks serializes operations on one environment, so a delete
+// cannot run in the middle of a rebuild of it, while operations on
+// different environments, and reads, go ahead in parallel
+type environmentLocks struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// lock blocks until no other operation holds name and returns the function
+// that releases it
+func (l *environmentLocks) lock(name string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*sync.Mutex)
+	}
+	lock, ok := l.locks[name]
+	if !ok {
+		lock = &sync.Mutex{}
+		l.locks[name] = lock
+	}
+	l.mu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
@@ -0,0 +1,39 @@
+package daemon
+
+import (
+	"context"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// scheduleMaintenance starts running the configured maintenance jobs on
+// their schedules. The caller stops the returned scheduler.
+func (s *Server) scheduleMaintenance(ctx context.Context, jobs []config.MaintenanceJob) (*cron.Cron, error) {
+	scheduler := cron.New()
+	for _, job := range jobs {
+		job := job
+		if _, err := scheduler.AddFunc(job.Schedule, func() { s.runMaintenance(ctx, job) }); err != nil {
+			return nil, err
+		}
+	}
+	scheduler.Start()
+	return scheduler, nil
+}
+
+// runMaintenance runs one job; the result is logged here and recorded in
+// the history log by the environment manager
+func (s *Server) runMaintenance(ctx context.Context, job config.MaintenanceJob) {
+	s.maintenance.Lock()
+	defer s.maintenance.Unlock()
+
+	logging.Logger().Info("running maintenance task", "task", job.Task)
+	result, err := s.envManager.RunMaintenance(ctx, job, s.locks.lock)
+	if err != nil {
+		logging.Logger().Warn("maintenance task failed", "task", job.Task, "result", result, "error", err.Error())
+		return
+	}
+	logging.Logger().Info("maintenance task finished", "task", job.Task, "result", result)
+}
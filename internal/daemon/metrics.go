@@ -0,0 +1,179 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/audit"
+	"github.com/jhjaggars/cc-buddy/internal/container"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// durationBuckets are the histogram upper bounds, in seconds, for create and
+// build durations
+var durationBuckets = []float64{5, 15, 30, 60, 120, 300, 600, 1200, 1800}
+
+// histogram is a cumulative Prometheus histogram
+type histogram struct {
+	counts []uint64 // per bucket in durationBuckets, cumulative
+	count  uint64
+	sum    float64
+}
+
+// observe adds a sample in seconds
+func (h *histogram) observe(seconds float64) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(durationBuckets))
+	}
+	for i, bound := range durationBuckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// snapshot holds the values of one scrape
+type snapshot struct {
+	environments   map[string]int                     // by status
+	usage          map[string]container.ResourceUsage // by environment, running ones only
+	creates        map[string]uint64                  // by result
+	deletes        map[string]uint64                  // by result
+	createDuration histogram
+	buildDuration  histogram
+}
+
+// collect gathers environment status and resource usage from the runtime
+// and lifecycle counts from the history log
+func (s *Server) collect(ctx context.Context) (*snapshot, error) {
+	snap := &snapshot{
+		// Always report the common states so dashboards see zeros
+		environments: map[string]int{"running": 0, "stopped": 0},
+		usage:        map[string]container.ResourceUsage{},
+		creates:      map[string]uint64{"success": 0, "failure": 0},
+		deletes:      map[string]uint64{"success": 0, "failure": 0},
+	}
+
+	environments, err := s.envManager.ListEnvironments(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+
+	runtime := s.envManager.GetContainerManager().GetRuntime()
+	for _, env := range environments {
+		snap.environments[env.Status]++
+		if env.Status != "running" || env.ContainerID == "" {
+			continue
+		}
+		usage, err := runtime.Stats(ctx, env.ContainerID)
+		if err != nil {
+			logging.Logger().Debug("skipping resource usage", "environment", env.Name, "error", err.Error())
+			continue
+		}
+		snap.usage[env.Name] = usage
+	}
+
+	events, err := s.history.Read("")
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		result := "success"
+		if event.Error != "" {
+			result = "failure"
+		}
+		switch event.Event {
+		case audit.EventCreated, audit.EventCreateFailed:
+			snap.creates[result]++
+			if event.Event == audit.EventCreated {
+				snap.createDuration.observe(float64(event.DurationMS) / 1000)
+			}
+			if event.BuildMS > 0 {
+				snap.buildDuration.observe(float64(event.BuildMS) / 1000)
+			}
+		case audit.EventDeleted:
+			snap.deletes[result]++
+		}
+	}
+
+	return snap, nil
+}
+
+// write renders the snapshot in the Prometheus text exposition format
+func (snap *snapshot) write(w io.Writer) {
+	header(w, "cc_buddy_environments", "gauge", "Environments by status")
+	for _, status := range sortedKeys(snap.environments) {
+		fmt.Fprintf(w, "cc_buddy_environments{status=%q} %d\n", escapeLabel(status), snap.environments[status])
+	}
+
+	header(w, "cc_buddy_creates_total", "counter", "Environment creates recorded in the history log, by result")
+	for _, result := range sortedKeys(snap.creates) {
+		fmt.Fprintf(w, "cc_buddy_creates_total{result=%q} %d\n", result, snap.creates[result])
+	}
+
+	header(w, "cc_buddy_deletes_total", "counter", "Environment deletes recorded in the history log, by result")
+	for _, result := range sortedKeys(snap.deletes) {
+		fmt.Fprintf(w, "cc_buddy_deletes_total{result=%q} %d\n", result, snap.deletes[result])
+	}
+
+	header(w, "cc_buddy_create_duration_seconds", "histogram", "Time taken by successful environment creates")
+	writeHistogram(w, "cc_buddy_create_duration_seconds", snap.createDuration)
+
+	header(w, "cc_buddy_build_duration_seconds", "histogram", "Time taken by image builds during creates")
+	writeHistogram(w, "cc_buddy_build_duration_seconds", snap.buildDuration)
+
+	header(w, "cc_buddy_environment_cpu_percent", "gauge", "CPU usage of running environments, in percent of one CPU")
+	for _, name := range sortedKeys(snap.usage) {
+		fmt.Fprintf(w, "cc_buddy_environment_cpu_percent{environment=%q} %g\n", escapeLabel(name), snap.usage[name].CPUPercent)
+	}
+
+	header(w, "cc_buddy_environment_memory_bytes", "gauge", "Memory used by running environments")
+	for _, name := range sortedKeys(snap.usage) {
+		fmt.Fprintf(w, "cc_buddy_environment_memory_bytes{environment=%q} %d\n", escapeLabel(name), snap.usage[name].MemoryBytes)
+	}
+}
+
+// header writes a metric's HELP and TYPE lines
+func header(w io.Writer, name, metricType, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, metricType)
+}
+
+// writeHistogram writes a histogram's bucket, sum and count samples
+func writeHistogram(w io.Writer, name string, h histogram) {
+	for i, bound := range durationBuckets {
+		var count uint64
+		if h.counts != nil {
+			count = h.counts[i]
+		}
+		fmt.Fprintf(w, "%s_bucket{le=\"%g\"} %d\n", name, bound, count)
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, h.count)
+	fmt.Fprintf(w, "%s_sum %g\n", name, h.sum)
+	fmt.Fprintf(w, "%s_count %d\n", name, h.count)
+}
+
+// escapeLabel strips characters %q would escape differently from the
+// exposition format, which only allows \\, \" and \n escapes
+func escapeLabel(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, value)
+}
+
+// sortedKeys returns a map's keys in order, for stable output
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,39 @@
+package daemon
+
+import (
+	"context"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// poolCheckInterval is how often the daemon refills the warm pool
+const poolCheckInterval = time.Minute
+
+// fillPool keeps the warm_pool full, checking every poolCheckInterval until
+// ctx is cancelled
+func (s *Server) fillPool(ctx context.Context) {
+	ticker := time.NewTicker(poolCheckInterval)
+	defer ticker.Stop()
+	for {
+		s.fillPoolOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// fillPoolOnce builds the images missing from the warm pool. It takes no
+// environment lock: pool images belong to no environment.
+func (s *Server) fillPoolOnce(ctx context.Context) {
+	built, err := s.envManager.FillWarmPool(ctx)
+	if err != nil {
+		logging.Logger().Warn("warm pool refill failed", "built", built, "error", err.Error())
+		return
+	}
+	if built > 0 {
+		logging.Logger().Info("refilled warm pool", "built", built)
+	}
+}
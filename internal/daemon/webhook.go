@@ -0,0 +1,252 @@
+package daemon
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// maxWebhookBody bounds the size of a webhook delivery
+const maxWebhookBody = 5 << 20
+
+// errBadSignature is returned for deliveries not signed with the secret
+var errBadSignature = errors.New("invalid webhook signature or token")
+
+// pullRequestEvent is the part of a GitHub pull request or GitLab merge
+// request event the daemon acts on
+type pullRequestEvent struct {
+	Provider string // "github" or "gitlab"
+	Number   int
+	Branch   string // source branch
+	Sender   string
+	Fork     bool // the source branch lives in another repository
+	Open     bool // opened or reopened
+	Close    bool // closed or merged
+}
+
+// githubPullRequest is a GitHub pull_request event payload
+type githubPullRequest struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Head struct {
+			Ref  string `json:"ref"`
+			Repo struct {
+				FullName string `json:"full_name"`
+			} `json:"repo"`
+		} `json:"head"`
+		Base struct {
+			Repo struct {
+				FullName string `json:"full_name"`
+			} `json:"repo"`
+		} `json:"base"`
+	} `json:"pull_request"`
+	Sender struct {
+		Login string `json:"login"`
+	} `json:"sender"`
+}
+
+// gitlabMergeRequest is a GitLab "Merge Request Hook" payload
+type gitlabMergeRequest struct {
+	User struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	ObjectAttributes struct {
+		IID             int    `json:"iid"`
+		Action          string `json:"action"`
+		SourceBranch    string `json:"source_branch"`
+		SourceProjectID int    `json:"source_project_id"`
+		TargetProjectID int    `json:"target_project_id"`
+	} `json:"object_attributes"`
+}
+
+// handleWebhook receives pull request events and creates or deletes the
+// environment of the source branch in the background, answering at once
+func (s *Server) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	webhook := s.envManager.GetConfig().GetConfig().Webhook
+	if !webhook.Enabled {
+		http.NotFound(w, r)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	event, ignored, err := parseWebhook(r.Header, body, webhook.Secret)
+	if errors.Is(err, errBadSignature) {
+		logging.Logger().Warn("rejected webhook delivery", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if ignored != "" {
+		fmt.Fprintf(w, "ignored: %s\n", ignored)
+		return
+	}
+
+	if len(webhook.AllowedSenders) > 0 && !slices.Contains(webhook.AllowedSenders, event.Sender) {
+		logging.Logger().Warn("ignoring webhook from sender not allowed", "sender", event.Sender, "branch", event.Branch)
+		http.Error(w, fmt.Sprintf("sender %s is not allowed", event.Sender), http.StatusForbidden)
+		return
+	}
+	if event.Fork {
+		fmt.Fprintln(w, "ignored: the branch is in a fork")
+		return
+	}
+	if err := environment.ValidateBranchName(event.Branch); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	go s.applyPullRequest(event, webhook)
+	w.WriteHeader(http.StatusAccepted)
+	if event.Open {
+		fmt.Fprintf(w, "creating environment for %s\n", event.Branch)
+	} else {
+		fmt.Fprintf(w, "deleting environment for %s\n", event.Branch)
+	}
+}
+
+// parseWebhook verifies a GitHub or GitLab delivery against secret and
+// decodes it. Deliveries that are not pull request opens or closes give a
+// reason they were ignored instead of an event.
+func parseWebhook(header http.Header, body []byte, secret string) (*pullRequestEvent, string, error) {
+	switch {
+	case header.Get("X-GitHub-Event") != "":
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		if !hmac.Equal([]byte(header.Get("X-Hub-Signature-256")), []byte(expected)) {
+			return nil, "", errBadSignature
+		}
+		if kind := header.Get("X-GitHub-Event"); kind != "pull_request" {
+			return nil, kind + " event", nil
+		}
+
+		var payload githubPullRequest
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, "", fmt.Errorf("invalid pull_request payload: %w", err)
+		}
+		event := &pullRequestEvent{
+			Provider: "github",
+			Number:   payload.Number,
+			Branch:   payload.PullRequest.Head.Ref,
+			Sender:   payload.Sender.Login,
+			Fork:     payload.PullRequest.Head.Repo.FullName != payload.PullRequest.Base.Repo.FullName,
+			Open:     payload.Action == "opened" || payload.Action == "reopened",
+			Close:    payload.Action == "closed",
+		}
+		if !event.Open && !event.Close {
+			return nil, "pull request " + payload.Action, nil
+		}
+		return event, "", nil
+
+	case header.Get("X-Gitlab-Event") != "":
+		if subtle.ConstantTimeCompare([]byte(header.Get("X-Gitlab-Token")), []byte(secret)) != 1 {
+			return nil, "", errBadSignature
+		}
+		if kind := header.Get("X-Gitlab-Event"); kind != "Merge Request Hook" {
+			return nil, kind, nil
+		}
+
+		var payload gitlabMergeRequest
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, "", fmt.Errorf("invalid merge request payload: %w", err)
+		}
+		attributes := payload.ObjectAttributes
+		event := &pullRequestEvent{
+			Provider: "gitlab",
+			Number:   attributes.IID,
+			Branch:   attributes.SourceBranch,
+			Sender:   payload.User.Username,
+			Fork:     attributes.SourceProjectID != attributes.TargetProjectID,
+			Open:     attributes.Action == "open" || attributes.Action == "reopen",
+			Close:    attributes.Action == "close" || attributes.Action == "merge",
+		}
+		if !event.Open && !event.Close {
+			return nil, "merge request " + attributes.Action, nil
+		}
+		return event, "", nil
+	}
+	return nil, "", fmt.Errorf("not a GitHub or GitLab webhook delivery")
+}
+
+// applyPullRequest creates the environment of an opened pull request's
+// branch or deletes that of a closed one; results are logged and recorded
+// in the history log by the environment manager
+func (s *Server) applyPullRequest(event *pullRequestEvent, webhook config.WebhookConfig) {
+	log := logging.Logger().With("provider", event.Provider, "number", event.Number, "branch", event.Branch)
+	envName, err := s.envManager.GetGitOperations().GenerateEnvironmentName(event.Branch)
+	if err != nil {
+		log.Error("webhook: failed to name environment", "error", err.Error())
+		return
+	}
+	s.maintenance.RLock()
+	defer s.maintenance.RUnlock()
+	defer s.locks.lock(envName)()
+	// Other cc-buddy processes may have created or deleted it meanwhile
+	if err := s.envManager.GetConfig().LoadState(); err != nil {
+		log.Error("webhook: failed to load state", "error", err.Error())
+		return
+	}
+	env, err := s.envManager.GetConfig().GetEnvironment(envName)
+	exists := err == nil
+
+	switch {
+	case event.Open && exists:
+		log.Info("webhook: environment already exists", "environment", envName)
+	case event.Open:
+		remote := webhook.Remote
+		if remote == "" {
+			remote = "origin"
+		}
+		log.Info("webhook: creating environment", "environment", envName)
+		_, err := s.envManager.CreateEnvironment(s.ctx, environment.CreateEnvironmentOptions{
+			BranchName:     event.Branch,
+			IsRemoteBranch: true,
+			RemoteName:     remote,
+			Labels: map[string]string{
+				"pr":          strconv.Itoa(event.Number),
+				"pr.provider": event.Provider,
+			},
+		})
+		if err != nil {
+			log.Error("webhook: create failed", "environment", envName, "error", err.Error())
+			return
+		}
+		log.Info("webhook: environment created", "environment", envName)
+	case event.Close && exists && !createdFor(env.Labels, event):
+		// Someone created it by hand, or for another pull request
+		log.Info("webhook: environment not created for this pull request, keeping it", "environment", envName)
+	case event.Close && exists:
+		log.Info("webhook: deleting environment", "environment", envName)
+		if err := s.envManager.DeleteEnvironment(s.ctx, envName, false); err != nil {
+			log.Error("webhook: delete failed", "environment", envName, "error", err.Error())
+			return
+		}
+		log.Info("webhook: environment deleted", "environment", envName)
+	}
+}
+
+// createdFor reports whether labels mark an environment as created by the
+// webhook for event's pull request
+func createdFor(labels map[string]string, event *pullRequestEvent) bool {
+	return labels["pr"] == strconv.Itoa(event.Number) && labels["pr.provider"] == event.Provider
+}
@@ -0,0 +1,127 @@
+package daemon
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+const testSecret = "s3cret"
+
+// sign returns the X-Hub-Signature-256 value GitHub sends for body
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestParseWebhook(t *testing.T) {
+	githubOpened := `{"action": "opened", "number": 7,
+		"pull_request": {"head": {"ref": "feature/x", "repo": {"full_name": "o/r"}}, "base": {"repo": {"full_name": "o/r"}}},
+		"sender": {"login": "alice"}}`
+	githubFork := `{"action": "closed", "number": 8,
+		"pull_request": {"head": {"ref": "fix", "repo": {"full_name": "fork/r"}}, "base": {"repo": {"full_name": "o/r"}}},
+		"sender": {"login": "bob"}}`
+	gitlabMerged := `{"object_attributes": {"iid": 3, "action": "merge", "source_branch": "feature/y",
+		"source_project_id": 1, "target_project_id": 1}, "user": {"username": "carol"}}`
+
+	tests := []struct {
+		name    string
+		header  map[string]string
+		body    string
+		want    *pullRequestEvent
+		ignored string
+		wantErr error
+	}{
+		{
+			name:   "github signed",
+			header: map[string]string{"X-GitHub-Event": "pull_request", "X-Hub-Signature-256": sign(testSecret, githubOpened)},
+			body:   githubOpened,
+			want:   &pullRequestEvent{Provider: "github", Number: 7, Branch: "feature/x", Sender: "alice", Open: true},
+		},
+		{
+			name:   "github fork closed",
+			header: map[string]string{"X-GitHub-Event": "pull_request", "X-Hub-Signature-256": sign(testSecret, githubFork)},
+			body:   githubFork,
+			want:   &pullRequestEvent{Provider: "github", Number: 8, Branch: "fix", Sender: "bob", Fork: true, Close: true},
+		},
+		{
+			name:    "github signed with another secret",
+			header:  map[string]string{"X-GitHub-Event": "pull_request", "X-Hub-Signature-256": sign("other", githubOpened)},
+			body:    githubOpened,
+			wantErr: errBadSignature,
+		},
+		{
+			name:    "github body changed after signing",
+			header:  map[string]string{"X-GitHub-Event": "pull_request", "X-Hub-Signature-256": sign(testSecret, githubOpened)},
+			body:    githubFork,
+			wantErr: errBadSignature,
+		},
+		{
+			name:    "github unsigned",
+			header:  map[string]string{"X-GitHub-Event": "pull_request"},
+			body:    githubOpened,
+			wantErr: errBadSignature,
+		},
+		{
+			name:    "github signature without prefix",
+			header:  map[string]string{"X-GitHub-Event": "pull_request", "X-Hub-Signature-256": sign(testSecret, githubOpened)[len("sha256="):]},
+			body:    githubOpened,
+			wantErr: errBadSignature,
+		},
+		{
+			name:    "github other event",
+			header:  map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": sign(testSecret, "{}")},
+			body:    "{}",
+			ignored: "push event",
+		},
+		{
+			name:   "gitlab token",
+			header: map[string]string{"X-Gitlab-Event": "Merge Request Hook", "X-Gitlab-Token": testSecret},
+			body:   gitlabMerged,
+			want:   &pullRequestEvent{Provider: "gitlab", Number: 3, Branch: "feature/y", Sender: "carol", Close: true},
+		},
+		{
+			name:    "gitlab wrong token",
+			header:  map[string]string{"X-Gitlab-Event": "Merge Request Hook", "X-Gitlab-Token": "guess"},
+			body:    gitlabMerged,
+			wantErr: errBadSignature,
+		},
+		{
+			name:    "gitlab no token",
+			header:  map[string]string{"X-Gitlab-Event": "Merge Request Hook"},
+			body:    gitlabMerged,
+			wantErr: errBadSignature,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for key, value := range tt.header {
+				header.Set(key, value)
+			}
+			event, ignored, err := parseWebhook(header, []byte(tt.body), testSecret)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if ignored != tt.ignored {
+				t.Errorf("ignored = %q, want %q", ignored, tt.ignored)
+			}
+			switch {
+			case tt.want == nil && event != nil:
+				t.Errorf("event = %+v, want none", *event)
+			case tt.want != nil && (event == nil || *event != *tt.want):
+				t.Errorf("event = %+v, want %+v", event, *tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWebhookUnknownSource(t *testing.T) {
+	if _, _, err := parseWebhook(http.Header{}, []byte("{}"), testSecret); err == nil {
+		t.Error("expected an error for a delivery with no GitHub or GitLab event header")
+	}
+}
@@ -0,0 +1,101 @@
+package environment
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// cacheNamePattern restricts cache names to characters valid in volume names
+var cacheNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// cachePreset describes a cache a language toolchain uses and the environment
+// variable pointing the toolchain at it
+type cachePreset struct {
+	name   string
+	path   string // relative to the container user's home
+	envVar string
+}
+
+// cachePresets are the caches enabled by cache_preset for each language
+var cachePresets = map[string][]cachePreset{
+	"go": {
+		{name: "gomod", path: "go/pkg/mod", envVar: "GOMODCACHE"},
+		{name: "gobuild", path: ".cache/go-build", envVar: "GOCACHE"},
+	},
+	"node": {
+		{name: "npm", path: ".npm", envVar: "npm_config_cache"},
+		{name: "pnpm", path: ".local/share/pnpm/store", envVar: "npm_config_store_dir"},
+		{name: "yarn", path: ".cache/yarn", envVar: "YARN_CACHE_FOLDER"},
+	},
+	"python": {
+		{name: "pip", path: ".cache/pip", envVar: "PIP_CACHE_DIR"},
+		{name: "uv", path: ".cache/uv", envVar: "UV_CACHE_DIR"},
+	},
+	"rust": {
+		// Cargo's default CARGO_HOME is ~/.cargo; moving it would also move installed binaries
+		{name: "cargo-registry", path: ".cargo/registry"},
+		{name: "cargo-git", path: ".cargo/git"},
+	},
+}
+
+// CacheVolumeName returns the named volume backing a shared cache. Volumes are
+// named per repository, so every environment of a repository reuses them.
+func CacheVolumeName(repoName, cacheName string) string {
+	return fmt.Sprintf("cc-buddy-%s-cache-%s", strings.ToLower(repoName), cacheName)
+}
+
+// sharedCacheMounts returns mount specifications (volume:target) for the
+// configured shared caches and cache presets, in a stable order, along with
+// the environment variables the presets set. Explicit shared_caches entries
+// override preset caches of the same name.
+func (m *Manager) sharedCacheMounts() ([]string, map[string]string, error) {
+	cfg := m.configMgr.GetConfig()
+	caches := make(map[string]string)
+	envVars := make(map[string]string)
+
+	for _, language := range strings.Split(cfg.CachePreset, ",") {
+		language = strings.TrimSpace(language)
+		if language == "" {
+			continue
+		}
+		presets, ok := cachePresets[language]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown cache_preset %q (supported: go, node, python, rust)", language)
+		}
+		for _, preset := range presets {
+			path := m.containerHome() + "/" + preset.path
+			caches[preset.name] = path
+			if preset.envVar != "" {
+				envVars[preset.envVar] = path
+			}
+		}
+	}
+	for name, path := range cfg.SharedCaches {
+		caches[name] = path
+	}
+	if len(caches) == 0 {
+		return nil, nil, nil
+	}
+
+	repoName, err := m.gitOps.GetRepoName()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to determine repository name: %w", err)
+	}
+
+	names := make([]string, 0, len(caches))
+	for name := range caches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var specs []string
+	for _, name := range names {
+		if !cacheNamePattern.MatchString(name) {
+			return nil, nil, fmt.Errorf("invalid shared cache name %q: use letters, digits, '.', '_' or '-'", name)
+		}
+		specs = append(specs, CacheVolumeName(repoName, name)+":"+caches[name])
+	}
+	return specs, envVars, nil
+}
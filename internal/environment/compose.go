@@ -0,0 +1,101 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// composeProjectName names the compose project running an environment's services
+func composeProjectName(envName string) string {
+	return "cc-buddy-" + envName
+}
+
+// composeNetworkName is the default network compose creates for a project
+func composeNetworkName(project string) string {
+	return project + "_default"
+}
+
+// validateCompose checks that the runtime can run compose stacks
+func (m *Manager) validateCompose() error {
+	if m.configMgr.GetConfig().ComposeFile == "" {
+		return nil
+	}
+	if name := m.containerMgr.GetRuntime().Name(); name != "docker" && name != "podman" {
+		return fmt.Errorf("compose_file requires docker or podman; the %s runtime has no compose support", name)
+	}
+	return nil
+}
+
+// composeUp starts the environment's compose project from its worktree
+func (m *Manager) composeUp(ctx context.Context, env config.Environment, composePath string, noStart bool) error {
+	if noStart {
+		// Create the services and their network for "cc-buddy start" to start
+		if err := m.runCompose(ctx, env.ComposeProject, composePath, "up", "--no-start"); err != nil {
+			return fmt.Errorf("failed to create compose services: %w", err)
+		}
+		return nil
+	}
+	if err := m.runCompose(ctx, env.ComposeProject, composePath, "up", "-d"); err != nil {
+		return fmt.Errorf("failed to start compose services: %w", err)
+	}
+	return nil
+}
+
+// composeStart starts the environment's existing compose services
+func (m *Manager) composeStart(ctx context.Context, env config.Environment) error {
+	composePath := filepath.Join(env.WorktreePath, env.ComposeFile)
+	if err := m.runCompose(ctx, env.ComposeProject, composePath, "start"); err != nil {
+		return fmt.Errorf("failed to start compose services: %w", err)
+	}
+	return nil
+}
+
+// composeStop stops the environment's compose services, keeping them for
+// composeStart
+func (m *Manager) composeStop(ctx context.Context, env config.Environment) error {
+	composePath := filepath.Join(env.WorktreePath, env.ComposeFile)
+	if err := m.runCompose(ctx, env.ComposeProject, composePath, "stop"); err != nil {
+		return fmt.Errorf("failed to stop compose services: %w", err)
+	}
+	return nil
+}
+
+// composeDown stops the environment's compose project and removes its
+// containers, network and volumes
+func (m *Manager) composeDown(ctx context.Context, env config.Environment, composePath string) error {
+	if err := m.runCompose(ctx, env.ComposeProject, composePath, "down", "--volumes", "--remove-orphans"); err != nil {
+		return fmt.Errorf("failed to stop compose services: %w", err)
+	}
+	return nil
+}
+
+// connectNetwork attaches a running container to an additional network
+func (m *Manager) connectNetwork(ctx context.Context, network, containerID string) error {
+	args := append(m.containerMgr.CommandLine(), "network", "connect", network, containerID)
+	output, err := logging.CombinedOutput(exec.CommandContext(ctx, args[0], args[1:]...))
+	if err != nil {
+		return fmt.Errorf("failed to connect to network %s: %w: %s", network, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// runCompose runs "<runtime> compose" for a project
+func (m *Manager) runCompose(ctx context.Context, project, composePath string, args ...string) error {
+	command := append(m.containerMgr.CommandLine(), "compose", "--project-name", project)
+	if composePath != "" {
+		command = append(command, "--file", composePath)
+	}
+	command = append(command, args...)
+
+	output, err := logging.CombinedOutput(exec.CommandContext(ctx, command[0], command[1:]...))
+	if err != nil {
+		return fmt.Errorf("compose %s: %w: %s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,376 @@
+package environment
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/container"
+	"github.com/jhjaggars/cc-buddy/internal/secrets"
+	"github.com/jhjaggars/cc-buddy/internal/system"
+)
+
+// runSpec holds what (re)creating an environment's container needs beyond
+// its Environment record: the host-side settings resolved at create time
+type runSpec struct {
+	workspaceMode string
+	remoteHost    string            // runtime endpoint when the daemon is remote
+	labelOption   string            // SELinux relabel option for the workspace
+	consistency   string            // workspace bind mount consistency
+	extraMounts   []container.Mount // user mounts and shared caches
+	cacheEnv      map[string]string // variables set by cache presets
+	noStart       bool              // create the container without starting it
+	role          string            // LabelRole of a container that is not an environment's own
+}
+
+// imageTag names the image built for an environment
+func imageTag(envName string) string {
+	return fmt.Sprintf("cc-buddy-%s:latest", envName)
+}
+
+// buildImage builds the environment's image from the Containerfile in its
+// worktree, with build args matching the host user
+func (m *Manager) buildImage(ctx context.Context, env *config.Environment) error {
+	return m.buildImageWithLabels(ctx, env, nil)
+}
+
+// buildImageWithLabels is buildImage putting extra labels on the image
+func (m *Manager) buildImageWithLabels(ctx context.Context, env *config.Environment, extra map[string]string) error {
+	containerfilePath := containerfilePath(*env)
+	if _, err := os.Stat(containerfilePath); os.IsNotExist(err) {
+		return fmt.Errorf("containerfile not found: %s", containerfilePath)
+	}
+	// Hash before building so edits made during the build count as stale
+	hash, err := fileHash(containerfilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read containerfile: %w", err)
+	}
+
+	// Get host user information for user ID synchronization
+	userInfo := system.GetUserInfoWithFallback()
+
+	buildOpts := container.BuildOptions{
+		Context:    env.WorktreePath,
+		Dockerfile: containerfilePath,
+		Tags:       []string{imageTag(env.Name)},
+		BuildArgs: map[string]string{
+			"USERNAME": m.containerUser(),
+			"USER_UID": strconv.Itoa(userInfo.UID),
+			"USER_GID": strconv.Itoa(userInfo.GID),
+		},
+		Labels: m.resourceLabels(env.Name),
+	}
+	for key, value := range extra {
+		buildOpts.Labels[key] = value
+	}
+
+	if err := m.containerMgr.GetRuntime().Build(ctx, buildOpts); err != nil {
+		return fmt.Errorf("failed to build container image: %w", err)
+	}
+	env.ContainerfileHash = hash
+	return nil
+}
+
+// fileHash returns the hex sha256 of a file's contents
+func fileHash(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// imageOutdated reports whether the worktree's Containerfile differs from the
+// one env's image was built from. Environments created before hashes were
+// recorded, and unreadable Containerfiles, are not reported.
+func imageOutdated(env config.Environment) bool {
+	if env.ContainerfileHash == "" || env.Containerfile == "" {
+		return false
+	}
+	hash, err := fileHash(containerfilePath(env))
+	return err == nil && hash != env.ContainerfileHash
+}
+
+// runtimeArgs returns the configured runtime_args followed by extra, split
+// into the arguments recorded on an environment
+func (m *Manager) runtimeArgs(extra []string) []string {
+	var args []string
+	for _, arg := range append(append([]string{}, m.configMgr.GetConfig().RuntimeArgs...), extra...) {
+		args = append(args, ParseCommand(arg)...)
+	}
+	return args
+}
+
+// runSpecFor resolves the run settings of an existing environment from its
+// record and the current configuration, for recreating its container
+func (m *Manager) runSpecFor(ctx context.Context, env config.Environment) (runSpec, error) {
+	_, cacheEnv, err := m.sharedCacheMounts()
+	if err != nil {
+		return runSpec{}, err
+	}
+	extraMounts, _, err := resolveExtraMounts(env.Mounts, m.containerMgr.GetRuntime().Name())
+	if err != nil {
+		return runSpec{}, err
+	}
+	_, remoteHost, err := m.resolveWorkspaceMode(ctx)
+	if err != nil {
+		return runSpec{}, err
+	}
+	labelOption, err := m.workspaceLabelOption(ctx, filepath.Dir(env.WorktreePath))
+	if err != nil {
+		return runSpec{}, err
+	}
+
+	// Keep the mode the environment was created with; its volumes match it
+	workspaceMode := WorkspaceModeBind
+	if env.WorkspaceVolume != "" {
+		workspaceMode = WorkspaceModeSync
+	}
+
+	return runSpec{
+		workspaceMode: workspaceMode,
+		remoteHost:    remoteHost,
+		labelOption:   labelOption,
+		consistency:   m.configMgr.GetConfig().MountConsistency,
+		extraMounts:   extraMounts,
+		cacheEnv:      cacheEnv,
+	}, nil
+}
+
+// runContainer starts (or with spec.noStart only creates) the environment's
+// container from its image and returns the container ID. The ID is also returned when a step after the
+// container started fails, so the caller can remove it. It may set
+// env.ProxyHost.
+func (m *Manager) runContainer(ctx context.Context, env *config.Environment, spec runSpec) (string, error) {
+	var workspaceOptions []string
+	if spec.labelOption != "" {
+		workspaceOptions = append(workspaceOptions, spec.labelOption) // SELinux relabel
+	}
+	if env.ReadOnlyWorkspace {
+		// /data stays writable for scratch files and tool state
+		workspaceOptions = append(workspaceOptions, "ro")
+	}
+	if spec.consistency != "" {
+		// Docker Desktop on macOS relaxes host/VM coherence for speed
+		workspaceOptions = append(workspaceOptions, "consistency="+spec.consistency)
+	}
+	workspaceMount := container.Mount{
+		Type:    "bind",
+		Source:  env.WorktreePath,
+		Target:  "/workspace",
+		Options: workspaceOptions,
+	}
+	if spec.workspaceMode == WorkspaceModeSync {
+		// The worktree is copied in once the container is running
+		workspaceMount = container.Mount{
+			Type:   "volume",
+			Source: env.WorkspaceVolume,
+			Target: "/workspace",
+		}
+	}
+	mounts := []container.Mount{
+		workspaceMount,
+		{
+			Type:   "volume",
+			Source: env.VolumeName,
+			Target: "/data",
+		},
+	}
+	hostMounts, hostEnv := m.hostIntegration(ctx)
+	if spec.remoteHost != "" && len(hostMounts) > 0 {
+		// Host files such as ~/.gitconfig do not exist on the remote daemon
+		fmt.Printf("Note: skipping %d host file mount(s) because the runtime host %s is remote\n", len(hostMounts), spec.remoteHost)
+		hostMounts = nil
+	}
+	mounts = append(mounts, hostMounts...)
+	mounts = append(mounts, spec.extraMounts...)
+
+	envVars := map[string]string{
+		"GITHUB_TOKEN": os.Getenv("GITHUB_TOKEN"),
+	}
+	for name, value := range hostEnv {
+		envVars[name] = value
+	}
+	for name, value := range spec.cacheEnv {
+		envVars[name] = value
+	}
+	fileVars, err := loadEnvFiles(env.WorktreePath, m.configMgr.GetConfig().EnvFiles)
+	if err != nil {
+		return "", err
+	}
+	for name, value := range fileVars {
+		envVars[name] = value
+	}
+
+	// Resolve configured secrets just before the container is created. They
+	// are never written to cc-buddy's state and stay off the runtime's command
+	// line, though the runtime keeps them in the container's configuration.
+	var secretEnv map[string]string
+	if refs := m.configMgr.GetConfig().Secrets; len(refs) > 0 {
+		resolved, err := secrets.ResolveAll(ctx, refs)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve secrets: %w", err)
+		}
+		secretEnv = resolved
+		for name := range resolved {
+			delete(envVars, name)
+		}
+	}
+
+	// Set startup command - let entrypoint handle the default case
+	startupCommand := env.StartupCommand
+	if len(startupCommand) == 0 {
+		// Use empty command to let Dockerfile CMD and ENTRYPOINT work together
+		startupCommand = nil
+	}
+
+	image := imageTag(env.Name)
+	runOpts := container.RunOptions{
+		Name:       env.ContainerName,
+		Image:      image,
+		WorkingDir: "/workspace",
+		Detach:     true,
+		Mounts:     mounts,
+		EnvVars:    envVars,
+		SecretEnv:  secretEnv,
+		Command:    startupCommand,
+		HealthCmd:  m.configMgr.GetConfig().HealthCmd,
+		CPUs:       m.configMgr.GetConfig().CPUs,
+		Memory:     m.configMgr.GetConfig().Memory,
+		NoStart:    spec.noStart,
+		Labels:     m.resourceLabels(env.Name),
+		Privileged: env.Privileged,
+		CapAdd:     env.CapAdd,
+		Devices:    env.Devices,
+		ExtraArgs:  env.RuntimeArgs,
+	}
+	for key, value := range env.Labels {
+		runOpts.Labels[key] = value
+	}
+	if spec.role != "" {
+		runOpts.Labels[LabelRole] = spec.role
+	}
+
+	// Bind mount sources must be paths as seen from inside a Lima/Colima VM
+	if vm := m.containerMgr.DetectVM(ctx); vm != nil {
+		fmt.Printf("Note: containers run in the %s VM; bind mounts use VM file sharing, which is slow without virtiofs\n", vm)
+		pathMap := m.configMgr.GetConfig().VMPathMap
+		for i := range runOpts.Mounts {
+			if runOpts.Mounts[i].Type == "bind" {
+				runOpts.Mounts[i].Source = container.TranslatePath(runOpts.Mounts[i].Source, pathMap)
+			}
+		}
+	}
+
+	// Inside WSL, Windows drives (/mnt/c) reach Docker Desktop containers via
+	// /run/desktop/mnt/host, and access across the WSL boundary is very slow
+	if system.IsWSL() {
+		if container.WindowsDrivePath(env.WorktreePath) {
+			fmt.Printf("Warning: worktree %s is on a Windows drive; file access from WSL containers is very slow. Keep the repository in the Linux filesystem (e.g. ~/src) instead\n", env.WorktreePath)
+		}
+		if m.containerMgr.IsDockerDesktop(ctx) {
+			for i := range runOpts.Mounts {
+				if runOpts.Mounts[i].Type == "bind" {
+					runOpts.Mounts[i].Source = container.TranslateWSLPath(runOpts.Mounts[i].Source)
+				}
+			}
+		}
+	}
+
+	// Join the shared network so environments can reach each other by name
+	if env.Network == NetworkShared {
+		if err := m.containerMgr.GetRuntime().EnsureNetwork(ctx, SharedNetworkName); err != nil {
+			return "", fmt.Errorf("failed to create network %s: %w", SharedNetworkName, err)
+		}
+		runOpts.Network = SharedNetworkName
+		runOpts.NetworkAliases = []string{env.Name}
+	}
+
+	if env.Network == NetworkHost {
+		runOpts.Network = NetworkHost
+	}
+
+	// Join the backing services' network, where they resolve by service name.
+	// Shared-network containers join it as a second network after starting.
+	if env.ComposeProject != "" && runOpts.Network == "" {
+		runOpts.Network = composeNetworkName(env.ComposeProject)
+	}
+
+	// Route through the reverse proxy when enabled; failures only disable
+	// routing. Environments created on another network before the proxy was
+	// enabled keep it, so the proxy cannot reach them.
+	env.ProxyHost = ""
+	if m.configMgr.GetConfig().Proxy && env.Network != NetworkShared {
+		network := env.Network
+		if network == "" {
+			network = "default"
+		}
+		fmt.Printf("Warning: proxy routing disabled: %s is on the %s network, not the shared one the proxy uses; recreate it to route it\n", env.Name, network)
+	} else if m.configMgr.GetConfig().Proxy {
+		labels, host, err := m.proxyLabels(ctx, env.Name, image)
+		if err != nil {
+			fmt.Printf("Warning: proxy routing disabled: %v\n", err)
+		} else if host == "" {
+			fmt.Printf("Note: image exposes no ports; %s is not routed through the proxy\n", env.Name)
+		} else if err := m.EnsureProxy(ctx); err != nil {
+			fmt.Printf("Warning: proxy routing disabled: %v\n", err)
+		} else {
+			for key, value := range labels {
+				runOpts.Labels[key] = value
+			}
+			env.ProxyHost = host
+		}
+	}
+
+	// Add port mappings if requested; on the host network there is nothing
+	// to publish
+	switch {
+	case env.Network == NetworkHost:
+	case env.ExposeAllPorts:
+		runOpts.Ports = []container.PortMapping{
+			{Host: 0, Container: 0, Protocol: "tcp"}, // Expose all ports
+		}
+	default:
+		// Random host ports, so several environments can publish the same port
+		for _, port := range m.configMgr.GetConfig().Ports {
+			runOpts.Ports = append(runOpts.Ports, container.PortMapping{Host: 0, Container: port, Protocol: "tcp"})
+		}
+		for _, spec := range env.PortMappings {
+			// Validated at create time
+			mapping, err := container.ParsePortMapping(spec)
+			if err != nil {
+				return "", err
+			}
+			runOpts.Ports = append(runOpts.Ports, mapping)
+		}
+	}
+
+	if err := m.nestedRunOptions(ctx, *env, &runOpts); err != nil {
+		return "", err
+	}
+
+	containerID, err := m.containerMgr.GetRuntime().Run(ctx, runOpts)
+	if err != nil {
+		return "", fmt.Errorf("failed to start container: %w", err)
+	}
+
+	if env.ComposeProject != "" && runOpts.Network != composeNetworkName(env.ComposeProject) {
+		if err := m.connectNetwork(ctx, composeNetworkName(env.ComposeProject), containerID); err != nil {
+			return containerID, err
+		}
+	}
+
+	env.Ports = nil
+	if !spec.noStart && len(runOpts.Ports) > 0 {
+		env.ContainerID = containerID
+		m.recordPublishedPorts(ctx, env)
+	}
+
+	return containerID, nil
+}
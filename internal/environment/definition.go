@@ -0,0 +1,269 @@
+package environment
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/container"
+)
+
+// DefinitionVersion is the lockfile format written by export-def
+const DefinitionVersion = 1
+
+// Definition is a shareable lockfile of an environment: everything needed
+// to recreate it elsewhere with create --from-def, with its base images
+// pinned to the digests it was built from
+type Definition struct {
+	Version           int               `yaml:"version"`
+	Branch            string            `yaml:"branch"`
+	Containerfile     string            `yaml:"containerfile,omitempty"`      // in the worktree
+	ContainerfileHash string            `yaml:"containerfile_hash,omitempty"` // sha256 it was built from
+	Image             string            `yaml:"image,omitempty"`              // published image used instead of a build
+	BaseImages        []BaseImage       `yaml:"base_images,omitempty"`
+	BuildArgs         map[string]string `yaml:"build_args,omitempty"` // USER_UID and USER_GID always come from the host
+	Command           []string          `yaml:"command,omitempty"`
+	Mounts            []string          `yaml:"mounts,omitempty"` // besides those of the config and shared caches
+	Ports             []string          `yaml:"ports,omitempty"`
+	RuntimeArgs       []string          `yaml:"runtime_args,omitempty"` // besides the config's runtime_args
+	Privileged        bool              `yaml:"privileged,omitempty"`
+	CapAdd            []string          `yaml:"cap_add,omitempty"`
+	Devices           []string          `yaml:"devices,omitempty"`
+	ExposeAll         bool              `yaml:"expose_all,omitempty"`
+	Network           string            `yaml:"network,omitempty"`
+	ReadOnlyWorkspace bool              `yaml:"read_only_workspace,omitempty"`
+	ComposeFile       string            `yaml:"compose_file,omitempty"` // backing services
+	PostCreate        []string          `yaml:"post_create,omitempty"`  // hooks
+	Labels            map[string]string `yaml:"labels,omitempty"`
+}
+
+// BaseImage is a FROM image of the Containerfile and the digest it resolved to
+type BaseImage struct {
+	Image  string `yaml:"image"`
+	Digest string `yaml:"digest,omitempty"` // name@sha256:...; empty for local-only images
+}
+
+// ExportDefinition describes envName as a Definition
+func (m *Manager) ExportDefinition(ctx context.Context, envName string) (*Definition, error) {
+	env, err := m.configMgr.GetEnvironment(envName)
+	if err != nil {
+		return nil, fmt.Errorf("environment not found: %w", err)
+	}
+	if env.NoContainer {
+		return nil, fmt.Errorf("environment %s was created with --no-container and has nothing to export", envName)
+	}
+	if env.Containerfile == "" {
+		env.Containerfile = m.configMgr.GetConfig().Containerfile
+	}
+	if filepath.IsAbs(env.Containerfile) {
+		return nil, fmt.Errorf("environment %s was built from a template or definition outside its worktree; export the environment it came from", envName)
+	}
+
+	def := &Definition{
+		Version:           DefinitionVersion,
+		Branch:            env.Branch,
+		Image:             env.SourceImage,
+		Command:           env.StartupCommand,
+		Ports:             env.PortMappings,
+		ExposeAll:         env.ExposeAllPorts,
+		Network:           env.Network,
+		ReadOnlyWorkspace: env.ReadOnlyWorkspace,
+		ComposeFile:       env.ComposeFile,
+		Privileged:        env.Privileged,
+		CapAdd:            env.CapAdd,
+		Devices:           env.Devices,
+		PostCreate:        m.configMgr.GetConfig().Hooks.PostCreate,
+		Labels:            env.Labels,
+	}
+	if def.Image == "" {
+		def.Containerfile = env.Containerfile
+		def.ContainerfileHash = env.ContainerfileHash
+		def.BuildArgs = map[string]string{"USERNAME": env.ContainerUser}
+		bases, err := baseImages(containerfilePath(env))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read containerfile: %w", err)
+		}
+		for _, image := range bases {
+			digest, ok := env.BaseImageDigests[image]
+			if !ok {
+				if digest, err = m.containerMgr.GetRuntime().ImageDigest(ctx, image); err != nil {
+					return nil, err
+				}
+			}
+			def.BaseImages = append(def.BaseImages, BaseImage{Image: image, Digest: digest})
+		}
+	}
+	if def.Mounts, err = m.definitionMounts(env.Mounts); err != nil {
+		return nil, err
+	}
+	def.RuntimeArgs = env.RuntimeArgs
+	if configured := m.runtimeArgs(nil); len(configured) <= len(env.RuntimeArgs) && slices.Equal(configured, env.RuntimeArgs[:len(configured)]) {
+		def.RuntimeArgs = env.RuntimeArgs[len(configured):]
+	}
+	return def, nil
+}
+
+// definitionMounts returns the mounts given on the command line, leaving
+// out those the config and shared caches add anyway, with paths in the home
+// directory written as ~ so they work for others
+func (m *Manager) definitionMounts(specs []string) ([]string, error) {
+	cacheSpecs, _, err := m.sharedCacheMounts()
+	if err != nil {
+		return nil, err
+	}
+	configured := map[string]bool{}
+	for _, spec := range append(append([]string{}, m.configMgr.GetConfig().Mounts...), cacheSpecs...) {
+		if mount, err := container.ParseMount(spec); err == nil {
+			configured[mount.Target] = true
+		}
+	}
+
+	home, _ := os.UserHomeDir()
+	var mounts []string
+	for _, spec := range specs {
+		mount, err := container.ParseMount(spec)
+		if err != nil {
+			return nil, err
+		}
+		if configured[mount.Target] {
+			continue
+		}
+		if home != "" && mount.Type == "bind" && strings.HasPrefix(mount.Source, home+string(filepath.Separator)) {
+			mount.Source = "~" + strings.TrimPrefix(mount.Source, home)
+		}
+		mounts = append(mounts, mount.String())
+	}
+	return mounts, nil
+}
+
+// MarshalDefinition encodes def as YAML
+func MarshalDefinition(def *Definition) ([]byte, error) {
+	return yaml.Marshal(def)
+}
+
+// LoadDefinition reads a definition written by export-def. Unknown keys are
+// errors so typos do not silently drop settings.
+func LoadDefinition(path string) (*Definition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read definition: %w", err)
+	}
+	var def Definition
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&def); err != nil {
+		return nil, fmt.Errorf("invalid definition %s: %w", path, err)
+	}
+	if def.Version != DefinitionVersion {
+		return nil, fmt.Errorf("definition %s has version %d; this cc-buddy reads version %d", path, def.Version, DefinitionVersion)
+	}
+	return &def, nil
+}
+
+// Options returns the create options that reproduce def, on top of base
+func (def *Definition) Options(base CreateEnvironmentOptions) CreateEnvironmentOptions {
+	opts := base
+	opts.Containerfile = def.Containerfile
+	opts.Image = def.Image
+	opts.StartupCommand = def.Command
+	opts.Mounts = append(append([]string{}, def.Mounts...), base.Mounts...)
+	opts.Ports = append(append([]string{}, def.Ports...), base.Ports...)
+	opts.RuntimeArgs = append(append([]string{}, def.RuntimeArgs...), base.RuntimeArgs...)
+	opts.Privileged = base.Privileged || def.Privileged
+	opts.CapAdd = mergeUnique(def.CapAdd, base.CapAdd)
+	opts.Devices = mergeUnique(def.Devices, base.Devices)
+	opts.ExposeAllPorts = base.ExposeAllPorts || def.ExposeAll
+	if opts.Network == "" {
+		opts.Network = def.Network
+	}
+	opts.ReadOnlyWorkspace = base.ReadOnlyWorkspace || def.ReadOnlyWorkspace
+	if len(def.Labels) > 0 {
+		labels := map[string]string{}
+		for key, value := range def.Labels {
+			labels[key] = value
+		}
+		for key, value := range base.Labels {
+			labels[key] = value
+		}
+		opts.Labels = labels
+	}
+	opts.Definition = def
+	return opts
+}
+
+// DefinitionWarnings lists where this repository's config has drifted from
+// what def was exported with; those settings come from the config
+func (m *Manager) DefinitionWarnings(def *Definition) []string {
+	cfg := m.configMgr.GetConfig()
+	var warnings []string
+	if def.ComposeFile != cfg.ComposeFile {
+		warnings = append(warnings, fmt.Sprintf("the definition uses compose file %q but the config has %q", def.ComposeFile, cfg.ComposeFile))
+	}
+	if !slices.Equal(def.PostCreate, cfg.Hooks.PostCreate) {
+		warnings = append(warnings, "the config's post_create hooks differ from the definition's")
+	}
+	if username := def.BuildArgs["USERNAME"]; username != "" && username != m.containerUser() {
+		warnings = append(warnings, fmt.Sprintf("the definition was built for user %q but the config uses %q", username, m.containerUser()))
+	}
+	return warnings
+}
+
+// pinContainerfile writes a copy of env's Containerfile with def's base
+// images replaced by their digests, for a build that starts from exactly
+// the same layers, and points env at it. It reports whether it wrote one.
+func (m *Manager) pinContainerfile(env *config.Environment, def *Definition) (bool, error) {
+	path := containerfilePath(*env)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("containerfile not found: %s", path)
+	}
+	if def.ContainerfileHash != "" {
+		if hash, err := fileHash(path); err == nil && hash != def.ContainerfileHash {
+			fmt.Printf("Warning: %s differs from the one the definition was built from\n", env.Containerfile)
+		}
+	}
+
+	pins := map[string]string{}
+	for _, base := range def.BaseImages {
+		if base.Digest != "" {
+			pins[base.Image] = base.Digest
+		}
+	}
+	if len(pins) == 0 {
+		return false, nil
+	}
+	pinned, err := writeContainerfile(env.Name, pinBaseImages(data, pins))
+	if err != nil {
+		return false, err
+	}
+	env.Containerfile = pinned
+	return true, nil
+}
+
+// pinBaseImages replaces the images of FROM lines found in pins
+func pinBaseImages(data []byte, pins map[string]string) []byte {
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+		for _, field := range fields[1:] {
+			if strings.HasPrefix(field, "--") {
+				continue
+			}
+			if digest, ok := pins[field]; ok {
+				lines[i] = strings.Replace(line, field, digest, 1)
+			}
+			break
+		}
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,209 @@
+package environment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// DetachedCreateEnv is set for a background create to the name of the
+// environment it creates, so it can report its result
+const DetachedCreateEnv = "CC_BUDDY_DETACHED_CREATE"
+
+// PendingDir holds a record for each create running in the background
+var PendingDir = filepath.Join(config.StateDir, "pending")
+
+// pendingPollInterval is how often WaitForCreate checks a background create
+const pendingPollInterval = time.Second
+
+// PendingCreate records a create running in a background process. The
+// record is removed when the create succeeds and keeps the error when it fails.
+type PendingCreate struct {
+	Environment string    `json:"environment"`
+	Branch      string    `json:"branch"`
+	PID         int       `json:"pid"`
+	Started     time.Time `json:"started"`
+	LogFile     string    `json:"log_file"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// pendingPath returns the record file for an environment's background create
+func pendingPath(envName string) string {
+	return filepath.Join(PendingDir, envName+".json")
+}
+
+// readPendingCreate loads an environment's background create record
+func readPendingCreate(envName string) (*PendingCreate, error) {
+	data, err := os.ReadFile(pendingPath(envName))
+	if err != nil {
+		return nil, err
+	}
+	var pending PendingCreate
+	if err := json.Unmarshal(data, &pending); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", pendingPath(envName), err)
+	}
+	return &pending, nil
+}
+
+// writePendingCreate saves a background create record
+func writePendingCreate(pending *PendingCreate) error {
+	if err := os.MkdirAll(PendingDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", PendingDir, err)
+	}
+	data, err := json.MarshalIndent(pending, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(pendingPath(pending.Environment), data, 0644)
+}
+
+// pendingCreates returns the background creates whose process is still running
+func pendingCreates() []PendingCreate {
+	entries, err := os.ReadDir(PendingDir)
+	if err != nil {
+		return nil
+	}
+	var running []PendingCreate
+	for _, entry := range entries {
+		envName, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok {
+			continue
+		}
+		pending, err := readPendingCreate(envName)
+		if err == nil && pending.Error == "" && processAlive(pending.PID) {
+			running = append(running, *pending)
+		}
+	}
+	return running
+}
+
+// StartDetachedCreate runs "cc-buddy create <args>" for envName in a
+// background process that outlives this one, writing its output to a log
+// file. WaitForCreate blocks until it finishes.
+func (m *Manager) StartDetachedCreate(envName, branch string, args []string) (*PendingCreate, error) {
+	if _, err := m.configMgr.GetEnvironment(envName); err == nil {
+		return nil, fmt.Errorf("environment %s already exists", envName)
+	}
+	if pending, err := readPendingCreate(envName); err == nil && pending.Error == "" && processAlive(pending.PID) {
+		return nil, fmt.Errorf("environment %s is already being created (pid %d)", envName, pending.PID)
+	}
+	// Fail now rather than in the background process
+	m.limitMu.Lock()
+	err := m.checkEnvironmentLimits(context.Background(), envName)
+	m.limitMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate the cc-buddy executable: %w", err)
+	}
+	if err := os.MkdirAll(logging.LogDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	logPath := filepath.Join(logging.LogDir, "create-"+envName+".log")
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log file: %w", err)
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(executable, append([]string{"create"}, args...)...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.Env = append(os.Environ(), DetachedCreateEnv+"="+envName)
+	detachProcess(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start background create: %w", err)
+	}
+	pid := cmd.Process.Pid
+	// Not waited for; the process keeps running after this one exits
+	cmd.Process.Release()
+
+	pending := &PendingCreate{
+		Environment: envName,
+		Branch:      branch,
+		PID:         pid,
+		Started:     time.Now(),
+		LogFile:     logPath,
+	}
+	if err := writePendingCreate(pending); err != nil {
+		return nil, fmt.Errorf("failed to record background create: %w", err)
+	}
+	return pending, nil
+}
+
+// FinishDetachedCreate is called by a background create when it is done:
+// success removes its record, failure stores the error for WaitForCreate
+func FinishDetachedCreate(envName string, createErr error) {
+	if createErr == nil {
+		if err := os.Remove(pendingPath(envName)); err != nil && !os.IsNotExist(err) {
+			logging.Logger().Warn("failed to remove pending create record", "environment", envName, "error", err.Error())
+		}
+		return
+	}
+
+	pending, err := readPendingCreate(envName)
+	if err != nil {
+		// The parent has not written the record yet
+		pending = &PendingCreate{Environment: envName, PID: os.Getpid(), Started: time.Now()}
+	}
+	pending.Error = createErr.Error()
+	if err := writePendingCreate(pending); err != nil {
+		logging.Logger().Warn("failed to record create failure", "environment", envName, "error", err.Error())
+	}
+}
+
+// WaitForCreate blocks until a background create of envName finishes,
+// returning its error if it failed. It returns at once if the environment
+// already exists.
+func (m *Manager) WaitForCreate(ctx context.Context, envName string) error {
+	ticker := time.NewTicker(pendingPollInterval)
+	defer ticker.Stop()
+
+	for {
+		pending, err := readPendingCreate(envName)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			if m.environmentExists(envName) {
+				return nil
+			}
+			return fmt.Errorf("no environment or background create named %s", envName)
+		case err != nil:
+			return err
+		case pending.Error != "":
+			return fmt.Errorf("background create failed: %s (log: %s)", pending.Error, pending.LogFile)
+		case !processAlive(pending.PID):
+			if m.environmentExists(envName) {
+				return nil
+			}
+			return fmt.Errorf("background create exited without finishing (log: %s)", pending.LogFile)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("still creating %s: %w", envName, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// environmentExists reports whether envName is in the state file, re-reading
+// it because another process adds it
+func (m *Manager) environmentExists(envName string) bool {
+	if err := m.configMgr.LoadState(); err != nil {
+		return false
+	}
+	_, err := m.configMgr.GetEnvironment(envName)
+	return err == nil
+}
@@ -0,0 +1,32 @@
+//go:build windows
+
+package environment
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// detachedProcess is the Windows DETACHED_PROCESS creation flag
+const detachedProcess = 0x00000008
+
+// detachProcess starts cmd without a console so it survives the parent's
+func detachProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP | detachedProcess,
+	}
+}
+
+// processAlive reports whether a process with the given PID is running
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	process.Release()
+	return true
+}
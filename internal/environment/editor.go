@@ -0,0 +1,48 @@
+package environment
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// EditorCommand returns the command opening envName's worktree in the
+// configured editor, or $VISUAL or $EDITOR
+func (m *Manager) EditorCommand(envName string) ([]string, error) {
+	env, err := m.configMgr.GetEnvironment(envName)
+	if err != nil {
+		return nil, fmt.Errorf("environment not found: %w", err)
+	}
+	if env.WorktreePath == "" {
+		return nil, fmt.Errorf("environment %s has no worktree", envName)
+	}
+
+	editor := m.configMgr.GetConfig().Editor
+	for _, variable := range []string{"VISUAL", "EDITOR"} {
+		if editor == "" {
+			editor = os.Getenv(variable)
+		}
+	}
+	command := ParseCommand(editor)
+	if len(command) == 0 {
+		return nil, fmt.Errorf("no editor configured; set $EDITOR or \"editor\" in .cc-buddy/config.json")
+	}
+	return append(command, env.WorktreePath), nil
+}
+
+// OpenEditor opens envName's worktree in the editor on this terminal and
+// waits for the editor to exit
+func (m *Manager) OpenEditor(envName string) error {
+	command, err := m.EditorCommand(envName)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %s failed: %w", command[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package environment
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// loadEnvFiles reads the configured env files from the worktree; later
+// files override earlier ones
+func loadEnvFiles(worktreePath string, files []string) (map[string]string, error) {
+	vars := map[string]string{}
+	for _, file := range files {
+		path := file
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(worktreePath, file)
+		}
+		fileVars, err := parseEnvFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("env file %s: %w", file, err)
+		}
+		for name, value := range fileVars {
+			vars[name] = value
+		}
+	}
+	return vars, nil
+}
+
+// parseEnvFile reads KEY=value lines, skipping blank lines and # comments.
+// An "export " prefix and quotes around the value are dropped.
+func parseEnvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	vars := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("line %d: expected KEY=value", lineNo)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		vars[name] = value
+	}
+	return vars, scanner.Err()
+}
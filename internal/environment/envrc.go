@@ -0,0 +1,67 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+)
+
+// envrcMarker heads generated .envrc files; files without it are the
+// user's own and never overwritten
+const envrcMarker = "# Generated by cc-buddy"
+
+// writeEnvrc writes the worktree's .envrc when the envrc option is set. It
+// exports CC_BUDDY_ENV, CC_BUDDY_CONTAINER and CC_BUDDY_PORT_<port> (with a
+// _UDP suffix for UDP) set to each published host port. The file is
+// excluded from git so the worktree stays clean.
+func (m *Manager) writeEnvrc(ctx context.Context, env config.Environment) error {
+	if !m.configMgr.GetConfig().Envrc {
+		return nil
+	}
+
+	path := filepath.Join(env.WorktreePath, ".envrc")
+	if data, err := os.ReadFile(path); err == nil && !strings.HasPrefix(string(data), envrcMarker) {
+		fmt.Printf("Warning: %s exists and was not written by cc-buddy; leaving it alone\n", path)
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteString(envrcMarker + "; rewritten when the environment starts\n")
+	fmt.Fprintf(&b, "export CC_BUDDY_ENV=%s\n", env.Name)
+	if env.ContainerName != "" {
+		fmt.Fprintf(&b, "export CC_BUDDY_CONTAINER=%s\n", env.ContainerName)
+	}
+
+	if env.ContainerID != "" {
+		ports, err := m.containerMgr.GetRuntime().Ports(ctx, env.ContainerID)
+		if err != nil {
+			return err
+		}
+		sort.Slice(ports, func(i, j int) bool {
+			return ports[i].Container < ports[j].Container
+		})
+		for _, port := range ports {
+			if port.Host == 0 {
+				continue
+			}
+			name := fmt.Sprintf("CC_BUDDY_PORT_%d", port.Container)
+			if port.Protocol == "udp" {
+				name += "_UDP"
+			}
+			fmt.Fprintf(&b, "export %s=%d\n", name, port.Host)
+		}
+	}
+
+	if err := m.gitOps.ExcludePath(ctx, "/.envrc"); err != nil {
+		return fmt.Errorf("failed to exclude .envrc from git: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write .envrc: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package environment
+
+import (
+	"context"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/audit"
+	"github.com/jhjaggars/cc-buddy/internal/utils"
+)
+
+// Create steps, in the order CreateEnvironment reports them. Services, sync
+// and hooks steps only run when configured.
+const (
+	StepWorktree  = "creating worktree"
+	StepBuild     = "building image"
+	StepVolumes   = "creating volumes"
+	StepServices  = "starting services"
+	StepContainer = "starting container"
+	StepSync      = "syncing workspace"
+	StepHooks     = "running post_create hooks"
+)
+
+// CreateSteps lists the create steps in order
+var CreateSteps = []string{StepWorktree, StepBuild, StepVolumes, StepServices, StepContainer, StepSync, StepHooks}
+
+// estimateSamples is how many recent creates estimates average over
+const estimateSamples = 5
+
+// stepTimer reports an operation's steps and measures how long each took
+type stepTimer struct {
+	current   string
+	started   time.Time
+	durations map[string]int64
+}
+
+// step finishes the current step and reports the next one
+func (t *stepTimer) step(ctx context.Context, progress float64, name string) {
+	t.finish()
+	utils.ReportProgress(ctx, progress, name)
+	t.current = name
+	t.started = time.Now()
+}
+
+// finish ends the current step and returns the milliseconds spent in each
+func (t *stepTimer) finish() map[string]int64 {
+	if t.durations == nil {
+		t.durations = map[string]int64{}
+	}
+	if t.current != "" {
+		t.durations[t.current] += time.Since(t.started).Milliseconds()
+		t.current = ""
+	}
+	return t.durations
+}
+
+// EstimateCreateSteps predicts how long each create step will take in this
+// repository from the last few successful creates, in CreateSteps order.
+// Steps that did not run in those creates are estimated at zero. It returns
+// nil when no create has recorded its steps yet.
+func (m *Manager) EstimateCreateSteps() ([]time.Duration, error) {
+	events, err := m.history.Read("")
+	if err != nil {
+		return nil, err
+	}
+
+	var samples []audit.Event
+	for i := len(events) - 1; i >= 0 && len(samples) < estimateSamples; i-- {
+		if events[i].Event == audit.EventCreated && len(events[i].StepsMS) > 0 {
+			samples = append(samples, events[i])
+		}
+	}
+	if len(samples) == 0 {
+		return nil, nil
+	}
+
+	estimates := make([]time.Duration, len(CreateSteps))
+	for i, name := range CreateSteps {
+		var total int64
+		for _, sample := range samples {
+			total += sample.StepsMS[name]
+		}
+		estimates[i] = time.Duration(total/int64(len(samples))) * time.Millisecond
+	}
+	return estimates, nil
+}
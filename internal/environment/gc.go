@@ -0,0 +1,186 @@
+package environment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/container"
+)
+
+// Labels put on the images and volumes cc-buddy creates, so gc can find
+// them without guessing from names shared with other repositories
+const (
+	LabelRepo        = "cc-buddy.repo"        // repository root
+	LabelEnvironment = "cc-buddy.environment" // environment name
+	LabelPool        = "cc-buddy.pool"        // sha256 of a warm pool image's Containerfile
+	LabelRole        = "cc-buddy.role"        // set on containers that are not an environment's own
+)
+
+// Values of LabelRole
+const (
+	RoleDind   = "dind"   // an environment's docker-in-docker sidecar
+	RoleShared = "shared" // the container switch mounts worktrees into
+)
+
+// gcGracePeriod protects the resources of creates still running, which are
+// only added to the state once they finish
+const gcGracePeriod = time.Hour
+
+// GCOptions selects what gc removes
+type GCOptions struct {
+	KeepLast  int           // superseded builds to keep per environment
+	OlderThan time.Duration // only remove resources at least this old
+}
+
+// GCItem is an image or volume gc would remove
+type GCItem struct {
+	Kind        string // "image" or "volume"
+	Name        string // tag, image ID or volume name
+	Environment string
+	Created     time.Time
+	Reason      string
+}
+
+// resourceLabels returns the labels for an environment's images and volumes
+func (m *Manager) resourceLabels(envName string) map[string]string {
+	return map[string]string{
+		LabelRepo:        m.gitOps.repoRoot,
+		LabelEnvironment: envName,
+	}
+}
+
+// GCCandidates returns this repository's images and volumes that are no
+// longer needed: everything of environments that have been deleted, and
+// builds superseded by a rebuild. Only labelled resources are considered.
+func (m *Manager) GCCandidates(ctx context.Context, opts GCOptions) ([]GCItem, error) {
+	environments, err := m.ListEnvironments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	for _, env := range environments {
+		known[env.Name] = true
+	}
+	for i := 1; i <= m.configMgr.GetConfig().WarmPool; i++ {
+		if name, err := m.poolSlotName(i); err == nil {
+			known[name] = true
+		}
+	}
+	if m.configMgr.GetConfig().SharedContainer {
+		if name, err := m.sharedContainerName(); err == nil {
+			known[name] = true
+		}
+	}
+
+	runtime := m.containerMgr.GetRuntime()
+	repoLabel := LabelRepo + "=" + m.gitOps.repoRoot
+	images, err := runtime.Images(ctx, repoLabel)
+	if err != nil {
+		return nil, err
+	}
+	volumes, err := runtime.Volumes(ctx, repoLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	// Newest first, so the builds kept by KeepLast are the latest ones
+	sort.Slice(images, func(i, j int) bool { return images[i].Created.After(images[j].Created) })
+	superseded := map[string]int{}
+
+	var items []GCItem
+	for _, image := range images {
+		envName := imageEnvironment(image, known)
+		item := GCItem{Kind: "image", Name: imageName(image), Environment: envName, Created: image.Created}
+		if !known[envName] {
+			item.Reason = "environment deleted"
+		} else if current(image, envName) {
+			continue
+		} else {
+			superseded[envName]++
+			if superseded[envName] <= opts.KeepLast {
+				continue
+			}
+			item.Reason = "superseded build"
+		}
+		items = append(items, item)
+	}
+	for _, volume := range volumes {
+		envName := volume.Labels[LabelEnvironment]
+		if known[envName] {
+			continue
+		}
+		items = append(items, GCItem{Kind: "volume", Name: volume.Name, Environment: envName, Created: volume.Created, Reason: "environment deleted"})
+	}
+
+	// Resources of unknown age are kept rather than risk a running create's
+	var selected []GCItem
+	for _, item := range items {
+		age := time.Since(item.Created)
+		if item.Created.IsZero() || age < gcGracePeriod || age < opts.OlderThan {
+			continue
+		}
+		selected = append(selected, item)
+	}
+	return selected, nil
+}
+
+// RemoveGCItems removes the given images and volumes, carrying on past
+// failures, and returns how many were removed
+func (m *Manager) RemoveGCItems(ctx context.Context, items []GCItem) (int, error) {
+	runtime := m.containerMgr.GetRuntime()
+	removed := 0
+	var errs []error
+	for _, item := range items {
+		var err error
+		if item.Kind == "volume" {
+			err = runtime.RemoveVolume(ctx, item.Name)
+		} else {
+			err = runtime.RemoveImage(ctx, item.Name)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove %s %s: %w", item.Kind, item.Name, err))
+			continue
+		}
+		removed++
+	}
+	return removed, errors.Join(errs...)
+}
+
+// current reports whether image is envName's current build
+func current(image container.Image, envName string) bool {
+	for _, tag := range image.Tags {
+		if tag == imageTag(envName) || tag == "localhost/"+imageTag(envName) {
+			return true
+		}
+	}
+	return false
+}
+
+// imageEnvironment returns the environment image belongs to: the one it is
+// labelled with, or a known environment whose tag it carries, which is how
+// a claimed warm pool image stays with its environment
+func imageEnvironment(image container.Image, known map[string]bool) string {
+	for envName := range known {
+		if current(image, envName) {
+			return envName
+		}
+	}
+	return image.Labels[LabelEnvironment]
+}
+
+// imageName names an image for display and removal: its first tag, or its
+// short ID when dangling
+func imageName(image container.Image) string {
+	if len(image.Tags) > 0 {
+		return image.Tags[0]
+	}
+	id := strings.TrimPrefix(image.ID, "sha256:")
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	return id
+}
@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/logging"
 )
 
 // GitOperations handles git repository operations
@@ -28,7 +30,7 @@ func NewGitOperations() (*GitOperations, error) {
 // findGitRoot finds the root of the git repository
 func findGitRoot() (string, error) {
 	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
-	out, err := cmd.Output()
+	out, err := logging.Output(cmd)
 	if err != nil {
 		return "", err
 	}
@@ -39,7 +41,7 @@ func findGitRoot() (string, error) {
 func (g *GitOperations) GetRepoName() (string, error) {
 	cmd := exec.Command("git", "remote", "get-url", "origin")
 	cmd.Dir = g.repoRoot
-	out, err := cmd.Output()
+	out, err := logging.Output(cmd)
 	if err != nil {
 		// No origin remote, use directory name
 		return filepath.Base(g.repoRoot), nil
@@ -75,7 +77,7 @@ func extractRepoName(url string) string {
 func (g *GitOperations) BranchExists(ctx context.Context, branch string) (bool, error) {
 	cmd := exec.CommandContext(ctx, "git", "show-ref", "--verify", "--quiet", "refs/heads/"+branch)
 	cmd.Dir = g.repoRoot
-	err := cmd.Run()
+	err := logging.Run(cmd)
 	if err != nil {
 		// Check if it's just that the branch doesn't exist
 		if exitError, ok := err.(*exec.ExitError); ok && exitError.ExitCode() == 1 {
@@ -90,7 +92,7 @@ func (g *GitOperations) BranchExists(ctx context.Context, branch string) (bool,
 func (g *GitOperations) RemoteBranchExists(ctx context.Context, remote, branch string) (bool, error) {
 	cmd := exec.CommandContext(ctx, "git", "show-ref", "--verify", "--quiet", "refs/remotes/"+remote+"/"+branch)
 	cmd.Dir = g.repoRoot
-	err := cmd.Run()
+	err := logging.Run(cmd)
 	if err != nil {
 		if exitError, ok := err.(*exec.ExitError); ok && exitError.ExitCode() == 1 {
 			return false, nil
@@ -103,7 +105,7 @@ func (g *GitOperations) RemoteBranchExists(ctx context.Context, remote, branch s
 // CreateBranch creates a new branch from the current HEAD
 func (g *GitOperations) CreateBranch(ctx context.Context, branchName string) error {
 	// Validate branch name
-	if err := validateBranchName(branchName); err != nil {
+	if err := ValidateBranchName(branchName); err != nil {
 		return err
 	}
 	
@@ -119,7 +121,7 @@ func (g *GitOperations) CreateBranch(ctx context.Context, branchName string) err
 	// Create the branch without checking it out
 	cmd := exec.CommandContext(ctx, "git", "branch", branchName)
 	cmd.Dir = g.repoRoot
-	if err := cmd.Run(); err != nil {
+	if err := logging.Run(cmd); err != nil {
 		return fmt.Errorf("failed to create branch %s: %w", branchName, err)
 	}
 	
@@ -129,7 +131,7 @@ func (g *GitOperations) CreateBranch(ctx context.Context, branchName string) err
 // DeleteBranch deletes a local branch
 func (g *GitOperations) DeleteBranch(ctx context.Context, branchName string) error {
 	// Validate branch name
-	if err := validateBranchName(branchName); err != nil {
+	if err := ValidateBranchName(branchName); err != nil {
 		return err
 	}
 	
@@ -154,11 +156,11 @@ func (g *GitOperations) DeleteBranch(ctx context.Context, branchName string) err
 	// Delete the branch
 	cmd := exec.CommandContext(ctx, "git", "branch", "-d", branchName)
 	cmd.Dir = g.repoRoot
-	if err := cmd.Run(); err != nil {
+	if err := logging.Run(cmd); err != nil {
 		// Try force delete if normal delete fails
 		cmd = exec.CommandContext(ctx, "git", "branch", "-D", branchName)
 		cmd.Dir = g.repoRoot
-		if err := cmd.Run(); err != nil {
+		if err := logging.Run(cmd); err != nil {
 			return fmt.Errorf("failed to delete branch %s: %w", branchName, err)
 		}
 	}
@@ -193,7 +195,7 @@ func (g *GitOperations) CreateWorktree(ctx context.Context, worktreePath, branch
 	cmd.Dir = g.repoRoot
 	
 	// Capture both stdout and stderr for better error reporting
-	output, err := cmd.CombinedOutput()
+	output, err := logging.CombinedOutput(cmd)
 	if err != nil {
 		gitOutput := strings.TrimSpace(string(output))
 		commandStr := fmt.Sprintf("git %s", strings.Join(args, " "))
@@ -208,20 +210,36 @@ func (g *GitOperations) CreateWorktree(ctx context.Context, worktreePath, branch
 	return nil
 }
 
+// CreateDetachedWorktree creates a git worktree with a detached HEAD at
+// ref's commit, for throwaway use; ref need not be free of other worktrees
+func (g *GitOperations) CreateDetachedWorktree(ctx context.Context, worktreePath, ref string) error {
+	if err := os.MkdirAll(filepath.Dir(worktreePath), 0755); err != nil {
+		return fmt.Errorf("failed to create parent directory: %w", err)
+	}
+	
+	cmd := exec.CommandContext(ctx, "git", "worktree", "add", "--detach", worktreePath, ref+"^{commit}")
+	cmd.Dir = g.repoRoot
+	output, err := logging.CombinedOutput(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to check out %s: %s", ref, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
 // RemoveWorktree removes a git worktree
 func (g *GitOperations) RemoveWorktree(ctx context.Context, worktreePath string) error {
 	// First remove the worktree directory if it exists
 	if _, err := os.Stat(worktreePath); err == nil {
 		cmd := exec.CommandContext(ctx, "git", "worktree", "remove", worktreePath)
 		cmd.Dir = g.repoRoot
-		if err := cmd.Run(); err != nil {
+		if err := logging.Run(cmd); err != nil {
 			return fmt.Errorf("failed to remove worktree: %w", err)
 		}
 	} else {
 		// Worktree directory doesn't exist, try to prune it
 		cmd := exec.CommandContext(ctx, "git", "worktree", "prune")
 		cmd.Dir = g.repoRoot
-		if err := cmd.Run(); err != nil {
+		if err := logging.Run(cmd); err != nil {
 			return fmt.Errorf("failed to prune worktrees: %w", err)
 		}
 	}
@@ -229,11 +247,34 @@ func (g *GitOperations) RemoveWorktree(ctx context.Context, worktreePath string)
 	return nil
 }
 
+// ForceRemoveWorktree removes a git worktree along with any untracked or
+// modified files in it, deleting the directory itself when git cannot. The
+// error names the path when something is left behind.
+func (g *GitOperations) ForceRemoveWorktree(ctx context.Context, worktreePath string) error {
+	if _, err := os.Stat(worktreePath); err == nil {
+		cmd := exec.CommandContext(ctx, "git", "worktree", "remove", "--force", "--force", worktreePath)
+		cmd.Dir = g.repoRoot
+		if err := logging.Run(cmd); err != nil {
+			// e.g. files the container user owns that git would not touch
+			if removeErr := os.RemoveAll(worktreePath); removeErr != nil {
+				return fmt.Errorf("failed to remove worktree, left at %s: %w", worktreePath, removeErr)
+			}
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "worktree", "prune")
+	cmd.Dir = g.repoRoot
+	if err := logging.Run(cmd); err != nil {
+		return fmt.Errorf("failed to prune worktrees: %w", err)
+	}
+	return nil
+}
+
 // ListWorktrees returns a list of all worktrees
 func (g *GitOperations) ListWorktrees(ctx context.Context) ([]WorktreeInfo, error) {
 	cmd := exec.CommandContext(ctx, "git", "worktree", "list", "--porcelain")
 	cmd.Dir = g.repoRoot
-	out, err := cmd.Output()
+	out, err := logging.Output(cmd)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list worktrees: %w", err)
 	}
@@ -295,7 +336,7 @@ func (g *GitOperations) ParseBranchReference(branchRef string) (remote, branch s
 func (g *GitOperations) FetchRemote(ctx context.Context, remote string) error {
 	cmd := exec.CommandContext(ctx, "git", "fetch", remote)
 	cmd.Dir = g.repoRoot
-	if err := cmd.Run(); err != nil {
+	if err := logging.Run(cmd); err != nil {
 		return fmt.Errorf("failed to fetch from %s: %w", remote, err)
 	}
 	return nil
@@ -305,15 +346,69 @@ func (g *GitOperations) FetchRemote(ctx context.Context, remote string) error {
 func (g *GitOperations) GetCurrentBranch(ctx context.Context) (string, error) {
 	cmd := exec.CommandContext(ctx, "git", "branch", "--show-current")
 	cmd.Dir = g.repoRoot
-	out, err := cmd.Output()
+	out, err := logging.Output(cmd)
 	if err != nil {
 		return "", fmt.Errorf("failed to get current branch: %w", err)
 	}
 	return strings.TrimSpace(string(out)), nil
 }
 
-// validateBranchName validates that a branch name is valid according to git rules
-func validateBranchName(name string) error {
+// ExcludePath adds pattern to the repository's info/exclude file, shared by
+// all its worktrees, unless it is already listed
+func (g *GitOperations) ExcludePath(ctx context.Context, pattern string) error {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--git-common-dir")
+	cmd.Dir = g.repoRoot
+	out, err := logging.Output(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to find git directory: %w", err)
+	}
+	gitDir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(g.repoRoot, gitDir)
+	}
+
+	excludePath := filepath.Join(gitDir, "info", "exclude")
+	data, err := os.ReadFile(excludePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == pattern {
+			return nil
+		}
+	}
+	if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+		pattern = "\n" + pattern
+	}
+	if err := os.MkdirAll(filepath.Dir(excludePath), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(excludePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(pattern + "\n")
+	return err
+}
+
+// GetConfigValue returns the effective git config value for key, or an empty
+// string if it is not set
+func (g *GitOperations) GetConfigValue(ctx context.Context, key string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "config", "--get", key)
+	cmd.Dir = g.repoRoot
+	out, err := logging.Output(cmd)
+	if err != nil {
+		if exitError, ok := err.(*exec.ExitError); ok && exitError.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read git config %s: %w", key, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ValidateBranchName validates that a branch name is valid according to git rules
+func ValidateBranchName(name string) error {
 	if name == "" {
 		return fmt.Errorf("branch name cannot be empty")
 	}
@@ -0,0 +1,68 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/audit"
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+	"github.com/jhjaggars/cc-buddy/internal/notify"
+)
+
+// recordEvent appends a lifecycle event for env to the history log. A
+// failure to record is logged rather than failing the operation it describes.
+func (m *Manager) recordEvent(entry audit.Event, env config.Environment, began time.Time, opErr error) {
+	entry.Environment = env.Name
+	entry.Branch = env.Branch
+	if opErr != nil {
+		entry.Error = opErr.Error()
+	}
+	if err := m.history.Record(entry, began); err != nil {
+		logging.Logger().Warn("failed to record history", "event", entry.Event, "environment", env.Name, "error", err.Error())
+	}
+	m.notifyEvent(entry, time.Since(began))
+}
+
+// notifyEvent sends the configured notifications for a finished lifecycle
+// event that failed or took long enough for the user to have looked away
+func (m *Manager) notifyEvent(entry audit.Event, elapsed time.Duration) {
+	cfg := m.configMgr.GetConfig().Notify
+	failed := entry.Error != ""
+	if !notify.ShouldNotify(cfg, elapsed, failed) {
+		return
+	}
+
+	took := elapsed.Round(time.Second)
+	n := notify.Notification{Failed: failed}
+	switch {
+	case entry.Event == audit.EventMaintenance:
+		status := "finished"
+		n.Message = entry.Result
+		if failed {
+			status = "failed"
+			n.Message = entry.Error
+		}
+		n.Title = fmt.Sprintf("cc-buddy: maintenance task %s %s", entry.Task, status)
+	case failed && entry.Event == audit.EventCreateFailed:
+		n.Title = fmt.Sprintf("cc-buddy: creating %s failed", entry.Environment)
+		n.Message = entry.Error
+	case failed:
+		n.Title = fmt.Sprintf("cc-buddy: %s %s failed", entry.Event, entry.Environment)
+		n.Message = entry.Error
+	case entry.Event == audit.EventCreated:
+		n.Title = fmt.Sprintf("cc-buddy: %s is ready", entry.Environment)
+		n.Message = fmt.Sprintf("Created from branch %s in %s", entry.Branch, took)
+	default:
+		n.Title = fmt.Sprintf("cc-buddy: %s %s", entry.Environment, entry.Event)
+		n.Message = fmt.Sprintf("Finished in %s", took)
+	}
+
+	// Use a fresh context: a cancelled create should still report its failure
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := notify.Send(ctx, cfg, n); err != nil {
+		logging.Logger().Warn("failed to send notification", "environment", entry.Environment, "error", err.Error())
+	}
+}
@@ -0,0 +1,60 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// Hook stages, as named in the configuration
+const (
+	hookPostCreate = "post_create"
+	hookPreDelete  = "pre_delete"
+)
+
+// hookOutputLines is how much of a failed hook's output its error shows
+const hookOutputLines = 10
+
+// runHooks runs a stage's hook commands in the container with sh -c, in
+// order, stopping at the first that fails
+func (m *Manager) runHooks(ctx context.Context, containerID, stage string, commands []string) error {
+	for _, command := range commands {
+		logging.Logger().Info("running hook", "stage", stage, "command", command)
+		result, err := m.containerMgr.GetRuntime().ExecCapture(ctx, containerID, []string{"sh", "-c", command})
+		if err != nil {
+			return fmt.Errorf("%s hook %q: %w", stage, command, err)
+		}
+		if result.ExitCode != 0 {
+			output := outputTail(append(result.Stdout, result.Stderr...), hookOutputLines)
+			return fmt.Errorf("%s hook %q exited with status %d:\n%s", stage, command, result.ExitCode, output)
+		}
+	}
+	return nil
+}
+
+// runPreDeleteHooks runs the pre_delete hooks if the container is
+// running. Failures are only reported; they never block a delete.
+func (m *Manager) runPreDeleteHooks(ctx context.Context, containerID string) {
+	hooks := m.configMgr.GetConfig().Hooks.PreDelete
+	if len(hooks) == 0 || containerID == "" {
+		return
+	}
+	status, err := m.containerMgr.GetRuntime().Status(ctx, containerID)
+	if err != nil || !status.Running {
+		return
+	}
+	if err := m.runHooks(ctx, containerID, hookPreDelete, hooks); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+}
+
+// outputTail returns the last n lines of command output
+func outputTail(output []byte, n int) string {
+	lines := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
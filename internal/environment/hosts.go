@@ -0,0 +1,283 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/container"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+const (
+	// DefaultHostsFile is the system hosts file managed when manage_hosts is enabled
+	DefaultHostsFile = "/etc/hosts"
+
+	// Each repository keeps its own block, marked with its root, e.g.
+	// "# BEGIN cc-buddy /home/me/src/myrepo"
+	hostsBlockBegin = "# BEGIN cc-buddy"
+	hostsBlockEnd   = "# END cc-buddy"
+)
+
+// Hostname returns the friendly hostname for an environment. Most browsers
+// resolve *.localhost to loopback on their own; the hosts file entries cover
+// command-line tools and resolvers that do not.
+func Hostname(envName string) string {
+	return envName + ".localhost"
+}
+
+// EnvironmentURLs returns http URLs for each TCP port the environment publishes
+func (m *Manager) EnvironmentURLs(ctx context.Context, envName string) ([]string, error) {
+	env, err := m.configMgr.GetEnvironment(envName)
+	if err != nil {
+		return nil, fmt.Errorf("environment not found: %w", err)
+	}
+	if env.ContainerID == "" {
+		return nil, nil
+	}
+
+	ports, err := m.containerMgr.GetRuntime().Ports(ctx, env.ContainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var urls []string
+	for _, port := range ports {
+		if port.Protocol != "tcp" {
+			continue
+		}
+		if port.HostIP != "" {
+			// Bound to one address, which the hostname may not resolve to
+			urls = append(urls, "http://"+port.HostAddress())
+			continue
+		}
+		urls = append(urls, fmt.Sprintf("http://%s:%d", Hostname(env.Name), port.Host))
+	}
+	return urls, nil
+}
+
+// PrimaryURL returns the URL of the environment's main web service: its
+// reverse proxy route when it has one, otherwise the published host port of
+// its lowest TCP container port
+func (m *Manager) PrimaryURL(ctx context.Context, envName string) (string, error) {
+	return m.ServiceURL(ctx, envName, 0)
+}
+
+// ServiceURL returns the http URL on the host of the environment's web
+// service on containerPort, resolving the host port it is published on. A
+// containerPort of 0 picks the main service, as PrimaryURL does.
+func (m *Manager) ServiceURL(ctx context.Context, envName string, containerPort int) (string, error) {
+	env, err := m.configMgr.GetEnvironment(envName)
+	if err != nil {
+		return "", fmt.Errorf("environment not found: %w", err)
+	}
+	if containerPort == 0 {
+		if url := m.ProxyURL(env); url != "" {
+			return url, nil
+		}
+	}
+	if env.ContainerID == "" {
+		return "", fmt.Errorf("environment %s has no container", envName)
+	}
+	if env.Network == NetworkHost {
+		// Nothing is published; the service listens on the host itself
+		if containerPort == 0 {
+			return "", fmt.Errorf("environment %s uses the host network; give the port its service listens on, e.g. cc-buddy browse %s 3000", envName, envName)
+		}
+		return fmt.Sprintf("http://localhost:%d", containerPort), nil
+	}
+
+	ports, err := m.containerMgr.GetRuntime().Ports(ctx, env.ContainerID)
+	if err != nil {
+		return "", err
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		return ports[i].Container < ports[j].Container
+	})
+	for _, port := range ports {
+		if port.Protocol != "tcp" || port.Host == 0 {
+			continue
+		}
+		if containerPort == 0 || port.Container == containerPort {
+			return "http://" + port.HostAddress(), nil
+		}
+	}
+	if containerPort != 0 {
+		return "", fmt.Errorf("environment %s does not publish TCP port %d; see cc-buddy ports %s", envName, containerPort, envName)
+	}
+	return "", fmt.Errorf("environment %s publishes no TCP ports; create it with --port or --expose-all", envName)
+}
+
+// RunningPorts returns the ports an environment's running container
+// publishes on the host, sorted by container port
+func (m *Manager) RunningPorts(ctx context.Context, envName string) ([]container.PortMapping, error) {
+	env, err := m.configMgr.GetEnvironment(envName)
+	if err != nil {
+		return nil, fmt.Errorf("environment not found: %w", err)
+	}
+	if env.ContainerID == "" {
+		return nil, fmt.Errorf("environment %s has no container", envName)
+	}
+
+	status, err := m.containerMgr.GetRuntime().Status(ctx, env.ContainerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check container status: %w", err)
+	}
+	if !status.Running {
+		return nil, fmt.Errorf("container for environment %s is not running", envName)
+	}
+
+	ports, err := m.containerMgr.GetRuntime().Ports(ctx, env.ContainerID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Container != ports[j].Container {
+			return ports[i].Container < ports[j].Container
+		}
+		return ports[i].Protocol < ports[j].Protocol
+	})
+	return ports, nil
+}
+
+// recordPublishedPorts reads back where env's container landed on the host,
+// including host ports the runtime picked, and records them on env. Lookup
+// failures only warn; list and inspect look the ports up again.
+func (m *Manager) recordPublishedPorts(ctx context.Context, env *config.Environment) {
+	ports, err := m.PublishedPorts(ctx, *env)
+	if err != nil {
+		fmt.Printf("Warning: failed to read published ports: %v\n", err)
+		logging.Logger().Warn("failed to read published ports", "environment", env.Name, "error", err)
+		return
+	}
+	env.Ports = ports
+}
+
+// PublishedPorts returns the ports env's container publishes on the host,
+// formatted as [ip:]host->container/protocol
+func (m *Manager) PublishedPorts(ctx context.Context, env config.Environment) ([]string, error) {
+	if env.ContainerID == "" {
+		return nil, nil
+	}
+	ports, err := m.containerMgr.GetRuntime().Ports(ctx, env.ContainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var published []string
+	for _, port := range ports {
+		published = append(published, port.String())
+	}
+	return published, nil
+}
+
+// SyncHosts rewrites the cc-buddy block of the hosts file so that every
+// environment in state has a <env>.localhost entry, plus its proxy hostname
+// when routed through the reverse proxy
+func (m *Manager) SyncHosts() error {
+	var hostnames []string
+	for _, env := range m.configMgr.Environments() {
+		hostnames = append(hostnames, Hostname(env.Name))
+		if env.ProxyHost != "" {
+			hostnames = append(hostnames, env.ProxyHost)
+		}
+	}
+	return UpdateHostsFile(m.hostsFile(), m.gitOps.repoRoot, hostnames)
+}
+
+// ClearHosts removes the repository's cc-buddy block from the hosts file,
+// leaving other repositories' entries
+func (m *Manager) ClearHosts() error {
+	return UpdateHostsFile(m.hostsFile(), m.gitOps.repoRoot, nil)
+}
+
+// syncHostsIfEnabled keeps the hosts file current after environment changes.
+// Failures are reported but never fail the surrounding operation.
+func (m *Manager) syncHostsIfEnabled() {
+	if !m.configMgr.GetConfig().ManageHosts {
+		return
+	}
+	if err := m.SyncHosts(); err != nil {
+		fmt.Printf("Warning: failed to update %s: %v (try 'sudo cc-buddy hosts sync')\n", m.hostsFile(), err)
+	}
+}
+
+// hostsFile returns the configured hosts file path
+func (m *Manager) hostsFile() string {
+	if path := m.configMgr.GetConfig().HostsFile; path != "" {
+		return path
+	}
+	return defaultHostsFile()
+}
+
+// defaultHostsFile returns the system hosts file for the host OS
+func defaultHostsFile() string {
+	if runtime.GOOS == "windows" {
+		systemRoot := os.Getenv("SystemRoot")
+		if systemRoot == "" {
+			systemRoot = `C:\Windows`
+		}
+		return filepath.Join(systemRoot, "System32", "drivers", "etc", "hosts")
+	}
+	return DefaultHostsFile
+}
+
+// UpdateHostsFile replaces the cc-buddy managed block of the repository at
+// repoRoot in the hosts file at path with loopback entries for hostnames. An
+// empty list removes the block. Lines outside the block, including other
+// repositories' blocks, are left untouched. The unkeyed block written by
+// earlier versions is dropped; other repositories get their entries back on
+// their next sync.
+func UpdateHostsFile(path, repoRoot string, hostnames []string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat hosts file: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read hosts file: %w", err)
+	}
+
+	begin := hostsBlockBegin + " " + repoRoot
+	end := hostsBlockEnd + " " + repoRoot
+
+	var kept []string
+	inBlock := false
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		switch strings.TrimSpace(line) {
+		case begin, hostsBlockBegin:
+			inBlock = true
+			continue
+		case end, hostsBlockEnd:
+			inBlock = false
+			continue
+		}
+		if !inBlock {
+			kept = append(kept, line)
+		}
+	}
+
+	if len(hostnames) > 0 {
+		sorted := append([]string(nil), hostnames...)
+		sort.Strings(sorted)
+
+		kept = append(kept, begin)
+		for _, hostname := range sorted {
+			kept = append(kept, "127.0.0.1\t"+hostname)
+			kept = append(kept, "::1\t\t"+hostname)
+		}
+		kept = append(kept, end)
+	}
+
+	// Write in place rather than renaming: /etc/hosts is often a bind mount
+	content := strings.Join(kept, "\n") + "\n"
+	if err := os.WriteFile(path, []byte(content), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write hosts file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// idleCPUPercent is the CPU usage, in percent of one CPU, below which a
+// container counts as doing nothing
+const idleCPUPercent = 5.0
+
+// IdleEnvironment is a running environment nobody has touched for a while
+type IdleEnvironment struct {
+	Name       string
+	IdleFor    time.Duration // since the last use, start or create
+	CPUPercent float64
+}
+
+// StopAfterIdle returns the configured stop_after_idle, or 0 when idle
+// environments are kept running
+func (m *Manager) StopAfterIdle() (time.Duration, error) {
+	return ParseIdleDuration(m.configMgr.GetConfig().StopAfterIdle)
+}
+
+// ParseIdleDuration parses an idle timeout such as 4h; empty means none
+func ParseIdleDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid idle timeout %q (expected a duration like 4h or 90m)", value)
+	}
+	return duration, nil
+}
+
+// IdleEnvironments returns the running environments not used for at least
+// after that have no open terminal or exec session and whose container is
+// using almost no CPU. Environments whose activity cannot be checked are
+// kept.
+func (m *Manager) IdleEnvironments(ctx context.Context, after time.Duration) ([]IdleEnvironment, error) {
+	environments, err := m.ListEnvironments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	runtime := m.containerMgr.GetRuntime()
+	var idle []IdleEnvironment
+	for _, env := range environments {
+		if env.Status != "running" || env.ContainerID == "" {
+			continue
+		}
+		idleFor := time.Since(lastActivity(env))
+		if idleFor < after {
+			continue
+		}
+		sessions, err := runtime.ExecSessions(ctx, env.ContainerID)
+		if err != nil {
+			logging.Logger().Debug("keeping environment with unknown exec sessions", "environment", env.Name, "error", err.Error())
+			continue
+		}
+		if sessions > 0 {
+			continue
+		}
+		usage, err := runtime.Stats(ctx, env.ContainerID)
+		if err != nil {
+			logging.Logger().Debug("keeping environment with unknown resource usage", "environment", env.Name, "error", err.Error())
+			continue
+		}
+		if usage.CPUPercent >= idleCPUPercent {
+			continue
+		}
+		idle = append(idle, IdleEnvironment{Name: env.Name, IdleFor: idleFor, CPUPercent: usage.CPUPercent})
+	}
+	return idle, nil
+}
+
+// lastActivity is when env was last used, or else started or created
+func lastActivity(env config.Environment) time.Time {
+	last := env.Created
+	if env.StartedAt != nil && env.StartedAt.After(last) {
+		last = *env.StartedAt
+	}
+	if env.LastUsed != nil && env.LastUsed.After(last) {
+		last = *env.LastUsed
+	}
+	return last
+}
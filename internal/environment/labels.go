@@ -0,0 +1,82 @@
+package environment
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+)
+
+// ParseLabel parses a key=value label. Keys under the cc-buddy. prefix are
+// reserved for the labels cc-buddy sets itself.
+func ParseLabel(s string) (string, string, error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return "", "", fmt.Errorf("invalid label %q: expected key=value", s)
+	}
+	if err := validateLabelKey(key); err != nil {
+		return "", "", err
+	}
+	return key, value, nil
+}
+
+// validateLabelKey checks a user label key
+func validateLabelKey(key string) error {
+	if key == "" || strings.ContainsAny(key, " \t\n=,") {
+		return fmt.Errorf("invalid label key %q", key)
+	}
+	if strings.HasPrefix(key, "cc-buddy.") {
+		return fmt.Errorf("invalid label key %q: the cc-buddy. prefix is reserved", key)
+	}
+	return nil
+}
+
+// LabelSelector matches environments by label: key=value requires that
+// value, a bare key only that the label is set
+type LabelSelector struct {
+	Key      string
+	Value    string
+	HasValue bool
+}
+
+// ParseLabelSelector parses key=value or key
+func ParseLabelSelector(s string) (LabelSelector, error) {
+	key, value, hasValue := strings.Cut(s, "=")
+	if err := validateLabelKey(key); err != nil {
+		return LabelSelector{}, err
+	}
+	return LabelSelector{Key: key, Value: value, HasValue: hasValue}, nil
+}
+
+// MatchLabels reports whether env has every selected label
+func MatchLabels(env config.Environment, selectors []LabelSelector) bool {
+	for _, selector := range selectors {
+		value, ok := env.Labels[selector.Key]
+		if !ok || (selector.HasValue && value != selector.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// SetLabels adds or changes the labels in set and removes those in remove.
+// The container picks them up the next time it is recreated.
+func (m *Manager) SetLabels(envName string, set map[string]string, remove []string) error {
+	if _, err := m.configMgr.GetEnvironment(envName); err != nil {
+		return fmt.Errorf("environment not found: %w", err)
+	}
+	return m.configMgr.UpdateEnvironment(envName, func(env *config.Environment) {
+		if env.Labels == nil {
+			env.Labels = map[string]string{}
+		}
+		for key, value := range set {
+			env.Labels[key] = value
+		}
+		for _, key := range remove {
+			delete(env.Labels, key)
+		}
+		if len(env.Labels) == 0 {
+			env.Labels = nil
+		}
+	})
+}
@@ -0,0 +1,20 @@
+package environment
+
+import (
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// markUsed records that the environment was just used. Failing to record it
+// only loses the timestamp, so it is logged rather than returned.
+func (m *Manager) markUsed(envName string) {
+	now := time.Now()
+	err := m.configMgr.UpdateEnvironment(envName, func(env *config.Environment) {
+		env.LastUsed = &now
+	})
+	if err != nil {
+		logging.Logger().Warn("failed to record last use", "environment", envName, "error", err.Error())
+	}
+}
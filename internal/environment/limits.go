@@ -0,0 +1,107 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+)
+
+// reserveEnvironment checks that creating envName stays within the
+// max_environments limits of the repository and of the machine (the global
+// config), and holds a slot for it until release is called so concurrent
+// creates in this process cannot overshoot together
+func (m *Manager) reserveEnvironment(ctx context.Context, envName string) (release func(), err error) {
+	m.limitMu.Lock()
+	defer m.limitMu.Unlock()
+
+	if err := m.checkEnvironmentLimits(ctx, envName); err != nil {
+		return nil, err
+	}
+	if m.reserved == nil {
+		m.reserved = map[string]bool{}
+	}
+	m.reserved[envName] = true
+	return func() {
+		m.limitMu.Lock()
+		delete(m.reserved, envName)
+		m.limitMu.Unlock()
+	}, nil
+}
+
+// checkEnvironmentLimits returns an error if one more environment, envName,
+// would exceed max_environments. Environments being created (in the
+// background or by this process) count; envName itself does not. The
+// caller must hold m.limitMu.
+func (m *Manager) checkEnvironmentLimits(ctx context.Context, envName string) error {
+	repoLimit := m.configMgr.GetConfig().MaxEnvironments
+	global, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	if repoLimit <= 0 && global.MaxEnvironments <= 0 {
+		return nil
+	}
+
+	existing := map[string]bool{}
+	for _, env := range m.configMgr.Environments() {
+		existing[env.Name] = true
+	}
+	for _, pending := range pendingCreates() {
+		existing[pending.Environment] = true
+	}
+	for name := range m.reserved {
+		existing[name] = true
+	}
+	delete(existing, envName)
+
+	if repoLimit > 0 && len(existing) >= repoLimit {
+		return fmt.Errorf("this repository already has %d of at most %d environments (max_environments in %s/%s); delete one with 'cc-buddy delete <env-name>' or raise the limit",
+			len(existing), repoLimit, config.StateDir, config.ConfigFile)
+	}
+
+	if global.MaxEnvironments > 0 {
+		// Other repositories' environments are found by their container
+		// labels; dind sidecars and shared containers carry them too
+		containers, err := m.containerMgr.GetRuntime().Containers(ctx, LabelEnvironment)
+		if err == nil {
+			var helpers []string
+			if helpers, err = m.containerMgr.GetRuntime().Containers(ctx, LabelRole); err == nil {
+				containers = excludeNames(containers, helpers)
+			}
+		}
+		if err != nil {
+			fmt.Printf("Warning: global max_environments not enforced: %v\n", err)
+			return nil
+		}
+		all := map[string]bool{}
+		for _, name := range containers {
+			all[name] = true
+		}
+		for name := range existing {
+			all["cc-buddy-"+name] = true
+		}
+		delete(all, "cc-buddy-"+envName)
+		if len(all) >= global.MaxEnvironments {
+			path, _ := config.GlobalConfigPath()
+			return fmt.Errorf("this machine already has %d of at most %d environments across all repositories (max_environments in %s); delete one with 'cc-buddy delete <env-name>' or raise the limit",
+				len(all), global.MaxEnvironments, path)
+		}
+	}
+	return nil
+}
+
+// excludeNames returns names without those in excluded
+func excludeNames(names, excluded []string) []string {
+	skip := map[string]bool{}
+	for _, name := range excluded {
+		skip[name] = true
+	}
+	var kept []string
+	for _, name := range names {
+		if !skip[name] {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,280 @@
+package environment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/jhjaggars/cc-buddy/internal/audit"
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// Maintenance tasks the daemon can schedule
+const (
+	TaskGC      = "gc"      // remove unused images and volumes
+	TaskRefresh = "refresh" // pull the base images of the environments' Containerfiles
+	TaskTTL     = "ttl"     // delete environments older than max_age
+)
+
+// ValidateMaintenance checks the configured maintenance jobs
+func ValidateMaintenance(jobs []config.MaintenanceJob) error {
+	for i, job := range jobs {
+		name := fmt.Sprintf("maintenance job %d (%s)", i+1, job.Task)
+		if _, err := cron.ParseStandard(job.Schedule); err != nil {
+			return fmt.Errorf("%s: invalid schedule %q: %w", name, job.Schedule, err)
+		}
+		switch job.Task {
+		case TaskGC:
+			if job.KeepLast < 0 {
+				return fmt.Errorf("%s: invalid keep_last %d", name, job.KeepLast)
+			}
+			if job.OlderThan != "" {
+				if _, err := ParseAge(job.OlderThan); err != nil {
+					return fmt.Errorf("%s: older_than: %w", name, err)
+				}
+			}
+		case TaskRefresh:
+		case TaskTTL:
+			if job.MaxAge == "" {
+				return fmt.Errorf("%s: max_age is required", name)
+			}
+			if _, err := ParseAge(job.MaxAge); err != nil {
+				return fmt.Errorf("%s: max_age: %w", name, err)
+			}
+		default:
+			return fmt.Errorf("%s: unknown task %q (expected gc, refresh or ttl)", name, job.Task)
+		}
+	}
+	return nil
+}
+
+// RunMaintenance runs a validated job's task and records it in the history
+// log, returning a summary of what it did. lock, when set, is held around
+// each change to one environment, so it cannot race other operations on it.
+func (m *Manager) RunMaintenance(ctx context.Context, job config.MaintenanceJob, lock func(envName string) (unlock func())) (string, error) {
+	began := time.Now()
+	var result string
+	var err error
+	switch job.Task {
+	case TaskGC:
+		result, err = m.maintainGC(ctx, job)
+	case TaskRefresh:
+		result, err = m.refreshBaseImages(ctx)
+	case TaskTTL:
+		result, err = m.enforceTTL(ctx, job, lock)
+	default:
+		err = fmt.Errorf("unknown maintenance task %q", job.Task)
+	}
+	m.recordEvent(audit.Event{Event: audit.EventMaintenance, Task: job.Task, Result: result}, config.Environment{}, began, err)
+	return result, err
+}
+
+// maintainGC removes what "cc-buddy gc" would
+func (m *Manager) maintainGC(ctx context.Context, job config.MaintenanceJob) (string, error) {
+	opts := GCOptions{KeepLast: job.KeepLast}
+	if job.OlderThan != "" {
+		opts.OlderThan, _ = ParseAge(job.OlderThan)
+	}
+	items, err := m.GCCandidates(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+	removed, err := m.RemoveGCItems(ctx, items)
+	return fmt.Sprintf("removed %d of %d unused images and volumes", removed, len(items)), err
+}
+
+// refreshBaseImages pulls the base images of every environment's
+// Containerfile, so the next create or rebuild starts from the latest ones,
+// and reports the pinned ones that have moved upstream
+func (m *Manager) refreshBaseImages(ctx context.Context) (string, error) {
+	environments, err := m.ListEnvironments(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	seen := map[string]bool{}
+	var images []string
+	for _, env := range environments {
+		if env.Containerfile == "" {
+			continue
+		}
+		bases, err := baseImages(containerfilePath(env))
+		if err != nil {
+			continue // e.g. a worktree removed by hand
+		}
+		for _, image := range bases {
+			if !seen[image] {
+				seen[image] = true
+				images = append(images, image)
+			}
+		}
+	}
+
+	pulled := 0
+	var errs []error
+	for _, image := range images {
+		if err := m.containerMgr.GetRuntime().Pull(ctx, image); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		pulled++
+	}
+
+	moved := 0
+	for _, env := range environments {
+		for image, pinned := range env.BaseImageDigests {
+			digest, err := m.containerMgr.GetRuntime().ImageDigest(ctx, image)
+			if err == nil && digest != "" && digest != pinned {
+				logging.Logger().Warn("pinned base image moved upstream", "environment", env.Name, "image", image, "digest", digest)
+				moved++
+			}
+		}
+	}
+	result := fmt.Sprintf("pulled %d of %d base images", pulled, len(images))
+	if moved > 0 {
+		result += fmt.Sprintf(", %d pinned ones moved upstream", moved)
+	}
+	return result, errors.Join(errs...)
+}
+
+// PrefetchImages returns the base images to pull ahead of a create: those
+// of containerfile, or of the configured Containerfile when empty, relative
+// to the main checkout
+func (m *Manager) PrefetchImages(containerfile string) ([]string, error) {
+	if containerfile == "" {
+		containerfile = m.configMgr.GetConfig().Containerfile
+	}
+	path := containerfilePath(config.Environment{WorktreePath: m.gitOps.repoRoot, Containerfile: containerfile})
+	images, err := baseImages(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read containerfile: %w", err)
+	}
+	return images, nil
+}
+
+// PullImage pulls image from its registry
+func (m *Manager) PullImage(ctx context.Context, image string) error {
+	return m.containerMgr.GetRuntime().Pull(ctx, image)
+}
+
+// baseImages returns the registry images a Containerfile builds FROM,
+// leaving out earlier stages, scratch and images named by build args
+func baseImages(containerfile string) ([]string, error) {
+	data, err := os.ReadFile(containerfile)
+	if err != nil {
+		return nil, err
+	}
+
+	stages := map[string]bool{}
+	var images []string
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+		args := fields[1:]
+		for len(args) > 0 && strings.HasPrefix(args[0], "--") {
+			args = args[1:] // --platform
+		}
+		if len(args) == 0 {
+			continue
+		}
+		image := args[0]
+		if image != "scratch" && !stages[strings.ToLower(image)] && !strings.Contains(image, "$") {
+			images = append(images, image)
+		}
+		if len(args) >= 3 && strings.EqualFold(args[1], "AS") {
+			stages[strings.ToLower(args[2])] = true
+		}
+	}
+	return images, nil
+}
+
+// enforceTTL deletes the environments created more than max_age ago,
+// except protected ones
+func (m *Manager) enforceTTL(ctx context.Context, job config.MaintenanceJob, lock func(string) func()) (string, error) {
+	maxAge, err := ParseAge(job.MaxAge)
+	if err != nil {
+		return "", err
+	}
+	began := time.Now()
+	environments, err := m.ListEnvironments(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	deleted, protected, inUse := 0, 0, 0
+	var errs []error
+	for _, env := range environments {
+		if env.Status == "creating" || time.Since(env.Created) < maxAge {
+			continue
+		}
+		if env.Protected {
+			protected++
+			continue
+		}
+		expired, err := m.expireEnvironment(ctx, env.Name, began, lock)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", env.Name, err))
+		case expired:
+			deleted++
+		default:
+			inUse++
+		}
+	}
+
+	result := fmt.Sprintf("deleted %d environments older than %s", deleted, job.MaxAge)
+	if protected > 0 {
+		result += fmt.Sprintf(", kept %d protected", protected)
+	}
+	if inUse > 0 {
+		result += fmt.Sprintf(", kept %d in use", inUse)
+	}
+	return result, errors.Join(errs...)
+}
+
+// expireEnvironment deletes an environment found past its TTL, under lock.
+// The listing may be stale by the time the lock is held, so the environment
+// is kept if it has gone, been protected or been used since the job began.
+func (m *Manager) expireEnvironment(ctx context.Context, envName string, since time.Time, lock func(string) func()) (bool, error) {
+	if lock != nil {
+		defer lock(envName)()
+	}
+	if err := m.configMgr.LoadState(); err != nil {
+		return false, fmt.Errorf("failed to load state: %w", err)
+	}
+	env, err := m.configMgr.GetEnvironment(envName)
+	if err != nil {
+		return false, nil // deleted meanwhile
+	}
+	if env.Protected || (env.LastUsed != nil && env.LastUsed.After(since)) {
+		return false, nil
+	}
+	return true, m.DeleteEnvironment(ctx, envName, false)
+}
+
+// ParseAge parses an age: a Go duration such as 36h, or a number of days or
+// weeks such as 7d or 2w
+func ParseAge(value string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if number, ok := strings.CutSuffix(value, suffix); ok {
+			if n, err := strconv.Atoi(number); err == nil && n >= 0 {
+				return time.Duration(n) * unit, nil
+			}
+		}
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("invalid age %q (expected a duration like 7d, 2w or 36h)", value)
+	}
+	return age, nil
+}
@@ -2,22 +2,49 @@ package environment
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/jhjaggars/cc-buddy/internal/audit"
 	"github.com/jhjaggars/cc-buddy/internal/config"
 	"github.com/jhjaggars/cc-buddy/internal/container"
-	"github.com/jhjaggars/cc-buddy/internal/system"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+	"github.com/jhjaggars/cc-buddy/internal/notify"
+	"github.com/jhjaggars/cc-buddy/internal/scaffold"
 )
 
 // Manager orchestrates environment creation, management, and cleanup
 type Manager struct {
-	configMgr     *config.Manager
-	containerMgr  *container.Manager
-	gitOps        *GitOperations
+	configMgr    *config.Manager
+	containerMgr *container.Manager
+	gitOps       *GitOperations
+	history      *audit.Log
+	gitMu        sync.Mutex // serializes branch and worktree creation
+
+	// limitMu guards reserved, the environments this process is creating
+	limitMu  sync.Mutex
+	reserved map[string]bool
+}
+
+// ConfigError reports configuration that could not be loaded
+type ConfigError struct {
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
 }
 
 // NewManager creates a new environment manager
@@ -29,7 +56,7 @@ func NewManager() (*Manager, error) {
 	
 	// Load existing configuration
 	if err := configMgr.LoadConfig(); err != nil {
-		return nil, fmt.Errorf("failed to load config: %w", err)
+		return nil, fmt.Errorf("failed to load config: %w", &ConfigError{Err: err})
 	}
 	
 	if err := configMgr.LoadState(); err != nil {
@@ -37,12 +64,12 @@ func NewManager() (*Manager, error) {
 	}
 	
 	// Initialize container manager based on config
-	var containerMgr *container.Manager
 	cfg := configMgr.GetConfig()
-	if cfg.Runtime == "auto" {
-		containerMgr, err = container.NewManager()
-	} else {
-		containerMgr, err = container.NewManagerWithRuntime(cfg.Runtime)
+	containerMgr, err := newContainerManager(cfg)
+	var unavailableErr *container.UnavailableError
+	if errors.As(err, &unavailableErr) {
+		// Worktree-only mode: environments can still be listed and deleted
+		containerMgr, err = container.NewUnavailableManager(unavailableErr), nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create container manager: %w", err)
@@ -54,76 +81,306 @@ func NewManager() (*Manager, error) {
 		return nil, fmt.Errorf("failed to create git operations: %w", err)
 	}
 	
-	return &Manager{
+	m := &Manager{
 		configMgr:    configMgr,
 		containerMgr: containerMgr,
 		gitOps:       gitOps,
-	}, nil
+		history:      audit.NewLog(config.StateDir),
+	}
+	if len(configMgr.Environments()) > 0 {
+		// Repositories with environments from before the registry existed
+		m.register()
+	}
+	return m, nil
 }
 
 // CreateEnvironmentOptions holds options for environment creation
 type CreateEnvironmentOptions struct {
-	BranchName      string
-	IsRemoteBranch  bool
-	RemoteName      string
-	WorktreeDir     string
-	Containerfile   string
-	ExposeAllPorts  bool
-	StartupCommand  []string
+	BranchName        string
+	IsRemoteBranch    bool
+	RemoteName        string
+	WorktreeDir       string
+	Containerfile     string
+	ExposeAllPorts    bool
+	Ports             []string          // ports to publish as [host:]container[/protocol]
+	RuntimeArgs       []string          // extra run arguments, each split like a command line
+	Privileged        bool              // run the container privileged
+	CapAdd            []string          // capabilities to add
+	Devices           []string          // host devices as host[:container[:permissions]]
+	StartupCommand    []string
+	Mounts            []string          // extra mounts as source:target[:options]
+	ReadOnlyWorkspace bool              // mount the worktree read-only for review-only environments
+	Network           string            // network mode; "shared" joins the common cc-buddy network
+	NoStart           bool              // create the container without starting it
+	Template          string            // build from this init template's Containerfile instead
+	Labels            map[string]string // user labels for the environment and its container
+	NoContainer       bool              // only create the branch and worktree
+	Here              bool              // mount the current checkout instead of creating a worktree
+	WorktreePath      string            // adopt this existing worktree instead of creating one
+	Image             string            // use this published image instead of building
+	Definition        *Definition       // lockfile being reproduced (create --from-def)
+	Throwaway         bool              // check out the branch's commit detached, under a unique name (run)
+}
+
+// ParseCommand parses a command string into arguments
+// Simple implementation that splits on spaces, respecting quoted strings
+func ParseCommand(commandStr string) []string {
+	if commandStr == "" {
+		return nil
+	}
+	
+	var args []string
+	var current strings.Builder
+	inQuotes := false
+	quoteChar := byte(0)
+	
+	for i := 0; i < len(commandStr); i++ {
+		char := commandStr[i]
+		
+		switch char {
+		case '"', '\'':
+			if !inQuotes {
+				inQuotes = true
+				quoteChar = char
+			} else if char == quoteChar {
+				inQuotes = false
+				quoteChar = 0
+			} else {
+				current.WriteByte(char)
+			}
+		case ' ', '\t':
+			if inQuotes {
+				current.WriteByte(char)
+			} else {
+				if current.Len() > 0 {
+					args = append(args, current.String())
+					current.Reset()
+				}
+			}
+		default:
+			current.WriteByte(char)
+		}
+	}
+	
+	if current.Len() > 0 {
+		args = append(args, current.String())
+	}
+	
+	return args
 }
 
 // CreateEnvironment creates a new development environment
 func (m *Manager) CreateEnvironment(ctx context.Context, opts CreateEnvironmentOptions) (retEnv *config.Environment, retErr error) {
+	began := time.Now()
+	var buildTime time.Duration
+	
+	if !m.containerMgr.Available() && !opts.NoContainer {
+		_, err := m.containerMgr.GetRuntime().Detect(ctx)
+		return nil, err
+	}
+	if opts.Here {
+		if err := m.resolveHereBranch(ctx, &opts); err != nil {
+			return nil, err
+		}
+	}
+	if opts.WorktreePath != "" {
+		if err := m.resolveAdoptedWorktree(ctx, &opts); err != nil {
+			return nil, err
+		}
+	}
+	
 	// Generate environment name
 	envName, err := m.gitOps.GenerateEnvironmentName(opts.BranchName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate environment name: %w", err)
 	}
+	if opts.Throwaway {
+		// Never collides with the branch's own environment or another run
+		suffix := make([]byte, 3)
+		if _, err := rand.Read(suffix); err != nil {
+			return nil, fmt.Errorf("failed to generate environment name: %w", err)
+		}
+		envName += "-run-" + hex.EncodeToString(suffix)
+	}
 	
 	// Check if environment already exists
 	if _, err := m.configMgr.GetEnvironment(envName); err == nil {
 		return nil, fmt.Errorf("environment %s already exists", envName)
 	}
+	release, err := m.reserveEnvironment(ctx, envName)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 	
 	// Set up default options
 	if opts.WorktreeDir == "" {
 		opts.WorktreeDir = m.configMgr.GetConfig().WorktreeDir
 	}
+	if opts.NoContainer {
+		return m.createWorktreeEnvironment(ctx, envName, opts, began)
+	}
+	if opts.Image != "" && opts.Template != "" {
+		return nil, fmt.Errorf("--image cannot be combined with --template")
+	}
+	if opts.Definition != nil && opts.Template != "" {
+		return nil, fmt.Errorf("--from-def cannot be combined with --template")
+	}
 	if opts.Containerfile == "" {
 		opts.Containerfile = m.configMgr.GetConfig().Containerfile
 	}
+	if opts.Network == "" {
+		opts.Network = m.configMgr.GetConfig().Network
+	}
+	if err := validateNetworkMode(opts.Network); err != nil {
+		return nil, err
+	}
+	for _, spec := range opts.Ports {
+		if _, err := container.ParsePortMapping(spec); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Network == NetworkHost {
+		if err := m.validateHostNetwork(opts); err != nil {
+			return nil, err
+		}
+	} else if m.configMgr.GetConfig().Proxy && opts.Network != NetworkShared {
+		// The proxy reaches environments over the shared network
+		fmt.Printf("Note: joining the shared network (%s) instead of the default one so the reverse proxy can reach the environment\n", SharedNetworkName)
+		opts.Network = NetworkShared
+	}
+	
+	// Validate extra mounts up front so nothing needs to be rolled back.
+	// Shared caches are named volumes checked alongside them.
+	cacheSpecs, cacheEnv, err := m.sharedCacheMounts()
+	if err != nil {
+		return nil, err
+	}
+	mountRequests := append(append(append([]string{}, m.configMgr.GetConfig().Mounts...), cacheSpecs...), opts.Mounts...)
+	extraMounts, mountSpecs, err := resolveExtraMounts(mountRequests, m.containerMgr.GetRuntime().Name())
+	if err != nil {
+		return nil, err
+	}
+	
+	workspaceMode, remoteHost, err := m.resolveWorkspaceMode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	labelOption, err := m.workspaceLabelOption(ctx, opts.WorktreeDir)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Here && labelOption == SELinuxLabelPrivate {
+		// Other --here containers may mount the same checkout
+		labelOption = SELinuxLabelShared
+	}
+	workspaceSync, consistency := "", m.configMgr.GetConfig().MountConsistency
+	if err := validateMountConsistency(consistency); err != nil {
+		return nil, err
+	}
+	if err := m.validateCompose(); err != nil {
+		return nil, err
+	}
+	if err := m.validateContainerUser(); err != nil {
+		return nil, err
+	}
+	if err := notify.Validate(m.configMgr.GetConfig().Notify); err != nil {
+		return nil, err
+	}
+	if err := ValidateScanSeverity(m.configMgr.GetConfig().ScanOnCreate); err != nil {
+		return nil, err
+	}
+	if err := m.validateNestedContainers(m.configMgr.GetConfig().NestedContainers, remoteHost); err != nil {
+		return nil, err
+	}
+	if workspaceMode == WorkspaceModeSync {
+		if workspaceSync, err = m.validateWorkspaceSync(); err != nil {
+			return nil, err
+		}
+	}
+	if workspaceMode == WorkspaceModeSync && opts.ReadOnlyWorkspace {
+		return nil, fmt.Errorf("--read-only-workspace cannot be combined with the sync workspace mode")
+	}
+	if remoteHost != "" {
+		for _, mount := range extraMounts {
+			if mount.Type == "bind" {
+				return nil, fmt.Errorf("mount %s: bind mounts are unavailable with remote runtime host %s", mount, remoteHost)
+			}
+		}
+	}
+	
+	var tmpl *scaffold.Template
+	if opts.Template != "" {
+		if tmpl, err = scaffold.LoadTemplate(ctx, opts.Template); err != nil {
+			return nil, err
+		}
+	}
 	
 	// Create worktree path
 	worktreePath := filepath.Join(opts.WorktreeDir, envName)
+	if opts.Here {
+		worktreePath = m.gitOps.repoRoot
+	} else if opts.WorktreePath != "" {
+		worktreePath = opts.WorktreePath
+	}
 	
 	// Track resources for cleanup
 	type cleanupState struct {
-		environmentInState bool
-		branchCreated     bool
-		worktreeCreated   bool
-		imageBuilt        bool
-		volumeCreated     bool
-		containerStarted  bool
-		imageName         string
+		environmentInState     bool
+		branchCreated          bool
+		worktreeCreated        bool
+		imageBuilt             bool
+		volumeCreated          bool
+		workspaceVolumeCreated bool
+		composeStarted         bool
+		containerStarted       bool
+		dindVolumeCreated      bool
+		dindStarted            bool
+		templateWritten        bool
+		imageName              string
 	}
 	
 	cleanup := &cleanupState{}
 	
 	// Create the environment step by step
 	env := &config.Environment{
-		Name:          envName,
-		Branch:        opts.BranchName,
-		WorktreePath:  worktreePath,
-		ContainerName: fmt.Sprintf("cc-buddy-%s", envName),
-		VolumeName:    fmt.Sprintf("cc-buddy-%s-data", envName),
-		Created:       time.Now(),
-		Status:        "creating",
+		Name:              envName,
+		Branch:            opts.BranchName,
+		WorktreePath:      worktreePath,
+		ContainerName:     fmt.Sprintf("cc-buddy-%s", envName),
+		VolumeName:        fmt.Sprintf("cc-buddy-%s-data", envName),
+		Created:           time.Now(),
+		Status:            "creating",
+		Mounts:            mountSpecs,
+		ReadOnlyWorkspace: opts.ReadOnlyWorkspace,
+		Network:           opts.Network,
+		ContainerUser:     m.containerUser(),
+		Containerfile:     opts.Containerfile,
+		InPlace:           opts.Here,
+		ExposeAllPorts:    opts.ExposeAllPorts,
+		PortMappings:      opts.Ports,
+		RuntimeArgs:       m.runtimeArgs(opts.RuntimeArgs),
+		StartupCommand:    opts.StartupCommand,
+		Labels:            opts.Labels,
+		SourceImage:       opts.Image,
+		NestedContainers:  m.configMgr.GetConfig().NestedContainers,
+	}
+	if err := m.applyPrivileges(env, opts, remoteHost); err != nil {
+		return nil, err
 	}
 	
 	// Enhanced cleanup on failure - preserves original error
 	defer func() {
 		if retErr != nil {
+			logging.Logger().Error("create failed, rolling back", "environment", envName, "error", retErr.Error())
+			m.recordEvent(audit.Event{Event: audit.EventCreateFailed, BuildMS: buildTime.Milliseconds()}, *env, began, retErr)
+			
 			// Perform granular cleanup in reverse order of creation
+			if cleanup.dindStarted || cleanup.dindVolumeCreated {
+				if removeErr := m.removeDind(ctx, *env, cleanup.dindVolumeCreated); removeErr != nil {
+					fmt.Printf("Warning: Failed to remove docker-in-docker sidecar during cleanup: %v\n", removeErr)
+				}
+			}
 			if cleanup.containerStarted && env.ContainerID != "" {
 				if stopErr := m.containerMgr.GetRuntime().Stop(ctx, env.ContainerID); stopErr != nil {
 					// Log but don't override original error
@@ -134,6 +391,19 @@ func (m *Manager) CreateEnvironment(ctx context.Context, opts CreateEnvironmentO
 				}
 			}
 			
+			// The compose network can only be removed once the container is gone
+			if cleanup.composeStarted {
+				if downErr := m.composeDown(ctx, *env, filepath.Join(worktreePath, env.ComposeFile)); downErr != nil {
+					fmt.Printf("Warning: Failed to stop compose services during cleanup: %v\n", downErr)
+				}
+			}
+			
+			if cleanup.workspaceVolumeCreated {
+				if removeErr := m.containerMgr.GetRuntime().RemoveVolume(ctx, env.WorkspaceVolume); removeErr != nil {
+					fmt.Printf("Warning: Failed to remove workspace volume during cleanup: %v\n", removeErr)
+				}
+			}
+			
 			if cleanup.volumeCreated {
 				if removeErr := m.containerMgr.GetRuntime().RemoveVolume(ctx, env.VolumeName); removeErr != nil {
 					fmt.Printf("Warning: Failed to remove volume during cleanup: %v\n", removeErr)
@@ -147,6 +417,12 @@ func (m *Manager) CreateEnvironment(ctx context.Context, opts CreateEnvironmentO
 				}
 			}
 			
+			if cleanup.templateWritten {
+				if removeErr := removeTemplateContainerfile(envName); removeErr != nil {
+					fmt.Printf("Warning: %v\n", removeErr)
+				}
+			}
+			
 			if cleanup.worktreeCreated {
 				if removeErr := m.gitOps.RemoveWorktree(ctx, worktreePath); removeErr != nil {
 					fmt.Printf("Warning: Failed to remove worktree during cleanup: %v\n", removeErr)
@@ -168,133 +444,155 @@ func (m *Manager) CreateEnvironment(ctx context.Context, opts CreateEnvironmentO
 		}
 	}()
 	
-	// Step 1: Handle branch creation/validation
-	if opts.IsRemoteBranch {
-		// Fetch remote updates first
-		if err := m.gitOps.FetchRemote(ctx, opts.RemoteName); err != nil {
-			return nil, fmt.Errorf("failed to fetch remote %s: %w", opts.RemoteName, err)
-		}
-		
-		// Check if remote branch exists
-		exists, err := m.gitOps.RemoteBranchExists(ctx, opts.RemoteName, opts.BranchName)
+	steps := &stepTimer{}
+	
+	// Steps 1-2: Handle branch creation/validation and create the git worktree
+	if !opts.Here && opts.WorktreePath == "" {
+		steps.step(ctx, 0.05, StepWorktree)
+		branchCreated, err := m.prepareWorktree(ctx, opts, worktreePath)
+		cleanup.branchCreated = branchCreated
 		if err != nil {
-			return nil, fmt.Errorf("failed to check remote branch: %w", err)
-		}
-		if !exists {
-			return nil, fmt.Errorf("remote branch %s/%s does not exist", opts.RemoteName, opts.BranchName)
+			return nil, err
 		}
-	} else {
-		// Check if local branch exists
-		exists, err := m.gitOps.BranchExists(ctx, opts.BranchName)
+		cleanup.worktreeCreated = true
+	}
+	
+	if tmpl != nil {
+		path, err := writeTemplateContainerfile(envName, tmpl)
 		if err != nil {
-			return nil, fmt.Errorf("failed to check local branch: %w", err)
-		}
-		if !exists {
-			// Create new branch
-			if err := m.gitOps.CreateBranch(ctx, opts.BranchName); err != nil {
-				return nil, fmt.Errorf("failed to create branch: %w", err)
-			}
-			cleanup.branchCreated = true
+			return nil, err
 		}
+		env.Containerfile = path
+		cleanup.templateWritten = true
 	}
-	
-	// Step 2: Create git worktree
-	var remoteBranch string
-	if opts.IsRemoteBranch {
-		remoteBranch = fmt.Sprintf("%s/%s", opts.RemoteName, opts.BranchName)
+	if opts.Definition != nil && opts.Image == "" {
+		pinned, err := m.pinContainerfile(env, opts.Definition)
+		if err != nil {
+			return nil, err
+		}
+		cleanup.templateWritten = pinned
 	}
 	
-	if err := m.gitOps.CreateWorktree(ctx, worktreePath, opts.BranchName, remoteBranch); err != nil {
-		return nil, fmt.Errorf("failed to create worktree: %w", err)
+	// Steps 3-4: Check for the containerfile and build the image with user sync
+	steps.step(ctx, 0.15, StepBuild)
+	if opts.Image != "" {
+		if err := m.pullSourceImage(ctx, envName, opts.Image); err != nil {
+			return nil, err
+		}
+	} else if tmpl == nil && m.claimWarmImage(ctx, env) {
+		logging.Logger().Info("claimed warm pool image", "environment", envName)
+	} else {
+		buildStarted := time.Now()
+		err = m.buildImage(ctx, env)
+		buildTime = time.Since(buildStarted)
+		if err != nil {
+			return nil, err
+		}
+		m.recordBaseImageDigests(ctx, env)
 	}
-	cleanup.worktreeCreated = true
+	cleanup.imageBuilt = true
+	cleanup.imageName = imageTag(envName)
 	
-	// Step 3: Check for containerfile
-	containerfilePath := filepath.Join(worktreePath, opts.Containerfile)
-	if _, err := os.Stat(containerfilePath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("containerfile not found: %s", containerfilePath)
+	// Step 5: Create named volume
+	steps.step(ctx, 0.6, StepVolumes)
+	if err := m.containerMgr.GetRuntime().CreateVolume(ctx, env.VolumeName, m.resourceLabels(envName)); err != nil {
+		return nil, fmt.Errorf("failed to create volume: %w", err)
 	}
+	cleanup.volumeCreated = true
 	
-	// Step 4: Build container image with user sync
-	imageTag := fmt.Sprintf("cc-buddy-%s:latest", envName)
-	
-	// Get host user information for user ID synchronization
-	userInfo := system.GetUserInfoWithFallback()
-	
-	buildOpts := container.BuildOptions{
-		Context:    worktreePath,
-		Dockerfile: opts.Containerfile,
-		Tags:       []string{imageTag},
-		BuildArgs: map[string]string{
-			"USER_UID": strconv.Itoa(userInfo.UID),
-			"USER_GID": strconv.Itoa(userInfo.GID),
-		},
+	if workspaceMode == WorkspaceModeSync {
+		env.WorkspaceVolume = fmt.Sprintf("cc-buddy-%s-workspace", envName)
+		if err := m.containerMgr.GetRuntime().CreateVolume(ctx, env.WorkspaceVolume, m.resourceLabels(envName)); err != nil {
+			return nil, fmt.Errorf("failed to create workspace volume: %w", err)
+		}
+		cleanup.workspaceVolumeCreated = true
 	}
 	
-	if err := m.containerMgr.GetRuntime().Build(ctx, buildOpts); err != nil {
-		return nil, fmt.Errorf("failed to build container image: %w", err)
+	if env.NestedContainers == NestedDind {
+		if err := m.containerMgr.GetRuntime().CreateVolume(ctx, dindContainerName(envName), m.resourceLabels(envName)); err != nil {
+			return nil, fmt.Errorf("failed to create docker-in-docker volume: %w", err)
+		}
+		cleanup.dindVolumeCreated = true
+	} else if env.NestedContainers == NestedSocket {
+		fmt.Printf("Warning: nested_containers: socket gives %s full control of the host's %s, including every other container\n", envName, m.containerMgr.GetRuntime().Name())
 	}
-	cleanup.imageBuilt = true
-	cleanup.imageName = imageTag
 	
-	// Step 5: Create named volume
-	if err := m.containerMgr.GetRuntime().CreateVolume(ctx, env.VolumeName); err != nil {
-		return nil, fmt.Errorf("failed to create volume: %w", err)
+	// Start the backing services first; the container joins their network
+	if composeFile := m.configMgr.GetConfig().ComposeFile; composeFile != "" {
+		composePath := filepath.Join(worktreePath, composeFile)
+		if _, err := os.Stat(composePath); err != nil {
+			return nil, fmt.Errorf("compose file not found: %s", composePath)
+		}
+		env.ComposeProject = composeProjectName(env.Name)
+		env.ComposeFile = composeFile
+		steps.step(ctx, 0.7, StepServices)
+		if err := m.composeUp(ctx, *env, composePath, opts.NoStart); err != nil {
+			return nil, err
+		}
+		cleanup.composeStarted = true
 	}
-	cleanup.volumeCreated = true
 	
 	// Step 6: Start container
-	mounts := []container.Mount{
-		{
-			Type:   "bind",
-			Source: worktreePath,
-			Target: "/workspace",
-			Options: []string{"Z"}, // SELinux relabel for exclusive access
-		},
-		{
-			Type:   "volume",
-			Source: env.VolumeName,
-			Target: "/data",
-		},
-	}
-	
-	envVars := map[string]string{
-		"GITHUB_TOKEN": os.Getenv("GITHUB_TOKEN"),
-	}
-	
-	// Set startup command - let entrypoint handle the default case
-	startupCommand := opts.StartupCommand
-	if len(startupCommand) == 0 {
-		// Use empty command to let Dockerfile CMD and ENTRYPOINT work together
-		startupCommand = nil
+	steps.step(ctx, 0.8, StepContainer)
+	containerID, err := m.runContainer(ctx, env, runSpec{
+		workspaceMode: workspaceMode,
+		remoteHost:    remoteHost,
+		labelOption:   labelOption,
+		consistency:   consistency,
+		extraMounts:   extraMounts,
+		cacheEnv:      cacheEnv,
+		noStart:       opts.NoStart,
+	})
+	if containerID != "" {
+		env.ContainerID = containerID
+		cleanup.containerStarted = true
 	}
-
-	runOpts := container.RunOptions{
-		Name:       env.ContainerName,
-		Image:      imageTag,
-		WorkingDir: "/workspace",
-		Detach:     true,
-		Mounts:     mounts,
-		EnvVars:    envVars,
-		Command:    startupCommand,
+	if err != nil {
+		return nil, err
+	}
+	if env.NestedContainers == NestedDind && !opts.NoStart {
+		cleanup.dindStarted = true
+		if err := m.runDind(ctx, *env); err != nil {
+			return nil, err
+		}
 	}
 	
-	// Add port mappings if requested
-	if opts.ExposeAllPorts {
-		runOpts.Ports = []container.PortMapping{
-			{Host: 0, Container: 0, Protocol: "tcp"}, // Expose all ports
+	if workspaceMode == WorkspaceModeSync {
+		env.ContainerID = containerID
+		env.WorkspaceSync = workspaceSync
+		if opts.NoStart {
+			// Syncing needs a running container; start does it
+			env.WorkspacePending = true
+		} else {
+			steps.step(ctx, 0.9, StepSync)
+			if err := m.populateWorkspace(ctx, *env); err != nil {
+				return nil, err
+			}
 		}
 	}
 	
-	containerID, err := m.containerMgr.GetRuntime().Run(ctx, runOpts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to start container: %w", err)
+	// Run the post_create hooks once the workspace is in place
+	if hooks := m.configMgr.GetConfig().Hooks.PostCreate; len(hooks) > 0 {
+		if opts.NoStart {
+			env.HooksPending = true
+		} else {
+			steps.step(ctx, 0.95, StepHooks)
+			if err := m.runHooks(ctx, containerID, hookPostCreate, hooks); err != nil {
+				return nil, err
+			}
+		}
 	}
-	cleanup.containerStarted = true
 	
 	// Step 7: Update environment with container info and mark as running
 	env.ContainerID = containerID
 	env.Status = "running"
+	if opts.NoStart {
+		env.Status = "created"
+	}
+	
+	if err := m.writeEnvrc(ctx, *env); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
 	
 	// Add environment to state only after all resources are successfully created
 	if err := m.configMgr.AddEnvironment(*env); err != nil {
@@ -302,40 +600,169 @@ func (m *Manager) CreateEnvironment(ctx context.Context, opts CreateEnvironmentO
 	}
 	cleanup.environmentInState = true
 	
+	m.syncHostsIfEnabled()
+	m.recordEvent(audit.Event{Event: audit.EventCreated, BuildMS: buildTime.Milliseconds(), StepsMS: steps.finish()}, *env, began, nil)
+	m.register()
+	m.scanOnCreate(ctx, envName)
+	
 	return env, nil
 }
 
+// prepareWorktree creates or checks the branch and adds its worktree,
+// reporting whether it created the branch. Concurrent creates take turns
+// because git locks its refs and worktree metadata.
+func (m *Manager) prepareWorktree(ctx context.Context, opts CreateEnvironmentOptions, worktreePath string) (branchCreated bool, err error) {
+	m.gitMu.Lock()
+	defer m.gitMu.Unlock()
+	
+	if opts.Throwaway {
+		// A detached checkout works even where the branch is checked out
+		// already, and leaves no branch behind
+		ref := opts.BranchName
+		if opts.IsRemoteBranch {
+			if err := m.gitOps.FetchRemote(ctx, opts.RemoteName); err != nil {
+				return false, fmt.Errorf("failed to fetch remote %s: %w", opts.RemoteName, err)
+			}
+			ref = opts.RemoteName + "/" + opts.BranchName
+		}
+		if err := m.gitOps.CreateDetachedWorktree(ctx, worktreePath, ref); err != nil {
+			return false, fmt.Errorf("failed to create worktree: %w", err)
+		}
+		return false, nil
+	}
+	
+	if opts.IsRemoteBranch {
+		// Fetch remote updates first
+		if err := m.gitOps.FetchRemote(ctx, opts.RemoteName); err != nil {
+			return branchCreated, fmt.Errorf("failed to fetch remote %s: %w", opts.RemoteName, err)
+		}
+		
+		// Check if remote branch exists
+		exists, err := m.gitOps.RemoteBranchExists(ctx, opts.RemoteName, opts.BranchName)
+		if err != nil {
+			return branchCreated, fmt.Errorf("failed to check remote branch: %w", err)
+		}
+		if !exists {
+			return branchCreated, fmt.Errorf("remote branch %s/%s does not exist", opts.RemoteName, opts.BranchName)
+		}
+	} else {
+		// Check if local branch exists
+		exists, err := m.gitOps.BranchExists(ctx, opts.BranchName)
+		if err != nil {
+			return branchCreated, fmt.Errorf("failed to check local branch: %w", err)
+		}
+		if !exists {
+			// Create new branch
+			if err := m.gitOps.CreateBranch(ctx, opts.BranchName); err != nil {
+				return branchCreated, fmt.Errorf("failed to create branch: %w", err)
+			}
+			branchCreated = true
+		}
+	}
+	
+	// Create the worktree, tracking the remote branch if there is one
+	var remoteBranch string
+	if opts.IsRemoteBranch {
+		remoteBranch = fmt.Sprintf("%s/%s", opts.RemoteName, opts.BranchName)
+	}
+	
+	if err := m.gitOps.CreateWorktree(ctx, worktreePath, opts.BranchName, remoteBranch); err != nil {
+		return branchCreated, fmt.Errorf("failed to create worktree: %w", err)
+	}
+	return branchCreated, nil
+}
+
 // ListEnvironments returns all environments with their current status
 func (m *Manager) ListEnvironments(ctx context.Context) ([]config.Environment, error) {
-	environments := m.configMgr.GetState().Environments
+	// Other cc-buddy processes (watch, the daemon, a second terminal) change
+	// the state file, so re-read it rather than trusting what was loaded
+	if err := m.configMgr.LoadState(); err != nil {
+		return nil, fmt.Errorf("failed to load state: %w", err)
+	}
+	// A copy, so the computed fields below are not saved with the state
+	environments := m.configMgr.Environments()
 	
 	// Update status for each environment
 	for i := range environments {
-		if environments[i].ContainerID != "" {
+		if !environments[i].NoContainer {
+			environments[i].Image = imageTag(environments[i].Name)
+			if imageOutdated(environments[i]) {
+				environments[i].Stale = true
+			}
+		}
+		if environments[i].ContainerID != "" && m.containerMgr.Available() {
 			status, err := m.containerMgr.GetRuntime().Status(ctx, environments[i].ContainerID)
 			if err == nil && status.Running {
 				environments[i].Status = "running"
-			} else {
+				environments[i].Health = status.Health
+				if !status.StartedAt.IsZero() {
+					environments[i].StartedAt = &status.StartedAt
+				}
+			} else if environments[i].Status != "created" {
+				// "created" environments have never been started
 				environments[i].Status = "stopped"
+				environments[i].Health = ""
 			}
 		}
 	}
 	
+	// Background creates show as "creating" until they are added to the state
+	for _, pending := range pendingCreates() {
+		if _, err := m.configMgr.GetEnvironment(pending.Environment); err == nil {
+			continue
+		}
+		environments = append(environments, config.Environment{
+			Name:    pending.Environment,
+			Branch:  pending.Branch,
+			Status:  "creating",
+			Created: pending.Started,
+		})
+	}
+	
 	return environments, nil
 }
 
-// DeleteEnvironment removes an environment and cleans up all resources
-func (m *Manager) DeleteEnvironment(ctx context.Context, envName string) error {
-	_, err := m.configMgr.GetEnvironment(envName)
+// DeleteEnvironment removes an environment and cleans up all resources.
+// Protected environments are only deleted with force.
+func (m *Manager) DeleteEnvironment(ctx context.Context, envName string, force bool) error {
+	env, err := m.configMgr.GetEnvironment(envName)
 	if err != nil {
 		return fmt.Errorf("environment not found: %w", err)
 	}
+	if env.Protected && !force {
+		return protectedError(envName)
+	}
 	
-	return m.CleanupEnvironment(ctx, envName)
+	began := time.Now()
+	m.runPreDeleteHooks(ctx, env.ContainerID)
+	err = m.CleanupEnvironment(ctx, envName)
+	m.recordEvent(audit.Event{Event: audit.EventDeleted}, env, began, err)
+	return err
+}
+
+// DiscardEnvironment deletes a throwaway environment, even when protected,
+// including whatever was left in its worktree, such as build output
+func (m *Manager) DiscardEnvironment(ctx context.Context, envName string) error {
+	env, err := m.configMgr.GetEnvironment(envName)
+	if err != nil {
+		return fmt.Errorf("environment not found: %w", err)
+	}
+
+	began := time.Now()
+	m.runPreDeleteHooks(ctx, env.ContainerID)
+	err = m.cleanupEnvironment(ctx, envName, true)
+	m.recordEvent(audit.Event{Event: audit.EventDeleted}, env, began, err)
+	return err
 }
 
 // CleanupEnvironment performs cleanup of environment resources
 func (m *Manager) CleanupEnvironment(ctx context.Context, envName string) error {
+	return m.cleanupEnvironment(ctx, envName, false)
+}
+
+// cleanupEnvironment removes an environment's resources; with
+// discardWorktree the worktree goes even with untracked or modified files
+func (m *Manager) cleanupEnvironment(ctx context.Context, envName string, discardWorktree bool) error {
 	env, err := m.configMgr.GetEnvironment(envName)
 	if err != nil {
 		// Environment not in state, but try to clean up anyway
@@ -344,6 +771,25 @@ func (m *Manager) CleanupEnvironment(ctx context.Context, envName string) error
 	
 	var cleanupErrors []error
 	
+	if m.containerMgr.Available() {
+		if err := m.removeDind(ctx, env, true); err != nil {
+			cleanupErrors = append(cleanupErrors, err)
+		}
+	}
+	
+	if env.WorkspaceSync == WorkspaceSyncMutagen {
+		if err := stopMutagenSync(ctx, envName); err != nil {
+			cleanupErrors = append(cleanupErrors, fmt.Errorf("failed to stop mutagen sync: %w", err))
+		}
+	}
+	
+	if !m.containerMgr.Available() && !env.NoContainer {
+		// Worktree-only mode: the container, image and volumes stay behind
+		fmt.Printf("Warning: no container runtime found; %s's container, image and volumes were left behind. Remove them with 'cc-buddy gc' once a runtime is available.\n", envName)
+		env.ContainerID, env.ContainerName, env.ComposeProject = "", "", ""
+		env.VolumeName, env.WorkspaceVolume = "", ""
+	}
+	
 	// Stop and remove container
 	if env.ContainerID != "" {
 		if err := m.containerMgr.GetRuntime().Stop(ctx, env.ContainerID); err != nil {
@@ -363,11 +809,26 @@ func (m *Manager) CleanupEnvironment(ctx context.Context, envName string) error
 		}
 	}
 	
+	// Stop the backing services after the container has left their network
+	if env.ComposeProject != "" {
+		composePath := ""
+		if env.WorktreePath != "" && env.ComposeFile != "" {
+			path := filepath.Join(env.WorktreePath, env.ComposeFile)
+			if _, err := os.Stat(path); err == nil {
+				composePath = path
+			}
+		}
+		if err := m.composeDown(ctx, env, composePath); err != nil {
+			cleanupErrors = append(cleanupErrors, err)
+		}
+	}
+	
 	// Remove container image
-	imageTag := fmt.Sprintf("cc-buddy-%s:latest", envName)
-	if err := m.containerMgr.GetRuntime().RemoveImage(ctx, imageTag); err != nil {
-		// Image removal might fail if other containers are using it, that's okay
-		// Don't add to cleanupErrors as this is not critical
+	if m.containerMgr.Available() {
+		if err := m.containerMgr.GetRuntime().RemoveImage(ctx, imageTag(envName)); err != nil {
+			// Image removal might fail if other containers are using it, that's okay
+			// Don't add to cleanupErrors as this is not critical
+		}
 	}
 	
 	// Remove volume
@@ -377,18 +838,34 @@ func (m *Manager) CleanupEnvironment(ctx context.Context, envName string) error
 		}
 	}
 	
-	// Remove worktree
-	if env.WorktreePath != "" {
-		if err := m.gitOps.RemoveWorktree(ctx, env.WorktreePath); err != nil {
+	if env.WorkspaceVolume != "" {
+		if err := m.containerMgr.GetRuntime().RemoveVolume(ctx, env.WorkspaceVolume); err != nil {
+			cleanupErrors = append(cleanupErrors, fmt.Errorf("failed to remove workspace volume: %w", err))
+		}
+	}
+	
+	// Remove worktree; an in-place environment's checkout is the user's own
+	if env.WorktreePath != "" && !env.InPlace {
+		if discardWorktree {
+			if err := m.gitOps.ForceRemoveWorktree(ctx, env.WorktreePath); err != nil {
+				cleanupErrors = append(cleanupErrors, err)
+			}
+		} else if err := m.gitOps.RemoveWorktree(ctx, env.WorktreePath); err != nil {
 			cleanupErrors = append(cleanupErrors, fmt.Errorf("failed to remove worktree: %w", err))
 		}
 	}
 	
+	if err := removeTemplateContainerfile(envName); err != nil {
+		cleanupErrors = append(cleanupErrors, err)
+	}
+	
 	// Remove from state
 	if err := m.configMgr.RemoveEnvironment(envName); err != nil {
 		cleanupErrors = append(cleanupErrors, fmt.Errorf("failed to remove from state: %w", err))
 	}
 	
+	m.syncHostsIfEnabled()
+	
 	if len(cleanupErrors) > 0 {
 		return fmt.Errorf("cleanup errors: %v", cleanupErrors)
 	}
@@ -397,7 +874,7 @@ func (m *Manager) CleanupEnvironment(ctx context.Context, envName string) error
 }
 
 // OpenTerminal opens a terminal session in the environment's container
-func (m *Manager) OpenTerminal(ctx context.Context, envName string) error {
+func (m *Manager) OpenTerminal(ctx context.Context, envName string, opts TerminalOptions) error {
 	env, err := m.configMgr.GetEnvironment(envName)
 	if err != nil {
 		return fmt.Errorf("environment not found: %w", err)
@@ -417,37 +894,104 @@ func (m *Manager) OpenTerminal(ctx context.Context, envName string) error {
 		return fmt.Errorf("container for environment %s is not running", envName)
 	}
 	
+	m.markUsed(envName)
+	
 	// Open terminal
-	return m.containerMgr.GetRuntime().Exec(ctx, env.ContainerID, []string{"/bin/bash"})
+	return m.containerMgr.GetRuntime().Exec(ctx, env.ContainerID, m.terminalCommand(ctx, env.ContainerID, opts))
 }
 
 // ExecuteCommand executes a command in the environment's container
 func (m *Manager) ExecuteCommand(ctx context.Context, envName string, command []string, interactive bool) error {
+	containerID, err := m.runningContainer(ctx, envName)
+	if err != nil {
+		return err
+	}
+	
+	// Execute command with runtime-specific implementation
+	if interactive {
+		return m.containerMgr.GetRuntime().Exec(ctx, containerID, command)
+	} else {
+		return m.containerMgr.GetRuntime().ExecNonInteractive(ctx, containerID, command)
+	}
+}
+
+// CaptureCommand runs a command in the environment's container without a
+// TTY and returns its output and exit code
+func (m *Manager) CaptureCommand(ctx context.Context, envName string, command []string) (container.ExecResult, error) {
+	containerID, err := m.runningContainer(ctx, envName)
+	if err != nil {
+		return container.ExecResult{}, err
+	}
+	
+	return m.containerMgr.GetRuntime().ExecCapture(ctx, containerID, command)
+}
+
+// AttachCommand runs a command in the environment's container without a
+// TTY, connected to cc-buddy's stdin, stdout and stderr
+func (m *Manager) AttachCommand(ctx context.Context, envName string, command []string) error {
+	containerID, err := m.runningContainer(ctx, envName)
+	if err != nil {
+		return err
+	}
+	
+	return m.containerMgr.GetRuntime().ExecAttached(ctx, containerID, command)
+}
+
+// StreamCommand runs a command in the environment's container without a
+// TTY or stdin, writing its output to stdout and stderr as it arrives, and
+// returns its exit code
+func (m *Manager) StreamCommand(ctx context.Context, envName string, command []string, stdout, stderr io.Writer) (int, error) {
+	containerID, err := m.runningContainer(ctx, envName)
+	if err != nil {
+		return 0, err
+	}
+	
+	return m.containerMgr.GetRuntime().ExecStream(ctx, containerID, command, stdout, stderr)
+}
+
+// Logs returns the output of the environment's container, running or not
+func (m *Manager) Logs(ctx context.Context, envName string) ([]string, error) {
 	env, err := m.configMgr.GetEnvironment(envName)
 	if err != nil {
-		return fmt.Errorf("environment not found: %w", err)
+		return nil, fmt.Errorf("environment not found: %w", err)
+	}
+	if env.ContainerID == "" {
+		return nil, fmt.Errorf("environment %s has no container", envName)
+	}
+	return m.containerMgr.GetRuntime().Logs(ctx, env.ContainerID, false)
+}
+
+// runningContainer returns the ID of the environment's container after
+// checking that it is running, and marks the environment used
+func (m *Manager) runningContainer(ctx context.Context, envName string) (string, error) {
+	env, err := m.configMgr.GetEnvironment(envName)
+	if err != nil {
+		return "", fmt.Errorf("environment not found: %w", err)
 	}
 	
 	if env.ContainerID == "" {
-		return fmt.Errorf("environment %s has no running container", envName)
+		return "", fmt.Errorf("environment %s has no running container", envName)
 	}
 	
 	// Check container status
 	status, err := m.containerMgr.GetRuntime().Status(ctx, env.ContainerID)
 	if err != nil {
-		return fmt.Errorf("failed to check container status: %w", err)
+		return "", fmt.Errorf("failed to check container status: %w", err)
 	}
 	
 	if !status.Running {
-		return fmt.Errorf("container for environment %s is not running", envName)
+		return "", fmt.Errorf("container for environment %s is not running", envName)
 	}
 	
-	// Execute command with runtime-specific implementation
-	if interactive {
-		return m.containerMgr.GetRuntime().Exec(ctx, env.ContainerID, command)
-	} else {
-		return m.containerMgr.GetRuntime().ExecNonInteractive(ctx, env.ContainerID, command)
-	}
+	m.markUsed(envName)
+	return env.ContainerID, nil
+}
+
+// WorktreeOnly reports whether no container runtime was found, so only
+// worktrees and state can be managed: environments list and delete, but
+// cannot be created or entered
+func (m *Manager) WorktreeOnly() bool {
+	return !m.containerMgr.Available()
 }
 
 // GetConfig returns the configuration manager
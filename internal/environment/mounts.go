@@ -0,0 +1,278 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	goruntime "runtime"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/container"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// defaultContainerUser is the non-root user in generated images
+const defaultContainerUser = "developer"
+
+// containerUser returns the configured non-root container user
+func (m *Manager) containerUser() string {
+	if user := m.configMgr.GetConfig().ContainerUser; user != "" {
+		return user
+	}
+	return defaultContainerUser
+}
+
+// containerUserPattern matches portable POSIX user names
+var containerUserPattern = regexp.MustCompile(`^[a-z_][a-z0-9_-]*$`)
+
+// validateContainerUser checks the configured container user name
+func (m *Manager) validateContainerUser() error {
+	if user := m.containerUser(); !containerUserPattern.MatchString(user) {
+		return fmt.Errorf("invalid container_user %q: use lowercase letters, digits, '_' or '-'", user)
+	}
+	return nil
+}
+
+// containerHome returns the home directory of the container user
+func (m *Manager) containerHome() string {
+	if user := m.containerUser(); user != "root" {
+		return "/home/" + user
+	}
+	return "/root"
+}
+
+// gpgPublicFiles are the ~/.gnupg files shared with the container. Private keys
+// never leave the host; signing happens through the forwarded agent socket.
+var gpgPublicFiles = []string{
+	"pubring.kbx",
+	"pubring.gpg",
+	"trustdb.gpg",
+	"gpg.conf",
+	"common.conf",
+}
+
+// reservedTargets are container paths managed by cc-buddy itself
+var reservedTargets = map[string]bool{
+	"/workspace": true,
+	"/data":      true,
+}
+
+// resolveExtraMounts parses and validates user-defined mount specifications,
+// expanding host paths. It returns the mounts along with their normalized
+// specifications for recording on the environment. Options are checked
+// against the runtime named runtimeName.
+func resolveExtraMounts(specs []string, runtimeName string) ([]container.Mount, []string, error) {
+	var mounts []container.Mount
+	var normalized []string
+	seenTargets := make(map[string]bool)
+
+	for _, spec := range specs {
+		mount, err := container.ParseMount(spec)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := container.ValidateMountOptions(runtimeName, mount); err != nil {
+			return nil, nil, err
+		}
+
+		if reservedTargets[mount.Target] {
+			return nil, nil, fmt.Errorf("invalid mount %q: %s is managed by cc-buddy", spec, mount.Target)
+		}
+		if seenTargets[mount.Target] {
+			return nil, nil, fmt.Errorf("invalid mount %q: %s is mounted more than once", spec, mount.Target)
+		}
+		seenTargets[mount.Target] = true
+
+		if mount.Type == "bind" {
+			source, err := expandHostPath(mount.Source)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid mount %q: %w", spec, err)
+			}
+			if _, err := os.Stat(source); err != nil {
+				return nil, nil, fmt.Errorf("invalid mount %q: source %s does not exist", spec, source)
+			}
+			mount.Source = source
+		}
+
+		mounts = append(mounts, mount)
+		normalized = append(normalized, mount.String())
+	}
+
+	return mounts, normalized, nil
+}
+
+// expandHostPath expands a leading ~ and makes the path absolute
+func expandHostPath(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to determine home directory: %w", err)
+		}
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	return filepath.Abs(path)
+}
+
+// hostIntegration returns the mounts and environment variables that share
+// host tooling (git identity, GPG agent) with the container, based on configuration
+func (m *Manager) hostIntegration(ctx context.Context) ([]container.Mount, map[string]string) {
+	cfg := m.configMgr.GetConfig()
+	var mounts []container.Mount
+	envVars := make(map[string]string)
+
+	if cfg.ShareGitConfig {
+		mounts = append(mounts, gitConfigMounts(m.containerHome(), cfg.ShareGitCredentials)...)
+
+		// Identity env vars win over any gitconfig baked into the image and
+		// pick up repository-level overrides of user.name/user.email
+		for key, vars := range gitIdentityVars {
+			value, err := m.gitOps.GetConfigValue(ctx, key)
+			if err != nil || value == "" {
+				continue
+			}
+			for _, name := range vars {
+				envVars[name] = value
+			}
+		}
+	}
+
+	if cfg.ForwardGPG && goruntime.GOOS == "windows" {
+		// Gpg4win's agent socket is not a Unix socket and cannot be bind mounted
+		fmt.Printf("Warning: GPG forwarding is not supported on Windows hosts\n")
+	} else if cfg.ForwardGPG {
+		gpgMounts, err := gpgAgentMounts(ctx, m.containerHome())
+		if err != nil {
+			fmt.Printf("Warning: GPG forwarding disabled: %v\n", err)
+		} else {
+			mounts = append(mounts, gpgMounts...)
+		}
+	}
+
+	return mounts, envVars
+}
+
+// gitIdentityVars maps git config keys to the environment variables git reads
+var gitIdentityVars = map[string][]string{
+	"user.name":  {"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"},
+	"user.email": {"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"},
+}
+
+// gitConfigMounts shares the host's global gitconfig (and optionally the
+// plaintext credential store) read-only with the container user, whose home
+// is containerHome
+func gitConfigMounts(containerHome string, includeCredentials bool) []container.Mount {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	var mounts []container.Mount
+
+	gitconfig := filepath.Join(home, ".gitconfig")
+	if _, err := os.Stat(gitconfig); err == nil {
+		mounts = append(mounts, container.Mount{
+			Type:    "bind",
+			Source:  gitconfig,
+			Target:  containerHome + "/.gitconfig",
+			Options: []string{"ro"},
+		})
+	}
+
+	// XDG location is read by git in addition to ~/.gitconfig
+	xdgConfig := os.Getenv("XDG_CONFIG_HOME")
+	if xdgConfig == "" {
+		xdgConfig = filepath.Join(home, ".config")
+	}
+	xdgGitconfig := filepath.Join(xdgConfig, "git", "config")
+	if _, err := os.Stat(xdgGitconfig); err == nil {
+		mounts = append(mounts, container.Mount{
+			Type:    "bind",
+			Source:  xdgGitconfig,
+			Target:  containerHome + "/.config/git/config",
+			Options: []string{"ro"},
+		})
+	}
+
+	if includeCredentials {
+		credentials := filepath.Join(home, ".git-credentials")
+		if _, err := os.Stat(credentials); err == nil {
+			mounts = append(mounts, container.Mount{
+				Type:    "bind",
+				Source:  credentials,
+				Target:  containerHome + "/.git-credentials",
+				Options: []string{"ro"},
+			})
+		}
+	}
+
+	return mounts
+}
+
+// gpgAgentMounts forwards the host gpg-agent socket and public keyring into
+// the ~/.gnupg of the container user, whose home is containerHome
+func gpgAgentMounts(ctx context.Context, containerHome string) ([]container.Mount, error) {
+	socket, err := gpgAgentSocket(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gnupgDir := os.Getenv("GNUPGHOME")
+	if gnupgDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine home directory: %w", err)
+		}
+		gnupgDir = filepath.Join(home, ".gnupg")
+	}
+
+	containerGnupg := containerHome + "/.gnupg"
+	mounts := []container.Mount{
+		{
+			Type:   "bind",
+			Source: socket,
+			Target: containerGnupg + "/S.gpg-agent",
+		},
+	}
+
+	for _, name := range gpgPublicFiles {
+		source := filepath.Join(gnupgDir, name)
+		if _, err := os.Stat(source); err != nil {
+			continue
+		}
+		mounts = append(mounts, container.Mount{
+			Type:    "bind",
+			Source:  source,
+			Target:  containerGnupg + "/" + name,
+			Options: []string{"ro"},
+		})
+	}
+
+	return mounts, nil
+}
+
+// gpgAgentSocket locates the host gpg-agent socket intended for forwarding
+func gpgAgentSocket(ctx context.Context) (string, error) {
+	if _, err := exec.LookPath("gpgconf"); err != nil {
+		return "", fmt.Errorf("gpgconf not found in PATH")
+	}
+
+	// The "extra" socket is gpg-agent's restricted socket for remote use
+	for _, dir := range []string{"agent-extra-socket", "agent-socket"} {
+		out, err := logging.Output(exec.CommandContext(ctx, "gpgconf", "--list-dirs", dir))
+		if err != nil {
+			continue
+		}
+		socket := strings.TrimSpace(string(out))
+		if socket == "" {
+			continue
+		}
+		if _, err := os.Stat(socket); err == nil {
+			return socket, nil
+		}
+	}
+
+	return "", fmt.Errorf("no running gpg-agent socket found (start one with 'gpgconf --launch gpg-agent')")
+}
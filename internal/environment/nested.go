@@ -0,0 +1,120 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/container"
+)
+
+// Ways an environment can run containers of its own (nested_containers)
+const (
+	NestedSocket = "socket" // mount the host runtime's API socket
+	NestedDind   = "dind"   // run a Docker-in-Docker sidecar
+)
+
+// DindImage is the image of Docker-in-Docker sidecars
+const DindImage = "docker.io/library/docker:dind"
+
+// dindLabels returns the labels of envName's sidecar, which is not an
+// environment of its own
+func (m *Manager) dindLabels(envName string) map[string]string {
+	labels := m.resourceLabels(envName)
+	labels[LabelRole] = RoleDind
+	return labels
+}
+
+// dindContainerName names envName's Docker-in-Docker sidecar; its image
+// store volume has the same name
+func dindContainerName(envName string) string {
+	return fmt.Sprintf("cc-buddy-%s-dind", envName)
+}
+
+// validateNestedContainers checks the nested_containers mode against the runtime
+func (m *Manager) validateNestedContainers(mode, remoteHost string) error {
+	switch mode {
+	case "":
+		return nil
+	case NestedSocket, NestedDind:
+	default:
+		return fmt.Errorf("invalid nested_containers %q (expected socket or dind)", mode)
+	}
+	if name := m.containerMgr.GetRuntime().Name(); name != "docker" && name != "podman" {
+		return fmt.Errorf("nested_containers needs docker or podman, not %s", name)
+	}
+	if mode == NestedSocket && remoteHost != "" {
+		return fmt.Errorf("nested_containers: socket is unavailable with remote runtime host %s; use dind", remoteHost)
+	}
+	return nil
+}
+
+// nestedRunOptions adds what env's nested_containers mode needs to its
+// container: the host socket, or the address of the sidecar, which shares
+// the container's network namespace
+func (m *Manager) nestedRunOptions(ctx context.Context, env config.Environment, runOpts *container.RunOptions) error {
+	switch env.NestedContainers {
+	case NestedSocket:
+		socket, err := m.containerMgr.GetRuntime().SocketPath(ctx)
+		if err != nil {
+			return err
+		}
+		runOpts.Mounts = append(runOpts.Mounts, container.Mount{Type: "bind", Source: socket, Target: "/var/run/docker.sock"})
+		runOpts.EnvVars["DOCKER_HOST"] = "unix:///var/run/docker.sock"
+		// The API socket cannot be relabeled
+		runOpts.SecurityOpts = append(runOpts.SecurityOpts, "label=disable")
+	case NestedDind:
+		runOpts.EnvVars["DOCKER_HOST"] = "tcp://localhost:2375"
+	}
+	return nil
+}
+
+// runDind (re)starts env's Docker-in-Docker sidecar in the network
+// namespace of its running container. The sidecar is replaced each time,
+// since it has to follow the container through restarts and rebuilds; its
+// images and containers live on in the volume.
+func (m *Manager) runDind(ctx context.Context, env config.Environment) error {
+	if env.NestedContainers != NestedDind {
+		return nil
+	}
+	runtime := m.containerMgr.GetRuntime()
+	name := dindContainerName(env.Name)
+	// Replace any previous sidecar; there may be none
+	_ = runtime.Remove(ctx, name)
+
+	_, err := runtime.Run(ctx, container.RunOptions{
+		Name:       name,
+		Image:      DindImage,
+		Detach:     true,
+		Privileged: true,
+		Network:    "container:" + env.ContainerID,
+		Mounts: []container.Mount{
+			{Type: "volume", Source: name, Target: "/var/lib/docker"},
+		},
+		// Without certificates dockerd listens on plain tcp://localhost:2375
+		EnvVars: map[string]string{"DOCKER_TLS_CERTDIR": ""},
+		Labels:  m.dindLabels(env.Name),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start docker-in-docker sidecar: %w", err)
+	}
+	return nil
+}
+
+// removeDind removes env's sidecar, and with its volume everything built
+// or pulled inside the environment
+func (m *Manager) removeDind(ctx context.Context, env config.Environment, volume bool) error {
+	if env.NestedContainers != NestedDind {
+		return nil
+	}
+	runtime := m.containerMgr.GetRuntime()
+	name := dindContainerName(env.Name)
+	// Not running while the environment is stopped
+	_ = runtime.Remove(ctx, name)
+	if volume {
+		if err := runtime.RemoveVolume(ctx, name); err != nil {
+			return fmt.Errorf("failed to remove docker-in-docker volume: %w", err)
+		}
+	}
+	return nil
+}
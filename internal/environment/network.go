@@ -0,0 +1,44 @@
+package environment
+
+import "fmt"
+
+const (
+	// NetworkShared attaches the container to the common cc-buddy network
+	NetworkShared = "shared"
+
+	// NetworkHost shares the host's network stack: localhost is the host's,
+	// and ports are reachable without publishing them
+	NetworkHost = "host"
+
+	// SharedNetworkName is the runtime network used by NetworkShared. It is
+	// created on demand and left in place when environments are deleted.
+	SharedNetworkName = "cc-buddy"
+)
+
+// validateNetworkMode checks a network mode from flags or configuration.
+// The empty mode keeps the runtime's default per-container network.
+func validateNetworkMode(mode string) error {
+	switch mode {
+	case "", NetworkShared, NetworkHost:
+		return nil
+	default:
+		return fmt.Errorf("unsupported network mode %q (supported: %s, %s)", mode, NetworkShared, NetworkHost)
+	}
+}
+
+// validateHostNetwork checks that nothing else a create asks for needs the
+// container's own network, which host networking does away with
+func (m *Manager) validateHostNetwork(opts CreateEnvironmentOptions) error {
+	cfg := m.configMgr.GetConfig()
+	switch {
+	case len(opts.Ports) > 0 || opts.ExposeAllPorts:
+		return fmt.Errorf("--network host publishes nothing; every port the container listens on is already on the host, so drop --port and --expose-all")
+	case cfg.Proxy:
+		return fmt.Errorf("--network host cannot be used with the reverse proxy, which reaches environments over the shared network")
+	case cfg.ComposeFile != "":
+		return fmt.Errorf("--network host cannot be used with backing services, which the container reaches over their compose network")
+	case cfg.NestedContainers == NestedDind:
+		return fmt.Errorf("--network host cannot be used with nested_containers: dind, whose daemon would listen on the host")
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package environment
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+)
+
+// SetNote records what an environment is for; an empty note clears it
+func (m *Manager) SetNote(envName, note string) error {
+	if _, err := m.configMgr.GetEnvironment(envName); err != nil {
+		return fmt.Errorf("environment not found: %w", err)
+	}
+	note = strings.TrimSpace(note)
+	if strings.ContainsAny(note, "\n\r") {
+		return fmt.Errorf("notes must fit on one line")
+	}
+	return m.configMgr.UpdateEnvironment(envName, func(env *config.Environment) {
+		env.Note = note
+	})
+}
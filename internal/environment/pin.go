@@ -0,0 +1,102 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// recordBaseImageDigests pins env's base images to the digests its first
+// build resolved them to, when pin_base_images is set. Images without a
+// registry digest (built locally) are not pinned.
+func (m *Manager) recordBaseImageDigests(ctx context.Context, env *config.Environment) {
+	if !m.configMgr.GetConfig().PinBaseImages || len(env.BaseImageDigests) > 0 || env.SourceImage != "" {
+		return
+	}
+	bases, err := baseImages(containerfilePath(*env))
+	if err != nil {
+		return
+	}
+	digests := map[string]string{}
+	for _, image := range bases {
+		digest, err := m.containerMgr.GetRuntime().ImageDigest(ctx, image)
+		if err != nil || digest == "" {
+			logging.Logger().Warn("cannot pin base image", "environment", env.Name, "image", image)
+			continue
+		}
+		digests[image] = digest
+	}
+	if len(digests) > 0 {
+		env.BaseImageDigests = digests
+	}
+}
+
+// buildPinnedImage builds env's image like buildImage, but from its base
+// images' pinned digests, so rebuilds weeks later start from the same layers
+func (m *Manager) buildPinnedImage(ctx context.Context, env *config.Environment) error {
+	if len(env.BaseImageDigests) == 0 {
+		return m.buildImage(ctx, env)
+	}
+	path := containerfilePath(*env)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("containerfile not found: %s", path)
+	}
+	hash, err := fileHash(path)
+	if err != nil {
+		return fmt.Errorf("failed to read containerfile: %w", err)
+	}
+	m.warnMovedBaseImages(ctx, *env)
+
+	pinnedPath, err := writeContainerfile(env.Name+".pinned", pinBaseImages(data, env.BaseImageDigests))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(pinnedPath)
+	pinned := *env
+	pinned.Containerfile = pinnedPath
+	if err := m.buildImage(ctx, &pinned); err != nil {
+		return err
+	}
+	// Staleness is judged against the worktree's Containerfile, not the copy
+	env.ContainerfileHash = hash
+	return nil
+}
+
+// MovedBaseImages pulls env's pinned base images by tag and returns those
+// whose tag now resolves to a different digest upstream, mapped to the new
+// digest. Images that cannot be pulled are skipped.
+func (m *Manager) MovedBaseImages(ctx context.Context, env config.Environment) map[string]string {
+	moved := map[string]string{}
+	runtime := m.containerMgr.GetRuntime()
+	for image, pinned := range env.BaseImageDigests {
+		if err := runtime.Pull(ctx, image); err != nil {
+			logging.Logger().Warn("cannot check base image upstream", "image", image, "error", err.Error())
+			continue
+		}
+		digest, err := runtime.ImageDigest(ctx, image)
+		if err == nil && digest != "" && digest != pinned {
+			moved[image] = digest
+		}
+	}
+	return moved
+}
+
+// warnMovedBaseImages prints a warning for each pinned base image of env
+// that has moved upstream
+func (m *Manager) warnMovedBaseImages(ctx context.Context, env config.Environment) {
+	for image := range m.MovedBaseImages(ctx, env) {
+		fmt.Printf("Warning: %s has moved upstream since %s pinned it; building from the pinned digest. Run 'cc-buddy rebuild --repin %s' to update.\n", image, env.Name, env.Name)
+	}
+}
+
+// UnpinBaseImages forgets envName's pinned digests, so its next rebuild
+// resolves the FROM tags again and pins the result
+func (m *Manager) UnpinBaseImages(envName string) error {
+	return m.configMgr.UpdateEnvironment(envName, func(env *config.Environment) {
+		env.BaseImageDigests = nil
+	})
+}
@@ -0,0 +1,127 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/container"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// poolSlotName names the i-th (from 1) warm pool image of the repository.
+// Its image is tagged like an environment's, so a slot is full while that
+// tag exists.
+func (m *Manager) poolSlotName(i int) (string, error) {
+	repoName, err := m.gitOps.GetRepoName()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s_pool%d", repoName, i), nil
+}
+
+// poolContainerfileHash hashes the repository's configured Containerfile,
+// which warm pool images are built from
+func (m *Manager) poolContainerfileHash() (string, error) {
+	path := containerfilePath(config.Environment{
+		WorktreePath:  m.gitOps.repoRoot,
+		Containerfile: m.configMgr.GetConfig().Containerfile,
+	})
+	hash, err := fileHash(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read containerfile: %w", err)
+	}
+	return hash, nil
+}
+
+// poolImages returns the repository's warm pool images by slot name, for
+// the images built from the Containerfile with hash
+func (m *Manager) poolImages(ctx context.Context, hash string) (map[string]container.Image, error) {
+	images, err := m.containerMgr.GetRuntime().Images(ctx, LabelPool+"="+hash)
+	if err != nil {
+		return nil, err
+	}
+	slots := map[string]container.Image{}
+	for i := 1; i <= m.configMgr.GetConfig().WarmPool; i++ {
+		slot, err := m.poolSlotName(i)
+		if err != nil {
+			return nil, err
+		}
+		for _, image := range images {
+			if image.Labels[LabelRepo] == m.gitOps.repoRoot && current(image, slot) {
+				slots[slot] = image
+			}
+		}
+	}
+	return slots, nil
+}
+
+// FillWarmPool builds images for the empty slots of the warm_pool, from the
+// Containerfile of the main checkout, and returns how many it built. Slots
+// holding an image of an older Containerfile are rebuilt.
+func (m *Manager) FillWarmPool(ctx context.Context) (int, error) {
+	size := m.configMgr.GetConfig().WarmPool
+	if size <= 0 || !m.containerMgr.Available() {
+		return 0, nil
+	}
+	hash, err := m.poolContainerfileHash()
+	if err != nil {
+		return 0, err
+	}
+	full, err := m.poolImages(ctx, hash)
+	if err != nil {
+		return 0, err
+	}
+
+	built := 0
+	for i := 1; i <= size; i++ {
+		slot, err := m.poolSlotName(i)
+		if err != nil {
+			return built, err
+		}
+		if _, ok := full[slot]; ok {
+			continue
+		}
+		env := &config.Environment{
+			Name:          slot,
+			WorktreePath:  m.gitOps.repoRoot,
+			Containerfile: m.configMgr.GetConfig().Containerfile,
+		}
+		if err := m.buildImageWithLabels(ctx, env, map[string]string{LabelPool: hash}); err != nil {
+			return built, fmt.Errorf("warm pool slot %d: %w", i, err)
+		}
+		built++
+	}
+	return built, nil
+}
+
+// claimWarmImage gives env a warm pool image built from the same
+// Containerfile, if there is one, tagging it as env's image and emptying its
+// slot for the daemon to refill. Any failure means building as usual.
+func (m *Manager) claimWarmImage(ctx context.Context, env *config.Environment) bool {
+	if m.configMgr.GetConfig().WarmPool <= 0 {
+		return false
+	}
+	hash, err := fileHash(containerfilePath(*env))
+	if err != nil {
+		return false
+	}
+	slots, err := m.poolImages(ctx, hash)
+	if err != nil || len(slots) == 0 {
+		return false
+	}
+
+	runtime := m.containerMgr.GetRuntime()
+	for slot := range slots {
+		if err := runtime.Tag(ctx, imageTag(slot), imageTag(env.Name)); err != nil {
+			// Claimed by a concurrent create
+			continue
+		}
+		if err := runtime.RemoveImage(ctx, imageTag(slot)); err != nil {
+			logging.Logger().Warn("failed to empty warm pool slot", "slot", slot, "error", err.Error())
+		}
+		env.ContainerfileHash = hash
+		return true
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package environment
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+)
+
+// capabilityPattern matches a Linux capability name, with or without CAP_
+var capabilityPattern = regexp.MustCompile(`^(CAP_)?[A-Z_]+$`)
+
+// applyPrivileges records on env the privileged mode, capabilities and
+// devices of the config and opts, checking them first. Device paths are
+// only checked on a local runtime host.
+func (m *Manager) applyPrivileges(env *config.Environment, opts CreateEnvironmentOptions, remoteHost string) error {
+	cfg := m.configMgr.GetConfig()
+	env.Privileged = cfg.Privileged || opts.Privileged
+	env.CapAdd = mergeUnique(cfg.CapAdd, opts.CapAdd)
+	env.Devices = mergeUnique(cfg.Devices, opts.Devices)
+
+	for i, capability := range env.CapAdd {
+		capability = strings.ToUpper(capability)
+		if capability != "ALL" && !capabilityPattern.MatchString(capability) {
+			return fmt.Errorf("invalid capability %q (expected a name such as SYS_PTRACE)", env.CapAdd[i])
+		}
+		env.CapAdd[i] = capability
+	}
+	for _, device := range env.Devices {
+		path, _, _ := strings.Cut(device, ":")
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("invalid device %q (expected /dev/path[:container-path[:permissions]])", device)
+		}
+		if remoteHost != "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("invalid device %q: %s does not exist", device, path)
+		}
+	}
+	return nil
+}
+
+// mergeUnique returns the values of both lists in order, without repeats
+func mergeUnique(first, second []string) []string {
+	var merged []string
+	for _, value := range append(append([]string{}, first...), second...) {
+		if !slices.Contains(merged, value) {
+			merged = append(merged, value)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,27 @@
+package environment
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+)
+
+// ErrProtected is returned when deleting a protected environment without force
+var ErrProtected = errors.New("environment is protected")
+
+// SetProtected marks an environment as protected against deletion, or
+// removes the mark
+func (m *Manager) SetProtected(envName string, protected bool) error {
+	if _, err := m.configMgr.GetEnvironment(envName); err != nil {
+		return fmt.Errorf("environment not found: %w", err)
+	}
+	return m.configMgr.UpdateEnvironment(envName, func(env *config.Environment) {
+		env.Protected = protected
+	})
+}
+
+// protectedError explains how to delete a protected environment anyway
+func protectedError(envName string) error {
+	return fmt.Errorf("%s: %w; run 'cc-buddy unprotect %s' first or delete with --force", envName, ErrProtected, envName)
+}
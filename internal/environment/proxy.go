@@ -0,0 +1,135 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/container"
+)
+
+// ProxyContainerName is the name of the companion reverse proxy container
+const ProxyContainerName = "cc-buddy-proxy"
+
+// ProxyURL returns the https URL the reverse proxy serves for an environment
+func (m *Manager) ProxyURL(env config.Environment) string {
+	if env.ProxyHost == "" {
+		return ""
+	}
+	port := m.configMgr.GetConfig().ProxyHTTPSPort
+	if port == 443 {
+		return "https://" + env.ProxyHost
+	}
+	return fmt.Sprintf("https://%s:%d", env.ProxyHost, port)
+}
+
+// ProxyRunning reports whether the companion proxy container is running
+func (m *Manager) ProxyRunning(ctx context.Context) bool {
+	status, err := m.containerMgr.GetRuntime().Status(ctx, ProxyContainerName)
+	return err == nil && status.Running
+}
+
+// EnsureProxy starts the traefik companion container on the shared network if
+// it is not already running. Traefik watches the runtime socket and routes
+// containers carrying cc-buddy generated labels.
+func (m *Manager) EnsureProxy(ctx context.Context) error {
+	runtime := m.containerMgr.GetRuntime()
+	cfg := m.configMgr.GetConfig()
+
+	if status, err := runtime.Status(ctx, ProxyContainerName); err == nil {
+		if status.Running {
+			return nil
+		}
+		// Replace a stopped leftover so configuration changes take effect
+		if err := runtime.Remove(ctx, ProxyContainerName); err != nil {
+			return fmt.Errorf("failed to remove stopped proxy: %w", err)
+		}
+	}
+
+	if err := runtime.EnsureNetwork(ctx, SharedNetworkName); err != nil {
+		return fmt.Errorf("failed to create network %s: %w", SharedNetworkName, err)
+	}
+
+	socket, err := runtime.SocketPath(ctx)
+	if err != nil {
+		return err
+	}
+
+	runOpts := container.RunOptions{
+		Name:    ProxyContainerName,
+		Image:   cfg.ProxyImage,
+		Detach:  true,
+		Network: SharedNetworkName,
+		Mounts: []container.Mount{
+			{
+				Type:    "bind",
+				Source:  socket,
+				Target:  "/var/run/docker.sock",
+				Options: []string{"ro"},
+			},
+		},
+		Ports: []container.PortMapping{
+			{Host: cfg.ProxyHTTPSPort, Container: 443, Protocol: "tcp"},
+		},
+		// The API socket cannot be relabeled, so run the proxy unconfined
+		SecurityOpts: []string{"label=disable"},
+		Command: []string{
+			"--providers.docker=true",
+			"--providers.docker.exposedbydefault=false",
+			"--providers.docker.network=" + SharedNetworkName,
+			"--entrypoints.websecure.address=:443",
+		},
+	}
+
+	if _, err := runtime.Run(ctx, runOpts); err != nil {
+		return fmt.Errorf("failed to start proxy: %w", err)
+	}
+	return nil
+}
+
+// StopProxy stops and removes the companion proxy container
+func (m *Manager) StopProxy(ctx context.Context) error {
+	runtime := m.containerMgr.GetRuntime()
+	if _, err := runtime.Status(ctx, ProxyContainerName); err != nil {
+		return nil // not present
+	}
+	if err := runtime.Stop(ctx, ProxyContainerName); err != nil {
+		return fmt.Errorf("failed to stop proxy: %w", err)
+	}
+	if err := runtime.Remove(ctx, ProxyContainerName); err != nil {
+		return fmt.Errorf("failed to remove proxy: %w", err)
+	}
+	return nil
+}
+
+// proxyLabels generates traefik routing labels for an environment, routing
+// <env>.<proxy_domain> to the first TCP port the image exposes. It returns
+// nil when the image exposes no ports.
+func (m *Manager) proxyLabels(ctx context.Context, envName, image string) (map[string]string, string, error) {
+	ports, err := m.containerMgr.GetRuntime().ImageExposedPorts(ctx, image)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var target int
+	for _, port := range ports {
+		if port.Protocol == "tcp" {
+			target = port.Container
+			break
+		}
+	}
+	if target == 0 {
+		return nil, "", nil
+	}
+
+	host := envName + "." + m.configMgr.GetConfig().ProxyDomain
+	labels := map[string]string{
+		"traefik.enable": "true",
+		"traefik.http.routers." + envName + ".rule":                      "Host(`" + host + "`)",
+		"traefik.http.routers." + envName + ".entrypoints":               "websecure",
+		"traefik.http.routers." + envName + ".tls":                       "true",
+		"traefik.http.services." + envName + ".loadbalancer.server.port": strconv.Itoa(target),
+	}
+	return labels, host, nil
+}
@@ -0,0 +1,44 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+)
+
+// PublishEnvironment pushes envName's current image to ref, e.g.
+// ghcr.io/org/dev:branch, using the runtime's registry login, so others
+// can create from it with --image
+func (m *Manager) PublishEnvironment(ctx context.Context, envName, ref string) error {
+	env, err := m.configMgr.GetEnvironment(envName)
+	if err != nil {
+		return fmt.Errorf("environment not found: %w", err)
+	}
+	if env.NoContainer {
+		return fmt.Errorf("environment %s was created with --no-container and has no image to publish", envName)
+	}
+	if ref == "" {
+		return fmt.Errorf("an image reference is required, e.g. ghcr.io/org/dev:branch")
+	}
+
+	runtime := m.containerMgr.GetRuntime()
+	if err := runtime.Tag(ctx, imageTag(envName), ref); err != nil {
+		return fmt.Errorf("failed to tag image: %w", err)
+	}
+	if err := runtime.Push(ctx, ref); err != nil {
+		return fmt.Errorf("failed to push %s: %w", ref, err)
+	}
+	return nil
+}
+
+// pullSourceImage pulls the published image env was created from and tags
+// it as env's image, in place of a build
+func (m *Manager) pullSourceImage(ctx context.Context, envName, ref string) error {
+	runtime := m.containerMgr.GetRuntime()
+	if err := runtime.Pull(ctx, ref); err != nil {
+		return err
+	}
+	if err := runtime.Tag(ctx, ref, imageTag(envName)); err != nil {
+		return fmt.Errorf("failed to tag image: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package environment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DefaultWaitTimeout bounds how long create --wait blocks
+const DefaultWaitTimeout = 120 * time.Second
+
+// readinessPollInterval is how often readiness is re-checked
+const readinessPollInterval = time.Second
+
+// ErrNotReady is wrapped by the error of a readiness wait that timed out
+var ErrNotReady = errors.New("environment not ready")
+
+// ParseWaitTimeout parses a --wait-timeout value, accepting Go durations
+// ("90s", "2m") or a bare number of seconds; it must be positive
+func ParseWaitTimeout(value string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(value)
+	if seconds, atoiErr := strconv.Atoi(value); atoiErr == nil {
+		timeout, err = time.Duration(seconds)*time.Second, nil
+	}
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q: use a positive duration like 90s or 2m", value)
+	}
+	return timeout, nil
+}
+
+// WaitForReady blocks until the environment is ready or the timeout expires.
+// With a port, ready means the port accepts connections inside the container;
+// otherwise the container's HEALTHCHECK must report healthy. Containers with
+// neither are ready as soon as they are running.
+func (m *Manager) WaitForReady(ctx context.Context, envName string, port int, timeout time.Duration) error {
+	env, err := m.configMgr.GetEnvironment(envName)
+	if err != nil {
+		return fmt.Errorf("environment not found: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	runtime := m.containerMgr.GetRuntime()
+	ticker := time.NewTicker(readinessPollInterval)
+	defer ticker.Stop()
+
+	lastState := "unknown"
+	for {
+		status, err := runtime.Status(ctx, env.ContainerID)
+		if err == nil {
+			if status.State == "exited" || status.State == "dead" || status.State == "stopped" {
+				return fmt.Errorf("container %s before becoming ready", status.State)
+			}
+
+			if status.Running {
+				switch {
+				case port > 0:
+					if m.portAccepting(ctx, env.ContainerID, port) {
+						return nil
+					}
+					lastState = fmt.Sprintf("port %d not accepting connections", port)
+				case status.Health == "unhealthy":
+					return fmt.Errorf("container health check reports unhealthy")
+				case status.Health != "" && status.Health != "healthy":
+					lastState = "health " + status.Health
+				default:
+					return nil
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w after %s (%s)", ErrNotReady, timeout, lastState)
+		case <-ticker.C:
+		}
+	}
+}
+
+// portProbe is a POSIX sh script that succeeds once something accepts TCP
+// connections on port %[1]d: it connects with bash's /dev/tcp where bash
+// exists, else with nc, else looks for a listening socket on the port (hex
+// %[2]s) in /proc/net, which works on busybox images with neither
+const portProbe = `if command -v bash >/dev/null 2>&1; then
+	exec bash -c 'exec 3<>/dev/tcp/127.0.0.1/%[1]d' 2>/dev/null
+fi
+if command -v nc >/dev/null 2>&1 && nc -z 127.0.0.1 %[1]d >/dev/null 2>&1; then
+	exit 0
+fi
+grep -qE ':%[2]s [0-9A-F]+:[0-9A-F]+ 0A ' /proc/net/tcp /proc/net/tcp6 2>/dev/null`
+
+// portAccepting probes a TCP port from inside the container, so the check
+// works whether or not the port is published to the host
+func (m *Manager) portAccepting(ctx context.Context, containerID string, port int) bool {
+	probe := []string{"sh", "-c", fmt.Sprintf(portProbe, port, fmt.Sprintf("%04X", port))}
+	err := m.containerMgr.GetRuntime().ExecNonInteractive(ctx, containerID, probe)
+	return err == nil
+}
@@ -0,0 +1,127 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/audit"
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// RebuildEnvironment rebuilds an environment's image from its worktree, or
+// pulls its published image again for one created with --image, and
+// replaces its container, keeping the worktree, volumes and backing
+// services. If the build fails the old container keeps running.
+func (m *Manager) RebuildEnvironment(ctx context.Context, envName string) (retErr error) {
+	env, err := m.configMgr.GetEnvironment(envName)
+	if err != nil {
+		return fmt.Errorf("environment not found: %w", err)
+	}
+	if env.NoContainer {
+		return fmt.Errorf("environment %s was created with --no-container and has no container to rebuild", envName)
+	}
+	if env.Containerfile == "" {
+		// Recorded since rebuilds were added; older environments used the default
+		env.Containerfile = m.configMgr.GetConfig().Containerfile
+	}
+
+	began := time.Now()
+	var buildTime time.Duration
+	defer func() {
+		m.recordEvent(audit.Event{Event: audit.EventRebuilt, BuildMS: buildTime.Milliseconds()}, env, began, retErr)
+	}()
+
+	spec, err := m.runSpecFor(ctx, env)
+	if err != nil {
+		return err
+	}
+	// An environment created with --no-start stays unstarted
+	spec.noStart = env.Status == "created"
+
+	buildStarted := time.Now()
+	if env.SourceImage != "" {
+		err = m.pullSourceImage(ctx, env.Name, env.SourceImage)
+	} else {
+		err = m.buildPinnedImage(ctx, &env)
+	}
+	buildTime = time.Since(buildStarted)
+	if err != nil {
+		return err
+	}
+	m.recordBaseImageDigests(ctx, &env)
+
+	err = m.replaceContainer(ctx, &env, spec)
+	if err == nil {
+		// The new container publishes new host ports
+		if envrcErr := m.writeEnvrc(ctx, env); envrcErr != nil {
+			fmt.Printf("Warning: %v\n", envrcErr)
+		}
+	}
+	env.Status = "running"
+	if spec.noStart {
+		env.Status = "created"
+	}
+	env.Stale = false
+	if err != nil {
+		env.Status = "error"
+	}
+
+	if saveErr := m.configMgr.UpdateEnvironment(env.Name, func(stored *config.Environment) {
+		*stored = env
+	}); saveErr != nil && err == nil {
+		err = fmt.Errorf("failed to update environment state: %w", saveErr)
+	}
+	m.syncHostsIfEnabled()
+
+	return err
+}
+
+// replaceContainer removes env's container and runs a new one from its
+// image, bringing back the dind sidecar and the workspace. env records the
+// new container even when a later step fails.
+func (m *Manager) replaceContainer(ctx context.Context, env *config.Environment, spec runSpec) error {
+	// The old container has to go first because the name is reused
+	if env.WorkspaceSync == WorkspaceSyncMutagen {
+		if err := stopMutagenSync(ctx, env.Name); err != nil {
+			logging.Logger().Warn("failed to stop mutagen sync", "environment", env.Name, "error", err.Error())
+		}
+	}
+	if err := m.removeDind(ctx, *env, false); err != nil {
+		return err
+	}
+	if env.ContainerID != "" {
+		// The container may already be stopped or gone
+		m.containerMgr.GetRuntime().Stop(ctx, env.ContainerID)
+		m.containerMgr.GetRuntime().Remove(ctx, env.ContainerID)
+	}
+
+	containerID, err := m.runContainer(ctx, env, spec)
+	env.ContainerID = containerID
+	if err != nil || spec.noStart {
+		return err
+	}
+	// The sidecar has to join the new container's network namespace
+	if err := m.runDind(ctx, *env); err != nil {
+		return err
+	}
+	if env.WorkspaceVolume != "" {
+		// The workspace volume survives, but mutagen needs a new session
+		if env.WorkspacePending || env.WorkspaceSync == WorkspaceSyncMutagen {
+			if err := m.populateWorkspace(ctx, *env); err != nil {
+				return err
+			}
+		}
+		env.WorkspacePending = false
+	}
+	return nil
+}
+
+// MarkStale records whether an environment's image is out of date with its
+// Containerfile or watched files
+func (m *Manager) MarkStale(envName string, stale bool) error {
+	return m.configMgr.UpdateEnvironment(envName, func(env *config.Environment) {
+		env.Stale = stale
+	})
+}
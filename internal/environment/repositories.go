@@ -0,0 +1,57 @@
+package environment
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// repoDirMu serializes InRepository, which changes the working directory
+var repoDirMu sync.Mutex
+
+// InRepository runs fn with a manager for the repository at root. Managers
+// resolve the state directory and worktrees against the working directory,
+// so fn runs with it changed to root and restored afterwards; calls are
+// serialized.
+func InRepository(root string, fn func(*Manager) error) error {
+	repoDirMu.Lock()
+	defer repoDirMu.Unlock()
+
+	previous, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to determine working directory: %w", err)
+	}
+	if err := os.Chdir(root); err != nil {
+		return fmt.Errorf("failed to enter repository: %w", err)
+	}
+	defer os.Chdir(previous)
+
+	manager, err := NewManager()
+	if err != nil {
+		return err
+	}
+	return fn(manager)
+}
+
+// EnterRepository makes root the working directory, so managers created
+// afterwards act on its repository. It waits for running InRepository calls.
+func EnterRepository(root string) error {
+	repoDirMu.Lock()
+	defer repoDirMu.Unlock()
+
+	if err := os.Chdir(root); err != nil {
+		return fmt.Errorf("failed to enter repository: %w", err)
+	}
+	return nil
+}
+
+// register adds the repository to the global registry the dashboard reads;
+// failing to is only logged
+func (m *Manager) register() {
+	if err := config.RegisterRepository(m.gitOps.repoRoot); err != nil {
+		logging.Logger().Debug("failed to register repository", "repository", m.gitOps.repoRoot, "error", err.Error())
+	}
+}
@@ -0,0 +1,86 @@
+package environment
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/container"
+)
+
+// isAutoRuntime reports whether runtime asks for auto-detection
+func isAutoRuntime(runtime string) bool {
+	return runtime == "" || strings.EqualFold(runtime, "auto")
+}
+
+// newContainerManager connects to the configured runtime. With "auto", the
+// runtime chosen in the global config is used while it is still available.
+func newContainerManager(cfg *config.Config) (*container.Manager, error) {
+	opts := container.RuntimeOptions{
+		Host:       cfg.RuntimeHost,
+		Connection: cfg.RuntimeConnection,
+	}
+	if isAutoRuntime(cfg.Runtime) {
+		if global, err := config.LoadGlobalConfig(); err == nil && global.Runtime != "" {
+			if containerMgr, err := container.NewManagerWithOptions(global.Runtime, opts); err == nil {
+				return containerMgr, nil
+			}
+		}
+	}
+	return container.NewManagerWithOptions(cfg.Runtime, opts)
+}
+
+// RuntimeChoices returns the runtimes to choose between when the
+// repository's runtime is "auto", no choice has been recorded, and both
+// podman and docker are installed and responding. Otherwise it returns nil
+// and auto-detection goes on preferring podman.
+func RuntimeChoices() ([]string, error) {
+	configMgr, err := config.NewManager()
+	if err != nil {
+		return nil, err
+	}
+	if err := configMgr.LoadConfig(); err != nil {
+		return nil, &ConfigError{Err: err}
+	}
+	cfg := configMgr.GetConfig()
+	if !isAutoRuntime(cfg.Runtime) {
+		return nil, nil
+	}
+	global, err := config.LoadGlobalConfig()
+	if err != nil {
+		return nil, err
+	}
+	if global.Runtime != "" {
+		return nil, nil
+	}
+
+	choices := []string{"podman", "docker"}
+	for _, name := range choices {
+		// Looking up the binaries first keeps this cheap for most users
+		if _, err := exec.LookPath(name); err != nil {
+			return nil, nil
+		}
+	}
+	opts := container.RuntimeOptions{Host: cfg.RuntimeHost, Connection: cfg.RuntimeConnection}
+	for _, name := range choices {
+		if !container.RuntimeAvailable(name, opts) {
+			return nil, nil
+		}
+	}
+	return choices, nil
+}
+
+// SaveRuntimeChoice records runtime in the global config as the one to use
+// when a repository's runtime is "auto"
+func SaveRuntimeChoice(runtime string) error {
+	global, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+	if runtime != "podman" && runtime != "docker" {
+		return fmt.Errorf("unsupported runtime '%s'; use podman or docker", runtime)
+	}
+	global.Runtime = runtime
+	return config.SaveGlobalConfig(global)
+}
@@ -0,0 +1,208 @@
+package environment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// Scanners are the vulnerability scanners scan looks for, in order
+var Scanners = []string{"trivy", "grype"}
+
+// severityRank orders severities from most to least severe
+var severityRank = map[string]int{"CRITICAL": 0, "HIGH": 1, "MEDIUM": 2, "LOW": 3, "NEGLIGIBLE": 4, "UNKNOWN": 5}
+
+// Finding is one vulnerability in an image
+type Finding struct {
+	ID           string `json:"id"`
+	Severity     string `json:"severity"` // upper case, e.g. CRITICAL
+	Package      string `json:"package"`
+	Version      string `json:"version"`
+	FixedVersion string `json:"fixed_version,omitempty"`
+}
+
+// ScanResult summarizes a scan of an environment's image
+type ScanResult struct {
+	Environment string         `json:"environment"`
+	Image       string         `json:"image"`
+	Scanner     string         `json:"scanner"`
+	Counts      map[string]int `json:"counts"`   // findings by severity
+	Findings    []Finding      `json:"findings"` // most severe first
+}
+
+// AtLeast returns how many findings are severity or worse
+func (r *ScanResult) AtLeast(severity string) int {
+	limit, ok := severityRank[strings.ToUpper(severity)]
+	if !ok {
+		return 0
+	}
+	count := 0
+	for _, finding := range r.Findings {
+		if rank, ok := severityRank[finding.Severity]; ok && rank <= limit {
+			count++
+		}
+	}
+	return count
+}
+
+// findScanner returns the first installed scanner
+func findScanner() (string, error) {
+	for _, scanner := range Scanners {
+		if _, err := exec.LookPath(scanner); err == nil {
+			return scanner, nil
+		}
+	}
+	return "", fmt.Errorf("no vulnerability scanner found; install trivy (https://trivy.dev) or grype (https://github.com/anchore/grype)")
+}
+
+// ScanEnvironment scans envName's image with trivy or grype, whichever is
+// installed
+func (m *Manager) ScanEnvironment(ctx context.Context, envName string) (*ScanResult, error) {
+	env, err := m.configMgr.GetEnvironment(envName)
+	if err != nil {
+		return nil, fmt.Errorf("environment not found: %w", err)
+	}
+	if env.NoContainer {
+		return nil, fmt.Errorf("environment %s was created with --no-container and has no image to scan", envName)
+	}
+	scanner, err := findScanner()
+	if err != nil {
+		return nil, err
+	}
+
+	image := imageTag(envName)
+	var findings []Finding
+	switch scanner {
+	case "trivy":
+		findings, err = m.scanWithTrivy(ctx, image)
+	default:
+		findings, err = m.scanWithGrype(ctx, image)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return severityRank[findings[i].Severity] < severityRank[findings[j].Severity]
+	})
+	result := &ScanResult{Environment: envName, Image: image, Scanner: scanner, Counts: map[string]int{}, Findings: findings}
+	for _, finding := range findings {
+		result.Counts[finding.Severity]++
+	}
+	return result, nil
+}
+
+// scanWithTrivy runs trivy against a local image
+func (m *Manager) scanWithTrivy(ctx context.Context, image string) ([]Finding, error) {
+	args := []string{"image", "--quiet", "--format", "json"}
+	if runtime := m.containerMgr.GetRuntime().Name(); runtime == "docker" || runtime == "podman" {
+		args = append(args, "--image-src", runtime)
+	}
+	out, err := logging.Output(exec.CommandContext(ctx, "trivy", append(args, image)...))
+	if err != nil {
+		return nil, fmt.Errorf("trivy failed: %w", err)
+	}
+
+	var report struct {
+		Results []struct {
+			Vulnerabilities []struct {
+				VulnerabilityID  string
+				PkgName          string
+				InstalledVersion string
+				FixedVersion     string
+				Severity         string
+			}
+		}
+	}
+	if err := json.Unmarshal(out, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse trivy output: %w", err)
+	}
+	var findings []Finding
+	for _, result := range report.Results {
+		for _, vuln := range result.Vulnerabilities {
+			findings = append(findings, Finding{
+				ID:           vuln.VulnerabilityID,
+				Severity:     strings.ToUpper(vuln.Severity),
+				Package:      vuln.PkgName,
+				Version:      vuln.InstalledVersion,
+				FixedVersion: vuln.FixedVersion,
+			})
+		}
+	}
+	return findings, nil
+}
+
+// scanWithGrype runs grype against a local image
+func (m *Manager) scanWithGrype(ctx context.Context, image string) ([]Finding, error) {
+	source := image
+	if runtime := m.containerMgr.GetRuntime().Name(); runtime == "docker" || runtime == "podman" {
+		source = runtime + ":" + image
+	}
+	out, err := logging.Output(exec.CommandContext(ctx, "grype", source, "--quiet", "--output", "json"))
+	if err != nil {
+		return nil, fmt.Errorf("grype failed: %w", err)
+	}
+
+	var report struct {
+		Matches []struct {
+			Vulnerability struct {
+				ID       string `json:"id"`
+				Severity string `json:"severity"`
+				Fix      struct {
+					Versions []string `json:"versions"`
+				} `json:"fix"`
+			} `json:"vulnerability"`
+			Artifact struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+			} `json:"artifact"`
+		} `json:"matches"`
+	}
+	if err := json.Unmarshal(out, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse grype output: %w", err)
+	}
+	var findings []Finding
+	for _, match := range report.Matches {
+		findings = append(findings, Finding{
+			ID:           match.Vulnerability.ID,
+			Severity:     strings.ToUpper(match.Vulnerability.Severity),
+			Package:      match.Artifact.Name,
+			Version:      match.Artifact.Version,
+			FixedVersion: strings.Join(match.Vulnerability.Fix.Versions, ", "),
+		})
+	}
+	return findings, nil
+}
+
+// ValidateScanSeverity checks a scan_on_create severity
+func ValidateScanSeverity(severity string) error {
+	if severity == "" {
+		return nil
+	}
+	if _, ok := severityRank[strings.ToUpper(severity)]; !ok {
+		return fmt.Errorf("invalid scan_on_create %q (expected critical, high, medium or low)", severity)
+	}
+	return nil
+}
+
+// scanOnCreate warns when a new environment's image has findings at or
+// above the scan_on_create severity. Scanning never fails a create.
+func (m *Manager) scanOnCreate(ctx context.Context, envName string) {
+	severity := m.configMgr.GetConfig().ScanOnCreate
+	if severity == "" {
+		return
+	}
+	result, err := m.ScanEnvironment(ctx, envName)
+	if err != nil {
+		fmt.Printf("Warning: scan_on_create: %v\n", err)
+		return
+	}
+	if count := result.AtLeast(severity); count > 0 {
+		fmt.Printf("Warning: %s's image has %d %s or worse vulnerabilities; run 'cc-buddy scan %s' for details\n", envName, count, strings.ToLower(severity), envName)
+	}
+}
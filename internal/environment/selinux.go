@@ -0,0 +1,75 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+
+	"github.com/jhjaggars/cc-buddy/internal/system"
+)
+
+// SELinux relabeling modes for the workspace bind mount
+const (
+	SELinuxLabelAuto    = "auto" // relabel privately when SELinux is enabled
+	SELinuxLabelPrivate = "Z"    // relabel for exclusive use by one container
+	SELinuxLabelShared  = "z"    // relabel for use by several containers
+	SELinuxLabelNone    = "none" // never relabel
+)
+
+// workspaceLabelOption returns the mount option used to relabel worktrees
+// under worktreeDir, or "" when no relabeling should happen
+func (m *Manager) workspaceLabelOption(ctx context.Context, worktreeDir string) (string, error) {
+	// Windows paths reach the runtime's Linux VM through Docker Desktop's
+	// file sharing, which cannot carry SELinux labels
+	windows := goruntime.GOOS == "windows"
+	switch mode := m.configMgr.GetConfig().SELinuxLabel; mode {
+	case "", SELinuxLabelAuto:
+		if windows {
+			return "", nil
+		}
+		// A remote or VM-hosted daemon has its own SELinux state we cannot see,
+		// and Apple's runtime has none at all
+		if _, remote := m.containerMgr.RemoteHost(ctx); remote {
+			return "", nil
+		}
+		if m.containerMgr.GetRuntime().Name() == "container" || !system.SELinuxEnabled() {
+			return "", nil
+		}
+		if system.IsNetworkFilesystem(NearestExistingDir(worktreeDir)) {
+			fmt.Printf("Note: %s is on a network filesystem; skipping SELinux relabeling\n", worktreeDir)
+			return "", nil
+		}
+		return SELinuxLabelPrivate, nil
+	case SELinuxLabelPrivate, SELinuxLabelShared:
+		if windows {
+			fmt.Printf("Note: ignoring selinux_label %q; SELinux relabeling is skipped on Windows\n", mode)
+			return "", nil
+		}
+		return mode, nil
+	case SELinuxLabelNone:
+		return "", nil
+	default:
+		return "", fmt.Errorf("unsupported selinux_label %q (supported: auto, Z, z, none)", mode)
+	}
+}
+
+// NearestExistingDir walks up from path until it finds a directory that
+// exists, so filesystem checks work before the worktree is created
+func NearestExistingDir(path string) string {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "."
+	}
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
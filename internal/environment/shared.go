@@ -0,0 +1,127 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/utils"
+)
+
+// sharedContainerName names the repository's shared container, image and
+// volume; generated environment names never contain an underscore after
+// the repository name, so it cannot clash with one
+func (m *Manager) sharedContainerName() (string, error) {
+	repoName, err := m.gitOps.GetRepoName()
+	if err != nil {
+		return "", err
+	}
+	return repoName + "_shared", nil
+}
+
+// SwitchSharedContainer mounts envName's worktree at /workspace of the
+// repository's one long-lived container (the experimental shared_container
+// mode). The image is built the first time, or again with rebuild; after
+// that a switch only replaces the container, keeping its /data volume, so
+// moving between branches takes seconds. envName must not have a container
+// of its own, e.g. one created with --no-container.
+func (m *Manager) SwitchSharedContainer(ctx context.Context, envName string, rebuild bool) error {
+	if !m.configMgr.GetConfig().SharedContainer {
+		return fmt.Errorf("switch is experimental; enable it with \"shared_container\": true in %s/%s", config.StateDir, config.ConfigFile)
+	}
+	env, err := m.configMgr.GetEnvironment(envName)
+	if err != nil {
+		return fmt.Errorf("environment not found: %w", err)
+	}
+	if !env.NoContainer {
+		return fmt.Errorf("environment %s has its own container; switch mounts environments created with --no-container", envName)
+	}
+	name, err := m.sharedContainerName()
+	if err != nil {
+		return err
+	}
+
+	shared := &config.Environment{
+		Name:          name,
+		Branch:        env.Branch,
+		WorktreePath:  env.WorktreePath,
+		ContainerName: fmt.Sprintf("cc-buddy-%s", name),
+		VolumeName:    fmt.Sprintf("cc-buddy-%s-data", name),
+		Containerfile: m.configMgr.GetConfig().Containerfile,
+		Network:       m.configMgr.GetConfig().Network,
+		RuntimeArgs:   m.runtimeArgs(nil),
+	}
+	if err := m.applyPrivileges(shared, CreateEnvironmentOptions{}, ""); err != nil {
+		return err
+	}
+	runtime := m.containerMgr.GetRuntime()
+	label := LabelEnvironment + "=" + name
+
+	images, err := runtime.Images(ctx, label)
+	if err != nil {
+		return err
+	}
+	if rebuild || len(images) == 0 {
+		utils.ReportProgress(ctx, 0.1, StepBuild)
+		if err := m.buildImage(ctx, shared); err != nil {
+			return err
+		}
+	}
+	volumes, err := runtime.Volumes(ctx, label)
+	if err != nil {
+		return err
+	}
+	if len(volumes) == 0 {
+		if err := runtime.CreateVolume(ctx, shared.VolumeName, m.resourceLabels(name)); err != nil {
+			return fmt.Errorf("failed to create volume: %w", err)
+		}
+	}
+
+	// Mounts cannot change on a container, so replace it
+	utils.ReportProgress(ctx, 0.8, StepContainer)
+	_ = runtime.Stop(ctx, shared.ContainerName)
+	_ = runtime.Remove(ctx, shared.ContainerName)
+	spec, err := m.runSpecFor(ctx, *shared)
+	if err != nil {
+		return err
+	}
+	spec.role = RoleShared
+	containerID, err := m.runContainer(ctx, shared, spec)
+	if err != nil {
+		return err
+	}
+
+	// Point the worktree's .envrc at the new container and its ports
+	env.ContainerID = containerID
+	env.ContainerName = shared.ContainerName
+	if err := m.writeEnvrc(ctx, env); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
+	for _, other := range m.configMgr.Environments() {
+		if other.SharedContainer && other.Name != envName {
+			err := m.configMgr.UpdateEnvironment(other.Name, func(stored *config.Environment) {
+				stored.SharedContainer = false
+				stored.ContainerID = ""
+				stored.ContainerName = ""
+				stored.Status = "worktree"
+			})
+			if err != nil {
+				return fmt.Errorf("failed to update environment state: %w", err)
+			}
+		}
+	}
+	now := time.Now()
+	err = m.configMgr.UpdateEnvironment(envName, func(stored *config.Environment) {
+		stored.SharedContainer = true
+		stored.ContainerID = containerID
+		stored.ContainerName = shared.ContainerName
+		stored.Status = "running"
+		stored.LastUsed = &now
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update environment state: %w", err)
+	}
+	return nil
+}
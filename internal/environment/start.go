@@ -0,0 +1,140 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/audit"
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// StartEnvironment starts an environment's stopped container, or one created
+// with --no-start, along with its backing services. Sync-mode workspaces that
+// were never filled are copied in once the container is running, and then
+// pending post_create hooks run. With secrets configured the container is
+// replaced instead, so it gets their current values.
+func (m *Manager) StartEnvironment(ctx context.Context, envName string) (retErr error) {
+	env, err := m.configMgr.GetEnvironment(envName)
+	if err != nil {
+		return fmt.Errorf("environment not found: %w", err)
+	}
+	if env.ContainerID == "" {
+		return fmt.Errorf("environment %s has no container", envName)
+	}
+
+	began := time.Now()
+	defer func() {
+		m.recordEvent(audit.Event{Event: audit.EventStarted}, env, began, retErr)
+	}()
+
+	if env.ComposeProject != "" {
+		if err := m.composeStart(ctx, env); err != nil {
+			return err
+		}
+	}
+	if len(m.configMgr.GetConfig().Secrets) > 0 {
+		if err := m.restartWithSecrets(ctx, &env); err != nil {
+			return err
+		}
+	} else {
+		if err := m.containerMgr.GetRuntime().Start(ctx, env.ContainerID); err != nil {
+			return fmt.Errorf("failed to start container: %w", err)
+		}
+		if err := m.runDind(ctx, env); err != nil {
+			return err
+		}
+		if env.WorkspacePending {
+			if err := m.populateWorkspace(ctx, env); err != nil {
+				return err
+			}
+		}
+	}
+	if env.HooksPending {
+		if err := m.runHooks(ctx, env.ContainerID, hookPostCreate, m.configMgr.GetConfig().Hooks.PostCreate); err != nil {
+			return err
+		}
+	}
+
+	// Published host ports may differ after a restart
+	if err := m.writeEnvrc(ctx, env); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	m.recordPublishedPorts(ctx, &env)
+
+	now := time.Now()
+	err = m.configMgr.UpdateEnvironment(envName, func(stored *config.Environment) {
+		stored.Status = "running"
+		stored.ContainerID = env.ContainerID
+		stored.ProxyHost = env.ProxyHost
+		stored.Ports = env.Ports
+		stored.WorkspacePending = false
+		stored.HooksPending = false
+		stored.LastUsed = &now
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update environment state: %w", err)
+	}
+	return nil
+}
+
+// restartWithSecrets replaces env's stopped container with one holding
+// freshly resolved secrets; a container keeps the values it was created
+// with. Its ID is saved even when starting it fails.
+func (m *Manager) restartWithSecrets(ctx context.Context, env *config.Environment) error {
+	spec, err := m.runSpecFor(ctx, *env)
+	if err != nil {
+		return err
+	}
+	err = m.replaceContainer(ctx, env, spec)
+	if err != nil {
+		containerID := env.ContainerID
+		if saveErr := m.configMgr.UpdateEnvironment(env.Name, func(stored *config.Environment) {
+			stored.ContainerID = containerID
+			stored.Status = "error"
+		}); saveErr != nil {
+			logging.Logger().Warn("failed to record replaced container", "environment", env.Name, "error", saveErr.Error())
+		}
+	}
+	return err
+}
+
+// StopEnvironment stops an environment's container and backing services,
+// keeping everything so StartEnvironment can resume it
+func (m *Manager) StopEnvironment(ctx context.Context, envName string) (retErr error) {
+	env, err := m.configMgr.GetEnvironment(envName)
+	if err != nil {
+		return fmt.Errorf("environment not found: %w", err)
+	}
+	if env.ContainerID == "" {
+		return fmt.Errorf("environment %s has no container", envName)
+	}
+
+	began := time.Now()
+	defer func() {
+		m.recordEvent(audit.Event{Event: audit.EventStopped}, env, began, retErr)
+	}()
+
+	if err := m.removeDind(ctx, env, false); err != nil {
+		return err
+	}
+	if err := m.containerMgr.GetRuntime().Stop(ctx, env.ContainerID); err != nil {
+		return fmt.Errorf("failed to stop container: %w", err)
+	}
+	if env.ComposeProject != "" {
+		if err := m.composeStop(ctx, env); err != nil {
+			return err
+		}
+	}
+
+	err = m.configMgr.UpdateEnvironment(envName, func(stored *config.Environment) {
+		stored.Status = "stopped"
+		stored.Health = ""
+		stored.Ports = nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update environment state: %w", err)
+	}
+	return nil
+}
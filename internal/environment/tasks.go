@@ -0,0 +1,35 @@
+package environment
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TaskNames returns the names of the configured tasks, sorted
+func (m *Manager) TaskNames() []string {
+	tasks := m.configMgr.GetConfig().Tasks
+	names := make([]string, 0, len(tasks))
+	for name := range tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TaskCommand returns the container command that runs a configured task;
+// args are passed on to the task's shell command
+func (m *Manager) TaskCommand(name string, args []string) ([]string, error) {
+	script, ok := m.configMgr.GetConfig().Tasks[name]
+	if !ok {
+		names := m.TaskNames()
+		if len(names) == 0 {
+			return nil, fmt.Errorf("no tasks configured; add them under \"tasks\" in .cc-buddy/config.json")
+		}
+		return nil, fmt.Errorf("unknown task %q (tasks: %s)", name, strings.Join(names, ", "))
+	}
+	if len(args) > 0 {
+		script += ` "$@"`
+	}
+	return append([]string{"sh", "-c", script, name}, args...), nil
+}
@@ -0,0 +1,58 @@
+package environment
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/scaffold"
+)
+
+// TemplateContainerfileDir holds the Containerfiles of environments created
+// from a template. They live outside the worktree so it stays clean.
+var TemplateContainerfileDir = filepath.Join(config.StateDir, "containerfiles")
+
+// templateContainerfile returns where envName's template Containerfile is written
+func templateContainerfile(envName string) string {
+	return filepath.Join(TemplateContainerfileDir, envName+".Containerfile")
+}
+
+// writeTemplateContainerfile writes tmpl's Containerfile for envName and
+// returns its absolute path
+func writeTemplateContainerfile(envName string, tmpl *scaffold.Template) (string, error) {
+	return writeContainerfile(envName, tmpl.Containerfile)
+}
+
+// writeContainerfile writes a generated Containerfile for envName outside
+// its worktree and returns its absolute path
+func writeContainerfile(envName string, data []byte) (string, error) {
+	path, err := filepath.Abs(templateContainerfile(envName))
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", TemplateContainerfileDir, err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write containerfile: %w", err)
+	}
+	return path, nil
+}
+
+// removeTemplateContainerfile removes envName's template Containerfile, if any
+func removeTemplateContainerfile(envName string) error {
+	if err := os.Remove(templateContainerfile(envName)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove template containerfile: %w", err)
+	}
+	return nil
+}
+
+// containerfilePath returns the path of env's Containerfile. Relative paths
+// are inside its worktree; template Containerfiles are absolute.
+func containerfilePath(env config.Environment) string {
+	if filepath.IsAbs(env.Containerfile) {
+		return env.Containerfile
+	}
+	return filepath.Join(env.WorktreePath, env.Containerfile)
+}
@@ -0,0 +1,49 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+)
+
+// DefaultTmuxSession is the tmux session terminal attaches to unless told
+// otherwise
+const DefaultTmuxSession = "main"
+
+// installTmuxScript installs tmux with the image's package manager, using
+// the passwordless sudo the templates set up for the container user
+const installTmuxScript = `if command -v apt-get >/dev/null 2>&1; then sudo -n sh -c 'apt-get update -qq && apt-get install -y -qq tmux'
+elif command -v apk >/dev/null 2>&1; then sudo -n apk add --no-cache tmux
+elif command -v dnf >/dev/null 2>&1; then sudo -n dnf install -y tmux
+else exit 1
+fi`
+
+// TerminalOptions selects how OpenTerminal connects
+type TerminalOptions struct {
+	Fresh   bool   // open a plain shell even when tmux is enabled
+	Session string // tmux session to attach to; implies tmux
+}
+
+// terminalCommand returns the command a terminal runs in containerID: a
+// tmux session that outlives the terminal when tmux is enabled, else bash.
+// tmux is installed on first use when the image lacks it.
+func (m *Manager) terminalCommand(ctx context.Context, containerID string, opts TerminalOptions) []string {
+	shell := []string{"/bin/bash"}
+	if opts.Fresh || (!m.configMgr.GetConfig().Tmux && opts.Session == "") {
+		return shell
+	}
+	session := opts.Session
+	if session == "" {
+		session = DefaultTmuxSession
+	}
+
+	runtime := m.containerMgr.GetRuntime()
+	if err := runtime.ExecNonInteractive(ctx, containerID, []string{"sh", "-c", "command -v tmux"}); err != nil {
+		fmt.Println("Installing tmux in the container...")
+		if err := runtime.ExecNonInteractive(ctx, containerID, []string{"sh", "-c", installTmuxScript}); err != nil {
+			fmt.Println("Warning: could not install tmux (add it to the Containerfile); opening a plain shell")
+			return shell
+		}
+	}
+	// -A attaches when the session exists, so builds and REPLs keep running
+	return []string{"tmux", "new-session", "-A", "-s", session, "-c", "/workspace"}
+}
@@ -0,0 +1,171 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchDebounce is how long watch waits for changes to settle before
+// rebuilding, so an editor save or a git checkout triggers one rebuild
+const DefaultWatchDebounce = 2 * time.Second
+
+// WatchOptions configures Watch
+type WatchOptions struct {
+	Paths    []string      // extra files or directories, relative to the worktree
+	Debounce time.Duration // quiet period before rebuilding; DefaultWatchDebounce when zero
+}
+
+// watchTargets are the files and directory trees a watch reacts to
+type watchTargets struct {
+	files map[string]bool
+	dirs  []string
+}
+
+// matches reports whether path is one of the targets or inside one
+func (t *watchTargets) matches(path string) bool {
+	if t.files[path] {
+		return true
+	}
+	for _, dir := range t.dirs {
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Watch monitors an environment's Containerfile and watched paths and
+// rebuilds the environment when they change, until ctx is cancelled. The
+// environment is marked stale as soon as a change settles. report receives
+// progress messages; a failed rebuild is reported and watching continues.
+func (m *Manager) Watch(ctx context.Context, envName string, opts WatchOptions, report func(string)) error {
+	env, err := m.configMgr.GetEnvironment(envName)
+	if err != nil {
+		return fmt.Errorf("environment not found: %w", err)
+	}
+	containerfile := env.Containerfile
+	if containerfile == "" {
+		containerfile = m.configMgr.GetConfig().Containerfile
+	}
+	debounce := opts.Debounce
+	if debounce <= 0 {
+		debounce = DefaultWatchDebounce
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	paths := append([]string{containerfile}, m.configMgr.GetConfig().WatchPaths...)
+	paths = append(paths, opts.Paths...)
+	targets := &watchTargets{files: map[string]bool{}}
+	for _, path := range paths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(env.WorktreePath, path)
+		}
+		path = filepath.Clean(path)
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("cannot watch %s: %w", path, err)
+		}
+		if info.IsDir() {
+			if err := addWatchTree(watcher, path); err != nil {
+				return err
+			}
+			targets.dirs = append(targets.dirs, path)
+			report(fmt.Sprintf("Watching %s/", path))
+			continue
+		}
+
+		// Watch the parent: editors save by renaming a new file over the old
+		// one, which drops a watch on the file itself
+		if err := watcher.Add(filepath.Dir(path)); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		targets.files[path] = true
+		report(fmt.Sprintf("Watching %s", path))
+	}
+
+	var timer *time.Timer
+	var settled <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !targets.matches(event.Name) || event.Op == fsnotify.Chmod {
+				continue
+			}
+			// New directories inside a watched tree need their own watch
+			if event.Op.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := addWatchTree(watcher, event.Name); err != nil {
+						report(fmt.Sprintf("Warning: %v", err))
+					}
+				}
+			}
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.NewTimer(debounce)
+			settled = timer.C
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			report(fmt.Sprintf("Warning: file watcher error: %v", err))
+
+		case <-settled:
+			settled = nil
+			if err := m.MarkStale(envName, true); err != nil {
+				report(fmt.Sprintf("Warning: failed to mark %s stale: %v", envName, err))
+			}
+
+			// Changes made during the rebuild queue up and trigger another one
+			report(fmt.Sprintf("Change detected, rebuilding %s...", envName))
+			started := time.Now()
+			if err := m.RebuildEnvironment(ctx, envName); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				report(fmt.Sprintf("Rebuild failed: %v", err))
+				continue
+			}
+			report(fmt.Sprintf("Rebuilt %s in %s", envName, time.Since(started).Round(time.Second)))
+		}
+	}
+}
+
+// addWatchTree watches dir and every directory below it, skipping .git
+func addWatchTree(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if err := watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,184 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// Workspace modes control how the worktree reaches /workspace
+const (
+	WorkspaceModeAuto = "auto" // sync when the runtime host is remote, bind otherwise
+	WorkspaceModeBind = "bind" // bind mount the local worktree
+	WorkspaceModeSync = "sync" // copy the worktree into a volume
+)
+
+// Workspace sync tools move files between the worktree and a synced volume
+const (
+	WorkspaceSyncCopy    = "copy"    // one-shot runtime cp (default)
+	WorkspaceSyncRsync   = "rsync"   // incremental rsync over "<runtime> exec"
+	WorkspaceSyncMutagen = "mutagen" // continuous two-way mutagen session (docker only)
+)
+
+// validateWorkspaceSync checks the workspace_sync tool is known and installed
+func (m *Manager) validateWorkspaceSync() (string, error) {
+	tool := m.configMgr.GetConfig().WorkspaceSync
+	switch tool {
+	case "", WorkspaceSyncCopy:
+		return WorkspaceSyncCopy, nil
+	case WorkspaceSyncRsync:
+	case WorkspaceSyncMutagen:
+		// mutagen reaches containers through its docker:// transport
+		if name := m.containerMgr.GetRuntime().Name(); name != "docker" {
+			return "", fmt.Errorf("workspace_sync \"mutagen\" requires the docker runtime (current: %s)", name)
+		}
+	default:
+		return "", fmt.Errorf("unsupported workspace_sync %q (supported: copy, rsync, mutagen)", tool)
+	}
+	if _, err := exec.LookPath(tool); err != nil {
+		return "", fmt.Errorf("workspace_sync is %q but %s is not installed", tool, tool)
+	}
+	return tool, nil
+}
+
+// validateMountConsistency checks the mount_consistency setting
+func validateMountConsistency(consistency string) error {
+	switch consistency {
+	case "", "consistent", "cached", "delegated":
+		return nil
+	default:
+		return fmt.Errorf("unsupported mount_consistency %q (supported: consistent, cached, delegated)", consistency)
+	}
+}
+
+// resolveWorkspaceMode decides whether the worktree is bind mounted or synced.
+// It also returns the runtime endpoint when the daemon is remote.
+func (m *Manager) resolveWorkspaceMode(ctx context.Context) (string, string, error) {
+	remoteHost, isRemote := m.containerMgr.RemoteHost(ctx)
+
+	switch mode := m.configMgr.GetConfig().WorkspaceMode; mode {
+	case "", WorkspaceModeAuto:
+		if isRemote {
+			return WorkspaceModeSync, remoteHost, nil
+		}
+		return WorkspaceModeBind, "", nil
+	case WorkspaceModeBind:
+		if isRemote {
+			return "", "", fmt.Errorf("runtime host %s is remote, so the local worktree cannot be bind mounted (set workspace_mode to \"sync\" or \"auto\")", remoteHost)
+		}
+		return WorkspaceModeBind, "", nil
+	case WorkspaceModeSync:
+		return WorkspaceModeSync, remoteHost, nil
+	default:
+		return "", "", fmt.Errorf("unsupported workspace_mode %q (supported: auto, bind, sync)", mode)
+	}
+}
+
+// SyncWorkspace copies the worktree into the environment's /workspace, or
+// with pull set copies /workspace back into the worktree. It only applies to
+// environments created in sync mode.
+func (m *Manager) SyncWorkspace(ctx context.Context, envName string, pull bool) error {
+	env, err := m.configMgr.GetEnvironment(envName)
+	if err != nil {
+		return fmt.Errorf("environment not found: %w", err)
+	}
+	return m.copyWorkspace(ctx, env, pull)
+}
+
+// copyWorkspace performs the copy for SyncWorkspace
+func (m *Manager) copyWorkspace(ctx context.Context, env config.Environment, pull bool) error {
+	envName := env.Name
+	if env.WorkspaceVolume == "" {
+		return fmt.Errorf("environment %s bind mounts its worktree; there is nothing to sync", envName)
+	}
+	if env.ContainerID == "" {
+		return fmt.Errorf("environment %s has no container", envName)
+	}
+
+	switch env.WorkspaceSync {
+	case WorkspaceSyncRsync:
+		return m.rsyncWorkspace(ctx, env, pull)
+	case WorkspaceSyncMutagen:
+		// The session syncs both ways; flushing waits for pending changes
+		if err := runTool(ctx, "mutagen", "sync", "flush", mutagenSessionName(env.Name)); err != nil {
+			return fmt.Errorf("failed to flush mutagen session: %w", err)
+		}
+		return nil
+	}
+
+	runtime := m.containerMgr.GetRuntime()
+	if pull {
+		if err := runtime.CopyFrom(ctx, env.ContainerID, "/workspace/.", env.WorktreePath); err != nil {
+			return fmt.Errorf("failed to copy /workspace to %s: %w", env.WorktreePath, err)
+		}
+		return nil
+	}
+
+	if err := runtime.CopyTo(ctx, env.ContainerID, env.WorktreePath+string(filepath.Separator)+".", "/workspace"); err != nil {
+		return fmt.Errorf("failed to copy %s to /workspace: %w", env.WorktreePath, err)
+	}
+	return nil
+}
+
+// populateWorkspace fills a new sync-mode container's /workspace from the
+// worktree, starting the mutagen session when mutagen is the sync tool
+func (m *Manager) populateWorkspace(ctx context.Context, env config.Environment) error {
+	if env.WorkspaceSync == WorkspaceSyncMutagen {
+		return startMutagenSync(ctx, env)
+	}
+	return m.copyWorkspace(ctx, env, false)
+}
+
+// rsyncWorkspace copies changed files with rsync, tunnelling through
+// "<runtime> exec -i" so the image must have rsync installed
+func (m *Manager) rsyncWorkspace(ctx context.Context, env config.Environment, pull bool) error {
+	rsh := strings.Join(append(m.containerMgr.CommandLine(), "exec", "-i"), " ")
+	local := env.WorktreePath + "/"
+	remote := env.ContainerID + ":/workspace/"
+
+	args := []string{"-a", "--blocking-io", "--rsh", rsh, local, remote}
+	if pull {
+		args = []string{"-a", "--blocking-io", "--rsh", rsh, remote, local}
+	}
+	if err := runTool(ctx, "rsync", args...); err != nil {
+		return fmt.Errorf("rsync failed (is rsync installed in the image?): %w", err)
+	}
+	return nil
+}
+
+// startMutagenSync creates a two-way mutagen session between the worktree and
+// the container's /workspace
+func startMutagenSync(ctx context.Context, env config.Environment) error {
+	err := runTool(ctx, "mutagen", "sync", "create",
+		"--name", mutagenSessionName(env.Name),
+		"--sync-mode", "two-way-resolved",
+		env.WorktreePath, "docker://"+env.ContainerID+"/workspace")
+	if err != nil {
+		return fmt.Errorf("failed to start mutagen sync: %w", err)
+	}
+	return nil
+}
+
+// stopMutagenSync terminates the environment's mutagen session
+func stopMutagenSync(ctx context.Context, envName string) error {
+	return runTool(ctx, "mutagen", "sync", "terminate", mutagenSessionName(envName))
+}
+
+// mutagenSessionName names the mutagen session for an environment
+func mutagenSessionName(envName string) string {
+	return "cc-buddy-" + envName
+}
+
+// runTool runs an external sync tool, including its output in errors
+func runTool(ctx context.Context, name string, args ...string) error {
+	output, err := logging.CombinedOutput(exec.CommandContext(ctx, name, args...))
+	if err != nil {
+		return fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/audit"
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/utils"
+)
+
+// createWorktreeEnvironment creates an environment that is only a branch and
+// worktree, for create --no-container. It is listed, tracked and deleted
+// like any other, but has no image, volume or container.
+func (m *Manager) createWorktreeEnvironment(ctx context.Context, envName string, opts CreateEnvironmentOptions, began time.Time) (retEnv *config.Environment, retErr error) {
+	if len(opts.StartupCommand) > 0 || len(opts.Ports) > 0 || len(opts.Mounts) > 0 || opts.ReadOnlyWorkspace || opts.Template != "" || opts.Image != "" || len(opts.RuntimeArgs) > 0 || opts.Privileged || len(opts.CapAdd) > 0 || len(opts.Devices) > 0 {
+		return nil, fmt.Errorf("--no-container cannot be combined with container options such as -e, --port, --mount, --read-only-workspace, --template, --image, --runtime-arg, --privileged, --cap-add or --device")
+	}
+
+	worktreePath := filepath.Join(opts.WorktreeDir, envName)
+	if opts.WorktreePath != "" {
+		worktreePath = opts.WorktreePath
+	}
+	env := &config.Environment{
+		Name:         envName,
+		Branch:       opts.BranchName,
+		WorktreePath: worktreePath,
+		Created:      time.Now(),
+		Status:       "worktree",
+		NoContainer:  true,
+		Labels:       opts.Labels,
+	}
+
+	branchCreated := false
+	worktreeCreated := false
+	defer func() {
+		if retErr == nil {
+			return
+		}
+		m.recordEvent(audit.Event{Event: audit.EventCreateFailed}, *env, began, retErr)
+		if worktreeCreated {
+			if removeErr := m.gitOps.RemoveWorktree(ctx, worktreePath); removeErr != nil {
+				fmt.Printf("Warning: Failed to remove worktree during cleanup: %v\n", removeErr)
+			}
+		}
+		if branchCreated {
+			if deleteErr := m.gitOps.DeleteBranch(ctx, opts.BranchName); deleteErr != nil {
+				fmt.Printf("Warning: Failed to remove created branch during cleanup: %v\n", deleteErr)
+			}
+		}
+	}()
+
+	if opts.WorktreePath == "" {
+		utils.ReportProgress(ctx, 0.05, StepWorktree)
+		created, err := m.prepareWorktree(ctx, opts, worktreePath)
+		branchCreated = created
+		if err != nil {
+			return nil, err
+		}
+		worktreeCreated = true
+	}
+
+	if err := m.writeEnvrc(ctx, *env); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	if err := m.configMgr.AddEnvironment(*env); err != nil {
+		return nil, fmt.Errorf("failed to add environment to state: %w", err)
+	}
+
+	m.recordEvent(audit.Event{Event: audit.EventCreated}, *env, began, nil)
+	m.register()
+	return env, nil
+}
+
+// resolveHereBranch sets opts up for create --here: the environment takes
+// the branch checked out in the current checkout, which a branch name given
+// alongside must match
+func (m *Manager) resolveHereBranch(ctx context.Context, opts *CreateEnvironmentOptions) error {
+	if opts.NoContainer || opts.WorktreePath != "" {
+		return fmt.Errorf("--here cannot be combined with --no-container or --worktree-path")
+	}
+	if opts.IsRemoteBranch {
+		return fmt.Errorf("--here uses the checked-out branch; it cannot be combined with a remote branch")
+	}
+	branch, err := m.gitOps.GetCurrentBranch(ctx)
+	if err != nil {
+		return err
+	}
+	if branch == "" {
+		return fmt.Errorf("--here needs a checked-out branch, but HEAD is detached in %s", m.gitOps.repoRoot)
+	}
+	if opts.BranchName != "" && opts.BranchName != branch {
+		return fmt.Errorf("--here uses the checked-out branch %s, not %s", branch, opts.BranchName)
+	}
+	opts.BranchName = branch
+	return nil
+}
+
+// resolveAdoptedWorktree checks that opts.WorktreePath, for create
+// --worktree-path, is a linked worktree of this repository with a branch
+// checked out that no environment uses yet, and sets opts up to adopt it.
+// A branch name given alongside must match the worktree's.
+func (m *Manager) resolveAdoptedWorktree(ctx context.Context, opts *CreateEnvironmentOptions) error {
+	if opts.IsRemoteBranch {
+		return fmt.Errorf("--worktree-path uses the worktree's branch; it cannot be combined with a remote branch")
+	}
+	path, err := canonicalPath(opts.WorktreePath)
+	if err != nil {
+		return fmt.Errorf("worktree %s: %w", opts.WorktreePath, err)
+	}
+
+	worktrees, err := m.gitOps.ListWorktrees(ctx)
+	if err != nil {
+		return err
+	}
+	var adopted *WorktreeInfo
+	for i, worktree := range worktrees {
+		if candidate, err := canonicalPath(worktree.Path); err == nil && candidate == path {
+			if i == 0 {
+				// git lists the main worktree first
+				return fmt.Errorf("%s is the repository's main checkout; use --here to mount it", opts.WorktreePath)
+			}
+			adopted = &worktrees[i]
+			break
+		}
+	}
+	if adopted == nil {
+		return fmt.Errorf("%s is not a worktree of this repository (see 'git worktree list')", opts.WorktreePath)
+	}
+	if adopted.Branch == "" {
+		return fmt.Errorf("worktree %s has a detached HEAD; check out a branch in it first", opts.WorktreePath)
+	}
+	if opts.BranchName != "" && opts.BranchName != adopted.Branch {
+		return fmt.Errorf("worktree %s has branch %s checked out, not %s", opts.WorktreePath, adopted.Branch, opts.BranchName)
+	}
+
+	for _, env := range m.configMgr.Environments() {
+		if existing, err := canonicalPath(env.WorktreePath); err == nil && existing == path {
+			return fmt.Errorf("worktree %s already belongs to environment %s", opts.WorktreePath, env.Name)
+		}
+	}
+
+	opts.BranchName = adopted.Branch
+	opts.WorktreePath = path
+	return nil
+}
+
+// canonicalPath makes path absolute and resolves symlinks, so the same
+// directory always compares equal
+func canonicalPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(abs)
+}
@@ -0,0 +1,201 @@
+// Package logging writes the --debug log and traces the external git and
+// container runtime commands cc-buddy runs, echoing them with --verbose
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+)
+
+// DebugEnv enables debug logging when set to a non-empty value other than "0"
+const DebugEnv = "CC_BUDDY_DEBUG"
+
+// LogDir is where debug logs are written, relative to the repository root
+var LogDir = filepath.Join(config.StateDir, "logs")
+
+var (
+	logger  = slog.New(slog.NewTextHandler(io.Discard, nil))
+	logFile *os.File
+	verbose io.Writer
+)
+
+// DebugRequested reports whether CC_BUDDY_DEBUG asks for debug logging
+func DebugRequested() bool {
+	value := os.Getenv(DebugEnv)
+	return value != "" && value != "0" && value != "false"
+}
+
+// EnableDebug starts writing structured debug logs to a new timestamped file
+// in LogDir and returns its path. Call Close before exiting.
+func EnableDebug() (string, error) {
+	if err := os.MkdirAll(LogDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create log directory: %w", err)
+	}
+	path := filepath.Join(LogDir, fmt.Sprintf("cc-buddy-%s.log", time.Now().Format("20060102-150405")))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to open debug log: %w", err)
+	}
+
+	logFile = file
+	logger = slog.New(slog.NewJSONHandler(file, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	logger.Info("debug logging started", "args", os.Args, "pid", os.Getpid())
+	return path, nil
+}
+
+// Close flushes and closes the debug log, if any
+func Close() error {
+	if logFile == nil {
+		return nil
+	}
+	err := logFile.Close()
+	logFile = nil
+	logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	return err
+}
+
+// SetVerbose echoes each command to stderr, shell-quoted, before it runs
+func SetVerbose(enabled bool) {
+	if enabled {
+		verbose = os.Stderr
+	} else {
+		verbose = nil
+	}
+}
+
+// Logger returns the debug logger; it discards records unless debug logging is enabled
+func Logger() *slog.Logger {
+	return logger
+}
+
+// Run runs cmd like cmd.Run, logging it
+func Run(cmd *exec.Cmd) error {
+	done := start(cmd)
+	err := cmd.Run()
+	done(err, nil)
+	return err
+}
+
+// Output runs cmd like cmd.Output, logging it
+func Output(cmd *exec.Cmd) ([]byte, error) {
+	done := start(cmd)
+	out, err := cmd.Output()
+	done(err, nil)
+	return out, err
+}
+
+// CombinedOutput runs cmd like cmd.CombinedOutput, logging it along with
+// its output when it fails
+func CombinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	done := start(cmd)
+	out, err := cmd.CombinedOutput()
+	done(err, out)
+	return out, err
+}
+
+// start logs that cmd is about to run and returns a function recording how
+// it finished
+func start(cmd *exec.Cmd) func(err error, output []byte) {
+	command := strings.Join(redact(cmd.Args), " ")
+	if verbose != nil {
+		echo(cmd)
+	}
+	if cmd.Dir != "" {
+		logger.Debug("exec", "command", command, "dir", cmd.Dir)
+	} else {
+		logger.Debug("exec", "command", command)
+	}
+	began := time.Now()
+
+	return func(err error, output []byte) {
+		attrs := []any{
+			"command", command,
+			"duration", time.Since(began).String(),
+			"exit_code", exitCode(err),
+		}
+		if err != nil {
+			attrs = append(attrs, "error", err.Error())
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+				attrs = append(attrs, "stderr", strings.TrimSpace(string(exitErr.Stderr)))
+			} else if len(output) > 0 {
+				attrs = append(attrs, "output", strings.TrimSpace(string(output)))
+			}
+			logger.Debug("exec failed", attrs...)
+			return
+		}
+		logger.Debug("exec done", attrs...)
+	}
+}
+
+// echo prints cmd as a command line that can be pasted into a shell
+func echo(cmd *exec.Cmd) {
+	args := redact(cmd.Args)
+	for i, arg := range args {
+		args[i] = shellQuote(arg)
+	}
+	line := strings.Join(args, " ")
+	if cmd.Dir != "" {
+		line = fmt.Sprintf("(cd %s && %s)", shellQuote(cmd.Dir), line)
+	}
+	fmt.Fprintf(verbose, "+ %s\n", line)
+}
+
+// shellQuote single-quotes arg unless it only contains characters the shell
+// leaves alone
+func shellQuote(arg string) string {
+	if arg == "" {
+		return "''"
+	}
+	if strings.IndexFunc(arg, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=@%+,", r))
+	}) < 0 {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// redact hides environment variable values passed with -e/--env, which
+// carry tokens and resolved secrets
+func redact(args []string) []string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		redacted[i] = arg
+		if i > 0 && (args[i-1] == "-e" || args[i-1] == "--env") {
+			redacted[i] = maskValue(arg)
+		} else if value, ok := strings.CutPrefix(arg, "--env="); ok {
+			redacted[i] = "--env=" + maskValue(value)
+		}
+	}
+	return redacted
+}
+
+// maskValue replaces the value of a KEY=value pair
+func maskValue(pair string) string {
+	if key, _, ok := strings.Cut(pair, "="); ok {
+		return key + "=***"
+	}
+	return pair
+}
+
+// exitCode returns the process exit code for err, or -1 when the command
+// could not be started or was killed
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
@@ -0,0 +1,180 @@
+// Package notify tells the user that a long operation finished, with a
+// desktop notification or a chat webhook
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// DefaultMinDuration is how long an operation must take before its success
+// is worth a notification
+const DefaultMinDuration = 30 * time.Second
+
+// webhookTimeout bounds how long a slow webhook can delay the command
+const webhookTimeout = 5 * time.Second
+
+// Notification is one message about a finished operation
+type Notification struct {
+	Title   string
+	Message string
+	Failed  bool
+}
+
+// Enabled reports whether any notification channel is configured
+func Enabled(cfg config.NotifyConfig) bool {
+	return cfg.Desktop || cfg.WebhookURL != ""
+}
+
+// Validate checks the notification settings
+func Validate(cfg config.NotifyConfig) error {
+	if _, err := minDuration(cfg); err != nil {
+		return err
+	}
+	switch cfg.WebhookFormat {
+	case "", "slack", "discord", "json":
+		return nil
+	default:
+		return fmt.Errorf("invalid notify.webhook_format %q (expected slack, discord or json)", cfg.WebhookFormat)
+	}
+}
+
+// ShouldNotify reports whether an operation that took elapsed deserves a
+// notification: failures always do, successes once they pass min_duration
+func ShouldNotify(cfg config.NotifyConfig, elapsed time.Duration, failed bool) bool {
+	if !Enabled(cfg) {
+		return false
+	}
+	if failed {
+		return true
+	}
+	threshold, err := minDuration(cfg)
+	if err != nil {
+		threshold = DefaultMinDuration
+	}
+	return elapsed >= threshold
+}
+
+// Send delivers n on every configured channel, returning the errors of the
+// channels that failed
+func Send(ctx context.Context, cfg config.NotifyConfig, n Notification) error {
+	var errs []error
+	if cfg.Desktop {
+		if err := sendDesktop(ctx, n); err != nil {
+			errs = append(errs, fmt.Errorf("desktop notification: %w", err))
+		}
+	}
+	if cfg.WebhookURL != "" {
+		if err := sendWebhook(ctx, cfg, n); err != nil {
+			errs = append(errs, fmt.Errorf("webhook notification: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// minDuration parses notify.min_duration
+func minDuration(cfg config.NotifyConfig) (time.Duration, error) {
+	if cfg.MinDuration == "" {
+		return DefaultMinDuration, nil
+	}
+	duration, err := time.ParseDuration(cfg.MinDuration)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid notify.min_duration %q (expected a duration like 30s or 2m)", cfg.MinDuration)
+	}
+	return duration, nil
+}
+
+// sendDesktop shows a native desktop notification
+func sendDesktop(ctx context.Context, n Notification) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux":
+		urgency := "normal"
+		if n.Failed {
+			urgency = "critical"
+		}
+		cmd = exec.CommandContext(ctx, "notify-send", "--app-name=cc-buddy", "--urgency="+urgency, n.Title, n.Message)
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(n.Message), appleScriptString(n.Title))
+		cmd = exec.CommandContext(ctx, "osascript", "-e", script)
+	default:
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+
+	if output, err := logging.CombinedOutput(cmd); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// sendWebhook posts n to the configured webhook
+func sendWebhook(ctx context.Context, cfg config.NotifyConfig, n Notification) error {
+	icon := "✅"
+	if n.Failed {
+		icon = "❌"
+	}
+
+	var payload any
+	switch webhookFormat(cfg) {
+	case "slack":
+		payload = map[string]string{"text": fmt.Sprintf("%s *%s*\n%s", icon, n.Title, n.Message)}
+	case "discord":
+		payload = map[string]string{"content": fmt.Sprintf("%s **%s**\n%s", icon, n.Title, n.Message)}
+	default:
+		payload = map[string]any{"title": n.Title, "message": n.Message, "failed": n.Failed}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// webhookFormat returns the configured format, or guesses it from the URL
+func webhookFormat(cfg config.NotifyConfig) string {
+	if cfg.WebhookFormat != "" {
+		return cfg.WebhookFormat
+	}
+	switch {
+	case strings.Contains(cfg.WebhookURL, "hooks.slack.com"):
+		return "slack"
+	case strings.Contains(cfg.WebhookURL, "discord.com/api/webhooks"), strings.Contains(cfg.WebhookURL, "discordapp.com/api/webhooks"):
+		return "discord"
+	default:
+		return "json"
+	}
+}
@@ -0,0 +1,139 @@
+package scaffold
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ContainerfileOptions are the choices made in the init prompts or wizard
+type ContainerfileOptions struct {
+	BaseImage  string
+	Username   string   // non-root user; defaults to DefaultUsername
+	Packages   []string // Debian package names, translated for other distros
+	Ports      []string
+	Volumes    []string
+	EnvVars    []string // KEY=value
+	Commands   []string // RUN steps
+	GPGSigning bool     // prepare ~/.gnupg for agent forwarding
+}
+
+// DefaultUsername is the non-root user generated images create
+const DefaultUsername = "developer"
+
+// usernamePattern matches portable POSIX user names
+var usernamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_-]*$`)
+
+// ValidUsername reports whether name can be used as the container user
+func ValidUsername(name string) bool {
+	return name != "root" && usernamePattern.MatchString(name)
+}
+
+// BaseImages are the base images offered by init, recommended first
+var BaseImages = []string{"ubuntu:22.04", "node:18", "python:3.11", "golang:1.21", "rust:1.70", "alpine:3.20", "fedora:40"}
+
+// GenerateContainerfile renders a Containerfile.dev with a non-root developer
+// user and the workspace ownership entrypoint cc-buddy expects. Package
+// installation and user setup follow the base image's distro family.
+func GenerateContainerfile(opts ContainerfileOptions) string {
+	var content strings.Builder
+	distro := DetectDistro(opts.BaseImage)
+
+	content.WriteString("# Development Container for cc-buddy\n")
+	content.WriteString("# Generated automatically - feel free to customize!\n\n")
+
+	// Base image
+	content.WriteString(fmt.Sprintf("FROM %s\n\n", opts.BaseImage))
+
+	// System packages - always include sudo for user sync functionality
+	allPackages := append(basePackages(distro), translatePackages(distro, opts.Packages)...)
+	if opts.GPGSigning {
+		allPackages = append(allPackages, "gnupg")
+	}
+	content.WriteString("# Install system packages\n")
+	content.WriteString(installPackages(distro, allPackages) + "\n")
+
+	// User synchronization setup
+	content.WriteString("# Create a non-root user with dynamic UID/GID matching host user\n")
+	username := opts.Username
+	if username == "" {
+		username = DefaultUsername
+	}
+	content.WriteString(fmt.Sprintf("ARG USERNAME=%s\n", username))
+	content.WriteString("ARG USER_UID=1000\n")
+	content.WriteString("ARG USER_GID=1000\n\n")
+
+	content.WriteString("# Create group and user with dynamic IDs\n")
+	content.WriteString(createUser(distro) + "\n")
+
+	// GPG agent forwarding - cc-buddy mounts the host agent socket and public
+	// keyring into ~/.gnupg at runtime when forward_gpg is enabled
+	if opts.GPGSigning {
+		content.WriteString("# Prepare ~/.gnupg for the forwarded gpg-agent socket (forward_gpg: true)\n")
+		content.WriteString("RUN mkdir -p /home/$USERNAME/.gnupg \\\n")
+		content.WriteString("    && chmod 700 /home/$USERNAME/.gnupg \\\n")
+		content.WriteString("    && chown $USERNAME:$USERNAME /home/$USERNAME/.gnupg\n\n")
+	}
+
+	// Environment variables
+	if len(opts.EnvVars) > 0 {
+		content.WriteString("# Environment variables\n")
+		for _, env := range opts.EnvVars {
+			content.WriteString(fmt.Sprintf("ENV %s\n", env))
+		}
+		content.WriteString("\n")
+	}
+
+	// Expose ports
+	if len(opts.Ports) > 0 {
+		content.WriteString("# Expose ports\n")
+		for _, port := range opts.Ports {
+			content.WriteString(fmt.Sprintf("EXPOSE %s\n", port))
+		}
+		content.WriteString("\n")
+	}
+
+	// Volume mount points
+	if len(opts.Volumes) > 0 {
+		content.WriteString("# Volume mount points\n")
+		for _, volume := range opts.Volumes {
+			content.WriteString(fmt.Sprintf("VOLUME %s\n", volume))
+		}
+		content.WriteString("\n")
+	}
+
+	// Startup commands (run as root before user switch)
+	if len(opts.Commands) > 0 {
+		content.WriteString("# Startup commands\n")
+		for _, cmd := range opts.Commands {
+			content.WriteString(fmt.Sprintf("RUN %s\n", cmd))
+		}
+		content.WriteString("\n")
+	}
+
+	// Create workspace ownership fix script; printf rather than echo because
+	// only dash's echo expands \n (bash and BusyBox print it literally)
+	content.WriteString("# Create workspace ownership fix script\n")
+	content.WriteString("RUN printf '#!/bin/bash\\n\\\n")
+	content.WriteString("# Fix workspace ownership to match container user\\n\\\n")
+	content.WriteString("if [ -d \"/workspace\" ]; then\\n\\\n")
+	content.WriteString("    sudo chown -R \"$(id -u):$(id -g)\" /workspace || true\\n\\\n")
+	content.WriteString("fi\\n\\\n")
+	content.WriteString("# Execute the original command\\n\\\n")
+	content.WriteString("exec \"$@\"' > /usr/local/bin/fix-workspace-ownership.sh \\\n")
+	content.WriteString("    && chmod +x /usr/local/bin/fix-workspace-ownership.sh\n\n")
+
+	// Switch to non-root user
+	content.WriteString("USER $USERNAME\n\n")
+
+	// Working directory
+	content.WriteString("# Set working directory\n")
+	content.WriteString("WORKDIR /workspace\n\n")
+
+	// Use the ownership fix script as entrypoint
+	content.WriteString("# Use the ownership fix script as entrypoint\n")
+	content.WriteString("ENTRYPOINT [\"/usr/local/bin/fix-workspace-ownership.sh\"]\n")
+	content.WriteString("CMD [\"tail\", \"-f\", \"/dev/null\"]\n")
+
+	return content.String()
+}
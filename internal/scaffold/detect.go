@@ -0,0 +1,178 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Project describes a detected project type and the image settings it needs
+type Project struct {
+	Type      string   // "go", "node", "python" or "rust"
+	Marker    string   // file that identified the project, e.g. "go.mod"
+	BaseImage string   // suggested base image
+	Packages  []string // suggested system packages
+	Ports     []string // ports the project's dev server usually listens on
+	Toolchain []string // apt packages providing the toolchain on another base image
+}
+
+// detector recognizes one project type from its marker file
+type detector struct {
+	markers []string
+	detect  func(dir, marker string) *Project
+}
+
+// detectors are tried in order; the first match is the primary project type
+var detectors = []detector{
+	{markers: []string{"go.mod"}, detect: detectGo},
+	{markers: []string{"package.json"}, detect: detectNode},
+	{markers: []string{"pyproject.toml", "requirements.txt", "setup.py"}, detect: detectPython},
+	{markers: []string{"Cargo.toml"}, detect: detectRust},
+}
+
+// Detect scans dir for known project files and returns every project type
+// found, most specific first. It returns nil when nothing is recognized.
+func Detect(dir string) []*Project {
+	var projects []*Project
+	for _, d := range detectors {
+		for _, marker := range d.markers {
+			if _, err := os.Stat(filepath.Join(dir, marker)); err != nil {
+				continue
+			}
+			projects = append(projects, d.detect(dir, marker))
+			break
+		}
+	}
+	return projects
+}
+
+var goVersionPattern = regexp.MustCompile(`(?m)^go\s+(\d+\.\d+)`)
+
+func detectGo(dir, marker string) *Project {
+	image := "golang:1.24"
+	if data, err := os.ReadFile(filepath.Join(dir, marker)); err == nil {
+		if match := goVersionPattern.FindSubmatch(data); match != nil {
+			image = "golang:" + string(match[1])
+		}
+	}
+	return &Project{
+		Type:      "go",
+		Marker:    marker,
+		BaseImage: image,
+		Packages:  []string{"git", "curl", "make"},
+		Ports:     []string{"8080"},
+		Toolchain: []string{"golang-go"},
+	}
+}
+
+var majorVersionPattern = regexp.MustCompile(`\d+`)
+
+func detectNode(dir, marker string) *Project {
+	project := &Project{
+		Type:      "node",
+		Marker:    marker,
+		BaseImage: "node:20",
+		Packages:  []string{"git", "curl"},
+		Ports:     []string{"3000"},
+		Toolchain: []string{"nodejs", "npm"},
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, marker))
+	if err != nil {
+		return project
+	}
+	var pkg struct {
+		Engines         map[string]string `json:"engines"`
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return project
+	}
+
+	// Use the lowest major version the engines range mentions
+	if major := majorVersionPattern.FindString(pkg.Engines["node"]); major != "" {
+		project.BaseImage = "node:" + major
+	}
+	// Vite serves on 5173; Next.js, Express and most others on 3000
+	if _, ok := pkg.DevDependencies["vite"]; ok {
+		project.Ports = []string{"5173"}
+	} else if _, ok := pkg.Dependencies["vite"]; ok {
+		project.Ports = []string{"5173"}
+	}
+	return project
+}
+
+var requiresPythonPattern = regexp.MustCompile(`requires-python\s*=\s*"[^"\d]*(\d+\.\d+)`)
+
+func detectPython(dir, marker string) *Project {
+	image := "python:3.12"
+	if marker == "pyproject.toml" {
+		if data, err := os.ReadFile(filepath.Join(dir, marker)); err == nil {
+			if match := requiresPythonPattern.FindSubmatch(data); match != nil {
+				image = "python:" + string(match[1])
+			}
+		}
+	}
+	return &Project{
+		Type:      "python",
+		Marker:    marker,
+		BaseImage: image,
+		Packages:  []string{"git", "curl", "build-essential"},
+		Ports:     []string{"8000"},
+		Toolchain: []string{"python3", "python3-pip", "python3-venv"},
+	}
+}
+
+var rustVersionPattern = regexp.MustCompile(`(?m)^rust-version\s*=\s*"(\d+\.\d+)`)
+
+func detectRust(dir, marker string) *Project {
+	image := "rust:1"
+	if data, err := os.ReadFile(filepath.Join(dir, marker)); err == nil {
+		if match := rustVersionPattern.FindSubmatch(data); match != nil {
+			image = "rust:" + string(match[1])
+		}
+	}
+	return &Project{
+		Type:      "rust",
+		Marker:    marker,
+		BaseImage: image,
+		Packages:  []string{"git", "curl", "pkg-config", "libssl-dev"},
+		Ports:     []string{"8080"},
+		Toolchain: []string{"cargo"},
+	}
+}
+
+// SuggestedPackages returns the primary project's packages plus the
+// toolchains of any other detected project types, without duplicates
+func SuggestedPackages(projects []*Project) []string {
+	var packages []string
+	seen := make(map[string]bool)
+	add := func(names []string) {
+		for _, name := range names {
+			if !seen[name] {
+				seen[name] = true
+				packages = append(packages, name)
+			}
+		}
+	}
+	for i, project := range projects {
+		if i == 0 {
+			add(project.Packages)
+		} else {
+			add(project.Toolchain)
+		}
+	}
+	return packages
+}
+
+// Describe formats detected projects for display, e.g. "go (go.mod), node (package.json)"
+func Describe(projects []*Project) string {
+	parts := make([]string, len(projects))
+	for i, project := range projects {
+		parts[i] = project.Type + " (" + project.Marker + ")"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,92 @@
+package scaffold
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Devcontainer file locations
+const (
+	DevcontainerDir  = ".devcontainer"
+	DevcontainerFile = "devcontainer.json"
+)
+
+// devcontainer is the subset of the Development Container spec cc-buddy writes
+type devcontainer struct {
+	Name            string            `json:"name"`
+	Build           devcontainerBuild `json:"build"`
+	WorkspaceFolder string            `json:"workspaceFolder"`
+	WorkspaceMount  string            `json:"workspaceMount"`
+	RemoteUser      string            `json:"remoteUser"`
+	ForwardPorts    []int             `json:"forwardPorts,omitempty"`
+	ContainerEnv    map[string]string `json:"containerEnv,omitempty"`
+}
+
+type devcontainerBuild struct {
+	Dockerfile string `json:"dockerfile"`
+	Context    string `json:"context"`
+}
+
+// GenerateDevcontainer returns a devcontainer.json that builds containerfile,
+// so VS Code and Codespaces use the same image as cc-buddy. dockerfile and
+// buildContext are relative to the .devcontainer directory. Forwarded ports
+// and container environment are read from the Containerfile's EXPOSE and ENV
+// instructions.
+func GenerateDevcontainer(name, dockerfile, buildContext string, containerfile []byte) ([]byte, error) {
+	dc := devcontainer{
+		Name: name,
+		Build: devcontainerBuild{
+			Dockerfile: dockerfile,
+			Context:    buildContext,
+		},
+		// Match the /workspace mount cc-buddy uses
+		WorkspaceFolder: "/workspace",
+		WorkspaceMount:  "source=${localWorkspaceFolder},target=/workspace,type=bind",
+		RemoteUser:      "developer",
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(containerfile))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "EXPOSE":
+			for _, field := range fields[1:] {
+				port, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSuffix(field, "/tcp"), "/udp"))
+				if err == nil {
+					dc.ForwardPorts = append(dc.ForwardPorts, port)
+				}
+			}
+		case "ENV":
+			if dc.ContainerEnv == nil {
+				dc.ContainerEnv = make(map[string]string)
+			}
+			if !strings.Contains(fields[1], "=") {
+				// Legacy "ENV KEY value" form
+				dc.ContainerEnv[fields[1]] = strings.Join(fields[2:], " ")
+				continue
+			}
+			for _, field := range fields[1:] {
+				if key, value, ok := strings.Cut(field, "="); ok {
+					dc.ContainerEnv[key] = strings.Trim(value, `"`)
+				}
+			}
+		case "ARG":
+			// Connect as the Containerfile's non-root user
+			if key, value, ok := strings.Cut(fields[1], "="); ok && key == "USERNAME" {
+				dc.RemoteUser = value
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(dc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,134 @@
+package scaffold
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Distro families the Containerfile generator knows how to install packages on
+const (
+	DistroDebian = "debian" // apt-get: debian, ubuntu and most language images
+	DistroAlpine = "alpine" // apk
+	DistroFedora = "fedora" // dnf: fedora, centos, rhel/ubi, rocky, alma, amazonlinux
+)
+
+// fedoraImages are image names (or name fragments) built on a dnf-based distro
+var fedoraImages = []string{"fedora", "centos", "rhel", "ubi", "rockylinux", "almalinux", "amazonlinux", "oraclelinux"}
+
+// DetectDistro guesses the distro family of a base image from its name and
+// tag, e.g. "node:20-alpine" is alpine and "registry.fedoraproject.org/fedora:40"
+// is fedora. Unrecognized images are assumed to be Debian-based, like the
+// official language images.
+func DetectDistro(image string) string {
+	// Ignore the registry host, keeping the repository path and tag
+	ref := strings.ToLower(image)
+	if slash := strings.Index(ref, "/"); slash >= 0 && strings.ContainsAny(ref[:slash], ".:") {
+		ref = ref[slash+1:]
+	}
+
+	if strings.Contains(ref, "alpine") {
+		return DistroAlpine
+	}
+	name, _, _ := strings.Cut(ref, ":")
+	for _, fragment := range fedoraImages {
+		if strings.Contains(name, fragment) {
+			return DistroFedora
+		}
+	}
+	return DistroDebian
+}
+
+// packageNames translates Debian package names whose equivalents differ on
+// other distros; an empty name means the package is not needed there
+var packageNames = map[string]map[string]string{
+	DistroAlpine: {
+		"build-essential": "build-base",
+		"libssl-dev":      "openssl-dev",
+		"pkg-config":      "pkgconf",
+		"golang-go":       "go",
+		"python3-pip":     "py3-pip",
+		"python3-venv":    "",
+	},
+	DistroFedora: {
+		"build-essential": "gcc gcc-c++ make",
+		"libssl-dev":      "openssl-devel",
+		"pkg-config":      "pkgconf-pkg-config",
+		"golang-go":       "golang",
+		"python3-venv":    "",
+		"postgresql":      "postgresql postgresql-server",
+	},
+}
+
+// translatePackages maps Debian package names to the distro's names
+func translatePackages(distro string, packages []string) []string {
+	names := packageNames[distro]
+	var translated []string
+	for _, pkg := range packages {
+		if name, ok := names[pkg]; ok {
+			translated = append(translated, strings.Fields(name)...)
+		} else {
+			translated = append(translated, pkg)
+		}
+	}
+	return translated
+}
+
+// installPackages returns the RUN instruction installing packages
+func installPackages(distro string, packages []string) string {
+	var b strings.Builder
+	switch distro {
+	case DistroAlpine:
+		b.WriteString("RUN apk add --no-cache \\\n")
+		for i, pkg := range packages {
+			if i == len(packages)-1 {
+				b.WriteString(fmt.Sprintf("    %s\n", pkg))
+			} else {
+				b.WriteString(fmt.Sprintf("    %s \\\n", pkg))
+			}
+		}
+	case DistroFedora:
+		b.WriteString("RUN dnf install -y \\\n")
+		for _, pkg := range packages {
+			b.WriteString(fmt.Sprintf("    %s \\\n", pkg))
+		}
+		b.WriteString("    && dnf clean all\n")
+	default:
+		b.WriteString("RUN apt-get update && apt-get install -y \\\n")
+		for _, pkg := range packages {
+			b.WriteString(fmt.Sprintf("    %s \\\n", pkg))
+		}
+		b.WriteString("    && rm -rf /var/lib/apt/lists/*\n")
+	}
+	return b.String()
+}
+
+// basePackages are installed on every image: sudo for the workspace
+// ownership fix, plus what the distro's minimal image lacks for the user
+// setup and the bash entrypoint
+func basePackages(distro string) []string {
+	switch distro {
+	case DistroAlpine:
+		return []string{"sudo", "bash"}
+	case DistroFedora:
+		return []string{"sudo", "shadow-utils"}
+	default:
+		return []string{"sudo"}
+	}
+}
+
+// createUser returns the RUN instruction creating the non-root user with
+// passwordless sudo
+func createUser(distro string) string {
+	var b strings.Builder
+	if distro == DistroAlpine {
+		// BusyBox user tools; -D skips the password and -s keeps bash as the shell
+		b.WriteString("RUN addgroup -g $USER_GID $USERNAME \\\n")
+		b.WriteString("    && adduser -u $USER_UID -G $USERNAME -s /bin/bash -D $USERNAME \\\n")
+	} else {
+		b.WriteString("RUN groupadd --gid $USER_GID $USERNAME \\\n")
+		b.WriteString("    && useradd --uid $USER_UID --gid $USER_GID -m $USERNAME \\\n")
+	}
+	b.WriteString("    && echo $USERNAME ALL=\\(root\\) NOPASSWD:ALL > /etc/sudoers.d/$USERNAME \\\n")
+	b.WriteString("    && chmod 0440 /etc/sudoers.d/$USERNAME\n")
+	return b.String()
+}
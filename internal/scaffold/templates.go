@@ -0,0 +1,171 @@
+package scaffold
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// Template files inside a template directory
+const (
+	TemplateContainerfile = "Containerfile.dev"
+	TemplateConfig        = "config.json" // optional recommended configuration
+)
+
+//go:embed templates
+var embeddedTemplates embed.FS
+
+// Template is a complete Containerfile.dev plus recommended configuration
+type Template struct {
+	Name          string
+	Description   string // first comment line of the Containerfile
+	Source        string // "built-in", a directory or a git URL
+	Containerfile []byte
+	Config        []byte // JSON merged into .cc-buddy/config.json; may be empty
+}
+
+// UserTemplateDir returns the directory searched for user templates,
+// ~/.config/cc-buddy/templates on Linux
+func UserTemplateDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine config directory: %w", err)
+	}
+	return filepath.Join(configDir, "cc-buddy", "templates"), nil
+}
+
+// LoadTemplate resolves ref to a template. A git URL (optionally with
+// "#subdir") is cloned; otherwise ref names a template in the user template
+// directory, falling back to the built-in templates.
+func LoadTemplate(ctx context.Context, ref string) (*Template, error) {
+	if isGitURL(ref) {
+		return loadGitTemplate(ctx, ref)
+	}
+
+	if userDir, err := UserTemplateDir(); err == nil {
+		dir := filepath.Join(userDir, ref)
+		if _, err := os.Stat(filepath.Join(dir, TemplateContainerfile)); err == nil {
+			return loadTemplate(os.DirFS(dir), ref, dir)
+		}
+	}
+
+	builtin, err := fs.Sub(embeddedTemplates, "templates/"+ref)
+	if err != nil || !fileExists(builtin, TemplateContainerfile) {
+		return nil, fmt.Errorf("unknown template %q (run 'cc-buddy init --list-templates')", ref)
+	}
+	return loadTemplate(builtin, ref, "built-in")
+}
+
+// ListTemplates returns the built-in and user templates, user templates
+// shadowing built-in ones of the same name
+func ListTemplates() ([]*Template, error) {
+	byName := make(map[string]*Template)
+
+	entries, err := fs.ReadDir(embeddedTemplates, "templates")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read built-in templates: %w", err)
+	}
+	for _, entry := range entries {
+		builtin, err := fs.Sub(embeddedTemplates, "templates/"+entry.Name())
+		if err != nil {
+			continue
+		}
+		if tmpl, err := loadTemplate(builtin, entry.Name(), "built-in"); err == nil {
+			byName[tmpl.Name] = tmpl
+		}
+	}
+
+	if userDir, err := UserTemplateDir(); err == nil {
+		entries, _ := os.ReadDir(userDir) // the directory is optional
+		for _, entry := range entries {
+			dir := filepath.Join(userDir, entry.Name())
+			if tmpl, err := loadTemplate(os.DirFS(dir), entry.Name(), dir); err == nil {
+				byName[tmpl.Name] = tmpl
+			}
+		}
+	}
+
+	templates := make([]*Template, 0, len(byName))
+	for _, tmpl := range byName {
+		templates = append(templates, tmpl)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// loadTemplate reads a template from a directory
+func loadTemplate(fsys fs.FS, name, source string) (*Template, error) {
+	containerfile, err := fs.ReadFile(fsys, TemplateContainerfile)
+	if err != nil {
+		return nil, fmt.Errorf("template %s has no %s: %w", name, TemplateContainerfile, err)
+	}
+
+	tmpl := &Template{
+		Name:          name,
+		Description:   describeContainerfile(containerfile),
+		Source:        source,
+		Containerfile: containerfile,
+	}
+	if fileExists(fsys, TemplateConfig) {
+		if tmpl.Config, err = fs.ReadFile(fsys, TemplateConfig); err != nil {
+			return nil, fmt.Errorf("failed to read %s from template %s: %w", TemplateConfig, name, err)
+		}
+	}
+	return tmpl, nil
+}
+
+// loadGitTemplate shallow-clones a template repository into a temporary
+// directory and loads the template at its root or at the "#subdir" fragment
+func loadGitTemplate(ctx context.Context, ref string) (*Template, error) {
+	url, subdir, _ := strings.Cut(ref, "#")
+
+	tmpDir, err := os.MkdirTemp("", "cc-buddy-template-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cmd := exec.CommandContext(ctx, "git", "clone", "--depth", "1", "--quiet", url, tmpDir)
+	if output, err := logging.CombinedOutput(cmd); err != nil {
+		return nil, fmt.Errorf("failed to clone template %s: %w: %s", url, err, strings.TrimSpace(string(output)))
+	}
+
+	dir := filepath.Join(tmpDir, filepath.FromSlash(subdir))
+	name := filepath.Base(strings.TrimSuffix(url, ".git"))
+	if subdir != "" {
+		name = filepath.Base(subdir)
+	}
+	return loadTemplate(os.DirFS(dir), name, ref)
+}
+
+// isGitURL reports whether ref points at a git repository rather than a template name
+func isGitURL(ref string) bool {
+	return strings.Contains(ref, "://") || strings.HasPrefix(ref, "git@") || strings.HasSuffix(strings.SplitN(ref, "#", 2)[0], ".git")
+}
+
+// describeContainerfile returns the text of the Containerfile's first comment line
+func describeContainerfile(containerfile []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(containerfile))
+	if scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "#") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "#"))
+		}
+	}
+	return ""
+}
+
+// fileExists reports whether name exists in fsys
+func fileExists(fsys fs.FS, name string) bool {
+	_, err := fs.Stat(fsys, name)
+	return err == nil
+}
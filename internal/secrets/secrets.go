@@ -0,0 +1,179 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+)
+
+// Scheme is the prefix used to reference secrets in configuration
+const Scheme = "secret://"
+
+// Provider resolves secret paths for a single secret backend
+type Provider interface {
+	// Name returns the provider identifier used in secret references
+	Name() string
+
+	// Resolve returns the secret value stored at path
+	Resolve(ctx context.Context, path string) (string, error)
+}
+
+// providers holds the registered secret backends keyed by name
+var providers = map[string]Provider{}
+
+func init() {
+	Register(&OnePasswordProvider{})
+	Register(&VaultProvider{})
+	Register(&PassProvider{})
+}
+
+// Register makes a provider available for secret references
+func Register(p Provider) {
+	providers[p.Name()] = p
+}
+
+// IsReference reports whether value is a secret:// reference
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, Scheme)
+}
+
+// ParseReference splits a secret reference into provider name and path
+func ParseReference(ref string) (provider, path string, err error) {
+	if !IsReference(ref) {
+		return "", "", fmt.Errorf("invalid secret reference %q: must start with %s", ref, Scheme)
+	}
+
+	rest := strings.TrimPrefix(ref, Scheme)
+	parts := strings.SplitN(rest, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid secret reference %q: expected %s<provider>/<path>", ref, Scheme)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// Resolve resolves a single secret reference to its value
+func Resolve(ctx context.Context, ref string) (string, error) {
+	providerName, path, err := ParseReference(ref)
+	if err != nil {
+		return "", err
+	}
+
+	provider, ok := providers[providerName]
+	if !ok {
+		return "", fmt.Errorf("unknown secret provider %q in %s (available: %s)", providerName, ref, strings.Join(providerNames(), ", "))
+	}
+
+	value, err := provider.Resolve(ctx, path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+
+	return value, nil
+}
+
+// ResolveAll resolves a map of environment variable names to secret references.
+// The returned map holds the resolved values and must never be persisted.
+func ResolveAll(ctx context.Context, refs map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(refs))
+
+	// Resolve in a stable order so errors are reproducible
+	names := make([]string, 0, len(refs))
+	for name := range refs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value, err := Resolve(ctx, refs[name])
+		if err != nil {
+			return nil, fmt.Errorf("secret %s: %w", name, err)
+		}
+		resolved[name] = value
+	}
+
+	return resolved, nil
+}
+
+// providerNames returns the sorted list of registered provider names
+func providerNames() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// runSecretCommand runs a provider CLI and returns its trimmed stdout
+func runSecretCommand(ctx context.Context, name string, args ...string) (string, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return "", fmt.Errorf("%s CLI not found in PATH", name)
+	}
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	out, err := logging.Output(cmd)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%s: %s", name, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// OnePasswordProvider resolves secrets with the 1Password CLI.
+// References look like secret://op/<vault>/<item>/<field>.
+type OnePasswordProvider struct{}
+
+func (p *OnePasswordProvider) Name() string {
+	return "op"
+}
+
+func (p *OnePasswordProvider) Resolve(ctx context.Context, path string) (string, error) {
+	if strings.Count(path, "/") < 2 {
+		return "", fmt.Errorf("1Password references must be <vault>/<item>/<field>")
+	}
+	return runSecretCommand(ctx, "op", "read", "op://"+path)
+}
+
+// VaultProvider resolves secrets from HashiCorp Vault's KV engine.
+// References look like secret://vault/<mount>/<path>/<field>.
+type VaultProvider struct{}
+
+func (p *VaultProvider) Name() string {
+	return "vault"
+}
+
+func (p *VaultProvider) Resolve(ctx context.Context, path string) (string, error) {
+	idx := strings.LastIndex(path, "/")
+	if idx <= 0 || idx == len(path)-1 {
+		return "", fmt.Errorf("vault references must be <path>/<field>")
+	}
+	secretPath, field := path[:idx], path[idx+1:]
+	return runSecretCommand(ctx, "vault", "kv", "get", "-field="+field, secretPath)
+}
+
+// PassProvider resolves secrets from the standard unix password store.
+// References look like secret://pass/<entry>; only the first line is used.
+type PassProvider struct{}
+
+func (p *PassProvider) Name() string {
+	return "pass"
+}
+
+func (p *PassProvider) Resolve(ctx context.Context, path string) (string, error) {
+	out, err := runSecretCommand(ctx, "pass", "show", path)
+	if err != nil {
+		return "", err
+	}
+	firstLine, _, _ := strings.Cut(out, "\n")
+	return firstLine, nil
+}
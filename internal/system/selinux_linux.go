@@ -0,0 +1,37 @@
+//go:build linux
+
+package system
+
+import (
+	"os"
+	"syscall"
+)
+
+// Filesystem magic numbers for network filesystems that reject SELinux relabeling
+const (
+	nfsSuperMagic  = 0x6969
+	cifsMagic      = 0xFF534D42
+	smb2MagicNum   = 0xFE534D42
+	fuseSuperMagic = 0x65735546
+)
+
+// SELinuxEnabled reports whether SELinux is enabled (enforcing or permissive)
+func SELinuxEnabled() bool {
+	_, err := os.Stat("/sys/fs/selinux/enforce")
+	return err == nil
+}
+
+// IsNetworkFilesystem reports whether path lives on NFS, SMB or a FUSE mount,
+// where relabeling with :Z fails
+func IsNetworkFilesystem(path string) bool {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return false
+	}
+	switch uint32(stat.Type) {
+	case nfsSuperMagic, cifsMagic, smb2MagicNum, fuseSuperMagic:
+		return true
+	default:
+		return false
+	}
+}
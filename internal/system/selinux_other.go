@@ -0,0 +1,14 @@
+//go:build !linux
+
+package system
+
+// SELinuxEnabled reports whether SELinux is enabled; it never is off Linux
+func SELinuxEnabled() bool {
+	return false
+}
+
+// IsNetworkFilesystem reports whether path lives on a network filesystem.
+// Only relevant to SELinux relabeling, so it is not detected off Linux.
+func IsNetworkFilesystem(path string) bool {
+	return false
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/user"
+	"runtime"
 	"strconv"
 )
 
@@ -15,6 +16,12 @@ type UserInfo struct {
 
 // GetCurrentUser returns the current user's UID and GID
 func GetCurrentUser() (*UserInfo, error) {
+	// Windows identifies users by SID rather than numeric IDs; Docker Desktop
+	// maps bind mount ownership itself, so the defaults (or UID/GID) are used
+	if runtime.GOOS == "windows" {
+		return GetCurrentUserFromEnv(), nil
+	}
+	
 	currentUser, err := user.Current()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current user: %w", err)
@@ -0,0 +1,20 @@
+//go:build linux
+
+package system
+
+import (
+	"os"
+	"strings"
+)
+
+// IsWSL reports whether cc-buddy runs inside a WSL distribution
+func IsWSL() bool {
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	release, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(release)), "microsoft")
+}
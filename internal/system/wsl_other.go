@@ -0,0 +1,8 @@
+//go:build !linux
+
+package system
+
+// IsWSL reports whether cc-buddy runs inside a WSL distribution; WSL is Linux
+func IsWSL() bool {
+	return false
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// EnvironmentAction is something the action menu can do to an environment
+type EnvironmentAction string
+
+const (
+	ActionTerminal EnvironmentAction = "terminal"
+	ActionStart    EnvironmentAction = "start"
+	ActionStop     EnvironmentAction = "stop"
+	ActionRestart  EnvironmentAction = "restart"
+	ActionRebuild  EnvironmentAction = "rebuild"
+	ActionLogs     EnvironmentAction = "logs"
+	ActionBrowse   EnvironmentAction = "browse"
+	ActionCopyName EnvironmentAction = "copy-name"
+	ActionDelete   EnvironmentAction = "delete"
+)
+
+// actionDescriptions describes each action in the menu
+var actionDescriptions = map[EnvironmentAction]string{
+	ActionTerminal: "Open a terminal in the container",
+	ActionStart:    "Start the stopped container",
+	ActionStop:     "Stop the container, keeping everything",
+	ActionRestart:  "Stop and start the container",
+	ActionRebuild:  "Rebuild the image and replace the container",
+	ActionLogs:     "Show the container's output",
+	ActionBrowse:   "Open the web service in the browser",
+	ActionCopyName: "Copy the environment name to the clipboard",
+	ActionDelete:   "Delete the environment",
+}
+
+// ActionMenuModel lists what can be done to one environment
+type ActionMenuModel struct {
+	table       table.Model
+	environment string
+	actions     []EnvironmentAction
+	width       int
+	height      int
+}
+
+// OpenActionMenuMsg requests the action menu for an environment
+type OpenActionMenuMsg struct {
+	Environment string
+	Status      string
+}
+
+// ActionMenuClosedMsg is sent when the user leaves the action menu
+type ActionMenuClosedMsg struct{}
+
+// RunActionMsg requests running an action on an environment
+type RunActionMsg struct {
+	Environment string
+	Action      EnvironmentAction
+}
+
+// NewActionMenuModel creates the action menu for envName, offering start or
+// stop and restart depending on status
+func NewActionMenuModel(envName, status string) *ActionMenuModel {
+	actions := []EnvironmentAction{ActionTerminal}
+	if status == "running" {
+		actions = append(actions, ActionStop, ActionRestart, ActionBrowse)
+	} else {
+		actions = append(actions, ActionStart)
+	}
+	actions = append(actions, ActionRebuild, ActionLogs, ActionCopyName, ActionDelete)
+
+	columns := []table.Column{
+		{Title: "Action", Width: 12},
+		{Title: "Description", Width: 50},
+	}
+
+	rows := make([]table.Row, 0, len(actions))
+	for _, action := range actions {
+		rows = append(rows, table.Row{string(action), actionDescriptions[action]})
+	}
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithFocused(true),
+		table.WithHeight(len(rows)+1),
+	)
+
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Bold(false)
+	t.SetStyles(s)
+
+	return &ActionMenuModel{
+		table:       t,
+		environment: envName,
+		actions:     actions,
+	}
+}
+
+// Update handles navigation, running the selected action and closing
+func (m *ActionMenuModel) Update(msg tea.Msg) (*ActionMenuModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			return m, func() tea.Msg { return ActionMenuClosedMsg{} }
+		case "enter":
+			if i := m.table.Cursor(); i >= 0 && i < len(m.actions) {
+				run := RunActionMsg{Environment: m.environment, Action: m.actions[i]}
+				return m, func() tea.Msg { return run }
+			}
+			return m, nil
+		}
+	}
+
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+// View renders the action table
+func (m *ActionMenuModel) View() string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("205")).
+		Render("Actions")
+
+	subtitle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Render(fmt.Sprintf("For %s", m.environment))
+
+	footer := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Render("[↑↓] select  [enter] run  [esc] back")
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, subtitle, "", m.table.View(), "", footer)
+}
+
+// SetSize updates the model size
+func (m *ActionMenuModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
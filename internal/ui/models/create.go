@@ -4,12 +4,16 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/container"
 	"github.com/jhjaggars/cc-buddy/internal/environment"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
+	"github.com/jhjaggars/cc-buddy/internal/utils"
 )
 
 // CreateWizardModel handles the environment creation wizard
@@ -24,6 +28,9 @@ type CreateWizardModel struct {
 	branchInput     textinput.Model
 	branchType      int // 0=new, 1=existing local, 2=remote
 	remoteInput     textinput.Model
+	startupInput    textinput.Model // startup command, like create -e
+	exposeAll       bool            // publish every port the image exposes
+	portsInput      textinput.Model // explicit mappings, like create --port
 	worktreeInput   textinput.Model
 	
 	// UI state
@@ -34,6 +41,9 @@ type CreateWizardModel struct {
 	
 	// Options
 	options environment.CreateEnvironmentOptions
+	
+	// operations tracks creates for the operations view
+	operations *utils.OperationManager
 }
 
 // CreateProgressMsg represents progress during environment creation
@@ -45,6 +55,13 @@ type CreateProgressMsg struct {
 	Environment *config.Environment
 }
 
+// CreateStartedMsg is sent when the wizard starts creating an environment,
+// with each step's expected duration when earlier creates recorded them
+type CreateStartedMsg struct {
+	Branch    string
+	Estimates []time.Duration
+}
+
 // NewCreateWizardModel creates a new creation wizard
 func NewCreateWizardModel() *CreateWizardModel {
 	envManager, err := environment.NewManager()
@@ -61,22 +78,45 @@ func NewCreateWizardModel() *CreateWizardModel {
 	remoteInput.CharLimit = 50
 	remoteInput.Width = 30
 	
+	startupInput := textinput.New()
+	startupInput.Placeholder = "Leave empty for the image's default command"
+	startupInput.CharLimit = 500
+	startupInput.Width = 50
+	
+	portsInput := textinput.New()
+	portsInput.Placeholder = "e.g. 8080:3000 5432 5353/udp"
+	portsInput.CharLimit = 200
+	portsInput.Width = 50
+	
 	worktreeInput := textinput.New()
 	worktreeInput.Placeholder = "Leave empty for default"
 	worktreeInput.CharLimit = 200
 	worktreeInput.Width = 50
 	
+	exposeAll := false
+	if envManager != nil {
+		exposeAll = envManager.GetConfig().GetConfig().ExposeAll
+	}
+	
 	return &CreateWizardModel{
 		envManager:   envManager,
 		step:         0,
-		totalSteps:   3,
+		totalSteps:   5,
 		branchInput:  branchInput,
 		remoteInput:  remoteInput,
+		startupInput: startupInput,
+		exposeAll:    exposeAll,
+		portsInput:   portsInput,
 		worktreeInput: worktreeInput,
 		err:          err,
 	}
 }
 
+// SetOperationManager records creates as operations
+func (m *CreateWizardModel) SetOperationManager(operations *utils.OperationManager) {
+	m.operations = operations
+}
+
 // Init implements tea.Model
 func (m *CreateWizardModel) Init() tea.Cmd {
 	if m.envManager == nil {
@@ -111,6 +151,10 @@ func (m *CreateWizardModel) Update(msg tea.Msg) (*CreateWizardModel, tea.Cmd) {
 					m.focused = (m.focused - 1 + 4) % 4
 				}
 				m.updateFocus()
+			} else if m.step == 3 {
+				// Step 3: expose-all toggle + mappings input
+				m.focused = (m.focused + 1) % 2
+				m.updateFocus()
 			}
 			
 		case "enter":
@@ -132,6 +176,8 @@ func (m *CreateWizardModel) Update(msg tea.Msg) (*CreateWizardModel, tea.Cmd) {
 			if m.step == 0 && m.focused < 3 {
 				m.branchType = m.focused
 				m.updateFocus()
+			} else if m.step == 3 && m.focused == 0 {
+				m.exposeAll = !m.exposeAll
 			}
 		}
 
@@ -159,6 +205,16 @@ func (m *CreateWizardModel) Update(msg tea.Msg) (*CreateWizardModel, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 	case 2:
+		if m.focused == 0 {
+			m.startupInput, cmd = m.startupInput.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+	case 3:
+		if m.focused == 1 {
+			m.portsInput, cmd = m.portsInput.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+	case 4:
 		if m.focused == 0 {
 			m.worktreeInput, cmd = m.worktreeInput.Update(msg)
 			cmds = append(cmds, cmd)
@@ -204,6 +260,10 @@ func (m *CreateWizardModel) View() string {
 	case 1:
 		b.WriteString(m.renderRemoteStep())
 	case 2:
+		b.WriteString(m.renderStartupStep())
+	case 3:
+		b.WriteString(m.renderPortsStep())
+	case 4:
 		b.WriteString(m.renderConfigStep())
 	}
 	
@@ -306,6 +366,52 @@ func (m *CreateWizardModel) renderRemoteStep() string {
 	return b.String()
 }
 
+// renderStartupStep renders the startup command step
+func (m *CreateWizardModel) renderStartupStep() string {
+	var b strings.Builder
+	
+	b.WriteString("Startup Command\n\n")
+	b.WriteString("Command to run when the container starts (optional):\n")
+	b.WriteString(m.startupInput.View())
+	b.WriteString("\n\n")
+	b.WriteString(lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Render("Quote arguments containing spaces, e.g. python -m http.server 8000"))
+	
+	return b.String()
+}
+
+// renderPortsStep renders the port exposure step
+func (m *CreateWizardModel) renderPortsStep() string {
+	var b strings.Builder
+	
+	b.WriteString("Ports\n\n")
+	
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	marker := "☐"
+	if m.exposeAll {
+		style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+		marker = "☑"
+	}
+	focused := ""
+	if m.focused == 0 {
+		focused = " <"
+	}
+	b.WriteString(fmt.Sprintf("  %s %s%s\n\n",
+		style.Render(marker),
+		style.Render("Expose all ports the image declares (random host ports)"),
+		focused))
+	
+	b.WriteString("Port mappings, [[ip:]host:]container[/protocol] (optional):\n")
+	b.WriteString(m.portsInput.View())
+	b.WriteString("\n\n")
+	b.WriteString(lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Render("Without a host port, or with 0, a free one is picked"))
+	
+	return b.String()
+}
+
 // renderConfigStep renders the final configuration step
 func (m *CreateWizardModel) renderConfigStep() string {
 	var b strings.Builder
@@ -338,6 +444,20 @@ func (m *CreateWizardModel) renderConfigStep() string {
 		}
 	}
 	
+	startup := "(image default)"
+	if command := strings.TrimSpace(m.startupInput.Value()); command != "" {
+		startup = command
+	}
+	b.WriteString(fmt.Sprintf("  Startup Command: %s\n", startup))
+	
+	ports := "none"
+	if m.exposeAll {
+		ports = "all exposed ports"
+	} else if mappings := strings.Fields(m.portsInput.Value()); len(mappings) > 0 {
+		ports = strings.Join(mappings, ", ")
+	}
+	b.WriteString(fmt.Sprintf("  Ports: %s\n", ports))
+	
 	b.WriteString("\n")
 	
 	// Worktree directory input
@@ -347,11 +467,18 @@ func (m *CreateWizardModel) renderConfigStep() string {
 	return b.String()
 }
 
+// Typing reports whether a text input has focus and so needs every key
+func (m *CreateWizardModel) Typing() bool {
+	return m.branchInput.Focused() || m.remoteInput.Focused() || m.startupInput.Focused() || m.portsInput.Focused() || m.worktreeInput.Focused()
+}
+
 // updateFocus updates which input is focused
 func (m *CreateWizardModel) updateFocus() {
 	// Reset all focus states
 	m.branchInput.Blur()
 	m.remoteInput.Blur()
+	m.startupInput.Blur()
+	m.portsInput.Blur()
 	m.worktreeInput.Blur()
 	
 	// Set focus based on current step and focused element
@@ -365,6 +492,14 @@ func (m *CreateWizardModel) updateFocus() {
 			m.remoteInput.Focus()
 		}
 	case 2:
+		if m.focused == 0 { // Startup command input
+			m.startupInput.Focus()
+		}
+	case 3:
+		if m.focused == 1 { // Port mappings input
+			m.portsInput.Focus()
+		}
+	case 4:
 		if m.focused == 0 { // Worktree input
 			m.worktreeInput.Focus()
 		}
@@ -396,6 +531,31 @@ func (m *CreateWizardModel) validateCurrentStep() bool {
 		return true
 		
 	case 2:
+		// Validate startup command quoting
+		if unterminatedQuote(m.startupInput.Value()) {
+			m.err = fmt.Errorf("startup command has an unterminated quote")
+			return false
+		}
+		m.err = nil
+		return true
+		
+	case 3:
+		// Validate port mappings before anything is built
+		mappings := strings.Fields(m.portsInput.Value())
+		if m.exposeAll && len(mappings) > 0 {
+			m.err = fmt.Errorf("port mappings cannot be combined with exposing all ports")
+			return false
+		}
+		for _, spec := range mappings {
+			if _, err := container.ParsePortMapping(spec); err != nil {
+				m.err = err
+				return false
+			}
+		}
+		m.err = nil
+		return true
+		
+	case 4:
 		// Final validation
 		m.err = nil
 		return true
@@ -422,17 +582,50 @@ func (m *CreateWizardModel) startCreation() tea.Cmd {
 		}
 	}
 	
+	if command := strings.TrimSpace(m.startupInput.Value()); command != "" {
+		opts.StartupCommand = environment.ParseCommand(command)
+	}
+	
+	opts.ExposeAllPorts = m.exposeAll
+	opts.Ports = strings.Fields(m.portsInput.Value())
+	
 	if worktree := strings.TrimSpace(m.worktreeInput.Value()); worktree != "" {
 		opts.WorktreeDir = worktree
 	}
 	
-	return func() tea.Msg {
-		ctx := context.Background()
-		env, err := m.envManager.CreateEnvironment(ctx, opts)
+	estimates, err := m.envManager.EstimateCreateSteps()
+	if err != nil {
+		logging.Logger().Debug("failed to estimate create steps", "error", err.Error())
+	}
+	started := CreateStartedMsg{Branch: branchName, Estimates: estimates}
+	
+	operations := m.operations
+	return tea.Batch(func() tea.Msg { return started }, func() tea.Msg {
+		var env *config.Environment
+		err := operations.Run(utils.EnvironmentCreate, branchName, func(ctx context.Context) error {
+			var err error
+			env, err = m.envManager.CreateEnvironment(ctx, opts)
+			return err
+		})
 		return CreateProgressMsg{
 			Completed:   err == nil,
 			Error:       err,
 			Environment: env,
 		}
+	})
+}
+
+// unterminatedQuote reports whether command leaves a quote open, which
+// ParseCommand would silently close at the end
+func unterminatedQuote(command string) bool {
+	quote := byte(0)
+	for i := 0; i < len(command); i++ {
+		switch {
+		case quote == 0 && (command[i] == '"' || command[i] == '\''):
+			quote = command[i]
+		case command[i] == quote:
+			quote = 0
+		}
 	}
-}
\ No newline at end of file
+	return quote != 0
+}
@@ -0,0 +1,361 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+	"github.com/jhjaggars/cc-buddy/internal/utils"
+)
+
+// DashboardModel shows the environments of every repository in the global
+// registry, grouped by repository, and acts on them from any directory
+type DashboardModel struct {
+	table      table.Model
+	rows       []dashboardRow
+	operations *utils.OperationManager
+	current    string // working directory, to mark the current repository
+	loading    bool
+	busy       bool // an action is running
+	notice     string
+	err        error
+	picker     *RepoPickerModel // open repository switcher, if any
+	width      int
+	height     int
+}
+
+// dashboardRow is a repository's environment, or the repository itself
+// when it has none or could not be read
+type dashboardRow struct {
+	repo config.Repository
+	env  *config.Environment
+	err  error
+}
+
+// DashboardClosedMsg is sent when the user leaves the dashboard
+type DashboardClosedMsg struct{}
+
+// SwitchRepositoryMsg asks to continue in another repository, opening a
+// terminal in Environment when set
+type SwitchRepositoryMsg struct {
+	Path        string
+	Environment string
+}
+
+// dashboardLoadedMsg carries the environments of all repositories
+type dashboardLoadedMsg struct {
+	rows []dashboardRow
+	err  error
+}
+
+// dashboardActionMsg reports the result of a start, stop or delete
+type dashboardActionMsg struct {
+	notice string
+}
+
+// NewDashboardModel creates a dashboard over the registered repositories
+func NewDashboardModel(operations *utils.OperationManager) *DashboardModel {
+	columns := []table.Column{
+		{Title: "Repository", Width: 20},
+		{Title: "Name", Width: 25},
+		{Title: "Branch", Width: 20},
+		{Title: "Status", Width: 12},
+		{Title: "Created", Width: 8},
+	}
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithFocused(true),
+		table.WithHeight(10),
+	)
+
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Bold(false)
+	t.SetStyles(s)
+
+	current, _ := os.Getwd()
+	return &DashboardModel{
+		table:      t,
+		operations: operations,
+		current:    current,
+		loading:    true,
+	}
+}
+
+// Init loads the repositories
+func (m *DashboardModel) Init() tea.Cmd {
+	return loadDashboard
+}
+
+// loadDashboard lists the environments of each registered repository.
+// Repositories whose state is gone are dropped from the registry.
+func loadDashboard() tea.Msg {
+	repos, err := config.Repositories()
+	if err != nil {
+		return dashboardLoadedMsg{err: err}
+	}
+
+	var rows []dashboardRow
+	for _, repo := range repos {
+		if _, err := os.Stat(filepath.Join(repo.Path, config.StateDir, config.EnvironmentsFile)); os.IsNotExist(err) {
+			// Keep repositories picked in the switcher while they exist
+			if _, err := os.Stat(repo.Path); repo.Used.IsZero() || os.IsNotExist(err) {
+				config.UnregisterRepository(repo.Path)
+			} else {
+				rows = append(rows, dashboardRow{repo: repo})
+			}
+			continue
+		}
+		var environments []config.Environment
+		err := environment.InRepository(repo.Path, func(manager *environment.Manager) error {
+			var err error
+			environments, err = manager.ListEnvironments(context.Background())
+			return err
+		})
+		if err != nil || len(environments) == 0 {
+			rows = append(rows, dashboardRow{repo: repo, err: err})
+			continue
+		}
+		for i := range environments {
+			rows = append(rows, dashboardRow{repo: repo, env: &environments[i]})
+		}
+	}
+	return dashboardLoadedMsg{rows: rows}
+}
+
+// Update handles navigation, actions and closing
+func (m *DashboardModel) Update(msg tea.Msg) (*DashboardModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if _, ok := msg.(RepoPickerClosedMsg); ok {
+		m.picker = nil
+		return m, nil
+	}
+	if m.picker != nil {
+		// The picker's search box needs every key
+		if _, ok := msg.(dashboardLoadedMsg); !ok {
+			m.picker, cmd = m.picker.Update(msg)
+			return m, cmd
+		}
+	}
+
+	switch msg := msg.(type) {
+	case dashboardLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		m.rows = msg.rows
+		m.updateTableRows()
+		return m, nil
+
+	case dashboardActionMsg:
+		m.busy = false
+		m.notice = msg.notice
+		return m, loadDashboard
+
+	case tea.KeyMsg:
+		row := m.selected()
+		switch msg.String() {
+		case "esc":
+			return m, func() tea.Msg { return DashboardClosedMsg{} }
+		case "r":
+			m.loading = true
+			return m, loadDashboard
+		case "/":
+			m.picker = NewRepoPickerModel()
+			m.picker.SetSize(m.width, m.height)
+			return m, m.picker.Init()
+		case "o":
+			if row != nil {
+				path := row.repo.Path
+				return m, func() tea.Msg { return SwitchRepositoryMsg{Path: path} }
+			}
+			return m, nil
+		case "enter":
+			if row != nil && row.env != nil {
+				switchMsg := SwitchRepositoryMsg{Path: row.repo.Path, Environment: row.env.Name}
+				return m, func() tea.Msg { return switchMsg }
+			}
+			return m, nil
+		case "s":
+			if row != nil && row.env != nil && !m.busy {
+				m.busy = true
+				m.notice = ""
+				return m, m.startStop(row.repo, *row.env)
+			}
+			return m, nil
+		case "d":
+			if row != nil && row.env != nil && !m.busy {
+				if row.env.Protected {
+					m.notice = fmt.Sprintf("🔒 %s is protected; unprotect it in its repository first", row.env.Name)
+					return m, nil
+				}
+				m.busy = true
+				m.notice = fmt.Sprintf("Deleting %s...", row.env.Name)
+				return m, m.delete(row.repo, row.env.Name)
+			}
+			return m, nil
+		}
+	}
+
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+// startStop stops a running environment or starts a stopped one
+func (m *DashboardModel) startStop(repo config.Repository, env config.Environment) tea.Cmd {
+	return func() tea.Msg {
+		running := env.Status == "running"
+		err := environment.InRepository(repo.Path, func(manager *environment.Manager) error {
+			if running {
+				return manager.StopEnvironment(context.Background(), env.Name)
+			}
+			return manager.StartEnvironment(context.Background(), env.Name)
+		})
+		switch {
+		case err != nil:
+			return dashboardActionMsg{notice: fmt.Sprintf("❌ %s: %v", env.Name, err)}
+		case running:
+			return dashboardActionMsg{notice: fmt.Sprintf("⏹  Stopped %s", env.Name)}
+		default:
+			return dashboardActionMsg{notice: fmt.Sprintf("▶  Started %s", env.Name)}
+		}
+	}
+}
+
+// delete deletes an environment of repo
+func (m *DashboardModel) delete(repo config.Repository, envName string) tea.Cmd {
+	operations := m.operations
+	return func() tea.Msg {
+		err := operations.Run(utils.EnvironmentDelete, envName, func(ctx context.Context) error {
+			return environment.InRepository(repo.Path, func(manager *environment.Manager) error {
+				return manager.DeleteEnvironment(ctx, envName, false)
+			})
+		})
+		if err != nil {
+			return dashboardActionMsg{notice: fmt.Sprintf("❌ Delete of %s failed: %v", envName, err)}
+		}
+		return dashboardActionMsg{notice: fmt.Sprintf("✅ Deleted %s", envName)}
+	}
+}
+
+// Searching reports whether the repository switcher is open
+func (m *DashboardModel) Searching() bool {
+	return m.picker != nil
+}
+
+// selected returns the highlighted row, if any
+func (m *DashboardModel) selected() *dashboardRow {
+	i := m.table.Cursor()
+	if i < 0 || i >= len(m.rows) {
+		return nil
+	}
+	return &m.rows[i]
+}
+
+// updateTableRows fills the table, naming each repository on its first row
+func (m *DashboardModel) updateTableRows() {
+	rows := make([]table.Row, 0, len(m.rows))
+	for i, row := range m.rows {
+		repo := ""
+		if i == 0 || m.rows[i-1].repo.Path != row.repo.Path {
+			repo = row.repo.Name()
+			if row.repo.Path == m.current {
+				repo = "• " + repo
+			}
+		}
+		switch {
+		case row.err != nil:
+			rows = append(rows, table.Row{repo, "-", "", "🔴 error", ""})
+		case row.env == nil:
+			rows = append(rows, table.Row{repo, "-", "", "no environments", ""})
+		default:
+			status := getStatusDisplay(row.env.Status, row.env.Health)
+			if row.env.Protected {
+				status += " 🔒"
+			}
+			rows = append(rows, table.Row{repo, row.env.Name, row.env.Branch, status, formatTimeAgo(row.env.Created)})
+		}
+	}
+	m.table.SetRows(rows)
+	if m.table.Cursor() >= len(rows) {
+		m.table.SetCursor(max(len(rows)-1, 0))
+	}
+}
+
+// View renders the dashboard
+func (m *DashboardModel) View() string {
+	if m.picker != nil {
+		return m.picker.View()
+	}
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("205")).
+		Render("All repositories")
+
+	repos := map[string]bool{}
+	environments := 0
+	for _, row := range m.rows {
+		repos[row.repo.Path] = true
+		if row.env != nil {
+			environments++
+		}
+	}
+	subtitle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Render(fmt.Sprintf("%d environments in %d repositories", environments, len(repos)))
+
+	var body string
+	switch {
+	case m.err != nil:
+		body = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(fmt.Sprintf("Error: %v", m.err))
+	case m.loading:
+		body = "Loading repositories..."
+	case len(m.rows) == 0:
+		body = "No repositories with environments yet. Create one with 'cc-buddy create <branch>' in a repository."
+	default:
+		body = m.table.View()
+		if row := m.selected(); row != nil {
+			detail := row.repo.Path
+			if row.err != nil {
+				detail += ": " + row.err.Error()
+			}
+			body += "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Render(detail)
+		}
+	}
+	if m.notice != "" {
+		body += "\n" + m.notice
+	}
+
+	footer := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Render("[↑↓] navigate  [enter] terminal  [s] start/stop  [d] delete  [o] open repository  [/] switch repository  [r] refresh  [esc] back")
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, subtitle, "", body, "", footer)
+}
+
+// SetSize updates the table height to fill the screen
+func (m *DashboardModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	if height > 12 {
+		m.table.SetHeight(height - 10)
+	}
+	if m.picker != nil {
+		m.picker.SetSize(width, height)
+	}
+}
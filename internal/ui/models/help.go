@@ -25,6 +25,13 @@ const (
 	CreateHelpContext
 	ProgressHelpContext
 	ConfirmationHelpContext
+	InitHelpContext
+	HistoryHelpContext
+	OperationsHelpContext
+	TasksHelpContext
+	DashboardHelpContext
+	ActionsHelpContext
+	LogsHelpContext
 )
 
 // HelpEntry represents a single help item
@@ -200,6 +207,20 @@ func (m *HelpModel) getContextName() string {
 		return "Progress View"
 	case ConfirmationHelpContext:
 		return "Confirmation Dialog"
+	case InitHelpContext:
+		return "Generate Containerfile"
+	case HistoryHelpContext:
+		return "History"
+	case OperationsHelpContext:
+		return "Operations"
+	case TasksHelpContext:
+		return "Tasks"
+	case DashboardHelpContext:
+		return "All Repositories"
+	case ActionsHelpContext:
+		return "Actions"
+	case LogsHelpContext:
+		return "Logs"
 	default:
 		return "General"
 	}
@@ -212,8 +233,20 @@ func (m *HelpModel) getHelpEntries() []HelpEntry {
 		return []HelpEntry{
 			{"↑↓", "Navigate environments"},
 			{"enter", "Open terminal in environment"},
+			{"e", "Open worktree in $EDITOR"},
+			{"b", "Open web service of selected environment in browser"},
 			{"n", "Create new environment"},
+			{"i", "Generate Containerfile.dev"},
+			{"a", "Actions for selected environment"},
+			{"A", "Show environments of all repositories"},
+			{"H", "Show environment history"},
+			{"o", "Show recent operations"},
+			{"s", "Start or stop selected environment"},
+			{"y y/c/w", "Copy name, container name or worktree path"},
 			{"d", "Delete selected environment"},
+			{"p", "Protect or unprotect selected environment"},
+			{"R", "Rebuild image of selected environment"},
+			{"t", "Run a configured task in selected environment"},
 			{"r", "Refresh environment list"},
 			{"q", "Quit application"},
 			{"ctrl+c", "Interrupt/Quit"},
@@ -230,6 +263,69 @@ func (m *HelpModel) getHelpEntries() []HelpEntry {
 			{"?", "Toggle this help"},
 		}
 		
+	case InitHelpContext:
+		return []HelpEntry{
+			{"↑↓", "Choose base image"},
+			{"enter", "Continue/Write file"},
+			{"shift+tab", "Previous step"},
+			{"y/n", "Answer GPG question"},
+			{"space", "Toggle service"},
+			{"esc", "Cancel"},
+		}
+		
+	case HistoryHelpContext:
+		return []HelpEntry{
+			{"↑↓", "Scroll events"},
+			{"f", "Filter to the selected environment"},
+			{"r", "Reload"},
+			{"esc", "Back to environments"},
+			{"?", "Toggle this help"},
+		}
+		
+	case OperationsHelpContext:
+		return []HelpEntry{
+			{"↑↓", "Select operation (shows its error)"},
+			{"esc", "Back to environments"},
+			{"?", "Toggle this help"},
+		}
+		
+	case TasksHelpContext:
+		return []HelpEntry{
+			{"↑↓", "Select task"},
+			{"enter", "Run task (leaves the TUI until it finishes)"},
+			{"esc", "Back to environments"},
+			{"?", "Toggle this help"},
+		}
+		
+	case ActionsHelpContext:
+		return []HelpEntry{
+			{"↑↓", "Navigate actions"},
+			{"enter", "Run selected action"},
+			{"esc", "Back to environments"},
+			{"?", "Toggle this help"},
+		}
+		
+	case LogsHelpContext:
+		return []HelpEntry{
+			{"↑↓", "Scroll"},
+			{"pgup/pgdn", "Scroll a page"},
+			{"esc", "Back to environments"},
+			{"?", "Toggle this help"},
+		}
+		
+	case DashboardHelpContext:
+		return []HelpEntry{
+			{"↑↓", "Navigate environments"},
+			{"enter", "Open terminal (continues in its repository)"},
+			{"s", "Start or stop selected environment"},
+			{"d", "Delete selected environment"},
+			{"o", "Continue in the selected repository"},
+			{"/", "Search repositories to switch to"},
+			{"r", "Refresh"},
+			{"esc", "Back to environments"},
+			{"?", "Toggle this help"},
+		}
+		
 	case ProgressHelpContext:
 		return []HelpEntry{
 			{"ctrl+c", "Cancel operation"},
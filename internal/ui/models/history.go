@@ -0,0 +1,167 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jhjaggars/cc-buddy/internal/audit"
+	"github.com/jhjaggars/cc-buddy/internal/config"
+)
+
+// HistoryModel shows the environment lifecycle events log, newest first
+type HistoryModel struct {
+	table       table.Model
+	log         *audit.Log
+	environment string // environment the list had selected, for filtering
+	filtered    bool
+	events      int
+	width       int
+	height      int
+	err         error
+}
+
+// HistoryClosedMsg is sent when the user leaves the history view
+type HistoryClosedMsg struct{}
+
+// NewHistoryModel creates a history view; selected is the environment
+// highlighted in the list, which f filters to
+func NewHistoryModel(selected string) *HistoryModel {
+	columns := []table.Column{
+		{Title: "Time", Width: 19},
+		{Title: "Event", Width: 14},
+		{Title: "Environment", Width: 25},
+		{Title: "User", Width: 12},
+		{Title: "Duration", Width: 9},
+	}
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithFocused(true),
+		table.WithHeight(10),
+	)
+
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Bold(false)
+	t.SetStyles(s)
+
+	m := &HistoryModel{
+		table:       t,
+		log:         audit.NewLog(config.StateDir),
+		environment: selected,
+	}
+	m.load()
+	return m
+}
+
+// load reads the log into the table
+func (m *HistoryModel) load() {
+	envName := ""
+	if m.filtered {
+		envName = m.environment
+	}
+
+	events, err := m.log.Read(envName)
+	m.err = err
+	m.events = len(events)
+
+	rows := make([]table.Row, 0, len(events))
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		name := event.Event
+		if event.Error != "" {
+			name = "❌ " + name
+		}
+		target := event.Environment
+		if event.Event == audit.EventMaintenance {
+			target = event.Task
+		}
+		rows = append(rows, table.Row{
+			event.Time.Local().Format("2006-01-02 15:04:05"),
+			name,
+			target,
+			event.User,
+			event.Duration().String(),
+		})
+	}
+	m.table.SetRows(rows)
+	m.table.GotoTop()
+}
+
+// Update handles navigation, filtering and closing
+func (m *HistoryModel) Update(msg tea.Msg) (*HistoryModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			return m, func() tea.Msg { return HistoryClosedMsg{} }
+		case "f":
+			if m.environment != "" {
+				m.filtered = !m.filtered
+				m.load()
+			}
+			return m, nil
+		case "r":
+			m.load()
+			return m, nil
+		}
+	}
+
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+// View renders the history table
+func (m *HistoryModel) View() string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("205")).
+		Render("History")
+
+	scope := "all environments"
+	if m.filtered {
+		scope = m.environment
+	}
+	subtitle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Render(fmt.Sprintf("%d events for %s", m.events, scope))
+
+	help := "[↑↓] scroll  [r] reload  [esc] back"
+	if m.environment != "" {
+		help = "[↑↓] scroll  [f] filter to " + m.environment + "  [r] reload  [esc] back"
+	}
+	footer := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Render(help)
+
+	var body string
+	switch {
+	case m.err != nil:
+		body = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(fmt.Sprintf("Error: %v", m.err))
+	case m.events == 0:
+		body = "No history recorded yet."
+	default:
+		body = m.table.View()
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, subtitle, "", body, "", footer)
+}
+
+// SetSize updates the table height to fill the screen
+func (m *HistoryModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	if height > 10 {
+		m.table.SetHeight(height - 8)
+	}
+}
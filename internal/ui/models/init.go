@@ -0,0 +1,488 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/scaffold"
+)
+
+// Init wizard steps
+const (
+	initStepImage = iota
+	initStepPackages
+	initStepPorts
+	initStepVolumes
+	initStepEnvVars
+	initStepCommands
+	initStepGPG
+	initStepServices
+	initStepUser
+	initStepReview
+	initStepCount
+)
+
+// InitCompletedMsg reports that the init wizard finished or was cancelled
+type InitCompletedMsg struct {
+	Path       string
+	GPGSigning bool
+	Services   []string // backing services written to the compose file
+	Cancelled  bool
+}
+
+// InitWizardModel generates Containerfile.dev through a multi-step form
+type InitWizardModel struct {
+	path       string
+	standalone bool // quit the program when done instead of returning to the main view
+
+	step       int
+	detected   string   // detected project description, if any
+	images     []string // base image choices; the last entry is "Custom"
+	imageIdx   int
+	gpg        bool
+	services   []bool // selection state for each scaffold.Services entry
+	serviceIdx int
+	commands   []string
+	exists     bool
+	completed  *InitCompletedMsg
+
+	customInput   textinput.Model
+	packagesInput textinput.Model
+	portsInput    textinput.Model
+	volumesInput  textinput.Model
+	envVarsInput  textinput.Model
+	commandInput  textinput.Model
+	userInput     textinput.Model
+
+	configMgr *config.Manager // project configuration; nil when unavailable
+
+	width  int
+	height int
+	err    error
+}
+
+// NewInitWizardModel creates the init wizard for the Containerfile at path,
+// pre-filled from the project files in the current directory. Standalone
+// wizards quit the program when finished.
+func NewInitWizardModel(path string, standalone bool) *InitWizardModel {
+	m := &InitWizardModel{
+		path:       path,
+		standalone: standalone,
+		images:     append(append([]string{}, scaffold.BaseImages...), "Custom"),
+		services:   make([]bool, len(scaffold.Services)),
+	}
+
+	newInput := func(placeholder string) textinput.Model {
+		input := textinput.New()
+		input.Placeholder = placeholder
+		input.CharLimit = 200
+		input.Width = 60
+		return input
+	}
+	m.customInput = newInput("registry/image:tag")
+	m.packagesInput = newInput("space-separated apt packages")
+	m.portsInput = newInput("e.g. 3000 8080")
+	m.volumesInput = newInput("e.g. /var/lib/data")
+	m.envVarsInput = newInput("KEY=value ...")
+	m.commandInput = newInput("command run while building the image")
+	m.userInput = newInput(scaffold.DefaultUsername)
+
+	m.userInput.SetValue(scaffold.DefaultUsername)
+	if configMgr, err := config.NewManager(); err == nil && configMgr.LoadConfig() == nil {
+		m.configMgr = configMgr
+		if user := configMgr.GetConfig().ContainerUser; user != "" {
+			m.userInput.SetValue(user)
+		}
+	}
+
+	m.packagesInput.SetValue("git curl wget")
+	if projects := scaffold.Detect("."); len(projects) > 0 {
+		primary := projects[0]
+		m.detected = scaffold.Describe(projects)
+		m.images = append([]string{primary.BaseImage + " (detected)"}, m.images...)
+		m.packagesInput.SetValue(strings.Join(scaffold.SuggestedPackages(projects), " "))
+		m.portsInput.SetValue(strings.Join(primary.Ports, " "))
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		m.exists = true
+	}
+	m.updateFocus()
+	return m
+}
+
+// Init implements tea.Model
+func (m *InitWizardModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update implements tea.Model for standalone use
+func (m *InitWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	return m.UpdateWizard(msg)
+}
+
+// UpdateWizard handles a message and returns the concrete model, for
+// embedding in the main TUI
+func (m *InitWizardModel) UpdateWizard(msg tea.Msg) (*InitWizardModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.SetSize(msg.Width, msg.Height)
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return m.finish(InitCompletedMsg{Path: m.path, Cancelled: true})
+
+		case "shift+tab":
+			if m.step > 0 {
+				m.step--
+				m.updateFocus()
+			}
+			return m, nil
+
+		case "up", "down":
+			delta := 1
+			if msg.String() == "up" {
+				delta = -1
+			}
+			switch m.step {
+			case initStepImage:
+				m.imageIdx = (m.imageIdx + delta + len(m.images)) % len(m.images)
+				m.updateFocus()
+				return m, nil
+			case initStepServices:
+				m.serviceIdx = (m.serviceIdx + delta + len(m.services)) % len(m.services)
+				return m, nil
+			}
+
+		case "left", "right", " ", "y", "n":
+			if m.step == initStepServices && msg.String() == " " {
+				m.services[m.serviceIdx] = !m.services[m.serviceIdx]
+				return m, nil
+			}
+			if m.step == initStepGPG {
+				switch msg.String() {
+				case "y":
+					m.gpg = true
+				case "n":
+					m.gpg = false
+				default:
+					m.gpg = !m.gpg
+				}
+				return m, nil
+			}
+
+		case "enter":
+			return m.advance()
+		}
+	}
+
+	var cmd tea.Cmd
+	if input := m.activeInput(); input != nil {
+		*input, cmd = input.Update(msg)
+	}
+	return m, cmd
+}
+
+// advance validates the current step and moves to the next one
+func (m *InitWizardModel) advance() (*InitWizardModel, tea.Cmd) {
+	m.err = nil
+	switch m.step {
+	case initStepImage:
+		if m.isCustomImage() && strings.TrimSpace(m.customInput.Value()) == "" {
+			m.err = fmt.Errorf("enter a custom base image")
+			return m, nil
+		}
+	case initStepUser:
+		if !scaffold.ValidUsername(m.username()) {
+			m.err = fmt.Errorf("invalid user name %q: use lowercase letters, digits, '_' or '-' (not root)", m.username())
+			return m, nil
+		}
+	case initStepCommands:
+		// Each enter adds a command; an empty line moves on
+		if command := strings.TrimSpace(m.commandInput.Value()); command != "" {
+			m.commands = append(m.commands, command)
+			m.commandInput.SetValue("")
+			return m, nil
+		}
+	case initStepReview:
+		return m.write()
+	}
+
+	m.step++
+	m.updateFocus()
+	return m, nil
+}
+
+// write generates and saves the Containerfile, plus the compose file when
+// services were selected
+func (m *InitWizardModel) write() (*InitWizardModel, tea.Cmd) {
+	content := scaffold.GenerateContainerfile(m.containerfileOptions())
+	if err := os.WriteFile(m.path, []byte(content), 0644); err != nil {
+		m.err = fmt.Errorf("failed to write %s: %w", m.path, err)
+		return m, nil
+	}
+
+	services := m.selectedServices()
+	if (len(services) > 0 || m.username() != m.configuredUser()) && m.configMgr == nil {
+		m.err = fmt.Errorf("project configuration is unavailable; run init from the repository root")
+		return m, nil
+	}
+	if m.username() != m.configuredUser() {
+		if err := m.configMgr.MergeConfig([]byte(fmt.Sprintf(`{"container_user": %q}`, m.username()))); err != nil {
+			m.err = fmt.Errorf("failed to update configuration: %w", err)
+			return m, nil
+		}
+	}
+
+	var names []string
+	if len(services) > 0 {
+		if err := scaffold.WriteCompose(m.configMgr, services); err != nil {
+			m.err = err
+			return m, nil
+		}
+		for _, service := range services {
+			names = append(names, service.Name)
+		}
+	}
+	return m.finish(InitCompletedMsg{Path: m.path, GPGSigning: m.gpg, Services: names})
+}
+
+// username returns the container user entered in the wizard
+func (m *InitWizardModel) username() string {
+	return strings.TrimSpace(m.userInput.Value())
+}
+
+// configuredUser returns the project's current container user
+func (m *InitWizardModel) configuredUser() string {
+	if m.configMgr != nil && m.configMgr.GetConfig().ContainerUser != "" {
+		return m.configMgr.GetConfig().ContainerUser
+	}
+	return scaffold.DefaultUsername
+}
+
+// selectedServices returns the backing services chosen in the wizard
+func (m *InitWizardModel) selectedServices() []scaffold.Service {
+	var services []scaffold.Service
+	for i, selected := range m.services {
+		if selected {
+			services = append(services, scaffold.Services[i])
+		}
+	}
+	return services
+}
+
+// finish records the result and either quits or notifies the main TUI
+func (m *InitWizardModel) finish(result InitCompletedMsg) (*InitWizardModel, tea.Cmd) {
+	m.completed = &result
+	if m.standalone {
+		return m, tea.Quit
+	}
+	return m, func() tea.Msg { return result }
+}
+
+// Result returns how the wizard ended, or nil if it is still running
+func (m *InitWizardModel) Result() *InitCompletedMsg {
+	return m.completed
+}
+
+// containerfileOptions collects the wizard's answers
+func (m *InitWizardModel) containerfileOptions() scaffold.ContainerfileOptions {
+	return scaffold.ContainerfileOptions{
+		BaseImage:  m.baseImage(),
+		Username:   m.username(),
+		Packages:   strings.Fields(m.packagesInput.Value()),
+		Ports:      strings.Fields(m.portsInput.Value()),
+		Volumes:    strings.Fields(m.volumesInput.Value()),
+		EnvVars:    append(strings.Fields(m.envVarsInput.Value()), scaffold.ServiceEnvVars(m.selectedServices())...),
+		Commands:   m.commands,
+		GPGSigning: m.gpg,
+	}
+}
+
+// baseImage returns the selected or custom base image
+func (m *InitWizardModel) baseImage() string {
+	if m.isCustomImage() {
+		return strings.TrimSpace(m.customInput.Value())
+	}
+	return strings.TrimSuffix(m.images[m.imageIdx], " (detected)")
+}
+
+func (m *InitWizardModel) isCustomImage() bool {
+	return m.imageIdx == len(m.images)-1
+}
+
+// activeInput returns the text input for the current step, if any
+func (m *InitWizardModel) activeInput() *textinput.Model {
+	switch m.step {
+	case initStepImage:
+		if m.isCustomImage() {
+			return &m.customInput
+		}
+	case initStepPackages:
+		return &m.packagesInput
+	case initStepPorts:
+		return &m.portsInput
+	case initStepVolumes:
+		return &m.volumesInput
+	case initStepEnvVars:
+		return &m.envVarsInput
+	case initStepCommands:
+		return &m.commandInput
+	case initStepUser:
+		return &m.userInput
+	}
+	return nil
+}
+
+// updateFocus focuses the current step's input
+func (m *InitWizardModel) updateFocus() {
+	for _, input := range []*textinput.Model{&m.customInput, &m.packagesInput, &m.portsInput, &m.volumesInput, &m.envVarsInput, &m.commandInput, &m.userInput} {
+		input.Blur()
+	}
+	if input := m.activeInput(); input != nil {
+		input.Focus()
+	}
+}
+
+// SetSize updates the model size
+func (m *InitWizardModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// View implements tea.Model
+func (m *InitWizardModel) View() string {
+	if m.completed != nil && m.standalone {
+		return ""
+	}
+
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("205")).
+		Render("Generate " + m.path)
+	progress := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Render(fmt.Sprintf("Step %d of %d", m.step+1, initStepCount))
+	b.WriteString(title + "  " + progress + "\n\n")
+
+	if m.detected != "" && m.step == initStepImage {
+		b.WriteString(fmt.Sprintf("Detected project: %s\n\n", m.detected))
+	}
+
+	switch m.step {
+	case initStepImage:
+		b.WriteString("Base image:\n")
+		for i, image := range m.images {
+			marker := "○"
+			style := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+			if i == m.imageIdx {
+				marker = "●"
+				style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+			}
+			b.WriteString(fmt.Sprintf("  %s\n", style.Render(marker+" "+image)))
+		}
+		if m.isCustomImage() {
+			b.WriteString("\n" + m.customInput.View())
+		}
+	case initStepPackages:
+		b.WriteString("System packages to install:\n" + m.packagesInput.View())
+	case initStepPorts:
+		b.WriteString("Ports to expose:\n" + m.portsInput.View())
+	case initStepVolumes:
+		b.WriteString("Volume mount points:\n" + m.volumesInput.View())
+	case initStepEnvVars:
+		b.WriteString("Environment variables:\n" + m.envVarsInput.View())
+	case initStepCommands:
+		b.WriteString("Build commands (enter adds one, empty enter continues):\n")
+		for _, command := range m.commands {
+			b.WriteString("  RUN " + command + "\n")
+		}
+		b.WriteString(m.commandInput.View())
+	case initStepGPG:
+		answer := "No"
+		if m.gpg {
+			answer = "Yes"
+		}
+		b.WriteString("Prepare the image for GPG-signed commits via agent forwarding?\n\n")
+		b.WriteString("  " + lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205")).Render(answer))
+	case initStepServices:
+		b.WriteString("Backing services to run with each environment:\n")
+		for i, service := range scaffold.Services {
+			marker := "☐"
+			if m.services[i] {
+				marker = "☑"
+			}
+			style := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+			if i == m.serviceIdx {
+				style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+			}
+			b.WriteString(fmt.Sprintf("  %s\n", style.Render(fmt.Sprintf("%s %-6s %s", marker, service.Name, service.Description))))
+		}
+	case initStepUser:
+		b.WriteString("Non-root user in the container:\n" + m.userInput.View())
+	case initStepReview:
+		b.WriteString(m.renderReview())
+	}
+
+	if m.err != nil {
+		b.WriteString("\n\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(m.err.Error()))
+	}
+
+	b.WriteString("\n\n")
+	switch m.step {
+	case initStepImage:
+		b.WriteString("[↑↓] choose  [enter] continue  [esc] cancel")
+	case initStepGPG:
+		b.WriteString("[y/n] answer  [enter] continue  [shift+tab] back  [esc] cancel")
+	case initStepServices:
+		b.WriteString("[↑↓] move  [space] toggle  [enter] continue  [shift+tab] back  [esc] cancel")
+	case initStepReview:
+		b.WriteString("[enter] write file  [shift+tab] back  [esc] cancel")
+	default:
+		b.WriteString("[enter] continue  [shift+tab] back  [esc] cancel")
+	}
+
+	return b.String()
+}
+
+// renderReview summarizes the answers before writing
+func (m *InitWizardModel) renderReview() string {
+	opts := m.containerfileOptions()
+	var b strings.Builder
+
+	b.WriteString("Review:\n\n")
+	b.WriteString(fmt.Sprintf("  Base image:  %s\n", opts.BaseImage))
+	b.WriteString(fmt.Sprintf("  User:        %s\n", opts.Username))
+	b.WriteString(fmt.Sprintf("  Packages:    %s\n", strings.Join(opts.Packages, " ")))
+	b.WriteString(fmt.Sprintf("  Ports:       %s\n", strings.Join(opts.Ports, " ")))
+	b.WriteString(fmt.Sprintf("  Volumes:     %s\n", strings.Join(opts.Volumes, " ")))
+	b.WriteString(fmt.Sprintf("  Env vars:    %s\n", strings.Join(opts.EnvVars, " ")))
+	b.WriteString(fmt.Sprintf("  Commands:    %d\n", len(opts.Commands)))
+	b.WriteString(fmt.Sprintf("  GPG signing: %t\n", opts.GPGSigning))
+	var services []string
+	for _, service := range m.selectedServices() {
+		services = append(services, service.Name)
+	}
+	b.WriteString(fmt.Sprintf("  Services:    %s\n", strings.Join(services, " ")))
+	if len(services) > 0 {
+		note := fmt.Sprintf("%s will be written and set as the project's compose_file", scaffold.ComposeFile)
+		if _, err := os.Stat(scaffold.ComposeFile); err == nil {
+			note = fmt.Sprintf("%s already exists and will be overwritten", scaffold.ComposeFile)
+		}
+		b.WriteString("\n" + note + "\n")
+	}
+
+	if m.exists {
+		b.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("208")).Render(fmt.Sprintf("⚠️  %s already exists and will be overwritten", m.path)))
+	}
+	return b.String()
+}
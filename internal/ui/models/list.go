@@ -9,8 +9,10 @@ import (
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/jhjaggars/cc-buddy/internal/audit"
 	"github.com/jhjaggars/cc-buddy/internal/config"
 	"github.com/jhjaggars/cc-buddy/internal/environment"
+	"github.com/jhjaggars/cc-buddy/internal/utils"
 )
 
 // EnvironmentListModel handles the environment list view
@@ -22,6 +24,12 @@ type EnvironmentListModel struct {
 	height      int
 	loading     bool
 	err         error
+	operations  *utils.OperationManager      // tracks deletes for the operations view
+	rebuilding  map[string]bool              // environments with a rebuild in progress
+	pending     map[string]EnvironmentAction // start, stop or restart in progress
+	notice      string                       // result of the last rebuild
+	yanking     bool                         // y was pressed; the next key picks what to copy
+	maintenance *audit.Event                 // last scheduled daemon task, for the status bar
 }
 
 // RefreshEnvironmentsMsg is sent when environments should be refreshed (periodic)
@@ -30,9 +38,39 @@ type RefreshEnvironmentsMsg struct{}
 // ManualRefreshMsg is sent when user manually refreshes (shows loading state)
 type ManualRefreshMsg struct{}
 
+// RebuildFinishedMsg is sent when a rebuild started with R finishes
+type RebuildFinishedMsg struct {
+	Environment string
+	Error       error
+}
+
+// OpenTaskMenuMsg requests the task menu for an environment
+type OpenTaskMenuMsg struct {
+	Environment string
+	Names       []string          // task names in order
+	Tasks       map[string]string // commands by task name
+}
+
+// ActionFinishedMsg is sent when a start, stop or restart from the action
+// menu finishes
+type ActionFinishedMsg struct {
+	Environment string
+	Action      EnvironmentAction
+	Error       error
+}
+
+// BrowseFinishedMsg is sent when opening an environment's web service in
+// the browser finishes
+type BrowseFinishedMsg struct {
+	Environment string
+	URL         string
+	Error       error
+}
+
 // EnvironmentsLoadedMsg is sent when environments are loaded
 type EnvironmentsLoadedMsg struct {
 	Environments []config.Environment
+	Maintenance  *audit.Event // last maintenance task the daemon ran, if any
 	Error        error
 }
 
@@ -71,6 +109,8 @@ func NewEnvironmentListModel() *EnvironmentListModel {
 		envManager: envManager,
 		loading:    true,
 		err:        err,
+		rebuilding: map[string]bool{},
+		pending:    map[string]EnvironmentAction{},
 	}
 }
 
@@ -100,7 +140,21 @@ func (m *EnvironmentListModel) Update(msg tea.Msg) (*EnvironmentListModel, tea.C
 		m.updateTableSize()
 		
 	case tea.KeyMsg:
+		if m.yanking {
+			m.yanking = false
+			m.notice = ""
+			return m, m.yank(msg.String())
+		}
+		
 		switch msg.String() {
+		case "y":
+			// Copy an identifier of the selected environment; the next key picks which
+			if m.table.SelectedRow() != nil {
+				m.yanking = true
+				m.notice = "📋 Copy: [y] name  [c] container name  [w] worktree path"
+				return m, nil
+			}
+			
 		case "r":
 			// Manual refresh environments
 			return m, func() tea.Msg { return ManualRefreshMsg{} }
@@ -114,14 +168,129 @@ func (m *EnvironmentListModel) Update(msg tea.Msg) (*EnvironmentListModel, tea.C
 				}
 			}
 			
+		case "e":
+			// Request opening the worktree in the editor (will suspend the TUI)
+			if m.table.SelectedRow() != nil {
+				envName := m.table.SelectedRow()[0]
+				return m, func() tea.Msg {
+					return OpenEditorMsg{Environment: envName}
+				}
+			}
+			
+		case "b":
+			// Open the selected environment's web service in the browser
+			if m.table.SelectedRow() != nil {
+				return m, m.RunAction(m.table.SelectedRow()[0], ActionBrowse)
+			}
+			
+		case "a":
+			// Open the action menu for the selected environment
+			if m.table.SelectedRow() != nil {
+				msg := OpenActionMenuMsg{
+					Environment: m.table.SelectedRow()[0],
+					Status:      m.status(m.table.SelectedRow()[0]),
+				}
+				return m, func() tea.Msg { return msg }
+			}
+			
+		case "t":
+			// Pick a configured task to run in the selected environment
+			if m.table.SelectedRow() != nil && m.envManager != nil {
+				names := m.envManager.TaskNames()
+				if len(names) == 0 {
+					m.notice = "No tasks configured; add them under \"tasks\" in .cc-buddy/config.json"
+					return m, nil
+				}
+				msg := OpenTaskMenuMsg{
+					Environment: m.table.SelectedRow()[0],
+					Names:       names,
+					Tasks:       m.envManager.GetConfig().GetConfig().Tasks,
+				}
+				return m, func() tea.Msg { return msg }
+			}
+			
 		case "d":
 			// Delete selected environment
+			if m.table.SelectedRow() != nil {
+				return m, m.RunAction(m.table.SelectedRow()[0], ActionDelete)
+			}
+			
+		case "s":
+			// Stop the selected environment if running, start it otherwise
 			if m.table.SelectedRow() != nil {
 				envName := m.table.SelectedRow()[0]
-				// TODO: Show confirmation dialog
-				return m, m.deleteEnvironment(envName)
+				if m.status(envName) == "running" {
+					return m, m.RunAction(envName, ActionStop)
+				}
+				return m, m.RunAction(envName, ActionStart)
 			}
+			
+		case "p":
+			// Toggle protection against deletion
+			if m.table.SelectedRow() != nil {
+				envName := m.table.SelectedRow()[0]
+				protect := !m.protected(envName)
+				if err := m.envManager.SetProtected(envName, protect); err != nil {
+					m.notice = fmt.Sprintf("❌ %v", err)
+				} else if protect {
+					m.notice = fmt.Sprintf("🔒 Protected %s", envName)
+				} else {
+					m.notice = fmt.Sprintf("🔓 Unprotected %s", envName)
+				}
+				return m, m.refreshEnvironments()
+			}
+			
+		case "R":
+			// Rebuild the selected environment's image, e.g. when it is stale
+			if m.table.SelectedRow() != nil {
+				return m, m.RunAction(m.table.SelectedRow()[0], ActionRebuild)
+			}
+		}
+	
+	case ActionFinishedMsg:
+		delete(m.pending, msg.Environment)
+		if msg.Error == nil {
+			// Show the outcome until the refresh confirms it
+			for i := range m.environments {
+				if m.environments[i].Name == msg.Environment {
+					m.environments[i].Status = "running"
+					if msg.Action == ActionStop {
+						m.environments[i].Status = "stopped"
+					}
+				}
+			}
+		}
+		m.updateTableRows()
+		if msg.Error != nil {
+			m.notice = fmt.Sprintf("❌ Could not %s %s: %v", msg.Action, msg.Environment, msg.Error)
+		} else {
+			done := map[EnvironmentAction]string{ActionStart: "Started", ActionStop: "Stopped", ActionRestart: "Restarted"}
+			m.notice = fmt.Sprintf("✅ %s %s", done[msg.Action], msg.Environment)
+		}
+		return m, m.refreshEnvironments()
+	
+	case BrowseFinishedMsg:
+		if msg.Error != nil {
+			m.notice = fmt.Sprintf("❌ Could not browse %s: %v", msg.Environment, msg.Error)
+		} else {
+			m.notice = fmt.Sprintf("🌐 Opened %s", msg.URL)
+		}
+		return m, nil
+	
+	case LogsLoadedMsg:
+		// Only failures come back here; output opens the logs view
+		m.notice = fmt.Sprintf("❌ Logs of %s: %v", msg.Environment, msg.Error)
+		return m, nil
+
+	case RebuildFinishedMsg:
+		delete(m.rebuilding, msg.Environment)
+		if msg.Error != nil {
+			m.notice = fmt.Sprintf("❌ Rebuild of %s failed: %v", msg.Environment, msg.Error)
+		} else {
+			m.notice = fmt.Sprintf("✅ Rebuilt %s", msg.Environment)
 		}
+		m.updateTableRows()
+		return m, m.refreshEnvironments()
 
 	case ManualRefreshMsg:
 		// Show loading state for manual refreshes
@@ -135,6 +304,7 @@ func (m *EnvironmentListModel) Update(msg tea.Msg) (*EnvironmentListModel, tea.C
 	case EnvironmentsLoadedMsg:
 		m.loading = false
 		m.err = msg.Error
+		m.maintenance = msg.Maintenance
 		if msg.Error == nil {
 			// Only update if environments have actually changed
 			if m.environmentsChanged(msg.Environments) {
@@ -161,29 +331,68 @@ func (m *EnvironmentListModel) View() string {
 		return "Loading environments..."
 	}
 
+	warning := ""
+	if m.envManager != nil && m.envManager.WorktreeOnly() {
+		warning = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("208")).
+			Render("⚠️  No container runtime found: worktree-only mode, so environments can be listed and deleted but not created or entered. Run 'cc-buddy doctor' for install instructions.") + "\n"
+	}
+
 	if len(m.environments) == 0 {
-		return lipgloss.NewStyle().
+		return warning + lipgloss.NewStyle().
 			Margin(2, 0).
 			Render("No environments found.\n\nPress 'n' to create your first environment.")
 	}
 
 	// Build the view
 	var b strings.Builder
+	b.WriteString(warning)
 	
 	// Table
 	b.WriteString(m.table.View())
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+	if note := m.selectedNote(); note != "" {
+		b.WriteString(lipgloss.NewStyle().
+			Foreground(lipgloss.Color("252")).
+			Render("📝 " + note))
+	}
+	b.WriteString("\n")
+	
+	if m.notice != "" {
+		b.WriteString(m.notice)
+		b.WriteString("\n")
+	}
 	
 	// Help text
 	help := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("241")).
-		Render("[↑↓] navigate  [enter] terminal  [d] delete  [n] new  [r] refresh")
+		Render("[↑↓] navigate  [enter] terminal  [e] editor  [b] browse  [a] actions  [s] start/stop  [y] copy  [d] delete  [p] protect  [R] rebuild  [n] new  [r] refresh")
 	
 	b.WriteString(help)
 	
+	if status := m.maintenanceStatus(); status != "" {
+		b.WriteString("\n")
+		b.WriteString(lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")).
+			Render(status))
+	}
+	
 	return b.String()
 }
 
+// SetOperationManager records deletes as operations
+func (m *EnvironmentListModel) SetOperationManager(operations *utils.OperationManager) {
+	m.operations = operations
+}
+
+// SelectedEnvironment returns the highlighted environment's name, if any
+func (m *EnvironmentListModel) SelectedEnvironment() string {
+	if row := m.table.SelectedRow(); row != nil {
+		return row[0]
+	}
+	return ""
+}
+
 // SetSize updates the model size
 func (m *EnvironmentListModel) SetSize(width, height int) {
 	m.width = width
@@ -194,8 +403,8 @@ func (m *EnvironmentListModel) SetSize(width, height int) {
 // updateTableSize adjusts table dimensions based on available space
 func (m *EnvironmentListModel) updateTableSize() {
 	if m.width > 0 && m.height > 0 {
-		// Leave space for header, help text, and margins
-		tableHeight := m.height - 8
+		// Leave space for header, help text, status bar, and margins
+		tableHeight := m.height - 9
 		if tableHeight < 3 {
 			tableHeight = 3
 		}
@@ -245,8 +454,11 @@ func (m *EnvironmentListModel) refreshEnvironments() tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
 		environments, err := m.envManager.ListEnvironments(ctx)
+		// The status bar is best effort; an unreadable log just hides it
+		maintenance, _ := audit.NewLog(config.StateDir).Latest(audit.EventMaintenance)
 		return EnvironmentsLoadedMsg{
 			Environments: environments,
+			Maintenance:  maintenance,
 			Error:        err,
 		}
 	}
@@ -257,7 +469,25 @@ func (m *EnvironmentListModel) updateTableRows() {
 	var rows []table.Row
 	
 	for _, env := range m.environments {
-		status := getStatusDisplay(env.Status)
+		status := getStatusDisplay(env.Status, env.Health)
+		if env.Stale && env.Status == "running" {
+			// The Containerfile changed since the image was built
+			status = "⚠️ stale"
+		}
+		if m.rebuilding[env.Name] {
+			status = "🔄 rebuilding"
+		}
+		switch m.pending[env.Name] {
+		case ActionStart:
+			status = "🔄 starting"
+		case ActionStop:
+			status = "🔄 stopping"
+		case ActionRestart:
+			status = "🔄 restarting"
+		}
+		if env.Protected {
+			status += " 🔒"
+		}
 		created := formatTimeAgo(env.Created)
 		
 		rows = append(rows, table.Row{
@@ -272,11 +502,173 @@ func (m *EnvironmentListModel) updateTableRows() {
 }
 
 
+// maintenanceStatus describes the last maintenance task for the status bar
+func (m *EnvironmentListModel) maintenanceStatus() string {
+	event := m.maintenance
+	if event == nil {
+		return ""
+	}
+	if event.Error != "" {
+		return fmt.Sprintf("❌ Maintenance: %s failed %s: %s", event.Task, formatTimeAgo(event.Time), event.Error)
+	}
+	return fmt.Sprintf("🛠  Maintenance: %s %s, %s", event.Task, formatTimeAgo(event.Time), event.Result)
+}
+
+// selectedNote returns the note of the highlighted environment, if any
+func (m *EnvironmentListModel) selectedNote() string {
+	name := m.SelectedEnvironment()
+	for _, env := range m.environments {
+		if env.Name == name {
+			return env.Note
+		}
+	}
+	return ""
+}
+
+// yank copies the selected environment's name (key y), container name (c)
+// or worktree path (w) to the clipboard; other keys cancel
+func (m *EnvironmentListModel) yank(key string) tea.Cmd {
+	name := m.SelectedEnvironment()
+	var value string
+	for _, env := range m.environments {
+		if env.Name != name {
+			continue
+		}
+		switch key {
+		case "y":
+			value = env.Name
+		case "c":
+			value = env.ContainerName
+		case "w":
+			value = env.WorktreePath
+		}
+	}
+	if value == "" {
+		return nil
+	}
+	if err := utils.CopyToClipboard(value); err != nil {
+		m.notice = fmt.Sprintf("❌ %v", err)
+	} else {
+		m.notice = fmt.Sprintf("📋 Copied %s", value)
+	}
+	return nil
+}
+
+// status returns the stored status of the listed environment envName
+func (m *EnvironmentListModel) status(envName string) string {
+	for _, env := range m.environments {
+		if env.Name == envName {
+			return env.Status
+		}
+	}
+	return ""
+}
+
+// RunAction runs an action from the action menu, or a key bound to one,
+// on the environment envName
+func (m *EnvironmentListModel) RunAction(envName string, action EnvironmentAction) tea.Cmd {
+	switch action {
+	case ActionTerminal:
+		return func() tea.Msg { return OpenTerminalMsg{Environment: envName} }
+		
+	case ActionStart, ActionStop, ActionRestart:
+		if _, busy := m.pending[envName]; busy {
+			return nil
+		}
+		// Show the transition right away; the result comes with ActionFinishedMsg
+		m.pending[envName] = action
+		m.notice = ""
+		m.updateTableRows()
+		return m.lifecycleAction(envName, action)
+		
+	case ActionRebuild:
+		if m.rebuilding[envName] {
+			return nil
+		}
+		m.rebuilding[envName] = true
+		m.notice = ""
+		m.updateTableRows()
+		return m.rebuildEnvironment(envName)
+		
+	case ActionLogs:
+		envManager := m.envManager
+		return func() tea.Msg {
+			lines, err := envManager.Logs(context.Background(), envName)
+			return LogsLoadedMsg{Environment: envName, Lines: lines, Error: err}
+		}
+		
+	case ActionBrowse:
+		envManager := m.envManager
+		return func() tea.Msg {
+			url, err := envManager.PrimaryURL(context.Background(), envName)
+			if err == nil {
+				err = utils.OpenWithDesktop(url)
+			}
+			return BrowseFinishedMsg{Environment: envName, URL: url, Error: err}
+		}
+		
+	case ActionCopyName:
+		if err := utils.CopyToClipboard(envName); err != nil {
+			m.notice = fmt.Sprintf("❌ %v", err)
+		} else {
+			m.notice = fmt.Sprintf("📋 Copied %s", envName)
+		}
+		return nil
+		
+	case ActionDelete:
+		if m.protected(envName) {
+			m.notice = fmt.Sprintf("🔒 %s is protected; press [p] to unprotect it first", envName)
+			return nil
+		}
+		// TODO: Show confirmation dialog
+		return m.deleteEnvironment(envName)
+	}
+	return nil
+}
+
+// lifecycleAction starts, stops or restarts an environment as an operation
+func (m *EnvironmentListModel) lifecycleAction(envName string, action EnvironmentAction) tea.Cmd {
+	operations := m.operations
+	envManager := m.envManager
+	opType := map[EnvironmentAction]utils.OperationType{
+		ActionStart:   utils.EnvironmentStart,
+		ActionStop:    utils.EnvironmentStop,
+		ActionRestart: utils.EnvironmentRestart,
+	}[action]
+	return func() tea.Msg {
+		err := operations.Run(opType, envName, func(ctx context.Context) error {
+			if action != ActionStart {
+				if err := envManager.StopEnvironment(ctx, envName); err != nil {
+					return err
+				}
+			}
+			if action != ActionStop {
+				return envManager.StartEnvironment(ctx, envName)
+			}
+			return nil
+		})
+		return ActionFinishedMsg{Environment: envName, Action: action, Error: err}
+	}
+}
+
+// protected reports whether the listed environment envName is protected
+func (m *EnvironmentListModel) protected(envName string) bool {
+	for _, env := range m.environments {
+		if env.Name == envName {
+			return env.Protected
+		}
+	}
+	return false
+}
+
 // deleteEnvironment deletes the specified environment
 func (m *EnvironmentListModel) deleteEnvironment(envName string) tea.Cmd {
+	operations := m.operations
 	return func() tea.Msg {
-		ctx := context.Background()
-		if err := m.envManager.DeleteEnvironment(ctx, envName); err != nil {
+		err := operations.Run(utils.EnvironmentDelete, envName, func(ctx context.Context) error {
+			return m.envManager.DeleteEnvironment(ctx, envName, false)
+		})
+		if err != nil {
 			// TODO: Show error message
 			return nil
 		}
@@ -285,13 +677,40 @@ func (m *EnvironmentListModel) deleteEnvironment(envName string) tea.Cmd {
 	}
 }
 
+// rebuildEnvironment rebuilds the specified environment's image and container
+func (m *EnvironmentListModel) rebuildEnvironment(envName string) tea.Cmd {
+	operations := m.operations
+	return func() tea.Msg {
+		err := operations.Run(utils.EnvironmentRebuild, envName, func(ctx context.Context) error {
+			return m.envManager.RebuildEnvironment(ctx, envName)
+		})
+		return RebuildFinishedMsg{Environment: envName, Error: err}
+	}
+}
+
 // getStatusDisplay returns a user-friendly status display with emoji
-func getStatusDisplay(status string) string {
+func getStatusDisplay(status, health string) string {
+	// A running container with a HEALTHCHECK is shown by its health instead
+	if status == "running" {
+		switch health {
+		case "healthy":
+			return "🟢 healthy"
+		case "unhealthy":
+			return "🔴 unhealthy"
+		case "starting":
+			return "🔄 starting"
+		}
+	}
+	
 	switch status {
 	case "running":
 		return "🟢 running"
 	case "stopped":
 		return "🟡 stopped"
+	case "created":
+		return "⚪ created"
+	case "worktree":
+		return "📁 worktree"
 	case "creating":
 		return "🔄 creating"
 	case "error":
@@ -316,7 +735,7 @@ func (m *EnvironmentListModel) environmentsChanged(newEnvs []config.Environment)
 	for _, newEnv := range newEnvs {
 		if existing, exists := current[newEnv.Name]; !exists {
 			return true
-		} else if existing.Status != newEnv.Status || existing.ContainerID != newEnv.ContainerID {
+		} else if existing.Status != newEnv.Status || existing.Health != newEnv.Health || existing.ContainerID != newEnv.ContainerID || existing.Stale != newEnv.Stale || existing.Protected != newEnv.Protected || existing.Note != newEnv.Note {
 			return true
 		}
 	}
@@ -199,7 +199,7 @@ func (m *StandaloneListModel) renderMainView() string {
 
 	help := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("241")).
-		Render("[↑↓] navigate  [enter] terminal  [d] delete  [r] refresh  [q] quit  [?] help")
+		Render("[↑↓] navigate  [enter] terminal  [b] browse  [s] start/stop  [d] delete  [p] protect  [R] rebuild  [r] refresh  [q] quit  [?] help")
 
 	header := lipgloss.JoinHorizontal(
 		lipgloss.Left,
@@ -255,6 +255,11 @@ func (m *StandaloneListModel) handleDeleteAction() (tea.Model, tea.Cmd) {
 		m.messageStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
 		return m, nil
 	}
+	if env.Protected {
+		m.message = fmt.Sprintf("%s is protected; press [p] to unprotect it first", envName)
+		m.messageStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("208"))
+		return m, nil
+	}
 
 	details := []string{
 		fmt.Sprintf("Branch: %s", branch),
@@ -262,6 +267,12 @@ func (m *StandaloneListModel) handleDeleteAction() (tea.Model, tea.Cmd) {
 		fmt.Sprintf("Container: %s", env.ContainerName),
 		fmt.Sprintf("Volume: %s", env.VolumeName),
 	}
+	if env.InPlace {
+		details[1] = fmt.Sprintf("Worktree: %s (current checkout, kept)", env.WorktreePath)
+	}
+	if env.Note != "" {
+		details = append(details, fmt.Sprintf("Note: %s", env.Note))
+	}
 
 	m.confirmModel = NewDeleteConfirmationModel(envName, "Environment", details)
 	m.confirmModel.SetSize(m.width, m.height)
@@ -279,7 +290,7 @@ func (m *StandaloneListModel) executeDelete() (tea.Model, tea.Cmd) {
 
 	return m, func() tea.Msg {
 		ctx := context.Background()
-		if err := m.envManager.DeleteEnvironment(ctx, envName); err != nil {
+		if err := m.envManager.DeleteEnvironment(ctx, envName, false); err != nil {
 			return DeleteErrorMsg{
 				Environment: envName,
 				Error:       err,
@@ -311,4 +322,10 @@ type DeleteSuccessMsg struct {
 // OpenTerminalMsg requests opening a terminal (causes TUI to quit)
 type OpenTerminalMsg struct {
 	Environment string
+}
+
+// OpenEditorMsg requests opening an environment's worktree in the editor
+// (causes TUI to quit)
+type OpenEditorMsg struct {
+	Environment string
 }
\ No newline at end of file
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// LogsModel shows an environment's container output, scrolled to the end
+type LogsModel struct {
+	viewport    viewport.Model
+	environment string
+	lines       []string
+	width       int
+	height      int
+}
+
+// LogsLoadedMsg carries an environment's container output for the logs view
+type LogsLoadedMsg struct {
+	Environment string
+	Lines       []string
+	Error       error
+}
+
+// LogsClosedMsg is sent when the user leaves the logs view
+type LogsClosedMsg struct{}
+
+// NewLogsModel creates a logs view over lines
+func NewLogsModel(envName string, lines []string) *LogsModel {
+	m := &LogsModel{
+		viewport:    viewport.New(80, 20),
+		environment: envName,
+		lines:       lines,
+	}
+	m.setContent()
+	return m
+}
+
+// Update handles scrolling and closing
+func (m *LogsModel) Update(msg tea.Msg) (*LogsModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "esc" {
+		return m, func() tea.Msg { return LogsClosedMsg{} }
+	}
+
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// View renders the output
+func (m *LogsModel) View() string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("205")).
+		Render("Logs")
+
+	subtitle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Render(fmt.Sprintf("%s, %d lines", m.environment, len(m.lines)))
+
+	footer := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Render("[↑↓/pgup/pgdn] scroll  [esc] back")
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, subtitle, "", m.viewport.View(), "", footer)
+}
+
+// SetSize fits the viewport to the screen
+func (m *LogsModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	if width > 0 {
+		m.viewport.Width = width
+	}
+	if height > 8 {
+		m.viewport.Height = height - 6
+	}
+	m.setContent()
+}
+
+// setContent fills the viewport and scrolls to the latest output
+func (m *LogsModel) setContent() {
+	content := strings.Join(m.lines, "\n")
+	if len(m.lines) == 0 {
+		content = "No output yet."
+	}
+	m.viewport.SetContent(content)
+	m.viewport.GotoBottom()
+}
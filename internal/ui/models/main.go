@@ -1,8 +1,14 @@
 package models
 
 import (
+	"fmt"
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/jhjaggars/cc-buddy/internal/config"
+	"github.com/jhjaggars/cc-buddy/internal/environment"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
 	"github.com/jhjaggars/cc-buddy/internal/utils"
 )
 
@@ -16,6 +22,13 @@ const (
 	ProgressView
 	ConfirmationView
 	InterruptionView
+	InitView
+	HistoryView
+	OperationsView
+	TasksView
+	DashboardView
+	ActionsView
+	LogsView
 )
 
 // MainModel is the root Bubble Tea model
@@ -25,13 +38,21 @@ type MainModel struct {
 	height      int
 	
 	// Sub-models for different views
-	listModel           *EnvironmentListModel
-	createModel         *CreateWizardModel
-	deleteModel         *DeleteModel
-	progressModel       *ProgressModel
-	confirmationModel   *ConfirmationModel
-	interruptionDialog  *InterruptionDialog
-	helpModel           *HelpModel
+	listModel          *EnvironmentListModel
+	createModel        *CreateWizardModel
+	initModel          *InitWizardModel
+	historyModel       *HistoryModel
+	operationsModel    *OperationsModel
+	taskMenuModel      *TaskMenuModel
+	dashboardModel     *DashboardModel
+	actionMenuModel    *ActionMenuModel
+	logsModel          *LogsModel
+	deleteModel        *DeleteModel
+	progressModel      *ProgressModel
+	creatingBranch     string // branch of the create shown in the progress view
+	confirmationModel  *ConfirmationModel
+	interruptionDialog *InterruptionDialog
+	helpModel          *HelpModel
 	
 	// Operation management
 	operationManager    *utils.OperationManager
@@ -39,6 +60,8 @@ type MainModel struct {
 	
 	// Terminal launch state
 	terminalEnvName     string
+	taskName            string // task to run instead of opening a terminal
+	editorEnvName       string // environment whose worktree to open in the editor
 }
 
 // NewMainModel creates a new main model
@@ -53,6 +76,15 @@ func NewMainModel() *MainModel {
 		helpModel:        NewHelpModel(),
 		operationManager: operationManager,
 	}
+	m.listModel.SetOperationManager(operationManager)
+	m.createModel.SetOperationManager(operationManager)
+	
+	if _, err := environment.NewGitOperations(); err != nil {
+		// Outside a repository, start with every repository's environments
+		m.dashboardModel = NewDashboardModel(operationManager)
+		m.currentView = DashboardView
+		m.helpModel.SetContext(DashboardHelpContext)
+	}
 	
 	return m
 }
@@ -65,11 +97,15 @@ func (m *MainModel) SetProgram(program *tea.Program) {
 
 // Init implements tea.Model
 func (m *MainModel) Init() tea.Cmd {
-	return tea.Batch(
+	cmds := []tea.Cmd{
 		m.listModel.Init(),
 		m.createModel.Init(),
 		m.deleteModel.Init(),
-	)
+	}
+	if m.dashboardModel != nil {
+		cmds = append(cmds, m.dashboardModel.Init())
+	}
+	return tea.Batch(cmds...)
 }
 
 // Update implements tea.Model
@@ -92,6 +128,27 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.confirmationModel != nil {
 			m.confirmationModel.SetSize(msg.Width, msg.Height)
 		}
+		if m.initModel != nil {
+			m.initModel.SetSize(msg.Width, msg.Height)
+		}
+		if m.historyModel != nil {
+			m.historyModel.SetSize(msg.Width, msg.Height)
+		}
+		if m.operationsModel != nil {
+			m.operationsModel.SetSize(msg.Width, msg.Height)
+		}
+		if m.taskMenuModel != nil {
+			m.taskMenuModel.SetSize(msg.Width, msg.Height)
+		}
+		if m.dashboardModel != nil {
+			m.dashboardModel.SetSize(msg.Width, msg.Height)
+		}
+		if m.actionMenuModel != nil {
+			m.actionMenuModel.SetSize(msg.Width, msg.Height)
+		}
+		if m.logsModel != nil {
+			m.logsModel.SetSize(msg.Width, msg.Height)
+		}
 		m.helpModel.SetSize(msg.Width, msg.Height)
 		
 	case utils.InterruptionMsg:
@@ -109,12 +166,42 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 		
+	case CreateStartedMsg:
+		m.progressModel = NewProgressModel("Creating "+msg.Branch, environment.CreateSteps)
+		m.progressModel.SetEstimates(msg.Estimates)
+		m.progressModel.SetSize(m.width, m.height)
+		m.creatingBranch = msg.Branch
+		m.currentView = ProgressView
+		return m, createProgressTick()
+
+	case createProgressTickMsg:
+		// Stop following once the progress view has been closed
+		if m.progressModel == nil {
+			return m, nil
+		}
+		active := m.operationManager.GetActiveOperations()
+		for i := range active {
+			op := &active[i]
+			if op.Type != utils.EnvironmentCreate || op.Environment != m.creatingBranch {
+				continue
+			}
+			for i, step := range environment.CreateSteps {
+				if step == op.Status {
+					m.progressModel, cmd = m.progressModel.Update(ProgressUpdateMsg{StepIndex: i, Progress: op.Progress})
+					cmds = append(cmds, cmd)
+				}
+			}
+		}
+		return m, tea.Batch(append(cmds, createProgressTick())...)
+
 	case CreateProgressMsg:
 		// Handle creation progress
 		if msg.Error != nil {
-			// Show error and return to main view
-			m.currentView = MainView
+			// Back to the wizard, which shows the error
+			m.currentView = CreateView
 			m.progressModel = nil
+			m.createModel, cmd = m.createModel.Update(msg)
+			return m, cmd
 		} else if msg.Completed {
 			// Creation completed, refresh list and return to main
 			m.currentView = MainView
@@ -123,12 +210,124 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case InitCompletedMsg:
+		// Return to the list whether the wizard wrote the file or was cancelled
+		m.currentView = MainView
+		m.initModel = nil
+		return m, nil
+
+	case HistoryClosedMsg:
+		m.currentView = MainView
+		m.historyModel = nil
+		return m, nil
+
+	case OperationsClosedMsg:
+		m.currentView = MainView
+		m.operationsModel = nil
+		return m, nil
+
+	case OpenTaskMenuMsg:
+		m.taskMenuModel = NewTaskMenuModel(msg.Environment, msg.Names, msg.Tasks)
+		m.taskMenuModel.SetSize(m.width, m.height)
+		m.currentView = TasksView
+		m.helpModel.SetContext(TasksHelpContext)
+		return m, nil
+
+	case TaskMenuClosedMsg:
+		m.currentView = MainView
+		m.taskMenuModel = nil
+		return m, nil
+
+	case OpenActionMenuMsg:
+		m.actionMenuModel = NewActionMenuModel(msg.Environment, msg.Status)
+		m.actionMenuModel.SetSize(m.width, m.height)
+		m.currentView = ActionsView
+		m.helpModel.SetContext(ActionsHelpContext)
+		return m, nil
+
+	case ActionMenuClosedMsg:
+		m.currentView = MainView
+		m.actionMenuModel = nil
+		return m, nil
+
+	case RunActionMsg:
+		m.currentView = MainView
+		m.actionMenuModel = nil
+		return m, m.listModel.RunAction(msg.Environment, msg.Action)
+
+	case LogsLoadedMsg:
+		if msg.Error != nil {
+			m.listModel, cmd = m.listModel.Update(msg)
+			return m, cmd
+		}
+		m.logsModel = NewLogsModel(msg.Environment, msg.Lines)
+		m.logsModel.SetSize(m.width, m.height)
+		m.currentView = LogsView
+		m.helpModel.SetContext(LogsHelpContext)
+		return m, nil
+
+	case LogsClosedMsg:
+		m.currentView = MainView
+		m.logsModel = nil
+		return m, nil
+
+	case ActionFinishedMsg, BrowseFinishedMsg:
+		// Deliver to the list even when another view is open
+		m.listModel, cmd = m.listModel.Update(msg)
+		return m, cmd
+
+	case DashboardClosedMsg:
+		m.currentView = MainView
+		m.dashboardModel = nil
+		return m, nil
+
+	case SwitchRepositoryMsg:
+		return m.switchRepository(msg)
+
+	case RunTaskMsg:
+		// Quit to run the task in the foreground, like a terminal
+		m.terminalEnvName = msg.Environment
+		m.taskName = msg.Task
+		return m, tea.Quit
+
+	case RebuildFinishedMsg:
+		// Deliver to the list even when another view is open
+		m.listModel, cmd = m.listModel.Update(msg)
+		return m, cmd
+
+	case operationsTickMsg:
+		// Stop refreshing once the view has been closed
+		if m.operationsModel == nil {
+			return m, nil
+		}
+
 	case OpenTerminalMsg:
 		// Store environment name and quit to launch terminal
 		m.terminalEnvName = msg.Environment
 		return m, tea.Quit
 
+	case OpenEditorMsg:
+		// Quit to run the editor in the foreground, like a terminal
+		m.editorEnvName = msg.Environment
+		return m, tea.Quit
+
 	case tea.KeyMsg:
+		if m.currentView == InitView && m.initModel != nil && msg.String() != "ctrl+c" {
+			// The wizard's text inputs need every key, including q, n and ?
+			m.initModel, cmd = m.initModel.UpdateWizard(msg)
+			return m, cmd
+		}
+		if m.currentView == CreateView && m.createModel.Typing() && msg.String() != "ctrl+c" {
+			// Startup commands and paths contain q, h and ?
+			m.createModel, cmd = m.createModel.Update(msg)
+			return m, cmd
+		}
+		if m.currentView == DashboardView && m.dashboardModel != nil && m.dashboardModel.Searching() && msg.String() != "ctrl+c" {
+			// Likewise the repository switcher's search box
+			m.dashboardModel, cmd = m.dashboardModel.Update(msg)
+			return m, cmd
+		}
+		
 		switch msg.String() {
 		case "ctrl+c":
 			// Let signal handler manage this
@@ -142,6 +341,12 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.currentView = MainView
 			m.progressModel = nil
 			m.confirmationModel = nil
+			m.historyModel = nil
+			m.operationsModel = nil
+			m.taskMenuModel = nil
+			m.dashboardModel = nil
+			m.actionMenuModel = nil
+			m.logsModel = nil
 			return m, nil
 			
 		case "n":
@@ -151,6 +356,42 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 			
+		case "i":
+			if m.currentView == MainView {
+				m.initModel = NewInitWizardModel("Containerfile.dev", false)
+				m.initModel.SetSize(m.width, m.height)
+				m.currentView = InitView
+				m.helpModel.SetContext(InitHelpContext)
+				return m, m.initModel.Init()
+			}
+			
+		case "H":
+			if m.currentView == MainView {
+				m.historyModel = NewHistoryModel(m.listModel.SelectedEnvironment())
+				m.historyModel.SetSize(m.width, m.height)
+				m.currentView = HistoryView
+				m.helpModel.SetContext(HistoryHelpContext)
+				return m, nil
+			}
+			
+		case "A":
+			if m.currentView == MainView {
+				m.dashboardModel = NewDashboardModel(m.operationManager)
+				m.dashboardModel.SetSize(m.width, m.height)
+				m.currentView = DashboardView
+				m.helpModel.SetContext(DashboardHelpContext)
+				return m, m.dashboardModel.Init()
+			}
+			
+		case "o":
+			if m.currentView == MainView {
+				m.operationsModel = NewOperationsModel(m.operationManager)
+				m.operationsModel.SetSize(m.width, m.height)
+				m.currentView = OperationsView
+				m.helpModel.SetContext(OperationsHelpContext)
+				return m, m.operationsModel.Init()
+			}
+			
 		case "?", "h":
 			// Toggle help
 			m.helpModel.Update(msg)
@@ -191,6 +432,55 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.confirmationModel, cmd = m.confirmationModel.Update(msg)
 			cmds = append(cmds, cmd)
 		}
+		
+	case InitView:
+		m.helpModel.SetContext(InitHelpContext)
+		if m.initModel != nil {
+			m.initModel, cmd = m.initModel.UpdateWizard(msg)
+			cmds = append(cmds, cmd)
+		}
+		
+	case HistoryView:
+		m.helpModel.SetContext(HistoryHelpContext)
+		if m.historyModel != nil {
+			m.historyModel, cmd = m.historyModel.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		
+	case OperationsView:
+		m.helpModel.SetContext(OperationsHelpContext)
+		if m.operationsModel != nil {
+			m.operationsModel, cmd = m.operationsModel.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		
+	case TasksView:
+		m.helpModel.SetContext(TasksHelpContext)
+		if m.taskMenuModel != nil {
+			m.taskMenuModel, cmd = m.taskMenuModel.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		
+	case DashboardView:
+		m.helpModel.SetContext(DashboardHelpContext)
+		if m.dashboardModel != nil {
+			m.dashboardModel, cmd = m.dashboardModel.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		
+	case ActionsView:
+		m.helpModel.SetContext(ActionsHelpContext)
+		if m.actionMenuModel != nil {
+			m.actionMenuModel, cmd = m.actionMenuModel.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		
+	case LogsView:
+		m.helpModel.SetContext(LogsHelpContext)
+		if m.logsModel != nil {
+			m.logsModel, cmd = m.logsModel.Update(msg)
+			cmds = append(cmds, cmd)
+		}
 	}
 
 	return m, tea.Batch(cmds...)
@@ -219,6 +509,48 @@ func (m *MainModel) View() string {
 		} else {
 			baseView = "Error: confirmation model not initialized"
 		}
+	case InitView:
+		if m.initModel != nil {
+			baseView = m.initModel.View()
+		} else {
+			baseView = "Error: init wizard not initialized"
+		}
+	case HistoryView:
+		if m.historyModel != nil {
+			baseView = m.historyModel.View()
+		} else {
+			baseView = "Error: history view not initialized"
+		}
+	case OperationsView:
+		if m.operationsModel != nil {
+			baseView = m.operationsModel.View()
+		} else {
+			baseView = "Error: operations view not initialized"
+		}
+	case TasksView:
+		if m.taskMenuModel != nil {
+			baseView = m.taskMenuModel.View()
+		} else {
+			baseView = "Error: task menu not initialized"
+		}
+	case DashboardView:
+		if m.dashboardModel != nil {
+			baseView = m.dashboardModel.View()
+		} else {
+			baseView = "Error: dashboard not initialized"
+		}
+	case ActionsView:
+		if m.actionMenuModel != nil {
+			baseView = m.actionMenuModel.View()
+		} else {
+			baseView = "Error: action menu not initialized"
+		}
+	case LogsView:
+		if m.logsModel != nil {
+			baseView = m.logsModel.View()
+		} else {
+			baseView = "Error: logs view not initialized"
+		}
 	case InterruptionView:
 		if m.interruptionDialog != nil {
 			baseView = m.interruptionDialog.View()
@@ -247,7 +579,7 @@ func (m *MainModel) renderMainView() string {
 		
 	help := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("241")).
-		Render("[q] quit  [n] new environment  [?] help")
+		Render("[q] quit  [n] new environment  [i] init  [A] all repos  [H] history  [o] ops  [?] help")
 		
 	header := lipgloss.JoinHorizontal(
 		lipgloss.Left,
@@ -309,6 +641,16 @@ func (m *MainModel) handleConfirmationResult(result ConfirmationResult) (tea.Mod
 	return m, func() tea.Msg { return RefreshEnvironmentsMsg{} }
 }
 
+// createProgressTickMsg refreshes the progress of the create being shown
+type createProgressTickMsg struct{}
+
+// createProgressTick schedules the next create progress refresh
+func createProgressTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return createProgressTickMsg{}
+	})
+}
+
 // ShowProgress displays a progress dialog
 func (m *MainModel) ShowProgress(title string, steps []string) {
 	m.progressModel = NewProgressModel(title, steps)
@@ -326,6 +668,55 @@ func (m *MainModel) GetTerminalEnvironment() string {
 	return m.terminalEnvName
 }
 
+// GetEditorEnvironment returns the environment whose worktree to open in
+// the editor
+func (m *MainModel) GetEditorEnvironment() string {
+	return m.editorEnvName
+}
+
+// GetTask returns the task to run in the terminal environment, if one was
+// picked from the task menu instead of opening a shell
+func (m *MainModel) GetTask() string {
+	return m.taskName
+}
+
+// switchRepository re-targets the TUI at another repository: the list and
+// create wizard get managers for it in place, while opening a terminal
+// quits as usual and the TUI comes back in the new repository
+func (m *MainModel) switchRepository(msg SwitchRepositoryMsg) (tea.Model, tea.Cmd) {
+	if active := m.operationManager.GetActiveOperations(); len(active) > 0 {
+		// Their managers resolve paths against the working directory
+		m.dashboardModel.notice = fmt.Sprintf("⏳ Wait for %d running operation(s) before switching repositories", len(active))
+		return m, nil
+	}
+	if err := environment.EnterRepository(msg.Path); err != nil {
+		m.dashboardModel.notice = fmt.Sprintf("❌ %v", err)
+		return m, nil
+	}
+	if err := config.RecordRepositoryUse(msg.Path); err != nil {
+		logging.Logger().Debug("failed to record repository use", "repository", msg.Path, "error", err.Error())
+	}
+
+	if msg.Environment != "" {
+		m.terminalEnvName = msg.Environment
+		return m, tea.Quit
+	}
+
+	m.listModel = NewEnvironmentListModel()
+	m.listModel.SetOperationManager(m.operationManager)
+	m.listModel.SetSize(m.width, m.height)
+	m.createModel = NewCreateWizardModel()
+	m.createModel.SetOperationManager(m.operationManager)
+	m.createModel.SetSize(m.width, m.height)
+	m.dashboardModel = nil
+	m.currentView = MainView
+	m.helpModel.SetContext(ListHelpContext)
+	return m, tea.Batch(
+		m.createModel.Init(),
+		func() tea.Msg { return ManualRefreshMsg{} },
+	)
+}
+
 // Cleanup performs cleanup when the model is destroyed
 func (m *MainModel) Cleanup() {
 	if m.signalHandler != nil {
@@ -0,0 +1,183 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jhjaggars/cc-buddy/internal/utils"
+)
+
+// OperationsModel lists running and recently finished operations
+type OperationsModel struct {
+	table      table.Model
+	operations *utils.OperationManager
+	errors     []string // error of each row, shown for the selected one
+	width      int
+	height     int
+}
+
+// OperationsClosedMsg is sent when the user leaves the operations view
+type OperationsClosedMsg struct{}
+
+// operationsTickMsg refreshes running operations' durations
+type operationsTickMsg struct{}
+
+// NewOperationsModel creates an operations view over operations
+func NewOperationsModel(operations *utils.OperationManager) *OperationsModel {
+	columns := []table.Column{
+		{Title: "Operation", Width: 20},
+		{Title: "Environment", Width: 25},
+		{Title: "Status", Width: 14},
+		{Title: "Started", Width: 10},
+		{Title: "Duration", Width: 9},
+	}
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithFocused(true),
+		table.WithHeight(10),
+	)
+
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Bold(false)
+	t.SetStyles(s)
+
+	m := &OperationsModel{
+		table:      t,
+		operations: operations,
+	}
+	m.load()
+	return m
+}
+
+// Init starts the refresh ticker
+func (m *OperationsModel) Init() tea.Cmd {
+	return m.tick()
+}
+
+// tick schedules the next refresh
+func (m *OperationsModel) tick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return operationsTickMsg{}
+	})
+}
+
+// load fills the table with running operations followed by the history
+func (m *OperationsModel) load() {
+	var rows []table.Row
+	m.errors = nil
+
+	active := m.operations.GetActiveOperations()
+	for i := range active {
+		op := &active[i]
+		status := "🔄 " + op.Status
+		if op.Progress > 0 {
+			status = fmt.Sprintf("🔄 %.0f%%", op.Progress*100)
+		}
+		rows = append(rows, table.Row{
+			op.Type.String(),
+			op.Environment,
+			status,
+			op.StartTime.Format("15:04:05"),
+			time.Since(op.StartTime).Round(time.Second).String(),
+		})
+		m.errors = append(m.errors, "")
+	}
+
+	for _, record := range m.operations.GetHistory() {
+		status := "✅ completed"
+		switch record.Status {
+		case "failed":
+			status = "❌ failed"
+		case "cancelled":
+			status = "⏹ cancelled"
+		}
+		errText := ""
+		if record.Error != nil {
+			errText = record.Error.Error()
+		}
+		rows = append(rows, table.Row{
+			record.Type.String(),
+			record.Environment,
+			status,
+			record.StartTime.Format("15:04:05"),
+			record.Duration().Round(time.Second).String(),
+		})
+		m.errors = append(m.errors, errText)
+	}
+
+	m.table.SetRows(rows)
+}
+
+// Update handles navigation, refreshes and closing
+func (m *OperationsModel) Update(msg tea.Msg) (*OperationsModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case operationsTickMsg:
+		m.load()
+		return m, m.tick()
+
+	case tea.KeyMsg:
+		if msg.String() == "esc" {
+			return m, func() tea.Msg { return OperationsClosedMsg{} }
+		}
+	}
+
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+// View renders the operations table and the selected operation's error
+func (m *OperationsModel) View() string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("205")).
+		Render("Operations")
+
+	subtitle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Render(fmt.Sprintf("Running and the last %d finished operations this session", utils.MaxOperationHistory))
+
+	footer := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Render("[↑↓] select  [esc] back")
+
+	if len(m.errors) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, title, subtitle, "", "No operations yet.", "", footer)
+	}
+
+	detail := ""
+	if cursor := m.table.Cursor(); cursor >= 0 && cursor < len(m.errors) && m.errors[cursor] != "" {
+		width := m.width - 2
+		if width < 40 {
+			width = 78
+		}
+		detail = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Width(width).
+			Render("Error: " + m.errors[cursor])
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, subtitle, "", m.table.View(), "", detail, footer)
+}
+
+// SetSize updates the table height to leave room for the error detail
+func (m *OperationsModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	if height > 14 {
+		m.table.SetHeight(height - 12)
+	}
+}
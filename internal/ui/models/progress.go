@@ -23,6 +23,8 @@ type ProgressModel struct {
 	err          error
 	cancelled    bool
 	cancelFunc   func() error // Function to call when cancellation is requested
+	estimates    []time.Duration // expected duration of each step, if known
+	stepStarted  time.Time       // when the current step began
 }
 
 // ProgressStep represents a single step in a multi-step operation
@@ -107,6 +109,16 @@ func (m *ProgressModel) Update(msg tea.Msg) (*ProgressModel, tea.Cmd) {
 	case ProgressUpdateMsg:
 		if msg.StepIndex >= 0 && msg.StepIndex < len(m.steps) {
 			step := &m.steps[msg.StepIndex]
+			if step.Status == StepPending {
+				m.stepStarted = time.Now()
+				// Steps run in order, so earlier ones are done
+				for i := 0; i < msg.StepIndex; i++ {
+					if m.steps[i].Status == StepInProgress {
+						m.steps[i].Status = StepCompleted
+						m.steps[i].Progress = 1.0
+					}
+				}
+			}
 			
 			if msg.Error != nil {
 				step.Status = StepFailed
@@ -267,12 +279,50 @@ func (m *ProgressModel) renderStep(index int, step ProgressStep) string {
 	if step.Status == StepFailed && step.Error != nil {
 		text = fmt.Sprintf("%s - %v", step.Name, step.Error)
 	}
+	if remaining, ok := m.Remaining(); ok && index == m.currentStep && step.Status == StepInProgress {
+		text = fmt.Sprintf("%s… %s left", step.Name, formatRemaining(remaining))
+	}
 	
 	return fmt.Sprintf("  %s %s", 
 		style.Render(icon), 
 		style.Render(text))
 }
 
+// SetEstimates sets how long each step is expected to take, from earlier
+// runs, so the view can show the time remaining
+func (m *ProgressModel) SetEstimates(estimates []time.Duration) {
+	m.estimates = estimates
+}
+
+// Remaining estimates the time left: what remains of the current step's
+// estimate plus the estimates of the steps after it. It reports false when
+// there are no estimates or nothing is running.
+func (m *ProgressModel) Remaining() (time.Duration, bool) {
+	if m.estimates == nil || m.completed || m.cancelled || m.err != nil {
+		return 0, false
+	}
+	if m.currentStep >= len(m.steps) || m.currentStep >= len(m.estimates) || m.steps[m.currentStep].Status != StepInProgress {
+		return 0, false
+	}
+
+	remaining := m.estimates[m.currentStep] - time.Since(m.stepStarted)
+	if remaining < 0 {
+		remaining = 0
+	}
+	for _, estimate := range m.estimates[m.currentStep+1:] {
+		remaining += estimate
+	}
+	return remaining, remaining > 0
+}
+
+// formatRemaining rounds an estimate to what is worth showing
+func formatRemaining(d time.Duration) string {
+	if d < 55*time.Second {
+		return fmt.Sprintf("~%ds", int((d + 4*time.Second).Seconds())/5*5)
+	}
+	return fmt.Sprintf("~%dm", int(d.Round(time.Minute).Minutes()))
+}
+
 // UpdateStep sends a progress update for a specific step
 func (m *ProgressModel) UpdateStep(stepIndex int, progress float64, description string) tea.Cmd {
 	return func() tea.Msg {
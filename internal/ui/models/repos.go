@@ -0,0 +1,268 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jhjaggars/cc-buddy/internal/config"
+)
+
+// RepoPickerModel picks a repository to switch to: recently used ones first,
+// narrowed by a fuzzy search over every known git root
+type RepoPickerModel struct {
+	query      textinput.Model
+	candidates []repoCandidate
+	matches    []repoCandidate
+	cursor     int
+	loading    bool
+	err        error
+	width      int
+	height     int
+}
+
+// repoCandidate is a git root the picker can switch to
+type repoCandidate struct {
+	path   string
+	recent bool // in the registry, as opposed to found next to one
+}
+
+// RepoPickerClosedMsg is sent when the picker is left without a choice
+type RepoPickerClosedMsg struct{}
+
+// repoCandidatesMsg carries the repositories found for the picker
+type repoCandidatesMsg struct {
+	candidates []repoCandidate
+	err        error
+}
+
+// NewRepoPickerModel creates a repository picker
+func NewRepoPickerModel() *RepoPickerModel {
+	query := textinput.New()
+	query.Placeholder = "Type to search repositories"
+	query.CharLimit = 100
+	query.Width = 50
+	query.Focus()
+
+	return &RepoPickerModel{
+		query:   query,
+		loading: true,
+	}
+}
+
+// Init finds the candidate repositories
+func (m *RepoPickerModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, loadRepoCandidates)
+}
+
+// loadRepoCandidates lists the registered repositories, most recently used
+// first, followed by the other git roots in the directories holding them
+func loadRepoCandidates() tea.Msg {
+	repos, err := config.Repositories()
+	if err != nil {
+		return repoCandidatesMsg{err: err}
+	}
+	sort.SliceStable(repos, func(i, j int) bool {
+		return repos[i].Used.After(repos[j].Used)
+	})
+
+	seen := map[string]bool{}
+	var candidates []repoCandidate
+	parents := map[string]bool{}
+	for _, repo := range repos {
+		seen[repo.Path] = true
+		parents[filepath.Dir(repo.Path)] = true
+		candidates = append(candidates, repoCandidate{path: repo.Path, recent: true})
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		parents[filepath.Dir(cwd)] = true
+	}
+
+	var found []string
+	for parent := range parents {
+		entries, err := os.ReadDir(parent)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			path := filepath.Join(parent, entry.Name())
+			if !entry.IsDir() || seen[path] {
+				continue
+			}
+			// .git is a directory in a clone and a file in a worktree;
+			// worktrees are environments, not repositories to switch to
+			if info, err := os.Stat(filepath.Join(path, ".git")); err == nil && info.IsDir() {
+				seen[path] = true
+				found = append(found, path)
+			}
+		}
+	}
+	sort.Strings(found)
+	for _, path := range found {
+		candidates = append(candidates, repoCandidate{path: path})
+	}
+	return repoCandidatesMsg{candidates: candidates}
+}
+
+// Update handles typing, navigation, picking and closing
+func (m *RepoPickerModel) Update(msg tea.Msg) (*RepoPickerModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case repoCandidatesMsg:
+		m.loading = false
+		m.err = msg.err
+		m.candidates = msg.candidates
+		m.filter()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return m, func() tea.Msg { return RepoPickerClosedMsg{} }
+		case "up", "ctrl+p":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			return m, nil
+		case "down", "ctrl+n":
+			if m.cursor < len(m.matches)-1 {
+				m.cursor++
+			}
+			return m, nil
+		case "enter":
+			if m.cursor < len(m.matches) {
+				path := m.matches[m.cursor].path
+				return m, func() tea.Msg { return SwitchRepositoryMsg{Path: path} }
+			}
+			return m, nil
+		}
+	}
+
+	m.query, cmd = m.query.Update(msg)
+	m.filter()
+	return m, cmd
+}
+
+// filter keeps the candidates matching the query, best matches first
+func (m *RepoPickerModel) filter() {
+	query := strings.TrimSpace(m.query.Value())
+	type scored struct {
+		candidate repoCandidate
+		score     int
+	}
+	var matches []scored
+	for _, candidate := range m.candidates {
+		score, ok := fuzzyScore(query, candidate.path)
+		if ok {
+			matches = append(matches, scored{candidate, score})
+		}
+	}
+	// Stable, so equal scores keep recent repositories first
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	m.matches = m.matches[:0]
+	for _, match := range matches {
+		m.matches = append(m.matches, match.candidate)
+	}
+	if m.cursor >= len(m.matches) {
+		m.cursor = max(len(m.matches)-1, 0)
+	}
+}
+
+// fuzzyScore reports whether query's characters appear in order in path,
+// ignoring case, and scores the match: characters in the directory name,
+// consecutive characters and characters starting a word count for more
+func fuzzyScore(query, path string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+	target := []rune(strings.ToLower(path))
+	nameStart := len([]rune(filepath.Dir(path))) + 1
+
+	score := 0
+	t := 0
+	previous := -2
+	for _, q := range strings.ToLower(query) {
+		for t < len(target) && target[t] != q {
+			t++
+		}
+		if t == len(target) {
+			return 0, false
+		}
+		score++
+		if t >= nameStart {
+			score += 2
+		}
+		if t == previous+1 {
+			score += 3
+		}
+		if t == 0 || !unicode.IsLetter(target[t-1]) && !unicode.IsDigit(target[t-1]) {
+			score += 2
+		}
+		previous = t
+		t++
+	}
+	return score, true
+}
+
+// View renders the search box and matching repositories
+func (m *RepoPickerModel) View() string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("205")).
+		Render("Switch repository")
+
+	var b strings.Builder
+	switch {
+	case m.err != nil:
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(fmt.Sprintf("Error: %v", m.err)))
+	case m.loading:
+		b.WriteString("Looking for repositories...")
+	case len(m.matches) == 0:
+		b.WriteString("No matching repositories")
+	default:
+		limit := len(m.matches)
+		if m.height > 12 && limit > m.height-10 {
+			limit = m.height - 10
+		}
+		start := 0
+		if m.cursor >= limit {
+			start = m.cursor - limit + 1
+		}
+		for i := start; i < start+limit && i < len(m.matches); i++ {
+			match := m.matches[i]
+			line := fmt.Sprintf("%-25s %s", filepath.Base(match.path), match.path)
+			if match.recent {
+				line += " ★"
+			}
+			if i == m.cursor {
+				line = lipgloss.NewStyle().
+					Foreground(lipgloss.Color("229")).
+					Background(lipgloss.Color("57")).
+					Render(line)
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	footer := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Render("[↑↓] select  [enter] switch  [esc] back  ★ known to cc-buddy")
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, "", m.query.View(), "", b.String(), "", footer)
+}
+
+// SetSize updates how many repositories fit
+func (m *RepoPickerModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TaskMenuModel lists the configured tasks for running one in an environment
+type TaskMenuModel struct {
+	table       table.Model
+	environment string
+	width       int
+	height      int
+}
+
+// TaskMenuClosedMsg is sent when the user leaves the task menu
+type TaskMenuClosedMsg struct{}
+
+// RunTaskMsg requests running a task in an environment (will quit the TUI)
+type RunTaskMsg struct {
+	Environment string
+	Task        string
+}
+
+// NewTaskMenuModel creates a task menu for envName; names are the task
+// names in order and tasks maps them to their commands
+func NewTaskMenuModel(envName string, names []string, tasks map[string]string) *TaskMenuModel {
+	columns := []table.Column{
+		{Title: "Task", Width: 20},
+		{Title: "Command", Width: 50},
+	}
+
+	rows := make([]table.Row, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, table.Row{name, tasks[name]})
+	}
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithFocused(true),
+		table.WithHeight(10),
+	)
+
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Bold(false)
+	t.SetStyles(s)
+
+	return &TaskMenuModel{
+		table:       t,
+		environment: envName,
+	}
+}
+
+// Update handles navigation, running the selected task and closing
+func (m *TaskMenuModel) Update(msg tea.Msg) (*TaskMenuModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			return m, func() tea.Msg { return TaskMenuClosedMsg{} }
+		case "enter":
+			if row := m.table.SelectedRow(); row != nil {
+				envName, task := m.environment, row[0]
+				return m, func() tea.Msg {
+					return RunTaskMsg{Environment: envName, Task: task}
+				}
+			}
+			return m, nil
+		}
+	}
+
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+// View renders the task table
+func (m *TaskMenuModel) View() string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("205")).
+		Render("Tasks")
+
+	subtitle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Render(fmt.Sprintf("Run in %s", m.environment))
+
+	footer := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Render("[↑↓] select  [enter] run  [esc] back")
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, subtitle, "", m.table.View(), "", footer)
+}
+
+// SetSize updates the table height
+func (m *TaskMenuModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	if height > 12 {
+		m.table.SetHeight(height - 10)
+	}
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// clipboardCommands are the platform clipboard tools to try, in order
+func clipboardCommands() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip"}}
+	}
+	commands := [][]string{}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		commands = append(commands, []string{"wl-copy"})
+	}
+	if os.Getenv("DISPLAY") != "" {
+		commands = append(commands, []string{"xclip", "-selection", "clipboard"}, []string{"xsel", "--clipboard", "--input"})
+	}
+	// WSL reaches the Windows clipboard through clip.exe
+	return append(commands, []string{"clip.exe"})
+}
+
+// CopyToClipboard puts text on the clipboard. It always sends an OSC 52
+// escape sequence, which the terminal applies even over SSH, and also uses
+// the first platform clipboard tool found (pbcopy, wl-copy, xclip, xsel,
+// clip) for terminals without OSC 52 support.
+func CopyToClipboard(text string) error {
+	copied := false
+	for _, command := range clipboardCommands() {
+		if _, err := exec.LookPath(command[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(command[0], command[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if cmd.Run() == nil {
+			copied = true
+			break
+		}
+	}
+
+	sequence := fmt.Sprintf("\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+	if os.Getenv("TMUX") != "" {
+		// tmux only passes sequences on to the outer terminal when wrapped
+		sequence = "\x1bPtmux;\x1b" + sequence + "\x1b\\"
+	}
+	if _, err := os.Stdout.WriteString(sequence); err != nil && !copied {
+		return fmt.Errorf("failed to copy to clipboard: %w", err)
+	}
+	return nil
+}
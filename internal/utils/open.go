@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// OpenWithDesktop opens a directory in the file manager, or a URL in the
+// browser, with the desktop's default application
+func OpenWithDesktop(target string) error {
+	var command []string
+	switch runtime.GOOS {
+	case "darwin":
+		command = []string{"open", target}
+	case "windows":
+		command = []string{"rundll32", "url.dll,FileProtocolHandler", target}
+	default:
+		command = []string{"xdg-open", target}
+		if _, err := exec.LookPath("xdg-open"); err != nil && os.Getenv("WSL_DISTRO_NAME") != "" {
+			// WSL without a Linux desktop hands off to Windows
+			command = []string{"wslview", target}
+		}
+	}
+
+	if _, err := exec.LookPath(command[0]); err != nil {
+		return fmt.Errorf("cannot open %s: %s not found", target, command[0])
+	}
+	if out, err := exec.Command(command[0], command[1:]...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s %s failed: %w: %s", command[0], target, err, out)
+	}
+	return nil
+}
@@ -6,6 +6,9 @@ import (
 	"log/slog"
 	"sync"
 	"time"
+
+	"github.com/jhjaggars/cc-buddy/internal/ci"
+	"github.com/jhjaggars/cc-buddy/internal/logging"
 )
 
 // OperationType represents the type of operation
@@ -17,6 +20,10 @@ const (
 	EnvironmentDelete
 	GitWorktree
 	ContainerStart
+	EnvironmentRebuild
+	EnvironmentStart
+	EnvironmentStop
+	EnvironmentRestart
 )
 
 // String returns the string representation of the operation type
@@ -32,6 +39,14 @@ func (ot OperationType) String() string {
 		return "Git Worktree"
 	case ContainerStart:
 		return "Container Start"
+	case EnvironmentRebuild:
+		return "Environment Rebuild"
+	case EnvironmentStart:
+		return "Environment Start"
+	case EnvironmentStop:
+		return "Environment Stop"
+	case EnvironmentRestart:
+		return "Environment Restart"
 	default:
 		return "Unknown"
 	}
@@ -55,10 +70,30 @@ type Operation struct {
 // CleanupFunc is a function that performs cleanup
 type CleanupFunc func() error
 
+// OperationRecord is a finished operation kept in the history
+type OperationRecord struct {
+	ID          string
+	Type        OperationType
+	Environment string
+	StartTime   time.Time
+	EndTime     time.Time
+	Status      string // "completed", "failed" or "cancelled"
+	Error       error
+}
+
+// Duration returns how long the operation ran
+func (r OperationRecord) Duration() time.Duration {
+	return r.EndTime.Sub(r.StartTime)
+}
+
+// MaxOperationHistory bounds how many finished operations are remembered
+const MaxOperationHistory = 50
+
 // OperationManager manages long-running operations
 type OperationManager struct {
 	mu         sync.RWMutex
 	operations map[string]*Operation
+	history    []OperationRecord // oldest first, at most MaxOperationHistory
 	logger     *slog.Logger
 	idCounter  int
 }
@@ -67,10 +102,80 @@ type OperationManager struct {
 func NewOperationManager() *OperationManager {
 	return &OperationManager{
 		operations: make(map[string]*Operation),
-		logger:     slog.Default(),
+		// The debug log rather than stderr, which would garble the TUI
+		logger: logging.Logger(),
 	}
 }
 
+// Run runs fn as an operation of opType, completing or failing it with fn's
+// result. fn's context is cancelled when the operation is. A nil manager
+// just runs fn.
+func (om *OperationManager) Run(opType OperationType, env string, fn func(ctx context.Context) error) error {
+	if om == nil {
+		return fn(context.Background())
+	}
+	
+	op, err := om.StartOperation(opType, env)
+	if err != nil {
+		return err
+	}
+	if err := fn(op.Context); err != nil {
+		om.FailOperation(op.ID, err)
+		return err
+	}
+	return om.CompleteOperation(op.ID)
+}
+
+// recordHistory moves a finished operation into the history, dropping the
+// oldest record once it is full. The caller must hold om.mu.
+func (om *OperationManager) recordHistory(op *Operation, status string, err error) {
+	om.history = append(om.history, OperationRecord{
+		ID:          op.ID,
+		Type:        op.Type,
+		Environment: op.Environment,
+		StartTime:   op.StartTime,
+		EndTime:     time.Now(),
+		Status:      status,
+		Error:       err,
+	})
+	if len(om.history) > MaxOperationHistory {
+		om.history = om.history[len(om.history)-MaxOperationHistory:]
+	}
+}
+
+// GetHistory returns the finished operations, most recent first
+func (om *OperationManager) GetHistory() []OperationRecord {
+	om.mu.RLock()
+	defer om.mu.RUnlock()
+	
+	history := make([]OperationRecord, len(om.history))
+	for i, record := range om.history {
+		history[len(om.history)-1-i] = record
+	}
+	return history
+}
+
+// operationKey is the context key under which an operation's context holds it
+type operationKey struct{}
+
+// ReportProgress updates the progress and status of the operation whose
+// context ctx is, so code deep inside an operation can report steps without
+// knowing about the manager. Outside an operation it only starts a CI log
+// section for the step.
+func ReportProgress(ctx context.Context, progress float64, status string) {
+	ci.Section(ctx, status)
+	op, ok := ctx.Value(operationKey{}).(*Operation)
+	if !ok {
+		return
+	}
+	op.mu.Lock()
+	op.Progress = progress
+	if status != "" {
+		op.Status = status
+	}
+	op.mu.Unlock()
+}
+
 // StartOperation starts a new operation
 func (om *OperationManager) StartOperation(opType OperationType, env string) (*Operation, error) {
 	om.mu.Lock()
@@ -91,6 +196,7 @@ func (om *OperationManager) StartOperation(opType OperationType, env string) (*O
 		Cleanup:     make([]CleanupFunc, 0),
 		Status:      "starting",
 	}
+	op.Context = context.WithValue(ctx, operationKey{}, op)
 	
 	om.operations[id] = op
 	om.logger.Info("Started operation", "id", id, "type", opType.String(), "environment", env)
@@ -114,6 +220,7 @@ func (om *OperationManager) CompleteOperation(id string) error {
 	op.mu.Unlock()
 	
 	delete(om.operations, id)
+	om.recordHistory(op, "completed", nil)
 	om.logger.Info("Completed operation", "id", id, "duration", time.Since(op.StartTime))
 	
 	return nil
@@ -141,7 +248,13 @@ func (om *OperationManager) FailOperation(id string, err error) error {
 		}
 	}
 	
+	// A cancelled context means the user interrupted the operation
+	status := "failed"
+	if op.Context.Err() != nil {
+		status = "cancelled"
+	}
 	delete(om.operations, id)
+	om.recordHistory(op, status, err)
 	om.logger.Error("Failed operation", "id", id, "error", err, "duration", time.Since(op.StartTime))
 	
 	return nil
@@ -175,7 +288,18 @@ func (om *OperationManager) GetActiveOperations() []Operation {
 	operations := make([]Operation, 0, len(om.operations))
 	for _, op := range om.operations {
 		op.mu.RLock()
-		operations = append(operations, *op)
+		operations = append(operations, Operation{
+			ID:          op.ID,
+			Type:        op.Type,
+			Environment: op.Environment,
+			StartTime:   op.StartTime,
+			Context:     op.Context,
+			Cancel:      op.Cancel,
+			Cleanup:     op.Cleanup,
+			Progress:    op.Progress,
+			Status:      op.Status,
+			Error:       op.Error,
+		})
 		op.mu.RUnlock()
 	}
 	
@@ -201,6 +325,7 @@ func (om *OperationManager) ForceCancel() {
 	for id, op := range om.operations {
 		op.Cancel()
 		delete(om.operations, id)
+		om.recordHistory(op, "cancelled", context.Canceled)
 	}
 }
 